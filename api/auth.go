@@ -0,0 +1,15 @@
+package api
+
+import "net/http"
+
+// AuthCheckResponse はAPIキーの確認エンドポイントのレスポンスです。
+type AuthCheckResponse struct {
+	OK bool `json:"ok"`
+}
+
+// handleAuthCheck はAPIキーが有効であることを返すハンドラーです。
+// クライアントの設定画面の接続テストを想定しており、キーの検証は認証ミドルウェアが行うため
+// （無効なキーは401）、ここではデータベースにアクセスせずに200を返すだけです。
+func (s *Server) handleAuthCheck(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, r, http.StatusOK, AuthCheckResponse{OK: true})
+}
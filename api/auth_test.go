@@ -0,0 +1,43 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuthCheck(t *testing.T) {
+	// データベースにアクセスしないため、ストアがなくても応答できる
+	server := NewServer(nil, newTestConfig())
+
+	check := func(apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v0/auth/check", nil)
+		if apiKey != "" {
+			req.Header.Set("X-API-Key", apiKey)
+		}
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		return w
+	}
+
+	// 有効なキーは200と{ok: true}
+	w := check(testAPIKey)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var response AuthCheckResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if !response.OK {
+		t.Error("Expected ok to be true")
+	}
+
+	// 無効なキーとキーなしは401
+	for _, apiKey := range []string{"wrong-key", ""} {
+		if w := check(apiKey); w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status %d for key %q, got %d", http.StatusUnauthorized, apiKey, w.Code)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/stsysd/sougen/model"
+	"github.com/stsysd/sougen/store"
+)
+
+// BadgeParams represents parameters for the shields.io endpoint badge.
+type BadgeParams struct {
+	ProjectID model.HexID
+	DateRange *model.DateRange
+	Tags      *model.Tags
+	Label     string // バッジの左側のラベル（空の場合はプロジェクト名）
+}
+
+// NewBadgeParams creates parameters for the badge from HTTP request.
+func NewBadgeParams(r *http.Request) (*BadgeParams, error) {
+	projectID, err := model.ParseHexID(r.PathValue("project_id"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid project_id: %w", err)
+	}
+
+	query := r.URL.Query()
+
+	dateRange, err := model.NewDateRange(query.Get("from"), query.Get("to"))
+	if err != nil {
+		return nil, err
+	}
+	if dateRange.From().After(dateRange.To()) {
+		return nil, fmt.Errorf("from must not be after to")
+	}
+
+	return &BadgeParams{
+		ProjectID: projectID,
+		DateRange: dateRange,
+		Tags:      model.NewTags(query.Get("tags")),
+		Label:     query.Get("label"),
+	}, nil
+}
+
+// BadgeResponse はshields.ioのendpointバッジのスキーマです。
+// https://shields.io/badges/endpoint-badge
+type BadgeResponse struct {
+	SchemaVersion int    `json:"schemaVersion"` // 常に1
+	Label         string `json:"label"`
+	Message       string `json:"message"` // 期間内の合計値
+	Color         string `json:"color"`   // 期間内の活動した日の割合に応じた色
+}
+
+// badgeColors は活動した日の割合が小さい順のバッジの色です（shields.ioの色名）。
+var badgeColors = []string{"yellowgreen", "green", "brightgreen"}
+
+// badgeColor は期間内の活動した日の割合に応じたバッジの色を返します。
+// 活動がない場合は灰色、それ以外は割合を均等に分けた段階で色を濃くします。
+func badgeColor(activeDays, days int) string {
+	if activeDays == 0 || days <= 0 {
+		return "lightgrey"
+	}
+	level := activeDays * len(badgeColors) / days
+	return badgeColors[min(level, len(badgeColors)-1)]
+}
+
+// handleBadge は期間内の合計値をshields.ioのendpointバッジとして返すハンドラーです。
+func (s *Server) handleBadge(w http.ResponseWriter, r *http.Request) {
+	// パラメータを検証
+	params, err := NewBadgeParams(r)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// プロジェクトの存在確認
+	project, err := s.store.GetProject(r.Context(), params.ProjectID)
+	if err != nil {
+		if errors.Is(err, model.ErrProjectNotFound) {
+			writeJSONError(w, fmt.Sprintf("Project with ID %s not found", params.ProjectID), http.StatusNotFound)
+		} else {
+			writeJSONError(w, fmt.Sprintf("Error retrieving project: %v", err), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// 日別に集計して合計値と活動した日数を求める（日付の区切りはプロジェクトのタイムゾーン）
+	loc := project.Location()
+	dateRange := params.DateRange.In(loc)
+	from := dateRange.From()
+	to := dateRange.To()
+	totals, err := s.store.GetDailyTotals(r.Context(), &store.GetDailyTotalsParams{
+		ProjectID: params.ProjectID,
+		From:      from,
+		To:        to,
+		Tags:      params.Tags.Values(),
+		ValueType: project.RecordValueType(),
+		Location:  loc,
+	})
+	if err != nil {
+		log.Printf("Error retrieving daily totals: %v", err)
+		writeJSONError(w, "Failed to retrieve daily totals", http.StatusInternalServerError)
+		return
+	}
+	var total float64
+	activeDays := 0
+	for _, t := range totals {
+		total += t.Value
+		if t.Value > 0 {
+			activeDays++
+		}
+	}
+	days := 0
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		days++
+	}
+
+	label := params.Label
+	if label == "" {
+		label = project.Name
+	}
+
+	// レスポンスの返却
+	writeJSON(w, r, http.StatusOK, &BadgeResponse{
+		SchemaVersion: 1,
+		Label:         label,
+		Message:       strconv.FormatFloat(total, 'f', -1, 64),
+		Color:         badgeColor(activeDays, days),
+	})
+}
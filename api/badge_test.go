@@ -0,0 +1,77 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stsysd/sougen/model"
+)
+
+func TestBadge(t *testing.T) {
+	mockStore := NewMockStore()
+	project, _ := model.NewProject("commits", "")
+	mockStore.CreateProject(context.Background(), project)
+	for i, tags := range [][]string{{"work"}, {"work"}, {"hobby"}} {
+		record, _ := model.NewRecord(time.Date(2025, 6, 10+i, 12, 0, 0, 0, time.Local), project.ID, 10*(i+1), tags)
+		mockStore.CreateRecord(context.Background(), record)
+	}
+	server := NewServer(mockStore, newTestConfig())
+
+	get := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v0/p/%s/badge.json%s", project.ID, query), nil)
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("?from=2025-06-01&to=2025-06-30")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	// shields.ioのendpointスキーマ（schemaVersion・label・message・color）のみを含む
+	var fields map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &fields); err != nil {
+		t.Fatalf("Failed to decode response body: %v", err)
+	}
+	if len(fields) != 4 || fields["schemaVersion"] != float64(1) {
+		t.Fatalf("Expected shields endpoint shape, got %v", fields)
+	}
+	for _, key := range []string{"label", "message", "color"} {
+		if _, ok := fields[key].(string); !ok {
+			t.Errorf("Expected %s to be a string, got %v", key, fields[key])
+		}
+	}
+	if fields["label"] != "commits" || fields["message"] != "60" || fields["color"] != "yellowgreen" {
+		t.Errorf("Unexpected badge: %v", fields)
+	}
+
+	// タグ・ラベルの指定と活動がない期間
+	tests := []struct {
+		query string
+		want  BadgeResponse
+	}{
+		{"?from=2025-06-01&to=2025-06-30&tags=work&label=work", BadgeResponse{1, "work", "30", "yellowgreen"}},
+		{"?from=2025-06-10&to=2025-06-12", BadgeResponse{1, "commits", "60", "brightgreen"}},
+		{"?from=2025-01-01&to=2025-01-31", BadgeResponse{1, "commits", "0", "lightgrey"}},
+	}
+	for _, tt := range tests {
+		w := get(tt.query)
+		var got BadgeResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+			t.Fatalf("Failed to decode response body: %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: expected %+v, got %+v", tt.query, tt.want, got)
+		}
+	}
+
+	if w := get("?from=2025-06-30&to=2025-06-01"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d for an inverted range, got %d", http.StatusBadRequest, w.Code)
+	}
+}
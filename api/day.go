@@ -0,0 +1,95 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/stsysd/sougen/model"
+)
+
+// DayRecordsParams represents parameters for listing records of a single day.
+type DayRecordsParams struct {
+	ProjectID model.HexID
+	Date      time.Time      // 対象の暦日（UTCの00:00:00）
+	Timezone  *time.Location // tzパラメータのタイムゾーン（nilの場合はプロジェクトのタイムゾーン）
+	Tags      *model.Tags
+}
+
+// Day はlocのタイムゾーンでのその日の00:00:00を返します。
+func (p *DayRecordsParams) Day(loc *time.Location) time.Time {
+	return time.Date(p.Date.Year(), p.Date.Month(), p.Date.Day(), 0, 0, 0, 0, loc)
+}
+
+// NewDayRecordsParams creates parameters for listing records of a single day from HTTP request.
+func NewDayRecordsParams(r *http.Request) (*DayRecordsParams, error) {
+	projectID, err := model.ParseHexID(r.PathValue("project_id"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid project_id: %w", err)
+	}
+
+	query := r.URL.Query()
+
+	var timezone *time.Location
+	if tz := query.Get("tz"); tz != "" {
+		timezone, err = model.NewTimezone(tz)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	date, err := time.Parse("2006-01-02", r.PathValue("date"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid date: use YYYY-MM-DD format")
+	}
+
+	return &DayRecordsParams{
+		ProjectID: projectID,
+		Date:      date,
+		Timezone:  timezone,
+		Tags:      model.NewTags(query.Get("tags")),
+	}, nil
+}
+
+// handleGetDayRecords は指定日（tz、未指定の場合はプロジェクトのタイムゾーンでの暦日）のレコードをすべて返すハンドラーです。
+// ヒートマップのセルからのドリルダウンを想定しています。
+func (s *Server) handleGetDayRecords(w http.ResponseWriter, r *http.Request) {
+	// パラメータを検証
+	params, err := NewDayRecordsParams(r)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// プロジェクトの存在確認（tzが指定されない場合はプロジェクトのタイムゾーンを使用）
+	project, err := s.store.GetProject(r.Context(), params.ProjectID)
+	if err != nil {
+		if errors.Is(err, model.ErrProjectNotFound) {
+			writeJSONError(w, fmt.Sprintf("Project with ID %s not found", params.ProjectID), http.StatusNotFound)
+		} else {
+			writeJSONError(w, fmt.Sprintf("Error retrieving project: %v", err), http.StatusInternalServerError)
+		}
+		return
+	}
+	loc := params.Timezone
+	if loc == nil {
+		loc = project.Location()
+	}
+
+	// その日のレコードを古い順にすべて取得
+	records, err := s.store.ListRecordsForDay(r.Context(), params.ProjectID, params.Day(loc), params.Tags.Values())
+	if err != nil {
+		log.Printf("Error retrieving records: %v", err)
+		writeJSONError(w, "Failed to retrieve records", http.StatusInternalServerError)
+		return
+	}
+	// 空配列を返すためにnilチェック
+	if records == nil {
+		records = []*model.Record{}
+	}
+
+	// レコード一覧をJSONで返す
+	writeJSON(w, r, http.StatusOK, records)
+}
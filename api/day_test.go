@@ -0,0 +1,137 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stsysd/sougen/model"
+	"github.com/stsysd/sougen/store"
+)
+
+func TestGetDayRecords(t *testing.T) {
+	// SQLiteStoreでは日時の文字列の比較で日を区切るため、オフセットの異なる日時で保存した場合も確認する
+	stores := []struct {
+		name  string
+		store func(t *testing.T) store.Store
+	}{
+		{"MockStore", func(t *testing.T) store.Store { return NewMockStore() }},
+		{"SQLiteStore", func(t *testing.T) store.Store { return newSQLiteTestStore(t) }},
+	}
+	for _, st := range stores {
+		t.Run(st.name, func(t *testing.T) {
+			testGetDayRecords(t, st.store(t))
+		})
+	}
+}
+
+func testGetDayRecords(t *testing.T, recordStore store.Store) {
+	server := NewServer(recordStore, newTestConfig())
+
+	project, _ := model.NewProject("day-project", "")
+	if err := recordStore.CreateProject(context.Background(), project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	// 2025-06-01T16:00Zは東京では2025-06-02 01:00
+	// 同じ日時をUTC以外のオフセットでも保存する
+	records := []struct {
+		timestamp time.Time
+		value     int
+		tags      []string
+	}{
+		{time.Date(2025, 6, 1, 3, 0, 0, 0, time.UTC).In(time.FixedZone("", -8*60*60)), 1, []string{"work"}},
+		{time.Date(2025, 6, 1, 16, 0, 0, 0, time.UTC).In(time.FixedZone("", 9*60*60)), 2, nil},
+		{time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC), 3, []string{"work"}},
+		{time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC).In(time.FixedZone("", 14*60*60)), 4, nil},
+	}
+	for _, rec := range records {
+		record, _ := model.NewRecord(rec.timestamp, project.ID, rec.value, rec.tags)
+		if err := recordStore.CreateRecord(context.Background(), record); err != nil {
+			t.Fatalf("Failed to create record: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		date     string
+		expected []int
+	}{
+		{"UTC day", "tz=UTC", "2025-06-01", []int{1, 2}},
+		{"Tokyo day", "tz=Asia/Tokyo", "2025-06-02", []int{2, 3, 4}},
+		{"Tag filter", "tz=UTC&tags=work", "2025-06-01", []int{1}},
+		{"Quiet day", "tz=UTC", "2025-06-10", []int{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			url := fmt.Sprintf("/api/v0/p/%s/day/%s?%s", project.ID, tc.date, tc.query)
+			req := httptest.NewRequest(http.MethodGet, url, nil)
+			req.Header.Set("X-API-Key", testAPIKey)
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+			}
+
+			var response []*model.Record
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response == nil {
+				t.Fatalf("Expected an array, got null")
+			}
+			values := make(map[int]bool)
+			for _, record := range response {
+				values[record.Value] = true
+			}
+			if len(response) != len(tc.expected) {
+				t.Fatalf("Expected %d records, got %d", len(tc.expected), len(response))
+			}
+			for _, v := range tc.expected {
+				if !values[v] {
+					t.Errorf("Expected record with value %d", v)
+				}
+			}
+		})
+	}
+}
+
+func TestGetDayRecordsErrors(t *testing.T) {
+	mockStore := NewMockStore()
+	server := NewServer(mockStore, newTestConfig())
+
+	project, _ := model.NewProject("day-project", "")
+	mockStore.CreateProject(context.Background(), project)
+
+	tests := []struct {
+		name           string
+		url            string
+		expectedStatus int
+	}{
+		{"Malformed date", fmt.Sprintf("/api/v0/p/%s/day/2025-6-1", project.ID), http.StatusBadRequest},
+		{"Invalid date", fmt.Sprintf("/api/v0/p/%s/day/2025-02-30", project.ID), http.StatusBadRequest},
+		{"Invalid tz", fmt.Sprintf("/api/v0/p/%s/day/2025-06-01?tz=Mars/Olympus", project.ID), http.StatusBadRequest},
+		{"Invalid project_id", "/api/v0/p/invalid/day/2025-06-01", http.StatusBadRequest},
+		{"Non-existent project", "/api/v0/p/00000000000000ff/day/2025-06-01", http.StatusNotFound},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.url, nil)
+			req.Header.Set("X-API-Key", testAPIKey)
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, w.Code)
+			}
+		})
+	}
+}
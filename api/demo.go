@@ -0,0 +1,47 @@
+package api
+
+import (
+	"hash/fnv"
+	"time"
+
+	"github.com/stsysd/sougen/heatmap"
+	"github.com/stsysd/sougen/model"
+)
+
+// demoSlotHours はdemoの週次ビューでサンプル値を割り当てるスロットの開始時刻です。
+var demoSlotHours = []int{0, 4, 8, 12, 16, 20}
+
+// demoGraphData はグラフのプレビュー用に、期間内のセルへ決定的なサンプル値を割り当てたデータを返します。
+// 値は日付（週次ビューではスロット）のハッシュから決まるため、同じ期間なら常に同じグラフになります。
+// ストアには一切アクセスしません。
+func demoGraphData(params *GetGraphParams, dateRange *model.DateRange) []heatmap.Data {
+	from := dateRange.From()
+	to := dateRange.To()
+
+	var data []heatmap.Data
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if !params.Weekdays.Contains(day.Weekday()) {
+			continue
+		}
+		if params.ViewType != "weekly" {
+			data = append(data, heatmap.Data{Date: day, Value: demoValue(day)})
+			continue
+		}
+		for _, hour := range demoSlotHours {
+			slot := time.Date(day.Year(), day.Month(), day.Day(), hour, 0, 0, 0, day.Location())
+			data = append(data, heatmap.Data{Date: slot, Value: demoValue(slot)})
+		}
+	}
+	return data
+}
+
+// demoValue はセルの時刻から0〜7のサンプル値を求めます。おおよそ3割のセルは0になります。
+func demoValue(t time.Time) float64 {
+	h := fnv.New32a()
+	h.Write([]byte(t.Format("2006-01-02T15")))
+	n := h.Sum32() % 10
+	if n < 3 {
+		return 0
+	}
+	return float64(n - 2)
+}
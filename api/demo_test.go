@@ -0,0 +1,79 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stsysd/sougen/model"
+	"github.com/stsysd/sougen/store"
+)
+
+// recordlessStore はレコードの読み書きをテストの失敗として報告するストアです。
+type recordlessStore struct {
+	store.Store
+	t *testing.T
+}
+
+func (s *recordlessStore) ListAllRecords(ctx context.Context, params *store.ListAllRecordsParams) iter.Seq2[*model.Record, error] {
+	s.t.Error("Expected demo graph not to read records")
+	return s.Store.ListAllRecords(ctx, params)
+}
+
+func (s *recordlessStore) GetProjectLastModified(ctx context.Context, projectID model.HexID) (time.Time, error) {
+	s.t.Error("Expected demo graph not to read records")
+	return s.Store.GetProjectLastModified(ctx, projectID)
+}
+
+func (s *recordlessStore) CreateRecord(ctx context.Context, record *model.Record) error {
+	s.t.Error("Expected demo graph not to write records")
+	return s.Store.CreateRecord(ctx, record)
+}
+
+func TestGetGraphDemo(t *testing.T) {
+	mockStore := NewMockStore()
+	project, _ := model.NewProject("empty-project", "")
+	mockStore.CreateProject(context.Background(), project)
+	server := NewServer(&recordlessStore{Store: mockStore, t: t}, newTestConfig())
+
+	getGraph := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/p/%s/graph%s", project.ID, query), nil)
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		return w
+	}
+
+	for _, view := range []string{"yearly", "weekly"} {
+		t.Run(view, func(t *testing.T) {
+			query := "?demo=true&from=2025-01-01&to=2025-03-31&view=" + view
+			w := getGraph(query)
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+			}
+			body := w.Body.String()
+			cells := strings.Count(body, "<rect ")
+			zeros := strings.Count(body, `data-value="0"`)
+			if zeros == cells {
+				t.Error("Expected the demo graph to have non-zero cells")
+			}
+			if zeros == 0 {
+				t.Error("Expected the demo graph to keep some empty cells")
+			}
+
+			// 同じ期間なら同じグラフ
+			if again := getGraph(query).Body.String(); again != body {
+				t.Error("Expected the demo graph to be deterministic")
+			}
+		})
+	}
+
+	// trackとは併用不可
+	if w := getGraph("?demo=true&track"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
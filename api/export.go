@@ -0,0 +1,267 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stsysd/sougen/model"
+	"github.com/stsysd/sougen/store"
+)
+
+// exportFlushEvery はエクスポート時にクライアントへフラッシュするレコード数の間隔です。
+const exportFlushEvery = 500
+
+// エクスポート対象期間の既定値（from/toが指定されない場合はすべてのレコード）
+var (
+	exportMinTime = time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC)
+	exportMaxTime = time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC)
+)
+
+// ExportRecordsParams represents parameters for exporting records.
+type ExportRecordsParams struct {
+	ProjectID model.HexID
+	From      time.Time
+	To        time.Time
+	Tags      *model.Tags
+	Format    string // "json" or "csv"
+	Order     model.RecordOrder
+	Download  bool // Content-Disposition: attachment if true, inline otherwise
+}
+
+// NewExportRecordsParams creates parameters for record export from HTTP request.
+func NewExportRecordsParams(r *http.Request) (*ExportRecordsParams, error) {
+	projectID, err := model.ParseHexID(r.PathValue("project_id"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid project_id: %w", err)
+	}
+
+	query := r.URL.Query()
+
+	format := query.Get("format")
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "csv" {
+		return nil, fmt.Errorf("invalid format parameter: must be 'json' or 'csv'")
+	}
+
+	order, err := model.NewRecordOrder(query.Get("order"))
+	if err != nil {
+		return nil, err
+	}
+
+	from, to, err := parseExportRange(query)
+	if err != nil {
+		return nil, err
+	}
+
+	// CSVは既定でダウンロード、JSONは既定でブラウザに表示
+	download, err := parseDownloadQuery(query, format == "csv")
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExportRecordsParams{
+		ProjectID: projectID,
+		From:      from,
+		To:        to,
+		Tags:      model.NewTags(query.Get("tags")),
+		Format:    format,
+		Order:     order,
+		Download:  download,
+	}, nil
+}
+
+// parseDownloadQuery はdownloadパラメータを解釈します。指定されていない場合はdefaultDownloadを返します。
+func parseDownloadQuery(query url.Values, defaultDownload bool) (bool, error) {
+	if !query.Has("download") {
+		return defaultDownload, nil
+	}
+	return parseBoolQuery(query, "download")
+}
+
+// setContentDisposition はエクスポートのContent-Dispositionヘッダーを設定します。
+// downloadがtrueの場合はattachment（ダウンロード）、falseの場合はinline（ブラウザに表示）で、
+// いずれも保存時のファイル名を付けます。
+func setContentDisposition(w http.ResponseWriter, download bool, filename string) {
+	disposition := "inline"
+	if download {
+		disposition = "attachment"
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`%s; filename="%s"`, disposition, filename))
+}
+
+// parseExportRange はエクスポート対象期間を返します。
+// from/toはそれぞれ指定された場合のみ期間を絞り込みます。
+func parseExportRange(query url.Values) (time.Time, time.Time, error) {
+	from, to := exportMinTime, exportMaxTime
+	if query.Get("from") != "" || query.Get("to") != "" {
+		dateRange, err := model.NewDateRange(query.Get("from"), query.Get("to"))
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		if query.Get("from") != "" {
+			from = dateRange.From()
+		}
+		if query.Get("to") != "" {
+			to = dateRange.To()
+		}
+	}
+	return from, to, nil
+}
+
+// flushWriter は一定件数ごとにクライアントへフラッシュするResponseWriterのラッパーです。
+// Content-Lengthを設定せずにフラッシュするため、レスポンスはchunked転送になります。
+type flushWriter struct {
+	w       io.Writer
+	rc      *http.ResponseController // Unwrapでミドルウェアのラッパーを辿ってフラッシュする
+	pending int
+}
+
+// newFlushWriter はflushWriterを作成します。
+func newFlushWriter(w http.ResponseWriter) *flushWriter {
+	return &flushWriter{w: w, rc: http.NewResponseController(w)}
+}
+
+// Write はレスポンスに書き込みます。
+func (f *flushWriter) Write(p []byte) (int, error) {
+	return f.w.Write(p)
+}
+
+// recordWritten はレコードの書き込みを記録し、間隔に達した場合はフラッシュします。
+// 直前にバッファされた出力（csv.Writerなど）はflushBufferで書き出してから呼び出します。
+func (f *flushWriter) recordWritten(flushBuffer func()) {
+	f.pending++
+	if f.pending < exportFlushEvery {
+		return
+	}
+	f.Flush(flushBuffer)
+}
+
+// Flush はバッファされた出力を書き出してクライアントへフラッシュします。
+func (f *flushWriter) Flush(flushBuffer func()) {
+	if flushBuffer != nil {
+		flushBuffer()
+	}
+	// フラッシュできないResponseWriterの場合は最後にまとめて送信される
+	if err := f.rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		log.Printf("Error flushing response: %v", err)
+	}
+	f.pending = 0
+}
+
+// exportCSVHeader はCSVエクスポートのヘッダー行です。
+var exportCSVHeader = []string{"id", "project_id", "timestamp", "value", "tags", "source"}
+
+// handleExportRecords はプロジェクトのレコードをJSONまたはCSVでストリーミング出力するハンドラーです。
+// レコードはストアから取得しながら逐次書き出し、一定件数ごとにフラッシュします。
+func (s *Server) handleExportRecords(w http.ResponseWriter, r *http.Request) {
+	// パラメータを検証
+	params, err := NewExportRecordsParams(r)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// プロジェクトの存在確認
+	if _, err := s.store.GetProject(r.Context(), params.ProjectID); err != nil {
+		if errors.Is(err, model.ErrProjectNotFound) {
+			writeJSONError(w, fmt.Sprintf("Project with ID %s not found", params.ProjectID), http.StatusNotFound)
+		} else {
+			writeJSONError(w, fmt.Sprintf("Error retrieving project: %v", err), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	records := s.store.ListAllRecords(r.Context(), &store.ListAllRecordsParams{
+		ProjectID: params.ProjectID,
+		From:      params.From,
+		To:        params.To,
+		Tags:      params.Tags.Values(),
+		Order:     params.Order,
+	})
+
+	// ヘッダー送信後のエラーはステータスコードで通知できないため、ログに記録して出力を打ち切る
+	setContentDisposition(w, params.Download, fmt.Sprintf("%s.%s", params.ProjectID, params.Format))
+	fw := newFlushWriter(w)
+	if params.Format == "csv" {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		err = writeRecordsCSV(fw, records)
+	} else {
+		w.Header().Set("Content-Type", "application/json")
+		err = writeRecordsJSON(fw, records)
+	}
+	if err != nil {
+		log.Printf("Error exporting records: %v", err)
+	}
+}
+
+// writeRecordsJSON はレコードをJSON配列として逐次書き出します。
+func writeRecordsJSON(fw *flushWriter, records iter.Seq2[*model.Record, error]) error {
+	if _, err := io.WriteString(fw, "["); err != nil {
+		return err
+	}
+	first := true
+	for record, err := range records {
+		if err != nil {
+			return err
+		}
+		if !first {
+			if _, err := io.WriteString(fw, ","); err != nil {
+				return err
+			}
+		}
+		first = false
+		data, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(data); err != nil {
+			return err
+		}
+		fw.recordWritten(nil)
+	}
+	if _, err := io.WriteString(fw, "]\n"); err != nil {
+		return err
+	}
+	fw.Flush(nil)
+	return nil
+}
+
+// writeRecordsCSV はレコードをヘッダー付きのCSVとして逐次書き出します。
+// タグはスペース区切りで1列にまとめます。
+func writeRecordsCSV(fw *flushWriter, records iter.Seq2[*model.Record, error]) error {
+	cw := csv.NewWriter(fw)
+	if err := cw.Write(exportCSVHeader); err != nil {
+		return err
+	}
+	for record, err := range records {
+		if err != nil {
+			return err
+		}
+		row := []string{
+			record.ID.String(),
+			record.ProjectID.String(),
+			record.Timestamp.Format(time.RFC3339),
+			strconv.FormatFloat(record.Amount(), 'f', -1, 64),
+			strings.Join(record.Tags, " "),
+			record.Source,
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+		fw.recordWritten(cw.Flush)
+	}
+	fw.Flush(cw.Flush)
+	return cw.Error()
+}
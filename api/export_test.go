@@ -0,0 +1,347 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stsysd/sougen/model"
+	"github.com/stsysd/sougen/store"
+)
+
+func TestExportRecords(t *testing.T) {
+	mockStore := NewMockStore()
+	project, _ := model.NewProject("export-project", "")
+	mockStore.CreateProject(context.Background(), project)
+	for i, tags := range [][]string{{"a", "b"}, nil, {"a"}} {
+		record, _ := model.NewRecord(time.Date(2025, 1, 10+i, 12, 0, 0, 0, time.UTC), project.ID, i+1, tags)
+		mockStore.CreateRecord(context.Background(), record)
+	}
+	server := NewServer(mockStore, newTestConfig())
+
+	doExport := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v0/p/%s/export%s", project.ID, query), nil)
+		req.Header.Set("X-API-Key", testAPIKey)
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("JSON", func(t *testing.T) {
+		w := doExport("")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d", http.StatusOK, w.Code)
+		}
+		if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Expected Content-Type application/json, got %s", ct)
+		}
+		var records []*model.Record
+		if err := json.Unmarshal(w.Body.Bytes(), &records); err != nil {
+			t.Fatalf("Failed to decode response body: %v", err)
+		}
+		if len(records) != 3 {
+			t.Fatalf("Expected 3 records, got %d", len(records))
+		}
+	})
+
+	t.Run("CSV with filters", func(t *testing.T) {
+		w := doExport("?format=csv&tags=a&from=2025-01-11")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d", http.StatusOK, w.Code)
+		}
+		rows, err := csv.NewReader(w.Body).ReadAll()
+		if err != nil {
+			t.Fatalf("Failed to parse CSV: %v", err)
+		}
+		if len(rows) != 2 {
+			t.Fatalf("Expected header and 1 row, got %d rows", len(rows))
+		}
+		if !slices.Equal(rows[0], exportCSVHeader) {
+			t.Errorf("Expected header %v, got %v", exportCSVHeader, rows[0])
+		}
+		if rows[1][2] != "2025-01-12T12:00:00Z" || rows[1][3] != "3" || rows[1][4] != "a" {
+			t.Errorf("Unexpected row: %v", rows[1])
+		}
+	})
+
+	t.Run("Empty JSON export", func(t *testing.T) {
+		w := doExport("?from=2030-01-01&to=2030-01-31")
+		if body := strings.TrimSpace(w.Body.String()); body != "[]" {
+			t.Errorf("Expected empty array, got %s", body)
+		}
+	})
+
+	t.Run("Ascending order", func(t *testing.T) {
+		w := doExport("?format=csv&order=asc")
+		rows, err := csv.NewReader(w.Body).ReadAll()
+		if err != nil {
+			t.Fatalf("Failed to parse CSV: %v", err)
+		}
+		if len(rows) != 4 {
+			t.Fatalf("Expected header and 3 rows, got %d rows", len(rows))
+		}
+		for i, expected := range []string{"2025-01-10T12:00:00Z", "2025-01-11T12:00:00Z", "2025-01-12T12:00:00Z"} {
+			if rows[i+1][2] != expected {
+				t.Errorf("Expected row %d timestamp %s, got %s", i+1, expected, rows[i+1][2])
+			}
+		}
+		if w := doExport("?order=random"); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status code %d for invalid order, got %d", http.StatusBadRequest, w.Code)
+		}
+	})
+
+	t.Run("Invalid format", func(t *testing.T) {
+		if w := doExport("?format=xml"); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, w.Code)
+		}
+	})
+
+	t.Run("Non-existent project", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v0/p/00000000000003e7/export", nil)
+		req.Header.Set("X-API-Key", testAPIKey)
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status code %d, got %d", http.StatusNotFound, w.Code)
+		}
+	})
+}
+
+// generatingStore は指定件数のレコードを逐次生成するストアです。
+// 最初のフラッシュ分を返した後、クライアントが受信を確認するまで生成を止めます。
+type generatingStore struct {
+	store.Store
+	count     int
+	firstRead chan struct{}
+}
+
+func (g *generatingStore) ListAllRecords(ctx context.Context, params *store.ListAllRecordsParams) iter.Seq2[*model.Record, error] {
+	return func(yield func(*model.Record, error) bool) {
+		base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		for i := range g.count {
+			if i == exportFlushEvery {
+				select {
+				case <-g.firstRead:
+				case <-time.After(5 * time.Second):
+					yield(nil, fmt.Errorf("client did not receive the first batch"))
+					return
+				}
+			}
+			record := &model.Record{
+				ID:        model.NewHexID(int64(i + 1)),
+				ProjectID: params.ProjectID,
+				Value:     1,
+				Timestamp: base.Add(time.Duration(i) * time.Minute),
+				Tags:      []string{"stream"},
+				Source:    model.RecordSourceAPI,
+			}
+			if !yield(record, nil) {
+				return
+			}
+		}
+	}
+}
+
+// unflushedCountingWriter はフラッシュされずにサーバー側に溜まった本文の最大バイト数を記録するラッパーです。
+// 全件をバッファしてから書き出す実装では、この値が出力全体の大きさになります。
+type unflushedCountingWriter struct {
+	http.ResponseWriter
+	mu           sync.Mutex
+	written      int64 // 書き込まれた本文の合計
+	unflushed    int64 // 最後のフラッシュ以降に書き込まれた本文
+	maxUnflushed int64
+}
+
+func (c *unflushedCountingWriter) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	c.written += int64(len(p))
+	c.unflushed += int64(len(p))
+	c.maxUnflushed = max(c.maxUnflushed, c.unflushed)
+	c.mu.Unlock()
+	return c.ResponseWriter.Write(p)
+}
+
+func (c *unflushedCountingWriter) Flush() {
+	c.mu.Lock()
+	c.unflushed = 0
+	c.mu.Unlock()
+	http.NewResponseController(c.ResponseWriter).Flush()
+}
+
+// Unwrap はhttp.ResponseControllerのために元のResponseWriterを返します。
+func (c *unflushedCountingWriter) Unwrap() http.ResponseWriter {
+	return c.ResponseWriter
+}
+
+func TestExportContentDisposition(t *testing.T) {
+	mockStore := NewMockStore()
+	project, _ := model.NewProject("export-project", "")
+	mockStore.CreateProject(context.Background(), project)
+	server := NewServer(mockStore, newTestConfig())
+
+	tests := []struct {
+		path     string
+		query    string
+		expected string
+	}{
+		{"export", "", fmt.Sprintf(`inline; filename="%s.json"`, project.ID)},
+		{"export", "?download=true", fmt.Sprintf(`attachment; filename="%s.json"`, project.ID)},
+		{"export", "?format=csv", fmt.Sprintf(`attachment; filename="%s.csv"`, project.ID)},
+		{"export", "?format=csv&download=false", fmt.Sprintf(`inline; filename="%s.csv"`, project.ID)},
+		{"records.ics", "", fmt.Sprintf(`attachment; filename="%s.ics"`, project.ID)},
+		{"records.ics", "?download=false", fmt.Sprintf(`inline; filename="%s.ics"`, project.ID)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path+tt.query, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v0/p/%s/%s%s", project.ID, tt.path, tt.query), nil)
+			req.Header.Set("X-API-Key", testAPIKey)
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status code %d, got %d", http.StatusOK, w.Code)
+			}
+			if got := w.Header().Get("Content-Disposition"); got != tt.expected {
+				t.Errorf("Expected Content-Disposition %q, got %q", tt.expected, got)
+			}
+		})
+	}
+
+	// 不正なdownloadパラメータ
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v0/p/%s/export?download=maybe", project.ID), nil)
+	req.Header.Set("X-API-Key", testAPIKey)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestExportRecordsStreaming(t *testing.T) {
+	const total = 100000
+
+	mockStore := NewMockStore()
+	project, _ := model.NewProject("stream-project", "")
+	mockStore.CreateProject(context.Background(), project)
+	generating := &generatingStore{Store: mockStore, count: total, firstRead: make(chan struct{})}
+	server := NewServer(generating, newTestConfig())
+	counting := &unflushedCountingWriter{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		counting.ResponseWriter = w
+		server.ServeHTTP(counting, r)
+	}))
+	defer ts.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v0/p/%s/export?format=csv", ts.URL, project.ID), nil)
+	req.Header.Set("X-API-Key", testAPIKey)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+	if !slices.Contains(resp.TransferEncoding, "chunked") {
+		t.Errorf("Expected chunked transfer encoding, got %v", resp.TransferEncoding)
+	}
+
+	// 全件をバッファする実装では最初のバッチが届かず、generatingStoreの生成が止まったまま失敗する
+	scanner := bufio.NewScanner(resp.Body)
+	rows := 0
+	for scanner.Scan() {
+		rows++
+		// ヘッダーと最初のバッチを受信できた時点でサーバーの生成を再開させる
+		if rows == exportFlushEvery+1 {
+			close(generating.firstRead)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("Failed to read stream: %v", err)
+	}
+
+	if rows != total+1 {
+		t.Fatalf("Expected %d rows including header, got %d", total+1, rows)
+	}
+
+	// フラッシュまでにサーバー側に溜まる本文は件数によらず一定（1回のフラッシュ分）に収まる
+	// 全件をバッファした場合はCSVだけで数MBになるため、余裕を持った上限で確認する
+	counting.mu.Lock()
+	defer counting.mu.Unlock()
+	const limit = 1 << 20
+	if counting.written < 4*limit {
+		t.Fatalf("Expected the export to be larger than %d bytes, got %d", 4*limit, counting.written)
+	}
+	if counting.maxUnflushed > limit {
+		t.Errorf("Expected at most %d unflushed bytes during export, got %d of %d", limit, counting.maxUnflushed, counting.written)
+	}
+}
+
+func TestExportRecordsStreamingCamelCase(t *testing.T) {
+	const total = 2000
+
+	mockStore := NewMockStore()
+	project, _ := model.NewProject("stream-project", "")
+	mockStore.CreateProject(context.Background(), project)
+	generating := &generatingStore{Store: mockStore, count: total, firstRead: make(chan struct{})}
+	ts := httptest.NewServer(NewServer(generating, newTestConfig()))
+	defer ts.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v0/p/%s/export?format=json&naming=camel", ts.URL, project.ID), nil)
+	req.Header.Set("X-API-Key", testAPIKey)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+	if !slices.Contains(resp.TransferEncoding, "chunked") {
+		t.Errorf("Expected chunked transfer encoding, got %v", resp.TransferEncoding)
+	}
+
+	// camelCaseへの変換で本文をバッファする実装では最初のバッチが届かず、生成が止まったまま失敗する
+	var body []byte
+	buf := make([]byte, 4096)
+	released := false
+	for {
+		n, err := resp.Body.Read(buf)
+		body = append(body, buf[:n]...)
+		if !released && bytes.Count(body, []byte(`"projectId":`)) >= exportFlushEvery {
+			close(generating.firstRead)
+			released = true
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Failed to read stream: %v", err)
+		}
+	}
+
+	var records []map[string]any
+	if err := json.Unmarshal(body, &records); err != nil {
+		t.Fatalf("Failed to decode export: %v", err)
+	}
+	if len(records) != total {
+		t.Fatalf("Expected %d records, got %d", total, len(records))
+	}
+	if _, ok := records[0]["projectId"]; !ok || bytes.Contains(body, []byte(`"project_id"`)) {
+		t.Errorf("Expected camelCase keys, got %v", records[0])
+	}
+}
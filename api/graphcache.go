@@ -0,0 +1,125 @@
+package api
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/stsysd/sougen/model"
+)
+
+// graphCacheEntry はキャッシュされた描画済みグラフです。
+type graphCacheEntry struct {
+	key          string
+	projectID    model.HexID
+	body         string     // 描画済みのグラフ（SVG・JSON・PNG）
+	lastModified *time.Time // 最新レコードの日時（レコードがない場合はnil）
+	expiresAt    time.Time
+}
+
+// graphCache は描画済みグラフを保持するTTL付きのLRUキャッシュです。
+// 複数のリクエストから同時に利用できます。nilの場合はキャッシュを無効として扱います。
+type graphCache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	entries map[string]*list.Element
+	order   *list.List // 先頭が最も最近使われたエントリ
+	now     func() time.Time
+}
+
+// newGraphCache はグラフキャッシュを作成します。sizeかttlが0以下の場合はnilを返します。
+func newGraphCache(size int, ttl time.Duration) *graphCache {
+	if size <= 0 || ttl <= 0 {
+		return nil
+	}
+	return &graphCache{
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+		now:     time.Now,
+	}
+}
+
+// get はキーに対応する有効期限内のエントリを返します。
+func (c *graphCache) get(key string) (*graphCacheEntry, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*graphCacheEntry)
+	if !c.now().Before(entry.expiresAt) {
+		c.removeElement(elem)
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return entry, true
+}
+
+// put はエントリを追加し、容量を超えた場合は最も古く使われたエントリを削除します。
+func (c *graphCache) put(key string, projectID model.HexID, body string, lastModified *time.Time) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &graphCacheEntry{
+		key:          key,
+		projectID:    projectID,
+		body:         body,
+		lastModified: lastModified,
+		expiresAt:    c.now().Add(c.ttl),
+	}
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.size {
+		c.removeElement(c.order.Back())
+	}
+}
+
+// invalidateProject は指定プロジェクトのエントリをすべて削除します。
+func (c *graphCache) invalidateProject(projectID model.HexID) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for elem := c.order.Front(); elem != nil; {
+		next := elem.Next()
+		if elem.Value.(*graphCacheEntry).projectID.Equals(projectID) {
+			c.removeElement(elem)
+		}
+		elem = next
+	}
+}
+
+// invalidateAll はすべてのエントリを削除します。
+func (c *graphCache) invalidateAll() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+// removeElement はエントリを削除します。呼び出し側でロックを保持している必要があります。
+func (c *graphCache) removeElement(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*graphCacheEntry).key)
+}
@@ -0,0 +1,207 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stsysd/sougen/model"
+	"github.com/stsysd/sougen/store"
+)
+
+// countingStore はグラフ生成で使われるストア呼び出しの回数を数えるラッパーです。
+type countingStore struct {
+	store.Store
+	calls atomic.Int64
+}
+
+func (c *countingStore) GetProject(ctx context.Context, id model.HexID) (*model.Project, error) {
+	c.calls.Add(1)
+	return c.Store.GetProject(ctx, id)
+}
+
+func (c *countingStore) GetProjectLastModified(ctx context.Context, id model.HexID) (time.Time, error) {
+	c.calls.Add(1)
+	return c.Store.GetProjectLastModified(ctx, id)
+}
+
+func (c *countingStore) ListAllRecords(ctx context.Context, params *store.ListAllRecordsParams) iter.Seq2[*model.Record, error] {
+	c.calls.Add(1)
+	return c.Store.ListAllRecords(ctx, params)
+}
+
+func newCachingTestServer(t *testing.T) (*Server, *countingStore, *model.Project) {
+	t.Helper()
+	mockStore := NewMockStore()
+	project, _ := model.NewProject("cache-project", "")
+	mockStore.CreateProject(context.Background(), project)
+	record, _ := model.NewRecord(time.Date(2025, 1, 11, 12, 0, 0, 0, time.Local), project.ID, 3, nil)
+	mockStore.CreateRecord(context.Background(), record)
+
+	counting := &countingStore{Store: mockStore}
+	cfg := newTestConfig()
+	cfg.GraphCacheSize = 10
+	cfg.GraphCacheTTL = time.Minute
+	return NewServer(counting, cfg), counting, project
+}
+
+func getGraph(server *Server, url string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	return w
+}
+
+func TestGraphCacheServesRepeatedRequests(t *testing.T) {
+	server, counting, project := newCachingTestServer(t)
+	url := fmt.Sprintf("/p/%s/graph?from=2025-01-05&to=2025-01-18", project.ID)
+
+	first := getGraph(server, url)
+	if first.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, first.Code)
+	}
+	calls := counting.calls.Load()
+	if calls == 0 {
+		t.Fatalf("Expected the first request to hit the store")
+	}
+
+	// 同じリクエストはストアにアクセスしない
+	second := getGraph(server, url)
+	if second.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, second.Code)
+	}
+	if got := counting.calls.Load(); got != calls {
+		t.Errorf("Expected no store calls for a cached graph, got %d more", got-calls)
+	}
+	if second.Body.String() != first.Body.String() {
+		t.Errorf("Expected cached graph to match the first response")
+	}
+	if second.Header().Get("Content-Type") != "image/svg+xml" {
+		t.Errorf("Expected SVG content type, got %q", second.Header().Get("Content-Type"))
+	}
+
+	// パラメータが異なればキャッシュされない
+	getGraph(server, url+"&theme=cividis")
+	if got := counting.calls.Load(); got == calls {
+		t.Errorf("Expected a different theme to bypass the cached graph")
+	}
+}
+
+func TestGraphCacheInvalidatedByTrack(t *testing.T) {
+	server, counting, project := newCachingTestServer(t)
+	url := fmt.Sprintf("/p/%s/graph?from=2025-01-05&to=2025-01-18", project.ID)
+
+	getGraph(server, url)
+
+	// trackはキャッシュを使わず、プロジェクトのキャッシュを破棄する
+	calls := counting.calls.Load()
+	getGraph(server, url+"&track")
+	if got := counting.calls.Load(); got == calls {
+		t.Fatalf("Expected track request to bypass the cache")
+	}
+
+	calls = counting.calls.Load()
+	getGraph(server, url)
+	if got := counting.calls.Load(); got == calls {
+		t.Errorf("Expected graph to be rebuilt after track")
+	}
+}
+
+func TestGraphCacheInvalidatedByTagAlias(t *testing.T) {
+	server, counting, project := newCachingTestServer(t)
+	url := fmt.Sprintf("/p/%s/graph?from=2025-01-05&to=2025-01-18&tags=js", project.ID)
+	aliasURL := fmt.Sprintf("/api/v0/p/%s/t/aliases/js", project.ID)
+
+	for _, change := range []struct {
+		method string
+		body   string
+		status int
+	}{
+		{http.MethodPut, `{"tag":"javascript"}`, http.StatusOK},
+		{http.MethodDelete, "", http.StatusNoContent},
+	} {
+		getGraph(server, url)
+
+		// 別名の変更でタグによる絞り込みが変わるため、キャッシュを破棄する
+		req := httptest.NewRequest(change.method, aliasURL, strings.NewReader(change.body))
+		req.Header.Set("X-API-Key", testAPIKey)
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		if w.Code != change.status {
+			t.Fatalf("Expected status code %d for %s, got %d: %s", change.status, change.method, w.Code, w.Body.String())
+		}
+
+		calls := counting.calls.Load()
+		getGraph(server, url)
+		if got := counting.calls.Load(); got == calls {
+			t.Errorf("Expected graph to be rebuilt after %s of a tag alias", change.method)
+		}
+	}
+}
+
+func TestGraphCacheNotModified(t *testing.T) {
+	server, _, project := newCachingTestServer(t)
+	url := fmt.Sprintf("/p/%s/graph?from=2025-01-05&to=2025-01-18", project.ID)
+
+	first := getGraph(server, url)
+	lastModified := first.Header().Get("Last-Modified")
+	if lastModified == "" {
+		t.Fatalf("Expected Last-Modified header")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	req.Header.Set("If-Modified-Since", lastModified)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if w.Code != http.StatusNotModified {
+		t.Errorf("Expected status code %d for cached graph, got %d", http.StatusNotModified, w.Code)
+	}
+}
+
+func TestGraphCacheEvictionAndTTL(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := newGraphCache(2, time.Minute)
+	cache.now = func() time.Time { return now }
+
+	project1 := model.NewHexID(1)
+	project2 := model.NewHexID(2)
+	cache.put("a", project1, "A", nil)
+	cache.put("b", project1, "B", nil)
+
+	// "a"を使用してから"c"を追加すると、最も古く使われた"b"が削除される
+	if _, ok := cache.get("a"); !ok {
+		t.Fatalf("Expected entry a")
+	}
+	cache.put("c", project2, "C", nil)
+	if _, ok := cache.get("b"); ok {
+		t.Errorf("Expected least recently used entry b to be evicted")
+	}
+
+	// プロジェクト単位の破棄
+	cache.invalidateProject(project1)
+	if _, ok := cache.get("a"); ok {
+		t.Errorf("Expected project1 entries to be invalidated")
+	}
+	if _, ok := cache.get("c"); !ok {
+		t.Errorf("Expected project2 entry to remain")
+	}
+
+	// TTL経過後は期限切れ
+	now = now.Add(time.Minute)
+	if _, ok := cache.get("c"); ok {
+		t.Errorf("Expected entry to expire after TTL")
+	}
+
+	// サイズ0は無効（nil）として扱う
+	disabled := newGraphCache(0, time.Minute)
+	disabled.put("a", project1, "A", nil)
+	if _, ok := disabled.get("a"); ok {
+		t.Errorf("Expected disabled cache to never hit")
+	}
+}
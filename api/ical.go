@@ -0,0 +1,220 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stsysd/sougen/model"
+	"github.com/stsysd/sougen/store"
+)
+
+// icalLineLimit はiCalendarの1行の最大オクテット数です（RFC 5545 3.1）。
+const icalLineLimit = 75
+
+// icalTimeFormat はUTCの日時（DATE-TIME）の書式です。
+const icalTimeFormat = "20060102T150405Z"
+
+// ICalendarParams represents parameters for the iCalendar feed of records.
+type ICalendarParams struct {
+	ProjectID model.HexID
+	From      time.Time
+	To        time.Time
+	Tags      *model.Tags
+	Aggregate string // ""（レコードごと）または"day"（日ごとの合計）
+	Download  bool   // Content-Dispositionをattachmentにするか（falseの場合はinline）
+}
+
+// NewICalendarParams creates parameters for the iCalendar feed from HTTP request.
+func NewICalendarParams(r *http.Request) (*ICalendarParams, error) {
+	projectID, err := model.ParseHexID(r.PathValue("project_id"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid project_id: %w", err)
+	}
+
+	query := r.URL.Query()
+
+	aggregate := query.Get("aggregate")
+	if aggregate != "" && aggregate != "day" {
+		return nil, fmt.Errorf("invalid aggregate parameter: must be 'day'")
+	}
+
+	from, to, err := parseExportRange(query)
+	if err != nil {
+		return nil, err
+	}
+
+	// 既定でダウンロード
+	download, err := parseDownloadQuery(query, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ICalendarParams{
+		ProjectID: projectID,
+		From:      from,
+		To:        to,
+		Tags:      model.NewTags(query.Get("tags")),
+		Aggregate: aggregate,
+		Download:  download,
+	}, nil
+}
+
+// handleRecordsICalendar はプロジェクトのレコードをiCalendar（VCALENDAR）として出力するハンドラーです。
+// レコードごと、またはaggregate=dayの場合は日ごとの合計を1つのVEVENTにします。
+func (s *Server) handleRecordsICalendar(w http.ResponseWriter, r *http.Request) {
+	// パラメータを検証
+	params, err := NewICalendarParams(r)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// プロジェクトの存在確認（カレンダー名と各イベントの概要に使用）
+	project, err := s.store.GetProject(r.Context(), params.ProjectID)
+	if err != nil {
+		if errors.Is(err, model.ErrProjectNotFound) {
+			writeJSONError(w, fmt.Sprintf("Project with ID %s not found", params.ProjectID), http.StatusNotFound)
+		} else {
+			writeJSONError(w, fmt.Sprintf("Error retrieving project: %v", err), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	records := s.store.ListAllRecords(r.Context(), &store.ListAllRecordsParams{
+		ProjectID: params.ProjectID,
+		From:      params.From,
+		To:        params.To,
+		Tags:      params.Tags.Values(),
+		Order:     model.RecordOrderAsc,
+	})
+
+	// 日ごとの合計は集計が終わるまで出力できないため、ヘッダー送信前に集計してエラーを返せるようにする
+	var dailyTotals []*store.DailyTotal
+	if params.Aggregate == "day" {
+		dailyTotals, err = store.AggregateDailyTotals(records, project.Location(), model.AggregationSum, project.RecordValueType())
+		if err != nil {
+			log.Printf("Error retrieving records: %v", err)
+			writeJSONError(w, "Failed to retrieve records", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	// ヘッダー送信後のエラーはステータスコードで通知できないため、ログに記録して出力を打ち切る
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	setContentDisposition(w, params.Download, fmt.Sprintf("%s.ics", params.ProjectID))
+	fw := newFlushWriter(w)
+	ical := &icalWriter{w: fw}
+	ical.calendarStart(project)
+	dtstamp := time.Now().UTC().Format(icalTimeFormat)
+	if params.Aggregate == "day" {
+		for _, total := range dailyTotals {
+			ical.dayEvent(project, total, dtstamp)
+		}
+	} else {
+		for record, err := range records {
+			if err != nil {
+				log.Printf("Error exporting records as iCalendar: %v", err)
+				return
+			}
+			ical.recordEvent(project, record, dtstamp)
+			fw.recordWritten(nil)
+		}
+	}
+	ical.line("END:VCALENDAR")
+	if ical.err != nil {
+		log.Printf("Error exporting records as iCalendar: %v", ical.err)
+		return
+	}
+	fw.Flush(nil)
+}
+
+// icalWriter はiCalendarのコンテンツ行を書き出します。
+// 最初に発生した書き込みエラーを保持し、以降の書き込みは行いません。
+type icalWriter struct {
+	w   io.Writer
+	err error
+}
+
+// calendarStart はVCALENDARの開始部分を書き出します。
+func (c *icalWriter) calendarStart(project *model.Project) {
+	c.line("BEGIN:VCALENDAR")
+	c.line("VERSION:2.0")
+	c.line("PRODID:-//sougen//records//EN")
+	c.line("CALSCALE:GREGORIAN")
+	c.line("X-WR-CALNAME:" + icalEscape(project.Name))
+}
+
+// recordEvent はレコードを記録時刻のVEVENTとして書き出します。
+func (c *icalWriter) recordEvent(project *model.Project, record *model.Record, dtstamp string) {
+	summary := project.Name + ": " + strconv.FormatFloat(record.Amount(), 'f', -1, 64)
+	if len(record.Tags) > 0 {
+		summary += " [" + strings.Join(record.Tags, ", ") + "]"
+	}
+	c.line("BEGIN:VEVENT")
+	c.line(fmt.Sprintf("UID:record-%s@sougen", record.ID))
+	c.line("DTSTAMP:" + dtstamp)
+	c.line("DTSTART:" + record.Timestamp.UTC().Format(icalTimeFormat))
+	c.line("SUMMARY:" + icalEscape(summary))
+	if len(record.Tags) > 0 {
+		tags := make([]string, len(record.Tags))
+		for i, tag := range record.Tags {
+			tags[i] = icalEscape(tag)
+		}
+		c.line("CATEGORIES:" + strings.Join(tags, ","))
+	}
+	c.line("END:VEVENT")
+}
+
+// dayEvent は日ごとの合計を終日のVEVENTとして書き出します。
+func (c *icalWriter) dayEvent(project *model.Project, total *store.DailyTotal, dtstamp string) {
+	summary := project.Name + ": " + strconv.FormatFloat(total.Value, 'f', -1, 64)
+	c.line("BEGIN:VEVENT")
+	c.line(fmt.Sprintf("UID:day-%s-%s@sougen", project.ID, total.Date.Format("20060102")))
+	c.line("DTSTAMP:" + dtstamp)
+	c.line("DTSTART;VALUE=DATE:" + total.Date.Format("20060102"))
+	c.line("DTEND;VALUE=DATE:" + total.Date.AddDate(0, 0, 1).Format("20060102"))
+	c.line("SUMMARY:" + icalEscape(summary))
+	c.line("END:VEVENT")
+}
+
+// line は1つのコンテンツ行をCRLFで終端して書き出します。
+// 75オクテットを超える行は、UTF-8の文字の途中で分割しないように折り返します。
+func (c *icalWriter) line(content string) {
+	if c.err != nil {
+		return
+	}
+	var sb strings.Builder
+	limit := icalLineLimit
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !isUTF8Start(content[cut]) {
+			cut--
+		}
+		sb.WriteString(content[:cut])
+		sb.WriteString("\r\n ")
+		content = content[cut:]
+		limit = icalLineLimit - 1 // 継続行は先頭の空白を含む
+	}
+	sb.WriteString(content)
+	sb.WriteString("\r\n")
+	_, c.err = io.WriteString(c.w, sb.String())
+}
+
+// isUTF8Start はbがUTF-8の文字の先頭バイトかどうかを判定します。
+func isUTF8Start(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// icalEscapeReplacer はTEXT値でエスケープが必要な文字を置き換えます（RFC 5545 3.3.11）。
+var icalEscapeReplacer = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// icalEscape はTEXT値をエスケープします。
+func icalEscape(text string) string {
+	return icalEscapeReplacer.Replace(text)
+}
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stsysd/sougen/model"
+)
+
+func TestRecordsICalendar(t *testing.T) {
+	mockStore := NewMockStore()
+	project, _ := model.NewProject("reading", "")
+	mockStore.CreateProject(context.Background(), project)
+	for _, e := range []struct {
+		timestamp time.Time
+		value     int
+		tags      []string
+	}{
+		{time.Date(2025, 6, 2, 9, 0, 0, 0, time.Local), 3, []string{"book", "a;b"}},
+		{time.Date(2025, 6, 2, 21, 0, 0, 0, time.Local), 2, nil},
+		{time.Date(2025, 6, 3, 9, 0, 0, 0, time.Local), 5, nil},
+	} {
+		record, _ := model.NewRecord(e.timestamp, project.ID, e.value, e.tags)
+		mockStore.CreateRecord(context.Background(), record)
+	}
+	server := NewServer(mockStore, newTestConfig())
+
+	getICalendar := func(projectID model.HexID, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v0/p/%s/records.ics%s", projectID, query), nil)
+		req.Header.Set("X-API-Key", testAPIKey)
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		return w
+	}
+
+	// 構造を検証し、VEVENTの一覧を返す
+	parseEvents := func(t *testing.T, body string) [][]string {
+		t.Helper()
+		if !strings.HasSuffix(body, "\r\n") {
+			t.Fatal("Expected CRLF line endings")
+		}
+		lines := strings.Split(strings.TrimSuffix(body, "\r\n"), "\r\n")
+		if lines[0] != "BEGIN:VCALENDAR" || lines[len(lines)-1] != "END:VCALENDAR" {
+			t.Fatalf("Expected a VCALENDAR, got:\n%s", body)
+		}
+		if !strings.Contains(body, "\r\nVERSION:2.0\r\n") || !strings.Contains(body, "\r\nPRODID:") {
+			t.Errorf("Expected VERSION and PRODID, got:\n%s", body)
+		}
+		var events [][]string
+		var current []string
+		for _, line := range lines[1 : len(lines)-1] {
+			switch {
+			case line == "BEGIN:VEVENT":
+				if current != nil {
+					t.Fatal("Nested VEVENT")
+				}
+				current = []string{}
+			case line == "END:VEVENT":
+				if current == nil {
+					t.Fatal("END:VEVENT without BEGIN:VEVENT")
+				}
+				events = append(events, current)
+				current = nil
+			case current != nil:
+				current = append(current, line)
+			}
+		}
+		if current != nil {
+			t.Fatal("Unterminated VEVENT")
+		}
+		return events
+	}
+
+	t.Run("Per record", func(t *testing.T) {
+		w := getICalendar(project.ID, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		if ct := w.Header().Get("Content-Type"); ct != "text/calendar; charset=utf-8" {
+			t.Errorf("Expected Content-Type text/calendar, got %s", ct)
+		}
+		events := parseEvents(t, w.Body.String())
+		if len(events) != 3 {
+			t.Fatalf("Expected 3 events, got %d", len(events))
+		}
+		// 古い順で、記録時刻とエスケープされた概要を含む
+		first := strings.Join(events[0], "\n")
+		start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.Local).UTC().Format(icalTimeFormat)
+		if !strings.Contains(first, "DTSTART:"+start) {
+			t.Errorf("Expected DTSTART %s, got:\n%s", start, first)
+		}
+		if !strings.Contains(first, `SUMMARY:reading: 3 [book\, a\;b]`) {
+			t.Errorf("Expected summary with value and tags, got:\n%s", first)
+		}
+		if !strings.Contains(first, `CATEGORIES:book,a\;b`) {
+			t.Errorf("Expected categories, got:\n%s", first)
+		}
+		for _, event := range events {
+			if !strings.HasPrefix(event[0], "UID:record-") || !strings.HasPrefix(event[1], "DTSTAMP:") {
+				t.Errorf("Expected UID and DTSTAMP, got %v", event)
+			}
+		}
+	})
+
+	t.Run("Aggregate by day", func(t *testing.T) {
+		w := getICalendar(project.ID, "?aggregate=day")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		events := parseEvents(t, w.Body.String())
+		if len(events) != 2 {
+			t.Fatalf("Expected 2 events, got %d", len(events))
+		}
+		first := strings.Join(events[0], "\n")
+		for _, expected := range []string{"DTSTART;VALUE=DATE:20250602", "DTEND;VALUE=DATE:20250603", "SUMMARY:reading: 5"} {
+			if !strings.Contains(first, expected) {
+				t.Errorf("Expected %q, got:\n%s", expected, first)
+			}
+		}
+	})
+
+	t.Run("Invalid aggregate", func(t *testing.T) {
+		if w := getICalendar(project.ID, "?aggregate=week"); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, w.Code)
+		}
+	})
+
+	t.Run("Non-existent project", func(t *testing.T) {
+		if w := getICalendar(model.NewHexID(999), ""); w.Code != http.StatusNotFound {
+			t.Errorf("Expected status code %d, got %d", http.StatusNotFound, w.Code)
+		}
+	})
+}
+
+func TestICalWriterFoldsLongLines(t *testing.T) {
+	var sb strings.Builder
+	ical := &icalWriter{w: &sb}
+	ical.line("SUMMARY:" + strings.Repeat("あ", 60))
+
+	for _, line := range strings.Split(strings.TrimSuffix(sb.String(), "\r\n"), "\r\n") {
+		if len(line) > icalLineLimit {
+			t.Errorf("Expected lines of at most %d octets, got %d", icalLineLimit, len(line))
+		}
+	}
+	unfolded := strings.ReplaceAll(sb.String(), "\r\n ", "")
+	if unfolded != "SUMMARY:"+strings.Repeat("あ", 60)+"\r\n" {
+		t.Errorf("Unexpected unfolded line: %q", unfolded)
+	}
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/stsysd/sougen/model"
+)
+
+// recordSourceGitHub はGitHubのpushイベントから作成されたレコードの作成元です。
+const recordSourceGitHub = "github"
+
+// githubPushEvent はGitHubのpushイベントのペイロードのうち、レコード作成に使う部分です。
+type githubPushEvent struct {
+	Repository *struct {
+		Name string `json:"name"`
+	} `json:"repository"`
+	Commits    []json.RawMessage `json:"commits"`
+	HeadCommit *struct {
+		Timestamp string `json:"timestamp"`
+	} `json:"head_commit"`
+}
+
+// GitHubPushParams represents parameters for ingesting a GitHub push event.
+type GitHubPushParams struct {
+	ProjectID model.HexID
+	Timestamp time.Time
+	Commits   int
+	Repo      string
+}
+
+// NewGitHubPushParams creates parameters for GitHub push ingestion from HTTP request.
+func NewGitHubPushParams(r *http.Request) (*GitHubPushParams, error) {
+	projectID, err := model.ParseHexID(r.PathValue("project_id"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid project_id: %w", err)
+	}
+
+	var event githubPushEvent
+	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+		return nil, fmt.Errorf("invalid push payload: %w", err)
+	}
+
+	// ペイロードの形式を検証
+	if event.Repository == nil || event.Repository.Name == "" {
+		return nil, fmt.Errorf("invalid push payload: repository.name is required")
+	}
+	if len(event.Commits) == 0 {
+		return nil, fmt.Errorf("invalid push payload: commits must not be empty")
+	}
+	if event.HeadCommit == nil || event.HeadCommit.Timestamp == "" {
+		return nil, fmt.Errorf("invalid push payload: head_commit.timestamp is required")
+	}
+	timestamp, err := time.Parse(time.RFC3339, event.HeadCommit.Timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("invalid push payload: head_commit.timestamp must be RFC3339")
+	}
+
+	return &GitHubPushParams{
+		ProjectID: projectID,
+		Timestamp: timestamp,
+		Commits:   len(event.Commits),
+		Repo:      event.Repository.Name,
+	}, nil
+}
+
+// handleIngestGitHubPush はGitHubのpushイベントを受け取り、コミット数を値とするレコードを作成するハンドラーです。
+// レコードはhead_commitの日時で作成し、リポジトリ名をタグとして付与します。
+func (s *Server) handleIngestGitHubPush(w http.ResponseWriter, r *http.Request) {
+	// パラメータを検証
+	params, err := NewGitHubPushParams(r)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// プロジェクトの存在確認
+	project, err := s.store.GetProject(r.Context(), params.ProjectID)
+	if err != nil {
+		if errors.Is(err, model.ErrProjectNotFound) {
+			writeJSONError(w, fmt.Sprintf("Project with ID %s not found", params.ProjectID), http.StatusNotFound)
+		} else {
+			writeJSONError(w, fmt.Sprintf("Error retrieving project: %v", err), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// 新しいレコードの作成
+	record, err := model.NewRecord(params.Timestamp, params.ProjectID, params.Commits, []string{params.Repo})
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	record.Source = recordSourceGitHub
+	if err := s.checkNewRecord(project, record); err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// レコードの保存
+	if err := s.store.CreateRecord(r.Context(), record); err != nil {
+		log.Printf("Error creating record: %v", err)
+		writeJSONError(w, "Failed to create record", http.StatusInternalServerError)
+		return
+	}
+	s.graphCache.invalidateProject(record.ProjectID)
+	s.notifyRecordCreated(record)
+
+	// 成功レスポンスの返却
+	writeJSON(w, r, http.StatusCreated, record)
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stsysd/sougen/model"
+)
+
+// samplePushPayload はGitHubのpushイベントのペイロード例（一部省略）です。
+const samplePushPayload = `{
+  "ref": "refs/heads/main",
+  "before": "6113728f27ae82c7b1a177c8d03f9e96e0adf246",
+  "after": "0d1a26e67d8f5eaf1f6ba5c57fc3c7d91ac0fd1c",
+  "repository": {
+    "id": 35129377,
+    "name": "sougen",
+    "full_name": "stsysd/sougen",
+    "private": false
+  },
+  "pusher": {"name": "stsysd", "email": "stsysd@example.com"},
+  "commits": [
+    {
+      "id": "1481a2de7b2a7d02428ad93446ab166be7793fbb",
+      "message": "Fix heatmap leveling",
+      "timestamp": "2025-06-10T08:58:12+09:00",
+      "author": {"name": "stsysd", "email": "stsysd@example.com"}
+    },
+    {
+      "id": "0d1a26e67d8f5eaf1f6ba5c57fc3c7d91ac0fd1c",
+      "message": "Add tests",
+      "timestamp": "2025-06-10T09:15:42+09:00",
+      "author": {"name": "stsysd", "email": "stsysd@example.com"}
+    }
+  ],
+  "head_commit": {
+    "id": "0d1a26e67d8f5eaf1f6ba5c57fc3c7d91ac0fd1c",
+    "message": "Add tests",
+    "timestamp": "2025-06-10T09:15:42+09:00",
+    "author": {"name": "stsysd", "email": "stsysd@example.com"}
+  }
+}`
+
+func TestIngestGitHubPush(t *testing.T) {
+	mockStore := NewMockStore()
+	project, _ := model.NewProject("github-project", "")
+	mockStore.CreateProject(context.Background(), project)
+	server := NewServer(mockStore, newTestConfig())
+
+	ingest := func(projectID model.HexID, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v0/p/%s/ingest/github", projectID), strings.NewReader(body))
+		req.Header.Set("X-API-Key", testAPIKey)
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		return w
+	}
+
+	w := ingest(project.ID, samplePushPayload)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var record model.Record
+	if err := json.NewDecoder(w.Body).Decode(&record); err != nil {
+		t.Fatalf("Failed to decode response body: %v", err)
+	}
+	if record.Value != 2 {
+		t.Errorf("Expected value 2 (number of commits), got %d", record.Value)
+	}
+	expectedTime := time.Date(2025, 6, 10, 0, 15, 42, 0, time.UTC)
+	if !record.Timestamp.Equal(expectedTime) {
+		t.Errorf("Expected timestamp %v, got %v", expectedTime, record.Timestamp)
+	}
+	if !slices.Equal(record.Tags, []string{"sougen"}) {
+		t.Errorf("Expected tags [sougen], got %v", record.Tags)
+	}
+	if record.Source != recordSourceGitHub {
+		t.Errorf("Expected source %q, got %q", recordSourceGitHub, record.Source)
+	}
+	if _, err := mockStore.GetRecord(context.Background(), record.ID); err != nil {
+		t.Errorf("Expected record to be stored: %v", err)
+	}
+
+	t.Run("Malformed payloads", func(t *testing.T) {
+		tests := []struct {
+			name string
+			body string
+		}{
+			{"Not JSON", `not json`},
+			{"Missing repository", `{"commits": [{}], "head_commit": {"timestamp": "2025-06-10T09:15:42Z"}}`},
+			{"No commits", `{"repository": {"name": "sougen"}, "commits": [], "head_commit": null}`},
+			{"Missing head_commit", `{"repository": {"name": "sougen"}, "commits": [{}]}`},
+			{"Invalid timestamp", `{"repository": {"name": "sougen"}, "commits": [{}], "head_commit": {"timestamp": "yesterday"}}`},
+			{"Wrong types", `{"repository": "sougen", "commits": 3}`},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if w := ingest(project.ID, tt.body); w.Code != http.StatusBadRequest {
+					t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, w.Code)
+				}
+			})
+		}
+	})
+
+	t.Run("Value out of project bounds", func(t *testing.T) {
+		bounded, _ := model.NewProject("bounded-project", "")
+		maxValue := 1.0
+		bounded.MaxValue = &maxValue
+		mockStore.CreateProject(context.Background(), bounded)
+		if w := ingest(bounded.ID, samplePushPayload); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, w.Code)
+		}
+	})
+
+	t.Run("Timestamp out of range", func(t *testing.T) {
+		cfg := newTestConfig()
+		cfg.MinTimestamp = time.Unix(0, 0).UTC()
+		cfg.MaxTimestampFuture = 24 * time.Hour
+		bounded := NewServer(mockStore, cfg)
+		for _, timestamp := range []string{"1960-01-01T00:00:00Z", time.Now().Add(48 * time.Hour).Format(time.RFC3339)} {
+			body := fmt.Sprintf(`{"repository": {"name": "sougen"}, "commits": [{}], "head_commit": {"timestamp": %q}}`, timestamp)
+			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v0/p/%s/ingest/github", project.ID), strings.NewReader(body))
+			req.Header.Set("X-API-Key", testAPIKey)
+			w := httptest.NewRecorder()
+			bounded.ServeHTTP(w, req)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status code %d for %s, got %d", http.StatusBadRequest, timestamp, w.Code)
+			}
+		}
+	})
+
+	t.Run("Non-existent project", func(t *testing.T) {
+		if w := ingest(model.NewHexID(999), samplePushPayload); w.Code != http.StatusNotFound {
+			t.Errorf("Expected status code %d, got %d", http.StatusNotFound, w.Code)
+		}
+	})
+}
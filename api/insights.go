@@ -0,0 +1,170 @@
+// Package api はsougenのAPIサーバー実装を提供します。
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/stsysd/sougen/model"
+	"github.com/stsysd/sougen/store"
+)
+
+// InsightsParams represents parameters for the activity insights.
+type InsightsParams struct {
+	ProjectID model.HexID
+	DateRange *model.DateRange
+	Tags      *model.Tags
+}
+
+// NewInsightsParams creates parameters for the activity insights from HTTP request.
+func NewInsightsParams(r *http.Request) (*InsightsParams, error) {
+	projectID, err := model.ParseHexID(r.PathValue("project_id"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid project_id: %w", err)
+	}
+
+	query := r.URL.Query()
+
+	dateRange, err := model.NewDateRange(query.Get("from"), query.Get("to"))
+	if err != nil {
+		return nil, err
+	}
+	if dateRange.From().After(dateRange.To()) {
+		return nil, fmt.Errorf("from must not be after to")
+	}
+
+	return &InsightsParams{
+		ProjectID: projectID,
+		DateRange: dateRange,
+		Tags:      model.NewTags(query.Get("tags")),
+	}, nil
+}
+
+// DayInsight は特定の日の合計値です。
+type DayInsight struct {
+	Date  string  `json:"date"` // YYYY-MM-DD（プロジェクトのタイムゾーン）
+	Total float64 `json:"total"`
+}
+
+// WeekdayInsight は曜日ごとの合計値です。
+type WeekdayInsight struct {
+	Weekday string  `json:"weekday"` // sun..sat
+	Total   float64 `json:"total"`
+}
+
+// SlotInsight は4時間単位のスロット（週次ビューの行）ごとの合計値です。
+type SlotInsight struct {
+	StartHour int     `json:"start_hour"` // 0, 4, 8, 12, 16, 20
+	EndHour   int     `json:"end_hour"`
+	Total     float64 `json:"total"`
+}
+
+// InsightsResponse はアクティビティの傾向のレスポンスです。
+// 期間内にアクティビティがない場合、各項目はnull、平均は0になります。
+type InsightsResponse struct {
+	BusiestDay          *DayInsight     `json:"busiest_day"`
+	QuietestActiveDay   *DayInsight     `json:"quietest_active_day"`
+	BusiestWeekday      *WeekdayInsight `json:"busiest_weekday"`
+	Busiest4hSlot       *SlotInsight    `json:"busiest_4h_slot"`
+	AveragePerActiveDay float64         `json:"average_per_active_day"`
+}
+
+// buildInsights は4時間スロットごとの合計値から傾向を計算します。
+// スロットの時刻は集計したタイムゾーンのもので、日付や曜日もそのタイムゾーンで判定します。
+// 同じ値の場合は早い日付・曜日・時間帯を優先します。
+func buildInsights(slotTotals []*store.DailyTotal) *InsightsResponse {
+	// スロットごとの合計を日別・曜日別・時間帯別にまとめる（slotTotalsは時刻順）
+	var days []*store.DailyTotal
+	var weekdayTotals [7]float64
+	var slotOfDayTotals [6]float64
+	for _, t := range slotTotals {
+		day := dayBucketIn(t.Date, t.Date.Location())
+		if len(days) == 0 || !days[len(days)-1].Date.Equal(day) {
+			days = append(days, &store.DailyTotal{Date: day})
+		}
+		days[len(days)-1].Value += t.Value
+		weekdayTotals[day.Weekday()] += t.Value
+		slotOfDayTotals[t.Date.Hour()/4] += t.Value
+	}
+
+	response := &InsightsResponse{}
+	var total float64
+	activeDays := 0
+	for _, day := range days {
+		if day.Value <= 0 {
+			continue
+		}
+		total += day.Value
+		activeDays++
+		if response.BusiestDay == nil || day.Value > response.BusiestDay.Total {
+			response.BusiestDay = &DayInsight{Date: day.Date.Format("2006-01-02"), Total: day.Value}
+		}
+		if response.QuietestActiveDay == nil || day.Value < response.QuietestActiveDay.Total {
+			response.QuietestActiveDay = &DayInsight{Date: day.Date.Format("2006-01-02"), Total: day.Value}
+		}
+	}
+	if activeDays == 0 {
+		return response
+	}
+	response.AveragePerActiveDay = total / float64(activeDays)
+
+	for weekday, value := range weekdayTotals {
+		if value > 0 && (response.BusiestWeekday == nil || value > response.BusiestWeekday.Total) {
+			name := strings.ToLower(time.Weekday(weekday).String()[:3])
+			response.BusiestWeekday = &WeekdayInsight{Weekday: name, Total: value}
+		}
+	}
+	for slot, value := range slotOfDayTotals {
+		if value > 0 && (response.Busiest4hSlot == nil || value > response.Busiest4hSlot.Total) {
+			response.Busiest4hSlot = &SlotInsight{StartHour: slot * 4, EndHour: (slot + 1) * 4, Total: value}
+		}
+	}
+	return response
+}
+
+// handleInsights は指定期間で最も活動した日・曜日・時間帯などの傾向を返すハンドラーです。
+// 集計は週次ビューと同じ4時間スロット単位で行い、日別の値はその合計です。
+func (s *Server) handleInsights(w http.ResponseWriter, r *http.Request) {
+	// パラメータを検証
+	params, err := NewInsightsParams(r)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// プロジェクトの存在確認
+	project, err := s.store.GetProject(r.Context(), params.ProjectID)
+	if err != nil {
+		if errors.Is(err, model.ErrProjectNotFound) {
+			writeJSONError(w, fmt.Sprintf("Project with ID %s not found", params.ProjectID), http.StatusNotFound)
+		} else {
+			writeJSONError(w, fmt.Sprintf("Error retrieving project: %v", err), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// 期間の日付とスロットの区切りはプロジェクトのタイムゾーン
+	loc := project.Location()
+	dateRange := params.DateRange.In(loc)
+	records := s.store.ListAllRecords(r.Context(), &store.ListAllRecordsParams{
+		ProjectID: params.ProjectID,
+		From:      dateRange.From(),
+		To:        dateRange.To(),
+		Tags:      params.Tags.Values(),
+	})
+	slotTotals, err := store.AggregateRecordsBy(records, func(t time.Time) time.Time {
+		return slotBucketIn(t, loc)
+	}, model.AggregationSum, project.RecordValueType())
+	if err != nil {
+		log.Printf("Error retrieving records: %v", err)
+		writeJSONError(w, "Failed to retrieve records", http.StatusInternalServerError)
+		return
+	}
+
+	// レスポンスの返却
+	writeJSON(w, r, http.StatusOK, buildInsights(slotTotals))
+}
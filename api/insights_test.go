@@ -0,0 +1,87 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stsysd/sougen/model"
+)
+
+func TestInsightsEndpoint(t *testing.T) {
+	mockStore := NewMockStore()
+	project, _ := model.NewProject("insights-project", "")
+	mockStore.CreateProject(context.Background(), project)
+	server := NewServer(mockStore, newTestConfig())
+
+	// 2025-06-04（水）の9時台に活動が集中するデータ
+	for _, e := range []struct {
+		day, hour, value int
+	}{
+		{2, 20, 1},
+		{4, 9, 10},
+		{4, 10, 5},
+		{4, 22, 1},
+		{5, 9, 2},
+		{7, 1, 3},
+	} {
+		record, _ := model.NewRecord(time.Date(2025, 6, e.day, e.hour, 0, 0, 0, time.Local), project.ID, e.value, nil)
+		mockStore.CreateRecord(context.Background(), record)
+	}
+
+	getInsights := func(projectID model.HexID, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v0/p/%s/insights?%s", projectID, query), nil)
+		req.Header.Set("X-API-Key", testAPIKey)
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		return w
+	}
+
+	w := getInsights(project.ID, "from=2025-06-01&to=2025-06-30")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var insights InsightsResponse
+	if err := json.NewDecoder(w.Body).Decode(&insights); err != nil {
+		t.Fatalf("Failed to decode response body: %v", err)
+	}
+
+	if insights.BusiestDay == nil || *insights.BusiestDay != (DayInsight{Date: "2025-06-04", Total: 16}) {
+		t.Errorf("Unexpected busiest_day: %+v", insights.BusiestDay)
+	}
+	if insights.QuietestActiveDay == nil || *insights.QuietestActiveDay != (DayInsight{Date: "2025-06-02", Total: 1}) {
+		t.Errorf("Unexpected quietest_active_day: %+v", insights.QuietestActiveDay)
+	}
+	if insights.BusiestWeekday == nil || *insights.BusiestWeekday != (WeekdayInsight{Weekday: "wed", Total: 16}) {
+		t.Errorf("Unexpected busiest_weekday: %+v", insights.BusiestWeekday)
+	}
+	// 8-12時のスロット: 10 + 5 + 2
+	if insights.Busiest4hSlot == nil || *insights.Busiest4hSlot != (SlotInsight{StartHour: 8, EndHour: 12, Total: 17}) {
+		t.Errorf("Unexpected busiest_4h_slot: %+v", insights.Busiest4hSlot)
+	}
+	// 合計22を4日で割る
+	if insights.AveragePerActiveDay != 5.5 {
+		t.Errorf("Expected average_per_active_day 5.5, got %v", insights.AveragePerActiveDay)
+	}
+
+	// 活動がない期間では各項目がnull
+	w = getInsights(project.ID, "from=2025-07-01&to=2025-07-31")
+	var empty InsightsResponse
+	if err := json.NewDecoder(w.Body).Decode(&empty); err != nil {
+		t.Fatalf("Failed to decode response body: %v", err)
+	}
+	if empty.BusiestDay != nil || empty.QuietestActiveDay != nil || empty.BusiestWeekday != nil || empty.Busiest4hSlot != nil || empty.AveragePerActiveDay != 0 {
+		t.Errorf("Expected empty insights, got %+v", empty)
+	}
+
+	if w := getInsights(project.ID, "from=2025-06-30&to=2025-06-01"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d for an inverted range, got %d", http.StatusBadRequest, w.Code)
+	}
+	if w := getInsights(model.NewHexID(999), ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d for a missing project, got %d", http.StatusNotFound, w.Code)
+	}
+}
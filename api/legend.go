@@ -0,0 +1,75 @@
+// Package api はsougenのAPIサーバー実装を提供します。
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+
+	"github.com/stsysd/sougen/heatmap"
+	"github.com/stsysd/sougen/model"
+)
+
+// LegendLevelResponse はグラフの凡例の1レベル分です。
+// レベル0は値が0のセルで、それ以外のレベルはmin_value以上max_value未満の値を表します。
+type LegendLevelResponse struct {
+	Level    int      `json:"level"`
+	MinValue float64  `json:"min_value"`
+	MaxValue *float64 `json:"max_value"` // 最上位レベルは上限がないためnull
+	Color    string   `json:"color"`
+}
+
+// handleGetGraphLegend はグラフの各色がどの値の範囲に対応するかを返すハンドラーです。
+// グラフと同じパラメータ（期間・タグ・曜日・集計方法・テーマなど）で絞り込んだデータから、
+// グラフと同じ自動スケーリングでレベルの境界を計算します。trackパラメータは無視します。
+func (s *Server) handleGetGraphLegend(w http.ResponseWriter, r *http.Request) {
+	// パラメータを検証
+	params, err := NewGetGraphParams(r)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// プロジェクトの存在確認
+	project, err := s.store.GetProject(r.Context(), params.ProjectID)
+	if err != nil {
+		if errors.Is(err, model.ErrProjectNotFound) {
+			writeJSONError(w, fmt.Sprintf("Project with ID %s not found", params.ProjectID), http.StatusNotFound)
+		} else {
+			writeJSONError(w, fmt.Sprintf("Error retrieving project: %v", err), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	data, err := s.graphData(r.Context(), params, project)
+	if err != nil {
+		log.Printf("Error retrieving records: %v", err)
+		writeJSONError(w, "Failed to retrieve records", http.StatusInternalServerError)
+		return
+	}
+
+	legend := heatmap.Legend(data, &heatmap.Options{
+		Colors:          graphColors(s.graphTheme(params)),
+		MinNonZeroLevel: params.MinLevel,
+	})
+
+	// レスポンスの構築
+	response := make([]LegendLevelResponse, 0, len(legend))
+	for _, level := range legend {
+		entry := LegendLevelResponse{
+			Level:    level.Level,
+			MinValue: level.Min,
+			Color:    level.Color,
+		}
+		if !math.IsInf(level.Max, 1) {
+			maxValue := level.Max
+			entry.MaxValue = &maxValue
+		}
+		response = append(response, entry)
+	}
+
+	// レスポンスの返却
+	writeJSON(w, r, http.StatusOK, response)
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stsysd/sougen/heatmap"
+	"github.com/stsysd/sougen/model"
+)
+
+func TestGetGraphLegend(t *testing.T) {
+	mockStore := NewMockStore()
+	project, _ := model.NewProject("legend-project", "")
+	mockStore.CreateProject(context.Background(), project)
+	server := NewServer(mockStore, newTestConfig())
+
+	// 2025-06-01〜03に合計1, 4, 12の記録
+	for i, values := range [][]int{{1}, {3, 1}, {12}} {
+		for _, v := range values {
+			record, _ := model.NewRecord(time.Date(2025, 6, 1+i, 12, 0, 0, 0, time.Local), project.ID, v, nil)
+			mockStore.CreateRecord(context.Background(), record)
+		}
+	}
+
+	getLegend := func(projectID model.HexID, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v0/p/%s/graph/legend?%s", projectID, query), nil)
+		req.Header.Set("X-API-Key", testAPIKey)
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		return w
+	}
+
+	w := getLegend(project.ID, "from=2025-06-01&to=2025-06-30&theme=cividis")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var legend []LegendLevelResponse
+	if err := json.NewDecoder(w.Body).Decode(&legend); err != nil {
+		t.Fatalf("Failed to decode response body: %v", err)
+	}
+
+	colors, _ := heatmap.ThemeColors("cividis")
+	if len(legend) != len(colors) {
+		t.Fatalf("Expected %d levels, got %d", len(colors), len(legend))
+	}
+	for i, level := range legend {
+		if level.Level != i || level.Color != colors[i] {
+			t.Errorf("Unexpected level %d: %+v", i, level)
+		}
+	}
+
+	// 境界は0から上限なしまで途切れずに続き、データの値域をすべて覆う
+	if legend[0].MinValue != 0 || legend[0].MaxValue == nil || *legend[0].MaxValue != 0 {
+		t.Errorf("Expected level 0 to cover only zero, got %+v", legend[0])
+	}
+	if legend[1].MinValue != 0 {
+		t.Errorf("Expected level 1 to start at 0, got %v", legend[1].MinValue)
+	}
+	for i := 2; i < len(legend); i++ {
+		if legend[i-1].MaxValue == nil || legend[i].MinValue != *legend[i-1].MaxValue {
+			t.Errorf("Expected level %d to start where level %d ends, got %+v after %+v", i, i-1, legend[i], legend[i-1])
+		}
+	}
+	if last := legend[len(legend)-1]; last.MaxValue != nil {
+		t.Errorf("Expected the last level to be unbounded, got %v", *last.MaxValue)
+	}
+	for _, v := range []float64{1, 4, 12} {
+		covered := false
+		for _, level := range legend[1:] {
+			if v >= level.MinValue && (level.MaxValue == nil || v < *level.MaxValue) {
+				covered = true
+			}
+		}
+		if !covered {
+			t.Errorf("Expected value %v to be covered by the legend", v)
+		}
+	}
+
+	t.Run("Filters change the scale", func(t *testing.T) {
+		w := getLegend(project.ID, "from=2025-06-01&to=2025-06-02")
+		var filtered []LegendLevelResponse
+		if err := json.NewDecoder(w.Body).Decode(&filtered); err != nil {
+			t.Fatalf("Failed to decode response body: %v", err)
+		}
+		if *filtered[1].MaxValue >= *legend[1].MaxValue {
+			t.Errorf("Expected a smaller scale without the 12-value day, got %v and %v", *filtered[1].MaxValue, *legend[1].MaxValue)
+		}
+	})
+
+	t.Run("Invalid parameters", func(t *testing.T) {
+		if w := getLegend(project.ID, "view=monthly"); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, w.Code)
+		}
+	})
+
+	t.Run("Non-existent project", func(t *testing.T) {
+		if w := getLegend(model.NewHexID(999), ""); w.Code != http.StatusNotFound {
+			t.Errorf("Expected status code %d, got %d", http.StatusNotFound, w.Code)
+		}
+	})
+}
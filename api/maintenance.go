@@ -0,0 +1,66 @@
+package api
+
+import (
+	"context"
+	"log"
+	"net/http"
+)
+
+// databaseSizer はデータベースのサイズを取得できるストアが実装するインターフェースです。
+type databaseSizer interface {
+	DatabaseSize(ctx context.Context) (int64, error)
+}
+
+// VacuumResponse はVACUUMエンドポイントのレスポンスです。
+type VacuumResponse struct {
+	ReclaimedBytes *int64 `json:"reclaimed_bytes,omitempty"` // サイズを取得できない場合は省略
+}
+
+// handlePruneTags は対応するレコードが存在しないタグ行を削除するハンドラーです。
+func (s *Server) handlePruneTags(w http.ResponseWriter, r *http.Request) {
+	count, err := s.store.PruneOrphanTags(r.Context())
+	if err != nil {
+		log.Printf("Error pruning orphan tags: %v", err)
+		writeJSONError(w, "Failed to prune orphan tags", http.StatusInternalServerError)
+		return
+	}
+
+	// 削除結果をJSONで返す
+	writeJSON(w, r, http.StatusOK, map[string]int{
+		"pruned_count": count,
+	})
+}
+
+// handleVacuum はデータベースを最適化して未使用領域を解放するハンドラーです。
+func (s *Server) handleVacuum(w http.ResponseWriter, r *http.Request) {
+	// サイズを取得できるストアの場合は実行前のサイズを記録
+	sizer, canMeasure := s.store.(databaseSizer)
+	var before int64
+	if canMeasure {
+		size, err := sizer.DatabaseSize(r.Context())
+		if err != nil {
+			log.Printf("Error getting database size: %v", err)
+			canMeasure = false
+		}
+		before = size
+	}
+
+	if err := s.store.Vacuum(r.Context()); err != nil {
+		log.Printf("Error vacuuming database: %v", err)
+		writeJSONError(w, "Failed to vacuum database", http.StatusInternalServerError)
+		return
+	}
+
+	response := &VacuumResponse{}
+	if canMeasure {
+		after, err := sizer.DatabaseSize(r.Context())
+		if err != nil {
+			log.Printf("Error getting database size: %v", err)
+		} else {
+			reclaimed := max(before-after, 0)
+			response.ReclaimedBytes = &reclaimed
+		}
+	}
+
+	writeJSON(w, r, http.StatusOK, response)
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPruneTagsEndpoint(t *testing.T) {
+	server := NewServer(NewMockStore(), newTestConfig())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v0/maintenance/prune-tags", nil)
+	req.Header.Set("X-API-Key", testAPIKey)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response map[string]int
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response body: %v", err)
+	}
+	if count, ok := response["pruned_count"]; !ok || count != 0 {
+		t.Errorf("Expected pruned_count 0, got %v", response)
+	}
+}
+
+func TestPruneTagsEndpointRequiresAuth(t *testing.T) {
+	server := NewServer(NewMockStore(), newTestConfig())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v0/maintenance/prune-tags", nil)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status code %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestVacuumEndpoint(t *testing.T) {
+	// モックストアはサイズを取得できないためreclaimed_bytesは省略される
+	server := NewServer(NewMockStore(), newTestConfig())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v0/maintenance/vacuum", nil)
+	req.Header.Set("X-API-Key", testAPIKey)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response map[string]any
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response body: %v", err)
+	}
+	if _, ok := response["reclaimed_bytes"]; ok {
+		t.Errorf("Expected reclaimed_bytes to be omitted, got %v", response)
+	}
+}
@@ -2,15 +2,64 @@
 package api
 
 import (
+	"context"
+	"crypto/subtle"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
 	"net/http"
+	"strings"
+
+	"github.com/stsysd/sougen/config"
+	"github.com/stsysd/sougen/model"
 )
 
+// requestAPIKey はリクエストからAPIキーを取得します。
+// config.AuthHeaderで許可されたヘッダー（X-API-KeyまたはAuthorization: Bearer）のみを参照します。
+func (s *Server) requestAPIKey(r *http.Request) string {
+	if s.config.AuthHeader != config.AuthHeaderBearer {
+		if apiKey := r.Header.Get("X-API-Key"); apiKey != "" {
+			return apiKey
+		}
+	}
+	if s.config.AuthHeader != config.AuthHeaderAPIKey {
+		scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+		if ok && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+	}
+	return ""
+}
+
+// clientIP はリクエスト元のクライアントIPを返します。
+// config.UseForwardedForが有効な場合のみX-Forwarded-For（先頭のアドレス）とX-Real-IPを参照し、
+// 無効な場合や値が不正な場合は接続元のアドレスを使用します。
+func (s *Server) clientIP(r *http.Request) string {
+	if s.config.UseForwardedFor {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			first, _, _ := strings.Cut(forwarded, ",")
+			if ip := net.ParseIP(strings.TrimSpace(first)); ip != nil {
+				return ip.String()
+			}
+		}
+		if ip := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-IP"))); ip != nil {
+			return ip.String()
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
 // authMiddleware はAPIリクエストの認証を行うミドルウェアです。
 func (s *Server) authMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// ヘッダーからAPIキーを取得
-		apiKey := r.Header.Get("X-API-Key")
+		apiKey := s.requestAPIKey(r)
 
 		// APIキーがサーバー側で設定されていない場合はエラー
 		if s.config.APIKey == "" {
@@ -28,7 +77,8 @@ func (s *Server) authMiddleware(next http.Handler) http.Handler {
 		}
 
 		// APIキーが一致するか確認
-		if apiKey != s.config.APIKey {
+		if subtle.ConstantTimeCompare([]byte(apiKey), []byte(s.config.APIKey)) != 1 {
+			log.Printf("Unauthorized request from %s: %s %s", s.clientIP(r), r.Method, r.URL.Path)
 			type errorResponse struct {
 				Error string `json:"error"`
 				Code  int    `json:"code"`
@@ -46,3 +96,126 @@ func (s *Server) authMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+// dbTimeoutMiddleware はリクエストのコンテキストにデータベース処理の期限（config.DBTimeout）を設定するミドルウェアです。
+// 期限切れによってハンドラーが5xxのエラーを返そうとした場合は、504とJSONのエラーに置き換えます。
+func (s *Server) dbTimeoutMiddleware(next http.Handler) http.Handler {
+	if s.config.DBTimeout <= 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), s.config.DBTimeout)
+		defer cancel()
+		next.ServeHTTP(&timeoutResponseWriter{ResponseWriter: w, ctx: ctx}, r.WithContext(ctx))
+	})
+}
+
+// tagFilterLimitMiddleware はtagsパラメータのタグ数がconfig.MaxFilterTagsを超えるリクエストを400で拒否するミドルウェアです。
+// タグ数に比例して重くなるフィルタのクエリからストアを保護します。
+func (s *Server) tagFilterLimitMiddleware(next http.Handler) http.Handler {
+	if s.config.MaxFilterTags <= 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tags := model.NewTags(r.URL.Query().Get("tags"))
+		if len(tags.Values()) > s.config.MaxFilterTags {
+			writeJSONError(w, fmt.Sprintf("Too many tags (max %d tags)", s.config.MaxFilterTags), http.StatusBadRequest)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// concurrencyLimitMiddleware は処理中のリクエストがconfig.MaxConcurrentRequestsに達している場合に、
+// 新しいリクエストを待たせずに503（Retry-After付き）で拒否するミドルウェアです。
+// 書き込みが直列化されるSQLiteにリクエストが滞留するのを防ぎます。ヘルスチェックは対象外です。
+func (s *Server) concurrencyLimitMiddleware(next http.Handler) http.Handler {
+	if s.inflight == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == s.config.BasePath+"/healthz" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		select {
+		case s.inflight <- struct{}{}:
+			defer func() { <-s.inflight }()
+			next.ServeHTTP(w, r)
+		default:
+			w.Header().Set("Retry-After", "1")
+			writeJSONError(w, "Too many concurrent requests", http.StatusServiceUnavailable)
+		}
+	})
+}
+
+// timeoutResponseWriter はコンテキストの期限切れ後に書き込まれる5xxのレスポンスを504に置き換えるResponseWriterです。
+type timeoutResponseWriter struct {
+	http.ResponseWriter
+	ctx      context.Context
+	timedOut bool
+}
+
+// WriteHeader は期限切れによるサーバーエラーを504のJSONエラーとして書き込みます。
+func (w *timeoutResponseWriter) WriteHeader(code int) {
+	if code >= http.StatusInternalServerError && errors.Is(w.ctx.Err(), context.DeadlineExceeded) {
+		w.timedOut = true
+		writeJSONError(w.ResponseWriter, "Database operation timed out", http.StatusGatewayTimeout)
+		return
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write は504に置き換えた場合、ハンドラーが書き込むエラー本文を破棄します。
+func (w *timeoutResponseWriter) Write(b []byte) (int, error) {
+	if w.timedOut {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap はhttp.ResponseControllerのために元のResponseWriterを返します。
+func (w *timeoutResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// jsonMuxErrors はmuxにマッチしないリクエストへの404と405を、プレーンテキストではなくJSONのエラーで返すハンドラーです。
+// 405の場合はmuxが設定するAllowヘッダーをそのまま返します。
+func jsonMuxErrors(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// パターンが空の場合はmux自身の404または405のハンドラー
+		if _, pattern := mux.Handler(r); pattern != "" {
+			mux.ServeHTTP(w, r)
+			return
+		}
+		mux.ServeHTTP(&muxErrorResponseWriter{ResponseWriter: w}, r)
+	})
+}
+
+// muxErrorResponseWriter はmuxが書き込む404と405のエラーをJSONのエラーに置き換えるResponseWriterです。
+type muxErrorResponseWriter struct {
+	http.ResponseWriter
+	replaced bool
+}
+
+// WriteHeader は404と405をJSONのエラーとして書き込みます。
+func (w *muxErrorResponseWriter) WriteHeader(code int) {
+	switch code {
+	case http.StatusNotFound:
+		w.replaced = true
+		writeJSONError(w.ResponseWriter, "Not found", code)
+	case http.StatusMethodNotAllowed:
+		w.replaced = true
+		writeJSONError(w.ResponseWriter, "Method not allowed", code)
+	default:
+		w.ResponseWriter.WriteHeader(code)
+	}
+}
+
+// Write はJSONに置き換えた場合、muxが書き込むプレーンテキストの本文を破棄します。
+func (w *muxErrorResponseWriter) Write(b []byte) (int, error) {
+	if w.replaced {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
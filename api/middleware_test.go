@@ -0,0 +1,313 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stsysd/sougen/config"
+	"github.com/stsysd/sougen/model"
+)
+
+func TestAuthMiddleware(t *testing.T) {
+	tests := []struct {
+		name           string
+		authHeader     string
+		headers        map[string]string
+		expectedStatus int
+	}{
+		{"X-API-Key", config.AuthHeaderAny, map[string]string{"X-API-Key": testAPIKey}, http.StatusOK},
+		{"Bearer token", config.AuthHeaderAny, map[string]string{"Authorization": "Bearer " + testAPIKey}, http.StatusOK},
+		{"Bearer scheme is case-insensitive", config.AuthHeaderAny, map[string]string{"Authorization": "bearer " + testAPIKey}, http.StatusOK},
+		{"Missing auth", config.AuthHeaderAny, nil, http.StatusUnauthorized},
+		{"Wrong X-API-Key", config.AuthHeaderAny, map[string]string{"X-API-Key": "wrong"}, http.StatusUnauthorized},
+		{"Wrong bearer token", config.AuthHeaderAny, map[string]string{"Authorization": "Bearer wrong"}, http.StatusUnauthorized},
+		{"Basic scheme", config.AuthHeaderAny, map[string]string{"Authorization": "Basic " + testAPIKey}, http.StatusUnauthorized},
+		{"Bearer only rejects X-API-Key", config.AuthHeaderBearer, map[string]string{"X-API-Key": testAPIKey}, http.StatusUnauthorized},
+		{"Bearer only accepts bearer", config.AuthHeaderBearer, map[string]string{"Authorization": "Bearer " + testAPIKey}, http.StatusOK},
+		{"X-API-Key only rejects bearer", config.AuthHeaderAPIKey, map[string]string{"Authorization": "Bearer " + testAPIKey}, http.StatusUnauthorized},
+		{"X-API-Key only accepts X-API-Key", config.AuthHeaderAPIKey, map[string]string{"X-API-Key": testAPIKey}, http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := newTestConfig()
+			cfg.AuthHeader = tc.authHeader
+			server := NewServer(NewMockStore(), cfg)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v0/version", nil)
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name            string
+		useForwardedFor bool
+		headers         map[string]string
+		expected        string
+	}{
+		{"Remote address", false, nil, "192.0.2.1"},
+		{"Forwarded headers ignored by default", false, map[string]string{"X-Forwarded-For": "203.0.113.7", "X-Real-IP": "203.0.113.8"}, "192.0.2.1"},
+		{"X-Forwarded-For", true, map[string]string{"X-Forwarded-For": "203.0.113.7, 10.0.0.1"}, "203.0.113.7"},
+		{"X-Forwarded-For takes precedence", true, map[string]string{"X-Forwarded-For": "203.0.113.7", "X-Real-IP": "203.0.113.8"}, "203.0.113.7"},
+		{"X-Real-IP", true, map[string]string{"X-Real-IP": "2001:db8::1"}, "2001:db8::1"},
+		{"Invalid header falls back", true, map[string]string{"X-Forwarded-For": "unknown"}, "192.0.2.1"},
+		{"No headers", true, nil, "192.0.2.1"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := newTestConfig()
+			cfg.UseForwardedFor = tc.useForwardedFor
+			server := NewServer(NewMockStore(), cfg)
+
+			req := httptest.NewRequest(http.MethodGet, "/p/0000000000000001/graph", nil)
+			req.RemoteAddr = "192.0.2.1:54321"
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+
+			if got := server.clientIP(req); got != tc.expected {
+				t.Errorf("Expected client IP %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+// blockingStore はコンテキストが終了するまでGetProjectをブロックするストアです。
+type blockingStore struct {
+	*MockStore
+}
+
+func (b *blockingStore) GetProject(ctx context.Context, id model.HexID) (*model.Project, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// gatedStore はreleaseが閉じられるまでプロジェクトの取得をブロックするストアです。
+type gatedStore struct {
+	*MockStore
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (g *gatedStore) GetProject(ctx context.Context, id model.HexID) (*model.Project, error) {
+	g.entered <- struct{}{}
+	<-g.release
+	return g.MockStore.GetProject(ctx, id)
+}
+
+func TestConcurrencyLimitMiddleware(t *testing.T) {
+	const limit = 3
+	cfg := newTestConfig()
+	cfg.MaxConcurrentRequests = limit
+	store := &gatedStore{MockStore: NewMockStore(), entered: make(chan struct{}, limit), release: make(chan struct{})}
+	server := NewServer(store, cfg)
+
+	request := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("X-API-Key", testAPIKey)
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		return w
+	}
+
+	// 上限までのリクエストをストアでブロックさせる
+	results := make(chan int, limit)
+	for range limit {
+		go func() {
+			results <- request("/api/v0/p/0000000000000001").Code
+		}()
+	}
+	for range limit {
+		select {
+		case <-store.entered:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Requests did not reach the store")
+		}
+	}
+
+	// 上限を超えたリクエストは503
+	w := request("/api/v0/p/0000000000000001")
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusServiceUnavailable, w.Code, w.Body.String())
+	}
+	if ra := w.Header().Get("Retry-After"); ra == "" {
+		t.Error("Expected Retry-After header")
+	}
+
+	// ヘルスチェックは対象外
+	if w := request("/healthz"); w.Code != http.StatusOK {
+		t.Errorf("Expected health check status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	// ブロックしていたリクエストが終われば再び受け付ける
+	close(store.release)
+	for range limit {
+		if code := <-results; code != http.StatusNotFound {
+			t.Errorf("Expected blocked request to finish with %d, got %d", http.StatusNotFound, code)
+		}
+	}
+	if w := request("/api/v0/p/0000000000000001"); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d after release, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestDBTimeoutMiddleware(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.DBTimeout = 50 * time.Millisecond
+	server := NewServer(&blockingStore{MockStore: NewMockStore()}, cfg)
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{"API", "/api/v0/p/0000000000000001"},
+		{"Graph", "/p/0000000000000001/graph"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			req.Header.Set("X-API-Key", testAPIKey)
+			w := httptest.NewRecorder()
+
+			done := make(chan struct{})
+			go func() {
+				server.ServeHTTP(w, req)
+				close(done)
+			}()
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("Request did not time out")
+			}
+
+			if w.Code != http.StatusGatewayTimeout {
+				t.Fatalf("Expected status %d, got %d: %s", http.StatusGatewayTimeout, w.Code, w.Body.String())
+			}
+			if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("Expected Content-Type application/json, got %s", ct)
+			}
+			var body struct {
+				Error string `json:"error"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if body.Error != "Database operation timed out" {
+				t.Errorf("Unexpected error message: %s", body.Error)
+			}
+		})
+	}
+}
+
+func TestTagFilterLimitMiddleware(t *testing.T) {
+	mockStore := NewMockStore()
+	project, _ := model.NewProject("test-project", "Test project")
+	mockStore.CreateProject(context.Background(), project)
+	cfg := newTestConfig()
+	cfg.MaxFilterTags = 3
+	server := NewServer(mockStore, cfg)
+
+	tests := []struct {
+		name         string
+		path         string
+		expectedCode int
+	}{
+		{"API within limit", fmt.Sprintf("/api/v0/r?project_id=%s&tags=a,b,c", project.ID), http.StatusOK},
+		{"API over limit", fmt.Sprintf("/api/v0/r?project_id=%s&tags=a,b,c,d", project.ID), http.StatusBadRequest},
+		{"Empty tags are not counted", fmt.Sprintf("/api/v0/r?project_id=%s&tags=a,,b,,c,", project.ID), http.StatusOK},
+		{"Export over limit", fmt.Sprintf("/api/v0/p/%s/export?tags=a,b,c,d", project.ID), http.StatusBadRequest},
+		{"Graph over limit", fmt.Sprintf("/p/%s/graph?tags=a,b,c,d", project.ID), http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			req.Header.Set("X-API-Key", testAPIKey)
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedCode {
+				t.Fatalf("Expected status %d, got %d: %s", tc.expectedCode, w.Code, w.Body.String())
+			}
+			if tc.expectedCode == http.StatusBadRequest && !strings.Contains(w.Body.String(), "Too many tags (max 3 tags)") {
+				t.Errorf("Unexpected error body: %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestJSONMuxErrors(t *testing.T) {
+	mockStore := NewMockStore()
+	project, _ := model.NewProject("test-project", "Test project")
+	mockStore.CreateProject(context.Background(), project)
+	server := NewServer(mockStore, newTestConfig())
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		expectedCode int
+		expectedMsg  string
+		allow        []string
+	}{
+		{"Unknown path", http.MethodGet, "/unknown", http.StatusNotFound, "Not found", nil},
+		{"Unknown API path", http.MethodGet, "/api/v0/unknown", http.StatusNotFound, "Not found", nil},
+		{"Wrong method on API", http.MethodPatch, "/api/v0/p", http.StatusMethodNotAllowed, "Method not allowed", []string{"GET", "POST"}},
+		{"Wrong method on graph", http.MethodPost, fmt.Sprintf("/p/%s/graph.svg", project.ID), http.StatusMethodNotAllowed, "Method not allowed", []string{"GET"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			req.Header.Set("X-API-Key", testAPIKey)
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedCode {
+				t.Fatalf("Expected status %d, got %d: %s", tc.expectedCode, w.Code, w.Body.String())
+			}
+			if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("Expected Content-Type application/json, got %s", ct)
+			}
+			var body ErrorResponse
+			if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if body.Error != tc.expectedMsg || body.Code != tc.expectedCode {
+				t.Errorf("Unexpected error response: %+v", body)
+			}
+			allow := w.Header().Get("Allow")
+			for _, method := range tc.allow {
+				if !strings.Contains(allow, method) {
+					t.Errorf("Expected Allow header to contain %s, got %q", method, allow)
+				}
+			}
+		})
+	}
+
+	// マッチしたルートのレスポンスはそのまま
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status %d for /healthz, got %d", http.StatusOK, w.Code)
+	}
+}
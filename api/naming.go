@@ -0,0 +1,194 @@
+// Package api はsougenのAPIサーバー実装を提供します。
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/stsysd/sougen/config"
+)
+
+// jsonNamingMiddleware はJSONレスポンスのフィールド名の形式を切り替えるミドルウェアです。
+// レスポンスはsnake_caseで生成し、namingパラメータ（省略時はconfig.JSONNaming）がcamelの場合は
+// JSONの本文を書き込みながらオブジェクトのキーをcamelCaseに変換します。
+// 本文はバッファしないため、エクスポートのようにストリーミングするレスポンスもそのままフラッシュされます。
+func (s *Server) jsonNamingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		naming := s.config.JSONNaming
+		if v := r.URL.Query().Get("naming"); v != "" {
+			if !config.IsJSONNaming(v) {
+				writeJSONError(w, fmt.Sprintf("invalid naming parameter: %q (must be 'snake' or 'camel')", v), http.StatusBadRequest)
+				return
+			}
+			naming = v
+		}
+		if naming != config.JSONNamingCamel {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &camelCaseResponseWriter{ResponseWriter: w}
+		next.ServeHTTP(cw, r)
+		cw.finish()
+	})
+}
+
+// camelCaseResponseWriter はJSONのレスポンスのキーをcamelCaseに変換するResponseWriterです。
+// JSON以外（SVG、PNG、CSVなど）のレスポンスはそのまま書き込みます。
+type camelCaseResponseWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+	converter   *camelCaseWriter // JSONの本文を変換している場合のみ設定
+}
+
+// WriteHeader はJSONのレスポンスの場合、本文の変換を開始します。
+// キーの長さが変わるため、Content-Lengthは削除します。
+func (w *camelCaseResponseWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	if code != http.StatusNoContent && code != http.StatusNotModified && isJSONContentType(w.Header().Get("Content-Type")) {
+		w.Header().Del("Content-Length")
+		w.converter = &camelCaseWriter{w: w.ResponseWriter}
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write はJSONのレスポンスの本文のキーを変換して書き込み、それ以外はそのまま書き込みます。
+func (w *camelCaseResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.converter != nil {
+		return w.converter.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush は変換済みの本文をクライアントへフラッシュします。
+func (w *camelCaseResponseWriter) Flush() {
+	if err := http.NewResponseController(w.ResponseWriter).Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		log.Printf("Error flushing response: %v", err)
+	}
+}
+
+// Unwrap はhttp.ResponseControllerのために元のResponseWriterを返します。
+func (w *camelCaseResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// finish は変換途中で残った本文（閉じられていないキーなど）を書き込みます。
+func (w *camelCaseResponseWriter) finish() {
+	if w.converter == nil {
+		return
+	}
+	if err := w.converter.Close(); err != nil {
+		log.Printf("Error writing response: %v", err)
+	}
+}
+
+// isJSONContentType はContent-TypeがJSONかどうかを判定します。
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "application/json"
+}
+
+// camelCaseWriter は書き込まれたJSONのオブジェクトのキーをcamelCaseに変換してwに書き込むio.Writerです。
+// バイト単位で字句を追跡し、キー以外はそのまま書き込むため、空白や値の表現、キーの順序は変わりません。
+// 書き込みの途中で分割されたキーだけを次の書き込みまで保持し、改行で区切られた複数の値にも対応します。
+type camelCaseWriter struct {
+	w      io.Writer
+	stack  []bool // 入れ子ごとにオブジェクト（true）か配列（false）か
+	expect bool   // 次の文字列がオブジェクトのキーか
+	str    bool   // 文字列の中か
+	escape bool   // 文字列の中でバックスラッシュの直後か
+	key    []byte // 読み込み中のキー（キーの中でない場合はnil）
+}
+
+// Write はpのうちキー以外をそのまま、閉じたキーを変換して書き込みます。
+func (c *camelCaseWriter) Write(p []byte) (int, error) {
+	var out []byte
+	for _, b := range p {
+		if c.str {
+			if c.key != nil {
+				c.key = append(c.key, b)
+			} else {
+				out = append(out, b)
+			}
+			switch {
+			case c.escape:
+				c.escape = false
+			case b == '\\':
+				c.escape = true
+			case b == '"':
+				c.str = false
+				if c.key != nil {
+					// c.keyは開きと閉じの引用符を含む
+					out = append(out, '"')
+					out = append(out, snakeToCamel(string(c.key[1:len(c.key)-1]))...)
+					out = append(out, '"')
+					c.key = nil
+				}
+			}
+			continue
+		}
+
+		switch b {
+		case '"':
+			c.str = true
+			if c.expect {
+				c.expect = false
+				c.key = []byte{b}
+				continue
+			}
+		case '{':
+			c.stack = append(c.stack, true)
+			c.expect = true
+		case '[':
+			c.stack = append(c.stack, false)
+		case '}', ']':
+			if len(c.stack) > 0 {
+				c.stack = c.stack[:len(c.stack)-1]
+			}
+			c.expect = false
+		case ',':
+			c.expect = len(c.stack) > 0 && c.stack[len(c.stack)-1]
+		}
+		out = append(out, b)
+	}
+	if _, err := c.w.Write(out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close は閉じられていないキーが残っている場合にそのまま書き込みます。
+func (c *camelCaseWriter) Close() error {
+	if c.key == nil {
+		return nil
+	}
+	_, err := c.w.Write(c.key)
+	c.key = nil
+	return err
+}
+
+// snakeToCamel はsnake_caseの名前をcamelCaseに変換します（例: project_id → projectId）。
+func snakeToCamel(name string) string {
+	parts := strings.Split(name, "_")
+	var b strings.Builder
+	b.WriteString(parts[0])
+	for _, part := range parts[1:] {
+		if part == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]))
+		b.WriteString(part[1:])
+	}
+	return b.String()
+}
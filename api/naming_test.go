@@ -0,0 +1,121 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stsysd/sougen/config"
+	"github.com/stsysd/sougen/model"
+)
+
+func TestJSONNaming(t *testing.T) {
+	mockStore := NewMockStore()
+	project, _ := model.NewProject("naming-project", "")
+	mockStore.CreateProject(context.Background(), project)
+	record, _ := model.NewRecord(time.Date(2025, 5, 21, 10, 0, 0, 0, time.UTC), project.ID, 1, []string{"work"})
+	mockStore.CreateRecord(context.Background(), record)
+
+	get := func(server *Server, target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set("X-API-Key", testAPIKey)
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		return w
+	}
+	recordURL := fmt.Sprintf("/api/v0/r/%s", record.ID)
+
+	server := NewServer(mockStore, newTestConfig())
+
+	// 既定はsnake_case
+	w := get(server, recordURL)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if body := w.Body.String(); !strings.Contains(body, `"project_id":`) || strings.Contains(body, `"projectId":`) {
+		t.Errorf("Expected snake_case keys by default, got %s", body)
+	}
+
+	// naming=camelではcamelCase（値とキーの順序はそのまま）
+	w = get(server, recordURL+"?naming=camel")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, fmt.Sprintf(`"projectId":"%s"`, project.ID)) || strings.Contains(body, `"project_id":`) {
+		t.Errorf("Expected camelCase keys, got %s", body)
+	}
+	if !strings.HasPrefix(body, fmt.Sprintf(`{"id":"%s",`, record.ID)) || !strings.Contains(body, `"tags":["work"]`) {
+		t.Errorf("Expected the original field order and values, got %s", body)
+	}
+
+	// pretty=trueと組み合わせるとインデントされる
+	w = get(server, recordURL+"?naming=camel&pretty=true")
+	if body := w.Body.String(); !strings.HasPrefix(body, "{\n  \"id\": ") || !strings.Contains(body, "\n  \"projectId\": ") {
+		t.Errorf("Expected indented camelCase JSON, got %q", body)
+	}
+
+	// JSON以外のレスポンスはそのまま
+	w = get(server, fmt.Sprintf("/p/%s/graph.svg?naming=camel", project.ID))
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Body.String(), "<svg") {
+		t.Errorf("Expected an unchanged SVG, got %d: %.40s", w.Code, w.Body.String())
+	}
+
+	// 不正な値は400
+	if w := get(server, recordURL+"?naming=kebab"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d for an invalid naming, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	// 設定で既定をcamelにでき、naming=snakeで上書きできる
+	cfg := newTestConfig()
+	cfg.JSONNaming = config.JSONNamingCamel
+	camelServer := NewServer(mockStore, cfg)
+	if body := get(camelServer, recordURL).Body.String(); !strings.Contains(body, `"projectId":`) {
+		t.Errorf("Expected camelCase keys from the config, got %s", body)
+	}
+	if body := get(camelServer, recordURL+"?naming=snake").Body.String(); !strings.Contains(body, `"project_id":`) {
+		t.Errorf("Expected snake_case keys with naming=snake, got %s", body)
+	}
+}
+
+func TestSnakeToCamel(t *testing.T) {
+	tests := map[string]string{
+		"id":              "id",
+		"project_id":      "projectId",
+		"first_record_at": "firstRecordAt",
+		"schemaVersion":   "schemaVersion",
+	}
+	for input, want := range tests {
+		if got := snakeToCamel(input); got != want {
+			t.Errorf("snakeToCamel(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestCamelCaseWriter(t *testing.T) {
+	input := `{"project_id":"a_b","tags":["x_y"],"nested_obj":{"first_record_at":"2025-01-01","esc\"_key":1},"list":[{"value_float":1.5}]}` + "\n" +
+		`{"created_at":"<&>"}` + "\n"
+	want := `{"projectId":"a_b","tags":["x_y"],"nestedObj":{"firstRecordAt":"2025-01-01","esc\"Key":1},"list":[{"valueFloat":1.5}]}` + "\n" +
+		`{"createdAt":"<&>"}` + "\n"
+
+	// 書き込みの境界がキーや値の途中にあっても同じ結果になる
+	for _, size := range []int{1, 3, 7, len(input)} {
+		var out strings.Builder
+		c := &camelCaseWriter{w: &out}
+		for i := 0; i < len(input); i += size {
+			if _, err := c.Write([]byte(input[i:min(i+size, len(input))])); err != nil {
+				t.Fatalf("Write failed: %v", err)
+			}
+		}
+		if err := c.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+		if got := out.String(); got != want {
+			t.Errorf("With writes of %d bytes, expected %s, got %s", size, want, got)
+		}
+	}
+}
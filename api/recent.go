@@ -0,0 +1,58 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/stsysd/sougen/model"
+)
+
+const (
+	defaultRecentRecords = 20
+	maxRecentRecords     = 100
+)
+
+// RecentRecordsParams represents parameters for the cross-project recent activity feed.
+type RecentRecordsParams struct {
+	N int // 返すレコード数
+}
+
+// NewRecentRecordsParams creates parameters for the recent activity feed from HTTP request.
+func NewRecentRecordsParams(r *http.Request) (*RecentRecordsParams, error) {
+	n, err := parsePositiveIntQuery(r.URL.Query(), "n")
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		n = defaultRecentRecords
+	}
+	if n > maxRecentRecords {
+		return nil, fmt.Errorf("invalid n parameter: must be at most %d", maxRecentRecords)
+	}
+	return &RecentRecordsParams{N: n}, nil
+}
+
+// handleListRecentRecords はすべてのプロジェクトのレコードを新しい順に返すハンドラーです。
+// ホームのダッシュボードの最近のアクティビティを想定しており、各レコードのproject_idでプロジェクトを区別します。
+func (s *Server) handleListRecentRecords(w http.ResponseWriter, r *http.Request) {
+	// パラメータを検証
+	params, err := NewRecentRecordsParams(r)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	records, err := s.store.ListRecentRecords(r.Context(), params.N)
+	if err != nil {
+		log.Printf("Error retrieving recent records: %v", err)
+		writeJSONError(w, "Failed to retrieve records", http.StatusInternalServerError)
+		return
+	}
+	// 空配列を返すためにnilチェック
+	if records == nil {
+		records = []*model.Record{}
+	}
+
+	writeJSON(w, r, http.StatusOK, records)
+}
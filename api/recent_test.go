@@ -0,0 +1,87 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stsysd/sougen/model"
+)
+
+func TestListRecentRecords(t *testing.T) {
+	mockStore := NewMockStore()
+	server := NewServer(mockStore, newTestConfig())
+
+	work, _ := model.NewProject("work", "")
+	mockStore.CreateProject(context.Background(), work)
+	home, _ := model.NewProject("home", "")
+	mockStore.CreateProject(context.Background(), home)
+
+	baseTime := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	for i, projectID := range []model.HexID{work.ID, home.ID, work.ID, home.ID} {
+		record, _ := model.NewRecord(baseTime.Add(time.Duration(i)*time.Hour), projectID, i+1, nil)
+		mockStore.CreateRecord(context.Background(), record)
+	}
+
+	get := func(target string, apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if apiKey != "" {
+			req.Header.Set("X-API-Key", apiKey)
+		}
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		return w
+	}
+
+	// 新しい順に両方のプロジェクトのレコードを返す
+	w := get("/api/v0/r/recent?n=3", testAPIKey)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var records []*model.Record
+	if err := json.NewDecoder(w.Body).Decode(&records); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	expected := []struct {
+		value     int
+		projectID model.HexID
+	}{{4, home.ID}, {3, work.ID}, {2, home.ID}}
+	if len(records) != len(expected) {
+		t.Fatalf("Expected %d records, got %d", len(expected), len(records))
+	}
+	for i, e := range expected {
+		if records[i].Value != e.value || !records[i].ProjectID.Equals(e.projectID) {
+			t.Errorf("Expected record %d to have value %d in project %s, got %+v", i, e.value, e.projectID, records[i])
+		}
+	}
+
+	// nの既定値は20件（ここでは全件）
+	w = get("/api/v0/r/recent", testAPIKey)
+	if err := json.NewDecoder(w.Body).Decode(&records); err != nil || len(records) != 4 {
+		t.Errorf("Expected all 4 records by default, got %d (%v)", len(records), err)
+	}
+
+	// 不正なnは400
+	for _, n := range []string{"0", "abc", "101"} {
+		if w := get("/api/v0/r/recent?n="+n, testAPIKey); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for n=%s, got %d", http.StatusBadRequest, n, w.Code)
+		}
+	}
+
+	// HEADはレコードの存在確認ではなく一覧として扱う
+	req := httptest.NewRequest(http.MethodHead, "/api/v0/r/recent", nil)
+	req.Header.Set("X-API-Key", testAPIKey)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status %d for HEAD, got %d", http.StatusOK, w.Code)
+	}
+
+	// APIキーが必要
+	if w := get("/api/v0/r/recent", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d without an API key, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
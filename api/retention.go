@@ -0,0 +1,51 @@
+// Package api はsougenのAPIサーバー実装を提供します。
+package api
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// sweepRetention は保持日数が設定されたプロジェクトについて、保持期間を過ぎたレコードを削除します。
+// 1つのプロジェクトで失敗しても残りのプロジェクトの削除は続行し、削除したレコードの総数を返します。
+func (s *Server) sweepRetention(ctx context.Context, now time.Time) (int, error) {
+	policies, err := s.store.ListRetentionPolicies(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for _, policy := range policies {
+		until := now.AddDate(0, 0, -policy.RetentionDays)
+		count, err := s.store.DeleteRecordsUntil(ctx, policy.ProjectID, until)
+		if err != nil {
+			log.Printf("Error deleting expired records of project %s: %v", policy.ProjectID, err)
+			continue
+		}
+		if count > 0 {
+			s.graphCache.invalidateProject(policy.ProjectID)
+			total += count
+		}
+	}
+	return total, nil
+}
+
+// runRetentionSweeper はctxがキャンセルされるまでinterval間隔でsweepRetentionを実行します。
+func (s *Server) runRetentionSweeper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			count, err := s.sweepRetention(ctx, time.Now())
+			if err != nil {
+				log.Printf("Error sweeping expired records: %v", err)
+			} else if count > 0 {
+				log.Printf("Deleted %d expired records", count)
+			}
+		}
+	}
+}
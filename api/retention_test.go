@@ -0,0 +1,50 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stsysd/sougen/model"
+)
+
+func TestSweepRetention(t *testing.T) {
+	mockStore := NewMockStore()
+	server := NewServer(mockStore, newTestConfig())
+	ctx := context.Background()
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+
+	// 30日保持のプロジェクトと無期限のプロジェクト
+	retentionDays := 30
+	expiring, _ := model.NewProject("expiring", "")
+	expiring.RetentionDays = &retentionDays
+	mockStore.CreateProject(ctx, expiring)
+	forever, _ := model.NewProject("forever", "")
+	mockStore.CreateProject(ctx, forever)
+
+	newRecord := func(project *model.Project, daysAgo int) *model.Record {
+		record, _ := model.NewRecord(now.AddDate(0, 0, -daysAgo), project.ID, 1, nil)
+		mockStore.CreateRecord(ctx, record)
+		return record
+	}
+	expired := newRecord(expiring, 40)
+	kept := newRecord(expiring, 10)
+	old := newRecord(forever, 400)
+
+	count, err := server.sweepRetention(ctx, now)
+	if err != nil {
+		t.Fatalf("Failed to sweep: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 deleted record, got %d", count)
+	}
+	if _, err := mockStore.GetRecord(ctx, expired.ID); err == nil {
+		t.Error("Expected the expired record to be deleted")
+	}
+	if _, err := mockStore.GetRecord(ctx, kept.ID); err != nil {
+		t.Errorf("Expected the record within retention to remain: %v", err)
+	}
+	if _, err := mockStore.GetRecord(ctx, old.ID); err != nil {
+		t.Errorf("Expected the record of a project without retention to remain: %v", err)
+	}
+}
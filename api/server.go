@@ -2,13 +2,23 @@
 package api
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"iter"
 	"log"
+	"maps"
+	"net"
 	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/stsysd/sougen/config"
@@ -19,37 +29,132 @@ import (
 
 // Server はAPIサーバーの構造体です。
 type Server struct {
-	router *http.ServeMux
-	store  store.Store
-	config *config.Config
+	router     *http.ServeMux
+	store      store.Store
+	config     *config.Config
+	notifier   RecordNotifier
+	httpServer *http.Server
+	graphCache *graphCache   // nilの場合はキャッシュしない
+	inflight   chan struct{} // 処理中のリクエストのセマフォ（nilの場合は無制限）
+
+	sweeperCtx  context.Context    // 保持期間のスイーパーの実行コンテキスト
+	stopSweeper context.CancelFunc // Shutdown時にスイーパーを停止する
+	sweeperDone sync.WaitGroup
+}
+
+// RecordNotifier はレコード作成を外部に通知するインターフェースです。
+type RecordNotifier interface {
+	Notify(record *model.Record)
+}
+
+// SetNotifier はレコード作成時の通知先を設定します。
+func (s *Server) SetNotifier(n RecordNotifier) {
+	s.notifier = n
+}
+
+// notifyRecordCreated は通知先が設定されていればレコード作成を通知します。
+func (s *Server) notifyRecordCreated(record *model.Record) {
+	if s.notifier != nil {
+		s.notifier.Notify(record)
+	}
 }
 
 // ErrorResponse はエラーレスポンスの構造体です。
 type ErrorResponse struct {
 	Error string `json:"error"`
 	Code  int    `json:"code"`
+
+	// クライアントが対処を判断するためのエラーの種類（errorCodeInvalidCursorなど、該当しない場合は省略）
+	ErrorCode string `json:"error_code,omitempty"`
 }
 
+// ErrorResponse.ErrorCodeの値
+const (
+	// カーソルが壊れている・期限切れなどで使用できない（カーソルを破棄して最初のページから取得し直す）
+	errorCodeInvalidCursor = "invalid_cursor"
+	// グラフの期間内にレコードがない（on_empty=errorの場合）
+	errorCodeEmptyGraph = "empty_graph"
+)
+
+// errInvalidCursor はパラメータの作成時にカーソルが使用できなかったことを表します。
+var errInvalidCursor = errors.New("invalid cursor")
+
 // writeJSONError はJSON形式でエラーレスポンスを返却します。
 func writeJSONError(w http.ResponseWriter, message string, statusCode int) {
+	writeJSONErrorCode(w, message, "", statusCode)
+}
+
+// writeJSONErrorCode はエラーの種類（ErrorResponse.ErrorCode）を含めてJSON形式でエラーレスポンスを返却します。
+func writeJSONErrorCode(w http.ResponseWriter, message, errorCode string, statusCode int) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
 	resp := ErrorResponse{
 		Error: message,
 		Code:  statusCode,
+
+		ErrorCode: errorCode,
 	}
 	if err := json.NewEncoder(w).Encode(resp); err != nil {
 		log.Printf("Error encoding error response: %v", err)
 	}
 }
 
+// writeJSON はvをJSONとしてステータスコードstatusで書き込みます。
+// クエリパラメータpretty=trueが指定されている場合は、手動での確認用にインデントして出力します。
+func writeJSON(w http.ResponseWriter, r *http.Request, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	if pretty, _ := parseBoolQuery(r.URL.Query(), "pretty"); pretty {
+		encoder.SetIndent("", "  ")
+	}
+	if err := encoder.Encode(v); err != nil {
+		log.Printf("Error encoding response: %v", err)
+	}
+}
+
+// parseBoolQuery はクエリパラメータを真偽値として解釈します。
+// パラメータが指定されていない場合はfalseを返します。
+func parseBoolQuery(query url.Values, name string) (bool, error) {
+	v := query.Get(name)
+	if v == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s parameter: must be a boolean", name)
+	}
+	return b, nil
+}
+
+// parsePositiveIntQuery はクエリパラメータを正の整数として解釈します。
+// パラメータが指定されていない場合は0を返します。
+func parsePositiveIntQuery(query url.Values, name string) (int, error) {
+	v := query.Get(name)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid %s parameter: must be a positive integer", name)
+	}
+	return n, nil
+}
+
 // NewServer は新しいAPIサーバーインスタンスを生成します。
 func NewServer(store store.Store, config *config.Config) *Server {
 	s := &Server{
 		router: http.NewServeMux(),
 		store:  store,
 		config: config,
+
+		graphCache: newGraphCache(config.GraphCacheSize, config.GraphCacheTTL),
 	}
+	if config.MaxConcurrentRequests > 0 {
+		s.inflight = make(chan struct{}, config.MaxConcurrentRequests)
+	}
+	s.sweeperCtx, s.stopSweeper = context.WithCancel(context.Background())
+	s.httpServer = &http.Server{Handler: s}
 	s.routes()
 	return s
 }
@@ -57,52 +162,106 @@ func NewServer(store store.Store, config *config.Config) *Server {
 // routes はAPIエンドポイントのルーティングを設定します。
 func (s *Server) routes() {
 	// ヘルスチェックエンドポイントは認証不要
-	s.router.HandleFunc("GET /healthz", s.handleHealthCheck)
+	s.router.HandleFunc(s.route("GET /healthz"), s.handleHealthCheck)
+	// テーマの一覧は静的なため認証不要
+	s.router.HandleFunc(s.route("GET /api/v0/themes"), s.handleListThemes)
 
 	// すべての保護されたエンドポイントをまずセキュアなルータに登録
 	securedHandler := http.NewServeMux()
 
+	// Version endpoint
+	securedHandler.HandleFunc(s.route("GET /api/v0/version"), s.handleVersion)
+
+	// APIキーの確認（接続テスト用、データベースにはアクセスしない）
+	securedHandler.HandleFunc(s.route("GET /api/v0/auth/check"), s.handleAuthCheck)
+
 	// Project endpoints
-	securedHandler.HandleFunc("GET /api/v0/p", s.handleListProjects)
-	securedHandler.HandleFunc("POST /api/v0/p", s.handleCreateProject)
-	securedHandler.HandleFunc("GET /api/v0/p/{project_id}", s.handleGetProject)
-	securedHandler.HandleFunc("PUT /api/v0/p/{project_id}", s.handleUpdateProject)
-	securedHandler.HandleFunc("DELETE /api/v0/p/{project_id}", s.handleDeleteProject)
+	securedHandler.HandleFunc(s.route("GET /api/v0/p"), s.handleListProjects)
+	securedHandler.HandleFunc(s.route("POST /api/v0/p"), s.handleCreateProject)
+	securedHandler.HandleFunc(s.route("GET /api/v0/p/{project_id}"), s.handleGetProject)
+	securedHandler.HandleFunc(s.route("PUT /api/v0/p/{project_id}"), s.handleUpdateProject)
+	securedHandler.HandleFunc(s.route("DELETE /api/v0/p/{project_id}"), s.handleDeleteProject)
+	securedHandler.HandleFunc(s.route("POST /api/v0/p/{project_id}/clone"), s.handleCloneProject)
 
 	// Record endpoints
-	securedHandler.HandleFunc("POST /api/v0/r", s.handleCreateRecord)
-	securedHandler.HandleFunc("GET /api/v0/r", s.handleListRecords)
-	securedHandler.HandleFunc("GET /api/v0/r/{record_id}", s.handleGetRecord)
-	securedHandler.HandleFunc("PUT /api/v0/r/{record_id}", s.handleUpdateRecord)
-	securedHandler.HandleFunc("DELETE /api/v0/r/{record_id}", s.handleDeleteRecord)
+	securedHandler.HandleFunc(s.route("POST /api/v0/r"), s.handleCreateRecord)
+	securedHandler.HandleFunc(s.route("GET /api/v0/r"), s.handleListRecords)
+	securedHandler.HandleFunc(s.route("GET /api/v0/r/{record_id}"), s.handleGetRecord)     // GET /api/v0/r/recentも含む
+	securedHandler.HandleFunc(s.route("HEAD /api/v0/r/{record_id}"), s.handleRecordExists) // HEAD /api/v0/r/recentも含む
+	securedHandler.HandleFunc(s.route("PUT /api/v0/r/{record_id}"), s.handleUpdateRecord)
+	securedHandler.HandleFunc(s.route("DELETE /api/v0/r/{record_id}"), s.handleDeleteRecord)
+	securedHandler.HandleFunc(s.route("POST /api/v0/r/{record_id}/restore"), s.handleRestoreRecord)
 
-	securedHandler.HandleFunc("POST /api/v0/bulk-deletion", s.handleBulkDeleteRecords)
+	securedHandler.HandleFunc(s.route("POST /api/v0/bulk-deletion"), s.handleBulkDeleteRecords)
+	securedHandler.HandleFunc(s.route("GET /api/v0/p/{project_id}/day/{date}"), s.handleGetDayRecords)
 
-	// Tag endpoints
-	securedHandler.HandleFunc("GET /api/v0/p/{project_id}/t", s.handleGetProjectTags)
+	// Integration endpoints
+	securedHandler.HandleFunc(s.route("POST /api/v0/p/{project_id}/ingest/github"), s.handleIngestGitHubPush)
 
-	// 認証ミドルウェアを適用し、メインルータにマウント
-	s.router.Handle("/api/", s.authMiddleware(securedHandler))
+	// Tag endpoints
+	securedHandler.HandleFunc(s.route("GET /api/v0/p/{project_id}/t"), s.handleGetProjectTags)
+	securedHandler.HandleFunc(s.route("POST /api/v0/p/{project_id}/records/shift"), s.handleShiftRecords)
+	securedHandler.HandleFunc(s.route("POST /api/v0/p/{project_id}/t/add"), s.handleAddTag)
+	securedHandler.HandleFunc(s.route("GET /api/v0/p/{project_id}/t/aliases"), s.handleListTagAliases)
+	securedHandler.HandleFunc(s.route("PUT /api/v0/p/{project_id}/t/aliases/{alias}"), s.handleSetTagAlias)
+	securedHandler.HandleFunc(s.route("DELETE /api/v0/p/{project_id}/t/aliases/{alias}"), s.handleDeleteTagAlias)
+
+	// Stats endpoints
+	securedHandler.HandleFunc(s.route("GET /api/v0/p/{project_id}/compare"), s.handleCompare)
+	securedHandler.HandleFunc(s.route("GET /api/v0/p/{project_id}/tag-breakdown"), s.handleTagBreakdown)
+	securedHandler.HandleFunc(s.route("GET /api/v0/p/{project_id}/insights"), s.handleInsights)
+	securedHandler.HandleFunc(s.route("GET /api/v0/p/{project_id}/histogram"), s.handleHistogram)
+	securedHandler.HandleFunc(s.route("GET /api/v0/p/{project_id}/top-days"), s.handleTopDays)
+	securedHandler.HandleFunc(s.route("GET /api/v0/p/{project_id}/graph/legend"), s.handleGetGraphLegend)
+
+	// Maintenance endpoints
+	securedHandler.HandleFunc(s.route("POST /api/v0/maintenance/prune-tags"), s.handlePruneTags)
+	securedHandler.HandleFunc(s.route("POST /api/v0/maintenance/vacuum"), s.handleVacuum)
+
+	// 認証ミドルウェア、DBタイムアウト、タグ数の上限を適用し、メインルータにマウント
+	s.router.Handle(s.route("/api/"), s.authMiddleware(s.dbTimeoutMiddleware(s.tagFilterLimitMiddleware(jsonMuxErrors(securedHandler)))))
+
+	// エクスポートとiCalendarは全件をストリーミングするため、DBタイムアウトを適用しない
+	s.router.Handle(s.route("GET /api/v0/p/{project_id}/export"), s.authMiddleware(s.tagFilterLimitMiddleware(http.HandlerFunc(s.handleExportRecords))))
+	s.router.Handle(s.route("GET /api/v0/p/{project_id}/records.ics"), s.authMiddleware(s.tagFilterLimitMiddleware(http.HandlerFunc(s.handleRecordsICalendar))))
 
 	// Graph endpoints - support both with and without .svg extension
-	s.router.HandleFunc("GET /p/{project_id}/graph.svg", s.handleGetGraph)
-	s.router.HandleFunc("GET /p/{project_id}/graph", s.handleGetGraph)
+	// 署名の秘密鍵が設定されている場合は署名またはAPIキーで認証する
+	graphHandler := s.graphAuthMiddleware(s.dbTimeoutMiddleware(s.tagFilterLimitMiddleware(http.HandlerFunc(s.handleGetGraph))))
+	// 拡張子のないパスはAcceptヘッダーで出力形式（SVG・JSON・PNG）を選ぶ
+	s.router.Handle(s.route("GET /p/{project_id}/graph.svg"), graphHandler)
+	s.router.Handle(s.route("GET /p/{project_id}/graph.json"), graphHandler)
+	s.router.Handle(s.route("GET /p/{project_id}/graph.png"), graphHandler)
+	s.router.Handle(s.route("GET /p/{project_id}/graph"), graphHandler)
+
+	// GETのみ送信できるクライアント向けの記録（グラフのtrackと同じく、署名の秘密鍵がなければ公開）
+	trackHandler := s.trackAuthMiddleware(s.dbTimeoutMiddleware(http.HandlerFunc(s.handleTrack)))
+	s.router.Handle(s.route("GET /p/{project_id}/track"), trackHandler)
+	s.router.Handle(s.route("GET /p/{project_id}/track/{value}"), trackHandler)
+
+	// shields.ioのバッジはグラフと同様に埋め込むため、グラフと同じ認証（署名の秘密鍵がなければ公開）を適用する
+	s.router.Handle(s.route("GET /api/v0/p/{project_id}/badge.json"), s.graphAuthMiddleware(s.dbTimeoutMiddleware(s.tagFilterLimitMiddleware(http.HandlerFunc(s.handleBadge)))))
+}
+
+// route はルーティングのパターンのパスにconfig.BasePathを付加します。
+// "GET /p"のようにメソッドを含むパターンにも対応します。
+func (s *Server) route(pattern string) string {
+	method, path, ok := strings.Cut(pattern, " ")
+	if !ok {
+		return s.config.BasePath + pattern
+	}
+	return method + " " + s.config.BasePath + path
 }
 
 // ServeHTTP はServer構造体をhttp.Handlerとして実装します。
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	// routesに設定されたルーティングを使用する
-	s.router.ServeHTTP(w, r)
+	// routesに設定されたルーティングを使用する（マッチしない場合はJSONのエラー）
+	s.jsonNamingMiddleware(s.concurrencyLimitMiddleware(jsonMuxErrors(s.router))).ServeHTTP(w, r)
 }
 
 // handleHealthCheck はヘルスチェックエンドポイントのハンドラーです。
 func (s *Server) handleHealthCheck(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	resp := map[string]string{"status": "ok"}
-	if err := json.NewEncoder(w).Encode(resp); err != nil {
-		log.Printf("Error encoding response: %v", err)
-	}
+	writeJSON(w, r, http.StatusOK, map[string]string{"status": "ok"})
 }
 
 // CreateRecordParams represents parameters for creating a record.
@@ -111,8 +270,19 @@ type CreateRecordParams struct {
 	Timestamp *model.Timestamp
 	Value     *model.Value
 	Tags      []string
+	Source    string
+
+	ValueFloat   *float64 // 小数の記録値（value_typeがfloatのプロジェクトのみ）
+	UniquePerDay bool     // 同じ日・同じタグの集合のレコードがあれば作成せずにそれを返すか
+	ExternalID   *string  // インポート元の外部システムでのID（同じIDのレコードがあれば作成せずにそれを返す）
 }
 
+// maxRecordSourceLength はクライアントが指定できる作成元の最大文字数です。
+const maxRecordSourceLength = 64
+
+// maxRecordExternalIDLength はクライアントが指定できる外部IDの最大文字数です。
+const maxRecordExternalIDLength = 255
+
 // NewCreateRecordParams creates parameters for record creation from HTTP request.
 func NewCreateRecordParams(r *http.Request) (*CreateRecordParams, error) {
 	// Parse request body
@@ -121,9 +291,24 @@ func NewCreateRecordParams(r *http.Request) (*CreateRecordParams, error) {
 		Timestamp string      `json:"timestamp"`
 		Value     *int        `json:"value"`
 		Tags      []string    `json:"tags"`
+		Source    string      `json:"source"`
+
+		ValueFloat    *float64 `json:"value_float"`
+		TimestampUnix *int64   `json:"timestamp_unix"` // Unixエポック（秒またはミリ秒）
+		ExternalID    *string  `json:"external_id"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+
+	// スキーマ検証（すべての違反をまとめて報告）
+	if err := validateBody(createRecordSchema, body); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(body, &requestBody); err != nil {
 		return nil, fmt.Errorf("invalid request body: %w", err)
 	}
 
@@ -131,26 +316,80 @@ func NewCreateRecordParams(r *http.Request) (*CreateRecordParams, error) {
 		return nil, fmt.Errorf("project_id is required")
 	}
 
-	timestamp, err := model.NewTimestamp(requestBody.Timestamp)
+	var timestamp *model.Timestamp
+	if requestBody.TimestampUnix != nil {
+		if requestBody.Timestamp != "" {
+			return nil, fmt.Errorf("timestamp and timestamp_unix are mutually exclusive")
+		}
+		timestamp = model.NewTimestampFromUnix(*requestBody.TimestampUnix)
+	} else {
+		timestamp, err = model.NewTimestamp(requestBody.Timestamp)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if requestBody.Value != nil && requestBody.ValueFloat != nil {
+		return nil, fmt.Errorf("value and value_float are mutually exclusive")
+	}
+
+	value, err := model.NewValue(requestBody.Value)
 	if err != nil {
 		return nil, err
 	}
 
-	value, err := model.NewValue(requestBody.Value)
+	// 作成元の指定がなければAPIとして扱う
+	source := strings.TrimSpace(requestBody.Source)
+	if source == "" {
+		source = model.RecordSourceAPI
+	}
+	if len(source) > maxRecordSourceLength {
+		return nil, fmt.Errorf("source must be at most %d characters", maxRecordSourceLength)
+	}
+
+	uniquePerDay, err := parseBoolQuery(r.URL.Query(), "unique_per_day")
 	if err != nil {
 		return nil, err
 	}
 
+	// 外部IDは前後の空白を除き、空の場合は指定なしとして扱う
+	var externalID *string
+	if requestBody.ExternalID != nil {
+		if id := strings.TrimSpace(*requestBody.ExternalID); id != "" {
+			if len(id) > maxRecordExternalIDLength {
+				return nil, fmt.Errorf("external_id must be at most %d characters", maxRecordExternalIDLength)
+			}
+			externalID = &id
+		}
+	}
+	if externalID != nil && uniquePerDay {
+		return nil, fmt.Errorf("external_id and unique_per_day are mutually exclusive")
+	}
+
 	return &CreateRecordParams{
 		ProjectID: requestBody.ProjectID,
 		Timestamp: timestamp,
 		Value:     value,
 		Tags:      requestBody.Tags,
+		Source:    source,
+
+		ValueFloat:   requestBody.ValueFloat,
+		UniquePerDay: uniquePerDay,
+		ExternalID:   externalID,
 	}, nil
 }
 
+// errValueFloatNotAllowed は整数のプロジェクトにvalue_floatが指定された場合のエラーメッセージです。
+const errValueFloatNotAllowed = "value_float requires a project with value_type 'float'"
+
 // handleCreateRecord はレコード作成エンドポイントのハンドラーです。
 func (s *Server) handleCreateRecord(w http.ResponseWriter, r *http.Request) {
+	// templateクエリパラメータが指定されている場合はボディを変換
+	if err := s.applyRequestTemplate(r); err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	// パラメータを検証
 	params, err := NewCreateRecordParams(r)
 	if err != nil {
@@ -159,10 +398,18 @@ func (s *Server) handleCreateRecord(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// プロジェクトの存在確認
-	_, err = s.store.GetProject(r.Context(), params.ProjectID)
+	project, err := s.store.GetProject(r.Context(), params.ProjectID)
 	if err != nil {
 		log.Printf("Error getting project: %v", err)
-		writeJSONError(w, "Project not found", http.StatusNotFound)
+		if errors.Is(err, model.ErrProjectNotFound) {
+			writeJSONError(w, "Project not found", http.StatusNotFound)
+		} else {
+			writeJSONError(w, "Failed to retrieve project", http.StatusInternalServerError)
+		}
+		return
+	}
+	if params.ValueFloat != nil && project.RecordValueType() != model.ValueTypeFloat {
+		writeJSONError(w, errValueFloatNotAllowed, http.StatusBadRequest)
 		return
 	}
 
@@ -173,25 +420,83 @@ func (s *Server) handleCreateRecord(w http.ResponseWriter, r *http.Request) {
 		writeJSONError(w, "Failed to create record", http.StatusBadRequest)
 		return
 	}
+	record.Source = params.Source
+	record.ExternalID = params.ExternalID
+	if params.ValueFloat != nil {
+		record.SetValueFloat(*params.ValueFloat)
+		if err := record.Validate(); err != nil {
+			writeJSONError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if err := s.checkNewRecord(project, record); err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	// レコードの保存
-	if err := s.store.CreateRecord(r.Context(), record); err != nil {
+	// unique_per_dayの場合は、その日（プロジェクトのタイムゾーン）に同じタグの集合のレコードがあればそれを返す
+	if params.UniquePerDay {
+		saved, created, err := s.store.CreateRecordUniquePerDay(r.Context(), record, project.Location())
+		if err != nil {
+			log.Printf("Error creating record: %v", err)
+			writeJSONError(w, "Failed to create record", http.StatusInternalServerError)
+			return
+		}
+		if !created {
+			writeJSON(w, r, http.StatusOK, saved)
+			return
+		}
+		record = saved
+	} else if err := s.store.CreateRecord(r.Context(), record); err != nil {
+		// 同じ外部IDのレコードがあれば作成せずにそれを返す（再インポートを冪等にする）
+		if errors.Is(err, model.ErrExternalIDTaken) {
+			existing, err := s.store.GetRecordByExternalID(r.Context(), record.ProjectID, *record.ExternalID)
+			if err != nil {
+				log.Printf("Error retrieving record by external ID: %v", err)
+				writeJSONError(w, "Failed to create record", http.StatusInternalServerError)
+				return
+			}
+			writeJSON(w, r, http.StatusOK, existing)
+			return
+		}
+		// レコードの保存
 		log.Printf("Error creating record: %v", err)
 		writeJSONError(w, "Failed to create record", http.StatusInternalServerError)
 		return
 	}
+	s.graphCache.invalidateProject(record.ProjectID)
+	s.notifyRecordCreated(record)
 
 	// 成功レスポンスの返却
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	if err := json.NewEncoder(w).Encode(record); err != nil {
-		log.Printf("Error encoding response: %v", err)
+	writeJSON(w, r, http.StatusCreated, record)
+}
+
+// checkNewRecord は作成するレコードの値がプロジェクトに設定された下限・上限に収まり、
+// 日時が設定された範囲内にあるか確認します。
+func (s *Server) checkNewRecord(project *model.Project, record *model.Record) error {
+	if err := project.CheckValue(record.Amount()); err != nil {
+		return err
+	}
+	return s.checkRecordTimestamp(record.Timestamp)
+}
+
+// checkRecordTimestamp はレコードの日時が設定された範囲（config.MinTimestamp以降、
+// 現在時刻からconfig.MaxTimestampFutureまで）に収まるか確認します。
+func (s *Server) checkRecordTimestamp(t time.Time) error {
+	if earliest := s.config.MinTimestamp; !earliest.IsZero() && t.Before(earliest) {
+		return fmt.Errorf("timestamp must not be before %s", earliest.Format(time.RFC3339))
 	}
+	if future := s.config.MaxTimestampFuture; future > 0 && t.After(time.Now().Add(future)) {
+		return fmt.Errorf("timestamp must not be more than %s in the future", future)
+	}
+	return nil
 }
 
 // GetRecordParams represents parameters for getting a record.
 type GetRecordParams struct {
-	RecordID model.HexID
+	RecordID       model.HexID
+	IncludeDeleted bool // 論理削除されたレコードも取得するか
+	ExpandProject  bool // レスポンスにレコードのプロジェクトを含めるか（expand=project）
 }
 
 // NewGetRecordParams creates parameters for record retrieval from HTTP request.
@@ -201,13 +506,44 @@ func NewGetRecordParams(r *http.Request) (*GetRecordParams, error) {
 		return nil, err
 	}
 
+	includeDeleted, err := parseBoolQuery(r.URL.Query(), "include_deleted")
+	if err != nil {
+		return nil, err
+	}
+
+	// expandパラメータの検証（カンマ区切り、現在はprojectのみ）
+	expandProject := false
+	if expand := r.URL.Query().Get("expand"); expand != "" {
+		for _, name := range strings.Split(expand, ",") {
+			if strings.TrimSpace(name) != "project" {
+				return nil, fmt.Errorf("invalid expand parameter: %q (must be 'project')", name)
+			}
+			expandProject = true
+		}
+	}
+
 	return &GetRecordParams{
-		RecordID: recordID,
+		RecordID:       recordID,
+		IncludeDeleted: includeDeleted,
+		ExpandProject:  expandProject,
 	}, nil
 }
 
+// RecordResponse はレコードの取得のレスポンスです。
+// expand=projectの場合のみレコードのプロジェクトを含めます。
+type RecordResponse struct {
+	*model.Record
+	Project *model.Project `json:"project,omitempty"`
+}
+
 // handleGetRecord は特定のIDのレコードを取得するハンドラーです。
 func (s *Server) handleGetRecord(w http.ResponseWriter, r *http.Request) {
+	// GET /api/v0/r/recentはHEAD /api/v0/r/{record_id}とパターンが衝突して登録できないため、ここで振り分ける
+	if r.PathValue("record_id") == "recent" {
+		s.handleListRecentRecords(w, r)
+		return
+	}
+
 	// パラメータを検証
 	params, err := NewGetRecordParams(r)
 	if err != nil {
@@ -215,8 +551,12 @@ func (s *Server) handleGetRecord(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// レコードの取得
-	record, err := s.store.GetRecord(r.Context(), params.RecordID)
+	// レコードの取得（include_deleted=trueの場合は論理削除されたレコードも対象）
+	getRecord := s.store.GetRecord
+	if params.IncludeDeleted {
+		getRecord = s.store.GetRecordIncludingDeleted
+	}
+	record, err := getRecord(r.Context(), params.RecordID)
 	if err != nil {
 		if errors.Is(err, model.ErrRecordNotFound) {
 			writeJSONError(w, "Record not found", http.StatusNotFound)
@@ -227,11 +567,48 @@ func (s *Server) handleGetRecord(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// expand=projectの場合のみプロジェクトを取得
+	response := RecordResponse{Record: record}
+	if params.ExpandProject {
+		response.Project, err = s.store.GetProject(r.Context(), record.ProjectID)
+		if err != nil {
+			log.Printf("Error retrieving project: %v", err)
+			writeJSONError(w, "Failed to retrieve project", http.StatusInternalServerError)
+			return
+		}
+	}
+
 	// レスポンスの返却
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(record); err != nil {
-		log.Printf("Error encoding response: %v", err)
+	writeJSON(w, r, http.StatusOK, response)
+}
+
+// handleRecordExists はレコードの存在のみを確認するハンドラーです（HEAD）。
+// 存在する場合は200、存在しない場合は404をボディなしで返します。
+func (s *Server) handleRecordExists(w http.ResponseWriter, r *http.Request) {
+	// HEAD /api/v0/r/recentはGETと同じく最近のレコードの一覧として扱う（本文はサーバーが送信しない）
+	if r.PathValue("record_id") == "recent" {
+		s.handleListRecentRecords(w, r)
+		return
+	}
+
+	// パラメータを検証
+	params, err := NewGetRecordParams(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	exists, err := s.store.RecordExists(r.Context(), params.RecordID)
+	if err != nil {
+		log.Printf("Error checking record existence: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if !exists {
+		w.WriteHeader(http.StatusNotFound)
+		return
 	}
+	w.WriteHeader(http.StatusOK)
 }
 
 // UpdateRecordParams represents parameters for updating a record.
@@ -239,7 +616,10 @@ type UpdateRecordParams struct {
 	RecordID  model.HexID
 	Timestamp *model.Timestamp
 	Value     *model.Value
+	Increment *int // 既存の値に加算する量（Valueとは排他）
 	Tags      []string
+
+	ValueFloat *float64 // 小数の記録値（Value・Incrementとは排他）
 }
 
 // NewUpdateRecordParams creates parameters for record update from HTTP request.
@@ -253,13 +633,23 @@ func NewUpdateRecordParams(r *http.Request) (*UpdateRecordParams, error) {
 	var requestBody struct {
 		Timestamp *string  `json:"timestamp"`
 		Value     *int     `json:"value"`
+		Increment *int     `json:"increment"`
 		Tags      []string `json:"tags"`
+
+		ValueFloat *float64 `json:"value_float"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
 		return nil, fmt.Errorf("invalid request body: %w", err)
 	}
 
+	if requestBody.Value != nil && requestBody.Increment != nil {
+		return nil, fmt.Errorf("value and increment are mutually exclusive")
+	}
+	if requestBody.ValueFloat != nil && (requestBody.Value != nil || requestBody.Increment != nil) {
+		return nil, fmt.Errorf("value_float cannot be combined with value or increment")
+	}
+
 	var timestamp *model.Timestamp
 	if requestBody.Timestamp != nil {
 		timestamp, err = model.NewTimestamp(*requestBody.Timestamp)
@@ -280,7 +670,10 @@ func NewUpdateRecordParams(r *http.Request) (*UpdateRecordParams, error) {
 		RecordID:  recordID,
 		Timestamp: timestamp,
 		Value:     value,
+		Increment: requestBody.Increment,
 		Tags:      requestBody.Tags,
+
+		ValueFloat: requestBody.ValueFloat,
 	}, nil
 }
 
@@ -310,12 +703,38 @@ func (s *Server) handleUpdateRecord(w http.ResponseWriter, r *http.Request) {
 
 	// timestampの更新（指定されている場合）
 	if params.Timestamp != nil {
+		if err := s.checkRecordTimestamp(params.Timestamp.Time()); err != nil {
+			writeJSONError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		updatedRecord.Timestamp = params.Timestamp.Time()
 	}
 
 	// valueの更新（指定されている場合）
+	// 整数の値を指定した場合は小数の記録値を解除
 	if params.Value != nil {
 		updatedRecord.Value = params.Value.Int()
+		updatedRecord.ValueFloat = nil
+	}
+
+	// 値を変更する場合はプロジェクトの設定（値の種類・下限・上限）を参照
+	var project *model.Project
+	if params.Value != nil || params.ValueFloat != nil || params.Increment != nil {
+		project, err = s.store.GetProject(r.Context(), existingRecord.ProjectID)
+		if err != nil {
+			log.Printf("Error getting project: %v", err)
+			writeJSONError(w, "Failed to retrieve project", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	// value_floatの更新（value_typeがfloatのプロジェクトのみ）
+	if params.ValueFloat != nil {
+		if project.RecordValueType() != model.ValueTypeFloat {
+			writeJSONError(w, errValueFloatNotAllowed, http.StatusBadRequest)
+			return
+		}
+		updatedRecord.SetValueFloat(*params.ValueFloat)
 	}
 
 	// tagsの更新（JSONで明示的に指定されている場合のみ）
@@ -324,33 +743,62 @@ func (s *Server) handleUpdateRecord(w http.ResponseWriter, r *http.Request) {
 		updatedRecord.Tags = params.Tags
 	}
 
-	// レコードの更新
-	if err := s.store.UpdateRecord(r.Context(), &updatedRecord); err != nil {
-		if errors.Is(err, model.ErrRecordNotFound) {
-			writeJSONError(w, "Record not found", http.StatusNotFound)
-		} else {
-			var validationErr *model.ValidationError
-			if errors.As(err, &validationErr) {
-				// バリデーションエラーの場合は400を返す
-				writeJSONError(w, err.Error(), http.StatusBadRequest)
-			} else {
-				log.Printf("Error updating record: %v", err)
-				writeJSONError(w, "Failed to update record", http.StatusInternalServerError)
-			}
+	// incrementは整数の値のみを加算するため、小数の記録値を持つレコードには使用できない
+	if params.Increment != nil && existingRecord.ValueFloat != nil {
+		writeJSONError(w, "increment is not supported for records with value_float", http.StatusBadRequest)
+		return
+	}
+
+	// 更新後の値がプロジェクトに設定された下限・上限に収まるか確認
+	// incrementの場合は加算と同じ書き込みの中で検証する（読み込んだ値は古い可能性があるため）
+	if project != nil && params.Increment == nil {
+		if err := project.CheckValue(updatedRecord.Amount()); err != nil {
+			writeJSONError(w, err.Error(), http.StatusBadRequest)
+			return
 		}
+	}
+
+	switch {
+	case params.Increment == nil:
+		err = s.store.UpdateRecord(r.Context(), &updatedRecord)
+	case params.Timestamp != nil || params.Tags != nil:
+		// 他の項目の更新と値の加算を1つの書き込みで行う
+		err = s.store.UpdateRecordWithIncrement(r.Context(), &updatedRecord, *params.Increment, project)
+	default:
+		// incrementのみの場合は値をアトミックに加算
+		updatedRecord.Value, err = s.store.IncrementRecordValue(r.Context(), params.RecordID, *params.Increment, project)
+	}
+	if err != nil {
+		writeUpdateRecordError(w, err)
 		return
 	}
+	s.graphCache.invalidateProject(existingRecord.ProjectID)
+	s.graphCache.invalidateProject(updatedRecord.ProjectID)
 
 	// 更新成功のレスポンスを返却
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(&updatedRecord); err != nil {
-		log.Printf("Error encoding response: %v", err)
+	writeJSON(w, r, http.StatusOK, &updatedRecord)
+}
+
+// writeUpdateRecordError はレコード更新時のエラーをレスポンスに変換します。
+func writeUpdateRecordError(w http.ResponseWriter, err error) {
+	if errors.Is(err, model.ErrRecordNotFound) {
+		writeJSONError(w, "Record not found", http.StatusNotFound)
+		return
+	}
+	var validationErr *model.ValidationError
+	if errors.As(err, &validationErr) {
+		// バリデーションエラーの場合は400を返す
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
 	}
+	log.Printf("Error updating record: %v", err)
+	writeJSONError(w, "Failed to update record", http.StatusInternalServerError)
 }
 
 // DeleteRecordParams represents parameters for deleting a record.
 type DeleteRecordParams struct {
 	RecordID model.HexID
+	Hard     bool // 論理削除ではなく完全に削除するか
 }
 
 // NewDeleteRecordParams creates parameters for record deletion from HTTP request.
@@ -360,12 +808,19 @@ func NewDeleteRecordParams(r *http.Request) (*DeleteRecordParams, error) {
 		return nil, err
 	}
 
+	hard, err := parseBoolQuery(r.URL.Query(), "hard")
+	if err != nil {
+		return nil, err
+	}
+
 	return &DeleteRecordParams{
 		RecordID: recordID,
+		Hard:     hard,
 	}, nil
 }
 
 // handleDeleteRecord は特定のIDのレコードを削除するハンドラーです。
+// 既定では論理削除（復元可能）し、hard=trueの場合は論理削除済みのレコードを含めて完全に削除します。
 func (s *Server) handleDeleteRecord(w http.ResponseWriter, r *http.Request) {
 	// パラメータを検証
 	params, err := NewDeleteRecordParams(r)
@@ -375,7 +830,11 @@ func (s *Server) handleDeleteRecord(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// レコードの削除
-	if err := s.store.DeleteRecord(r.Context(), params.RecordID); err != nil {
+	deleteRecord := s.store.DeleteRecord
+	if params.Hard {
+		deleteRecord = s.store.PurgeRecord
+	}
+	if err := deleteRecord(r.Context(), params.RecordID); err != nil {
 		if errors.Is(err, model.ErrRecordNotFound) {
 			writeJSONError(w, "Record not found", http.StatusNotFound)
 		} else {
@@ -384,11 +843,46 @@ func (s *Server) handleDeleteRecord(w http.ResponseWriter, r *http.Request) {
 		}
 		return
 	}
+	// 削除したレコードのプロジェクトは分からないため、すべてのキャッシュを破棄
+	s.graphCache.invalidateAll()
 
 	// 削除成功のレスポンスを返す
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// handleRestoreRecord は論理削除されたレコードを復元するハンドラーです。
+// 削除されていないレコードに対してもそのままレコードを返します。
+func (s *Server) handleRestoreRecord(w http.ResponseWriter, r *http.Request) {
+	// パラメータを検証
+	recordID, err := model.ParseHexID(r.PathValue("record_id"))
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// レコードの復元
+	if err := s.store.RestoreRecord(r.Context(), recordID); err != nil {
+		if errors.Is(err, model.ErrRecordNotFound) {
+			writeJSONError(w, "Record not found", http.StatusNotFound)
+		} else {
+			log.Printf("Error restoring record: %v", err)
+			writeJSONError(w, "Failed to restore record", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	record, err := s.store.GetRecord(r.Context(), recordID)
+	if err != nil {
+		log.Printf("Error retrieving restored record: %v", err)
+		writeJSONError(w, "Failed to retrieve record", http.StatusInternalServerError)
+		return
+	}
+	s.graphCache.invalidateProject(record.ProjectID)
+
+	// レスポンスの返却
+	writeJSON(w, r, http.StatusOK, record)
+}
+
 // GetGraphParams represents parameters for getting a graph.
 type GetGraphParams struct {
 	ProjectID model.HexID
@@ -396,8 +890,190 @@ type GetGraphParams struct {
 	Tags      *model.Tags
 	Track     bool
 	ViewType  string // "yearly" or "weekly"
+	Today     bool   // 今日のセルを強調表示するか
+	Weekdays  *model.Weekdays
+	Radius    int    // セルの角丸半径（px）
+	Source    string // 作成元でフィルタ（空の場合はすべて）
+
+	Stroke      string // セルの枠線の色（空の場合は枠線なし）
+	StrokeWidth int    // セルの枠線の幅（px、0の場合は1px）
+
+	TrackValue  *model.Value      // trackで作成するレコードの値（nilの場合はプロジェクトの既定値）
+	TrackName   string            // trackでプロジェクトを自動作成する場合の名前（空の場合はプロジェクトID）
+	TrackUpsert bool              // trackで新しいレコードを作成せず、その日のtrackのレコードに加算するか
+	Theme       string            // 配色テーマ（空の場合はサーバーのデフォルト）
+	Aggregation model.Aggregation // セル内のレコード値の集計方法
+	Responsive  bool              // コンテナの幅に合わせて拡縮するか（viewBoxとwidth="100%"）
+	MinLevel    int               // 0以外の値のセルの最低レベル（0の場合は指定なし）
+
+	MaxWidth  int // グラフの最大幅（px、超える場合はセルを縮小する。0の場合は制限なし）
+	MaxHeight int // グラフの最大高さ（px、超える場合はセルを縮小する。0の場合は制限なし）
+
+	GraphType string // "heatmap"（グリッド）または"spark"（1行のスパークライン）
+	Format    string // 出力形式（graphFormatSVG、graphFormatJSON、graphFormatPNG、graphFormatHTML）
+	Demo      bool   // レコードの代わりに決定的なサンプルデータで描画するか
+
+	Timezone *time.Location // 日付の区切りに使うタイムゾーン（nilの場合はプロジェクトのタイムゾーン）
+	OnEmpty  string         // 期間内にレコードがない場合の応答（config.EmptyGraphPlaceholderなど、空の場合はサーバーの設定）
+
+	ColorBy   string            // セルの色の決め方（graphColorByIntensityまたはgraphColorByTag）
+	TagColors map[string]string // color_by=tagで使うタグごとの色（指定のないタグは自動で割り当てる）
+
+	ClampToNow bool // 期間が未来に及ぶ場合にグリッドを今日で終えるか（clamp=now）
+	Gradient   bool // 0以外の値のセルをレベルの代わりに連続的なグラデーションで塗るか
+}
+
+// グラフのセルの色の決め方
+const (
+	graphColorByIntensity = "intensity" // 値の大きさによるレベルの色
+	graphColorByTag       = "tag"       // 値の合計が最大のタグの色（実験的、タグのない日は値の大きさによる色）
+)
+
+// グラフの出力形式
+const (
+	graphFormatSVG  = "svg"  // SVG画像
+	graphFormatJSON = "json" // セルの行列（GraphMatrixResponse）
+	graphFormatPNG  = "png"  // PNG画像（ラベルなし）
+	graphFormatHTML = "html" // セルのdivを並べたHTMLの断片（type=html）
+)
+
+// graphContentTypes は出力形式ごとのContent-Typeです。
+var graphContentTypes = map[string]string{
+	graphFormatSVG:  "image/svg+xml",
+	graphFormatJSON: "application/json",
+	graphFormatPNG:  "image/png",
+	graphFormatHTML: "text/html; charset=utf-8",
+}
+
+// graphFormat はグラフの出力形式を決定します。
+// 拡張子付きのパス（graph.svg、graph.json、graph.png）はAcceptヘッダーより優先します。
+func graphFormat(r *http.Request) string {
+	switch path.Ext(r.URL.Path) {
+	case ".svg":
+		return graphFormatSVG
+	case ".json":
+		return graphFormatJSON
+	case ".png":
+		return graphFormatPNG
+	}
+	return negotiateGraphFormat(r.Header.Get("Accept"))
+}
+
+// negotiateGraphFormat はAcceptヘッダーから出力形式を選びます。
+// qの値が最も大きい対応形式を選び、ヘッダーがない場合や対応形式がない場合、ワイルドカードの場合はSVGを返します。
+// ブラウザが画像の取得時に送るAcceptはPNGとSVGを同じqで並べるため、qが同じ場合はSVGを優先し、
+// それ以外は先に書かれたものを選びます。
+func negotiateGraphFormat(accept string) string {
+	format, bestQ := graphFormatSVG, 0.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, _ := strings.Cut(part, ";")
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if ok && name == "q" {
+				if v, err := strconv.ParseFloat(value, 64); err == nil {
+					q = v
+				}
+			}
+		}
+		candidate := ""
+		switch strings.ToLower(strings.TrimSpace(mediaType)) {
+		case "image/svg+xml", "image/*", "*/*":
+			candidate = graphFormatSVG
+		case "application/json":
+			candidate = graphFormatJSON
+		case "image/png":
+			candidate = graphFormatPNG
+		}
+		if candidate != "" && (q > bestQ || q == bestQ && candidate == graphFormatSVG) {
+			format, bestQ = candidate, q
+		}
+	}
+	return format
+}
+
+// GraphMatrixResponse はグラフのセルの行列（JSON形式のグラフ）です。
+// 行と列の並びはSVGと同じです（heatmap.Matrixを参照）。
+type GraphMatrixResponse struct {
+	View string            `json:"view"` // "yearly"、"weekly"、"spark"
+	From time.Time         `json:"from"`
+	To   time.Time         `json:"to"`
+	Rows [][]*heatmap.Cell `json:"rows"`
 }
 
+// cacheKey はグラフキャッシュのキーを返します。
+// 描画結果に影響するすべてのパラメータと解決済みのテーマを含めます。
+func (p *GetGraphParams) cacheKey(theme string) string {
+	return strings.Join([]string{
+		p.ProjectID.String(),
+		p.DateRange.From().Format(time.RFC3339Nano),
+		p.DateRange.To().Format(time.RFC3339Nano),
+		strings.Join(p.Tags.Values(), ","),
+		p.ViewType,
+		theme,
+		strconv.FormatBool(p.Today),
+		p.Weekdays.String(),
+		strconv.Itoa(p.Radius),
+		p.Stroke,
+		strconv.Itoa(p.StrokeWidth),
+		strconv.FormatBool(p.Responsive),
+		strconv.Itoa(p.MinLevel),
+		p.Source,
+		string(p.Aggregation),
+		strconv.Itoa(p.MaxWidth),
+		strconv.Itoa(p.MaxHeight),
+		p.GraphType,
+		p.Format,
+		strconv.FormatBool(p.Demo),
+		timezoneName(p.Timezone),
+		p.OnEmpty,
+		p.ColorBy,
+		encodeTagColors(p.TagColors),
+		strconv.FormatBool(p.ClampToNow),
+		strconv.FormatBool(p.Gradient),
+	}, "|")
+}
+
+// encodeTagColors はキャッシュキー用にタグごとの色をタグ名順の"tag:color"の並びにします。
+func encodeTagColors(tagColors map[string]string) string {
+	pairs := make([]string, 0, len(tagColors))
+	for _, tag := range slices.Sorted(maps.Keys(tagColors)) {
+		pairs = append(pairs, tag+":"+tagColors[tag])
+	}
+	return strings.Join(pairs, ",")
+}
+
+// timezoneName はキャッシュキー用のタイムゾーン名を返します（nilの場合は空文字列）。
+func timezoneName(loc *time.Location) string {
+	if loc == nil {
+		return ""
+	}
+	return loc.String()
+}
+
+// checkNotModified はLast-Modifiedヘッダーを設定し、If-Modified-Sinceの日時が
+// lastModified以降であれば304を返してtrueを返します。
+func checkNotModified(w http.ResponseWriter, r *http.Request, lastModified time.Time) bool {
+	// HTTP日付は秒精度のため切り捨てて比較
+	lastModified = lastModified.UTC().Truncate(time.Second)
+	w.Header().Set("Last-Modified", lastModified.Format(http.TimeFormat))
+
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		// ヘッダーがない、または不正な場合は通常どおり返す
+		return false
+	}
+	if lastModified.After(since) {
+		return false
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// graphCellSize はグラフのセルサイズ（px）です。
+const graphCellSize = 12
+
 // NewGetGraphParams creates parameters for graph generation from HTTP request.
 func NewGetGraphParams(r *http.Request) (*GetGraphParams, error) {
 	projectID, err := model.ParseHexID(r.PathValue("project_id"))
@@ -416,6 +1092,24 @@ func NewGetGraphParams(r *http.Request) (*GetGraphParams, error) {
 		return nil, fmt.Errorf("invalid view type: %s (must be 'yearly' or 'weekly')", viewType)
 	}
 
+	// typeを取得、デフォルトは"heatmap"（スパークラインは日単位のため週次ビューとは併用不可）
+	// "html"はグリッドをSVGの代わりにHTMLの断片で返す（拡張子やAcceptヘッダーより優先）
+	graphType := query.Get("type")
+	format := graphFormat(r)
+	switch graphType {
+	case "":
+		graphType = "heatmap"
+	case "html":
+		graphType = "heatmap"
+		format = graphFormatHTML
+	}
+	if graphType != "heatmap" && graphType != "spark" {
+		return nil, fmt.Errorf("invalid graph type: %s (must be 'heatmap', 'spark' or 'html')", graphType)
+	}
+	if graphType == "spark" && viewType == "weekly" {
+		return nil, fmt.Errorf("type 'spark' cannot be combined with view 'weekly'")
+	}
+
 	// viewTypeに応じてデフォルトの日付範囲を変更
 	fromStr := query.Get("from")
 	toStr := query.Get("to")
@@ -435,6 +1129,12 @@ func NewGetGraphParams(r *http.Request) (*GetGraphParams, error) {
 		fromStr = from.Format("2006-01-02")
 		toStr = now.Format("2006-01-02")
 	}
+	if fromStr == "" && toStr == "" && graphType == "spark" {
+		// スパークラインの場合、インラインで表示できるよう直近30日間
+		now := time.Now()
+		fromStr = now.AddDate(0, 0, -29).Format("2006-01-02")
+		toStr = now.Format("2006-01-02")
+	}
 
 	dateRange, err := model.NewDateRange(fromStr, toStr)
 	if err != nil {
@@ -444,104 +1144,379 @@ func NewGetGraphParams(r *http.Request) (*GetGraphParams, error) {
 	tags := model.NewTags(query.Get("tags"))
 	track := query.Has("track")
 
+	// trackで作成するレコードの値
+	var trackValue *model.Value
+	if valueStr := query.Get("value"); valueStr != "" {
+		v, err := strconv.Atoi(valueStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value parameter: must be an integer")
+		}
+		trackValue, err = model.NewValue(&v)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	upsert, err := parseBoolQuery(query, "upsert")
+	if err != nil {
+		return nil, err
+	}
+
+	// demoはレコードを読み書きしないため、レコードを作成するtrackとは併用不可
+	demo, err := parseBoolQuery(query, "demo")
+	if err != nil {
+		return nil, err
+	}
+	if demo && track {
+		return nil, fmt.Errorf("demo cannot be combined with track")
+	}
+
+	onEmpty := query.Get("on_empty")
+	if onEmpty != "" && !config.IsEmptyGraphBehavior(onEmpty) {
+		return nil, fmt.Errorf("invalid on_empty parameter: %q (must be 'placeholder', 'no_content' or 'error')", onEmpty)
+	}
+
+	// tzが指定されない場合はプロジェクトのタイムゾーン（未設定の場合はサーバーのタイムゾーン）
+	var timezone *time.Location
+	if tz := query.Get("tz"); tz != "" {
+		timezone, err = model.NewTimezone(tz)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	today, err := parseBoolQuery(query, "today")
+	if err != nil {
+		return nil, err
+	}
+
+	weekdays, err := model.NewWeekdays(query.Get("weekdays"))
+	if err != nil {
+		return nil, err
+	}
+
+	aggregation, err := model.NewAggregation(query.Get("agg"))
+	if err != nil {
+		return nil, err
+	}
+
+	responsive, err := parseBoolQuery(query, "responsive")
+	if err != nil {
+		return nil, err
+	}
+
+	// minlevelパラメータの検証（配色のレベル数を超える場合は最上位レベルとして扱われる）
+	minLevel := 0
+	if minLevelStr := query.Get("minlevel"); minLevelStr != "" {
+		minLevel, err = strconv.Atoi(minLevelStr)
+		if err != nil || minLevel < 0 {
+			return nil, fmt.Errorf("invalid minlevel parameter: must be a non-negative integer")
+		}
+	}
+
+	// max_width/max_heightパラメータの検証
+	maxWidth, err := parsePositiveIntQuery(query, "max_width")
+	if err != nil {
+		return nil, err
+	}
+	maxHeight, err := parsePositiveIntQuery(query, "max_height")
+	if err != nil {
+		return nil, err
+	}
+
+	// themeパラメータの検証
+	theme := query.Get("theme")
+	if theme != "" {
+		if _, ok := heatmap.ThemeColors(theme); !ok {
+			return nil, fmt.Errorf("invalid theme parameter: must be one of %s", strings.Join(heatmap.ThemeNames(), ", "))
+		}
+	}
+
+	// radiusパラメータの検証（セルサイズの半分を上限とする）
+	radius := 0
+	if radiusStr := query.Get("radius"); radiusStr != "" {
+		radius, err = strconv.Atoi(radiusStr)
+		if err != nil || radius < 0 {
+			return nil, fmt.Errorf("invalid radius parameter: must be a non-negative integer")
+		}
+		radius = min(radius, graphCellSize/2)
+	}
+
+	// stroke/stroke_widthパラメータの検証（SVGの属性に埋め込むため16進カラーコードのみ許可）
+	stroke := query.Get("stroke")
+	if stroke != "" && !model.IsHexColor(stroke) {
+		return nil, fmt.Errorf("invalid stroke parameter: must be a hex color (#RGB or #RRGGBB)")
+	}
+	strokeWidth, err := parsePositiveIntQuery(query, "stroke_width")
+	if err != nil {
+		return nil, err
+	}
+
+	// color_by/tag_colorsパラメータの検証（タグの色は日単位のため週表示とは併用不可）
+	colorBy := query.Get("color_by")
+	switch colorBy {
+	case "":
+		colorBy = graphColorByIntensity
+	case graphColorByIntensity, graphColorByTag:
+	default:
+		return nil, fmt.Errorf("invalid color_by parameter: %q (must be 'intensity' or 'tag')", colorBy)
+	}
+	if colorBy == graphColorByTag && viewType == "weekly" {
+		return nil, fmt.Errorf("color_by=tag cannot be combined with view=weekly")
+	}
+	tagColors, err := parseTagColors(query.Get("tag_colors"))
+	if err != nil {
+		return nil, err
+	}
+	if len(tagColors) > 0 && colorBy != graphColorByTag {
+		return nil, fmt.Errorf("tag_colors requires color_by=tag")
+	}
+
+	// clampパラメータの検証（現在は今日で終えるnowのみ）
+	clamp := query.Get("clamp")
+	if clamp != "" && clamp != "now" {
+		return nil, fmt.Errorf("invalid clamp parameter: %q (must be 'now')", clamp)
+	}
+
+	gradient, err := parseBoolQuery(query, "gradient")
+	if err != nil {
+		return nil, err
+	}
+
 	return &GetGraphParams{
 		ProjectID: projectID,
 		DateRange: dateRange,
 		Tags:      tags,
 		Track:     track,
 		ViewType:  viewType,
+		Today:     today,
+		Weekdays:  weekdays,
+		Radius:    radius,
+		Source:    query.Get("source"),
+
+		Stroke:      stroke,
+		StrokeWidth: strokeWidth,
+
+		TrackValue:  trackValue,
+		TrackName:   query.Get("name"),
+		TrackUpsert: upsert,
+		Theme:       theme,
+		Aggregation: aggregation,
+		Responsive:  responsive,
+		MinLevel:    minLevel,
+
+		MaxWidth:  maxWidth,
+		MaxHeight: maxHeight,
+
+		GraphType: graphType,
+		Format:    format,
+		Demo:      demo,
+
+		Timezone: timezone,
+		OnEmpty:  onEmpty,
+
+		ColorBy:   colorBy,
+		TagColors: tagColors,
+
+		ClampToNow: clamp == "now",
+		Gradient:   gradient,
 	}, nil
 }
 
+// parseTagColors はtag_colorsパラメータ（"tag:#RRGGBB"のカンマ区切り）を解釈します。
+// SVGの属性に埋め込むため色は16進カラーコードのみ許可します。
+func parseTagColors(v string) (map[string]string, error) {
+	if v == "" {
+		return nil, nil
+	}
+	tagColors := make(map[string]string)
+	for _, pair := range strings.Split(v, ",") {
+		tag, color, ok := strings.Cut(pair, ":")
+		tag = strings.TrimSpace(tag)
+		color = strings.TrimSpace(color)
+		if !ok || tag == "" {
+			return nil, fmt.Errorf("invalid tag_colors parameter: %q (must be tag:color pairs separated by commas)", pair)
+		}
+		if !model.IsHexColor(color) {
+			return nil, fmt.Errorf("invalid tag_colors parameter: color of tag %q must be a hex color (#RGB or #RRGGBB)", tag)
+		}
+		tagColors[tag] = color
+	}
+	return tagColors, nil
+}
+
 // handleGetGraph は指定プロジェクトのヒートマップグラフを生成・返却するハンドラーです。
 func (s *Server) handleGetGraph(w http.ResponseWriter, r *http.Request) {
 	// パラメータを検証
+	// 画像として埋め込まれても原因を調べられるよう、エラーはAPIと同じJSON形式で返す
+	// （不正なIDなどのパラメータは400、存在しないプロジェクトは404）
 	params, err := NewGetGraphParams(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	// 拡張子のないパスはAcceptヘッダーによって応答が変わる
+	if path.Ext(r.URL.Path) == "" {
+		w.Header().Add("Vary", "Accept")
+	}
 
-	// アクセスカウンター機能: trackパラメータがある場合、レコードを自動作成
-	if params.Track {
-		// 新しいレコードの作成（現在時刻、値は1）
-		record, err := model.NewRecord(time.Now(), params.ProjectID, 1, params.Tags.Values())
-		if err != nil {
-			log.Printf("Error creating access counter record: %v", err)
-			// エラーが発生してもグラフ表示は続行するため、エラーレスポンスは返さない
-		} else {
-			// レコードの保存
-			if err := s.store.CreateRecord(r.Context(), record); err != nil {
-				log.Printf("Error saving access counter record: %v", err)
-				// エラーが発生してもグラフ表示は続行
+	// 配色テーマの決定（パラメータ→サーバーのデフォルト→github）
+	theme := s.graphTheme(params)
+
+	// キャッシュ済みのグラフがあればストアにアクセスせずに返す
+	// trackの場合はレコードを作成するためキャッシュを使用しない
+	cacheKey := params.cacheKey(theme)
+	if !params.Track {
+		if entry, ok := s.graphCache.get(cacheKey); ok {
+			if entry.lastModified != nil && checkNotModified(w, r, *entry.lastModified) {
+				return
 			}
+			w.Header().Set("Content-Type", graphContentTypes[params.Format])
+			w.Write([]byte(entry.body))
+			return
 		}
 	}
 
 	// プロジェクトを取得（グラフ生成時のタイトル用）
 	project, err := s.store.GetProject(r.Context(), params.ProjectID)
+	if errors.Is(err, model.ErrProjectNotFound) && params.Track && s.config.AutoCreateProjectsOnTrack {
+		// 未作成のプロジェクトへのtrackは、プロジェクトを作成してから記録する
+		project, err = s.createTrackedProject(r.Context(), params)
+		if err != nil {
+			log.Printf("Error creating project on track: %v", err)
+			var validationErr *model.ValidationError
+			switch {
+			case errors.As(err, &validationErr):
+				writeJSONError(w, err.Error(), http.StatusBadRequest)
+			case errors.Is(err, model.ErrProjectNameTaken):
+				writeJSONError(w, "Project name already exists", http.StatusConflict)
+			case errors.Is(err, model.ErrProjectLimitReached):
+				s.writeProjectLimitError(w)
+			default:
+				writeJSONError(w, "Failed to create project", http.StatusInternalServerError)
+			}
+			return
+		}
+	}
 	if err != nil {
 		log.Printf("Error getting project: %v", err)
-		http.Error(w, "Project not found", http.StatusNotFound)
+		if errors.Is(err, model.ErrProjectNotFound) {
+			writeJSONError(w, fmt.Sprintf("Project with ID %s not found", params.ProjectID), http.StatusNotFound)
+		} else {
+			writeJSONError(w, "Failed to retrieve project", http.StatusInternalServerError)
+		}
 		return
 	}
 
-	storeParams := &store.ListAllRecordsParams{
-		ProjectID: params.ProjectID,
-		From:      params.DateRange.From(),
-		To:        params.DateRange.To(),
-		Tags:      params.Tags.Values(),
+	// 条件付きリクエスト: プロジェクトの設定・レコード・タグの別名の最終変更日時をLast-Modifiedとして扱う
+	// trackの場合はレコードを作成するため常にグラフを返す（demoの場合はレコードを参照しない）
+	var lastModified *time.Time
+	if !params.Track && !params.Demo {
+		changedAt, err := s.store.GetProjectLastModified(r.Context(), params.ProjectID)
+		if err != nil {
+			log.Printf("Error getting project last modified: %v", err)
+		} else {
+			lastModified = &changedAt
+			if checkNotModified(w, r, *lastModified) {
+				return
+			}
+		}
 	}
 
-	fromDate := params.DateRange.From()
-	toDate := params.DateRange.To()
-
-	var data []heatmap.Data
+	// 日付の区切りはtzパラメータ→プロジェクト→サーバーのタイムゾーンの順で決定
+	loc := graphLocation(params, project)
 
-	// すべてのレコードを取得してData配列に変換
-	// ヒートマップパッケージが重複するタイムスタンプ/日付を集計し、
-	// 空の日付には自動的に0値を割り当てます
-	if params.ViewType == "weekly" {
-		// 週次ビュー: タイムスタンプをそのまま使用
-		for record, err := range s.store.ListAllRecords(r.Context(), storeParams) {
+	// アクセスカウンター機能: trackパラメータがある場合、レコードを自動作成
+	if params.Track {
+		// 新しいレコードの作成（現在時刻、値はvalueパラメータ→プロジェクトの既定値→1の順で決定）
+		value := project.TrackValue()
+		if params.TrackValue != nil {
+			value = params.TrackValue.Int()
+		}
+		record, err := model.NewRecord(time.Now(), params.ProjectID, value, params.Tags.Values())
+		if err == nil {
+			// プロジェクトの下限・上限などを満たさない値は記録しない
+			err = s.checkNewRecord(project, record)
+		}
+		if err != nil {
+			log.Printf("Error creating access counter record: %v", err)
+			// エラーが発生してもグラフ表示は続行するため、エラーレスポンスは返さない
+		} else {
+			record.Source = model.RecordSourceTrack
+			// レコードの保存（upsertの場合は同時のビーコンでも1日1件になるよう、描画するグラフと同じ日のレコードに加算）
+			created := true
+			if params.TrackUpsert {
+				created, err = s.store.UpsertDailyRecord(r.Context(), record, loc)
+			} else {
+				err = s.store.CreateRecord(r.Context(), record)
+			}
 			if err != nil {
-				log.Printf("Error retrieving records: %v", err)
-				http.Error(w, "Failed to retrieve records", http.StatusInternalServerError)
-				return
+				log.Printf("Error saving access counter record: %v", err)
+				// エラーが発生してもグラフ表示は続行
+			} else {
+				s.graphCache.invalidateProject(params.ProjectID)
+				if created {
+					s.notifyRecordCreated(record)
+				}
 			}
-			data = append(data, heatmap.Data{
-				Date:  record.Timestamp.Local(),
-				Value: record.Value,
-			})
 		}
+	}
+
+	dateRange := params.DateRange.In(loc)
+
+	// demoの場合はストアのレコードの代わりにサンプルデータを使用
+	var data []heatmap.Data
+	if params.Demo {
+		data = demoGraphData(params, dateRange)
 	} else {
-		// 年次ビュー: 日付のみを使用（時刻は00:00:00）
-		for record, err := range s.store.ListAllRecords(r.Context(), storeParams) {
-			if err != nil {
-				log.Printf("Error retrieving records: %v", err)
-				http.Error(w, "Failed to retrieve records", http.StatusInternalServerError)
-				return
-			}
-			localTime := record.Timestamp.Local()
-			dateOnly := time.Date(localTime.Year(), localTime.Month(), localTime.Day(),
-				0, 0, 0, 0, localTime.Location())
-			data = append(data, heatmap.Data{
-				Date:  dateOnly,
-				Value: record.Value,
-			})
+		data, err = s.graphData(r.Context(), params, project)
+		if err != nil {
+			log.Printf("Error retrieving records: %v", err)
+			writeJSONError(w, "Failed to retrieve records", http.StatusInternalServerError)
+			return
 		}
 	}
 
+	// 期間内にレコードがない場合は設定に従って応答する（placeholderの場合は0値のセルのグラフを描画）
+	if !hasGraphValues(data) {
+		switch s.emptyGraphBehavior(params) {
+		case config.EmptyGraphNoContent:
+			w.WriteHeader(http.StatusNoContent)
+			return
+		case config.EmptyGraphError:
+			writeJSONErrorCode(w, "No records in the graph range", errorCodeEmptyGraph, http.StatusNotFound)
+			return
+		}
+	}
+
+	fromDate := dateRange.From()
+	toDate := dateRange.To()
+
 	// SVGの生成（データが空でもFrom/Toがあれば0値のセルを表示）
 	opts := &heatmap.Options{
-		CellSize:    12,
+		CellSize:    graphCellSize,
 		CellPadding: 2,
+		CellRadius:  params.Radius,
 		FontSize:    10,
-		FontFamily:  "sans-serif",
-		Colors:      []string{"#ebedf0", "#9be9a8", "#40c463", "#30a14e", "#216e39"},
+		FontFamily:  heatmap.DefaultFontFamily,
+		Colors:      graphColors(theme),
 		ProjectName: project.Name,
 		From:        fromDate,
 		To:          toDate,
+
+		CellStroke:      params.Stroke,
+		CellStrokeWidth: params.StrokeWidth,
+
+		Now:            time.Now().In(loc),
+		HighlightToday: params.Today,
+		ClampToNow:     params.ClampToNow,
+		Gradient:       params.Gradient,
+		Responsive:     params.Responsive,
+
+		MinNonZeroLevel: params.MinLevel,
+
+		MaxWidth:  params.MaxWidth,
+		MaxHeight: params.MaxHeight,
 	}
 
 	// tagsがある場合はタイトルに含める
@@ -549,29 +1524,276 @@ func (s *Server) handleGetGraph(w http.ResponseWriter, r *http.Request) {
 		opts.Tags = params.Tags.Values()
 	}
 
-	var svg string
-	if params.ViewType == "weekly" {
-		svg = heatmap.GenerateWeeklyHeatmapSVG(data, opts)
-	} else {
-		svg = heatmap.GenerateYearlyHeatmapSVG(data, opts)
+	// color_by=tagの場合は各日を値の合計が最大のタグの色にする（demoの場合はタグがないため値の大きさによる色）
+	if params.ColorBy == graphColorByTag && !params.Demo {
+		dayTags, err := s.graphDayTags(r.Context(), params, project)
+		if err != nil {
+			log.Printf("Error retrieving records: %v", err)
+			writeJSONError(w, "Failed to retrieve records", http.StatusInternalServerError)
+			return
+		}
+		opts.DayTags = dayTags
+		opts.TagColors = heatmap.AssignTagColors(dayTags, params.TagColors)
+	}
+
+	body, err := renderGraph(params, data, opts)
+	if err != nil {
+		log.Printf("Error rendering graph: %v", err)
+		writeJSONError(w, "Failed to render graph", http.StatusInternalServerError)
+		return
+	}
+
+	// Last-Modifiedとともにキャッシュ（trackの場合はレコード作成を伴うためキャッシュしない）
+	if !params.Track {
+		s.graphCache.put(cacheKey, params.ProjectID, body, lastModified)
 	}
 
 	// レスポンスの返却
-	w.Header().Set("Content-Type", "image/svg+xml")
-	w.Write([]byte(svg))
+	w.Header().Set("Content-Type", graphContentTypes[params.Format])
+	w.Write([]byte(body))
 }
 
+// renderGraph はセル単位に集計したデータを出力形式に従って描画します。
+// SVG・JSON・PNG・HTMLはいずれも同じセルの並びとレベル分けを使用します。
+func renderGraph(params *GetGraphParams, data []heatmap.Data, opts *heatmap.Options) (string, error) {
+	if params.Format == graphFormatSVG {
+		switch {
+		case params.GraphType == "spark":
+			return heatmap.GenerateSparklineSVG(data, opts), nil
+		case params.ViewType == "weekly":
+			return heatmap.GenerateWeeklyHeatmapSVG(data, opts), nil
+		default:
+			return heatmap.GenerateYearlyHeatmapSVG(data, opts), nil
+		}
+	}
+
+	view := params.ViewType
+	var matrix *heatmap.Matrix
+	switch {
+	case params.GraphType == "spark":
+		view = "spark"
+		matrix = heatmap.SparklineMatrix(data, opts)
+	case params.ViewType == "weekly":
+		matrix = heatmap.WeeklyMatrix(data, opts)
+	default:
+		matrix = heatmap.YearlyMatrix(data, opts)
+	}
+
+	switch params.Format {
+	case graphFormatPNG:
+		var buf bytes.Buffer
+		if err := matrix.EncodePNG(&buf, opts); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	case graphFormatHTML:
+		var buf bytes.Buffer
+		if err := matrix.EncodeHTML(&buf, opts); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	}
+	body, err := json.Marshal(GraphMatrixResponse{
+		View: view,
+		From: opts.From,
+		To:   opts.To,
+		Rows: matrix.Rows,
+	})
+	return string(body), err
+}
+
+// createTrackedProject はtrackで指定されたIDのプロジェクトを作成します。
+// 同時に作成された場合は作成済みのプロジェクトを返します。
+func (s *Server) createTrackedProject(ctx context.Context, params *GetGraphParams) (*model.Project, error) {
+	name := params.TrackName
+	if name == "" {
+		name = params.ProjectID.String()
+	}
+	name, err := s.projectName(name)
+	if err != nil {
+		return nil, err
+	}
+	project, err := model.NewProject(name, "")
+	if err != nil {
+		return nil, err
+	}
+	project.ID = params.ProjectID
+
+	if err := s.store.CreateProjectWithLimit(ctx, project, s.config.MaxProjects); err != nil {
+		if errors.Is(err, model.ErrProjectIDTaken) {
+			return s.store.GetProject(ctx, params.ProjectID)
+		}
+		return nil, err
+	}
+	return project, nil
+}
+
+// graphTheme はグラフの配色テーマを決定します（パラメータ→サーバーのデフォルト）。
+func (s *Server) graphTheme(params *GetGraphParams) string {
+	if params.Theme != "" {
+		return params.Theme
+	}
+	return s.config.DefaultTheme
+}
+
+// emptyGraphBehavior は期間内にレコードがないグラフの応答を決定します（パラメータ→サーバーの設定）。
+func (s *Server) emptyGraphBehavior(params *GetGraphParams) string {
+	if params.OnEmpty != "" {
+		return params.OnEmpty
+	}
+	if s.config.EmptyGraphBehavior != "" {
+		return s.config.EmptyGraphBehavior
+	}
+	return config.EmptyGraphPlaceholder
+}
+
+// hasGraphValues はグラフのデータに0以外の値のセルがあるかを返します。
+func hasGraphValues(data []heatmap.Data) bool {
+	for _, d := range data {
+		if d.Value != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// graphColors はテーマの配色を返します。未知のテーマの場合はデフォルトの配色を使用します。
+func graphColors(theme string) []string {
+	colors, ok := heatmap.ThemeColors(theme)
+	if !ok {
+		return heatmap.DefaultColors
+	}
+	return colors
+}
+
+// dayBucketIn はレコードの時刻をlocのタイムゾーンでの日付の00:00:00に丸めます。
+func dayBucketIn(t time.Time, loc *time.Location) time.Time {
+	localTime := t.In(loc)
+	return time.Date(localTime.Year(), localTime.Month(), localTime.Day(), 0, 0, 0, 0, loc)
+}
+
+// slotBucketIn はレコードの時刻をlocのタイムゾーンでの4時間単位のスロットの開始時刻に丸めます。
+func slotBucketIn(t time.Time, loc *time.Location) time.Time {
+	localTime := t.In(loc)
+	return time.Date(localTime.Year(), localTime.Month(), localTime.Day(),
+		localTime.Hour()/4*4, 0, 0, 0, loc)
+}
+
+// graphLocation はグラフの日付の区切りに使うタイムゾーンを決定します（tzパラメータ→プロジェクト→サーバー）。
+func graphLocation(params *GetGraphParams, project *model.Project) *time.Location {
+	if params.Timezone != nil {
+		return params.Timezone
+	}
+	return project.Location()
+}
+
+// graphData はグラフのパラメータに従ってレコードを取得し、セル単位に集計したヒートマップデータを返します。
+func (s *Server) graphData(ctx context.Context, params *GetGraphParams, project *model.Project) ([]heatmap.Data, error) {
+	loc := graphLocation(params, project)
+	records := s.graphRecords(ctx, params, loc)
+
+	// セル単位でレコード値を集計（aggパラメータに従う）
+	// 空のセルにはヒートマップパッケージが自動的に0値を割り当てます
+	bucket := func(t time.Time) time.Time { return dayBucketIn(t, loc) }
+	if params.ViewType == "weekly" {
+		bucket = func(t time.Time) time.Time { return slotBucketIn(t, loc) }
+	}
+	totals, err := store.AggregateRecordsBy(records, bucket, params.Aggregation, project.RecordValueType())
+	if err != nil {
+		return nil, err
+	}
+	data := make([]heatmap.Data, 0, len(totals))
+	for _, total := range totals {
+		data = append(data, heatmap.Data{
+			Date:  total.Date,
+			Value: total.Value,
+		})
+	}
+	return data, nil
+}
+
+// graphRecords はグラフの期間・タグ・作成元で絞り込んだレコードを返します。
+// weekdaysが指定されている場合、対象外の曜日のレコードは集計しません（グリッド上は0値になります）。
+func (s *Server) graphRecords(ctx context.Context, params *GetGraphParams, loc *time.Location) iter.Seq2[*model.Record, error] {
+	dateRange := params.DateRange.In(loc)
+	storeParams := &store.ListAllRecordsParams{
+		ProjectID: params.ProjectID,
+		From:      dateRange.From(),
+		To:        dateRange.To(),
+		Tags:      params.Tags.Values(),
+		Source:    params.Source,
+	}
+	return func(yield func(*model.Record, error) bool) {
+		for record, err := range s.store.ListAllRecords(ctx, storeParams) {
+			if err == nil && !params.Weekdays.Contains(record.Timestamp.In(loc).Weekday()) {
+				continue
+			}
+			if !yield(record, err) {
+				return
+			}
+		}
+	}
+}
+
+// graphDayTags はcolor_by=tagのグラフのために、期間内の各日で値の合計が最大のタグを返します（キーは"2006-01-02"）。
+func (s *Server) graphDayTags(ctx context.Context, params *GetGraphParams, project *model.Project) (map[string]string, error) {
+	loc := graphLocation(params, project)
+	var data []heatmap.TagData
+	for record, err := range s.graphRecords(ctx, params, loc) {
+		if err != nil {
+			return nil, err
+		}
+		day := dayBucketIn(record.Timestamp, loc)
+		for _, tag := range record.Tags {
+			data = append(data, heatmap.TagData{Date: day, Tag: tag, Value: record.Amount()})
+		}
+	}
+	return heatmap.DominantTags(data), nil
+}
+
+// エンドポイントごとのlimitの上限
+const (
+	maxListRecordsLimit  = 1000
+	maxListProjectsLimit = 1000
+	maxListTagsLimit     = 1000
+)
+
 // ListRecordsParams represents parameters for listing records.
 type ListRecordsParams struct {
 	ProjectID  *model.HexID
 	DateRange  *model.DateRange
 	Tags       *model.Tags
+	Source     string // 作成元でフィルタ（空の場合はすべて）
+	ExternalID string // 外部IDでフィルタ（空の場合はすべて）
 	Pagination *model.Pagination
+	Fields     []string              // レスポンスに含めるレコードのフィールド（空の場合はすべて）
+	TimeField  model.RecordTimeField // 期間と順序を適用する日時（timestampまたはcreated_at）
+}
+
+// recordFields はfieldsパラメータで指定できるレコードのフィールド（JSONのキー）です。
+var recordFields = []string{"id", "project_id", "value", "timestamp", "tags", "source", "created_at", "value_float", "deleted_at", "external_id"}
+
+// parseRecordFields はカンマ区切りのfieldsパラメータを解析します。未知のフィールドはエラーです。
+func parseRecordFields(fieldsStr string) ([]string, error) {
+	if fieldsStr == "" {
+		return nil, nil
+	}
+	var fields []string
+	for field := range strings.SplitSeq(fieldsStr, ",") {
+		field = strings.TrimSpace(field)
+		if !slices.Contains(recordFields, field) {
+			return nil, fmt.Errorf("invalid fields parameter: unknown field %q (must be one of %s)", field, strings.Join(recordFields, ", "))
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
 }
 
 // NewListRecordsParams creates parameters for record listing from HTTP request.
 // If cursor is present, all filter parameters are restored from the cursor.
-func NewListRecordsParams(r *http.Request) (*ListRecordsParams, error) {
+// external_id is not part of the cursor: it matches at most one record, so no cursor is issued with it.
+// Limits below minLimit are raised to it (config.MinPageLimit).
+func NewListRecordsParams(r *http.Request, minLimit int) (*ListRecordsParams, error) {
 	query := r.URL.Query()
 	cursorStr := query.Get("cursor")
 
@@ -579,13 +1801,13 @@ func NewListRecordsParams(r *http.Request) (*ListRecordsParams, error) {
 	if cursorStr != "" {
 		cursor, err := model.DecodeRecordCursor(cursorStr)
 		if err != nil {
-			return nil, fmt.Errorf("invalid cursor: %w", err)
+			return nil, fmt.Errorf("%w: %v", errInvalidCursor, err)
 		}
 
 		// Restore date range from cursor
 		dateRange, err := model.NewDateRange(cursor.From, cursor.To)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("%w: %v", errInvalidCursor, err)
 		}
 
 		// Restore tags from cursor
@@ -596,17 +1818,32 @@ func NewListRecordsParams(r *http.Request) (*ListRecordsParams, error) {
 		tags := model.NewTags(tagsStr)
 
 		// Create pagination with cursor
-		pagination, err := model.NewPagination(query.Get("limit"), cursorStr)
+		pagination, err := model.NewPaginationWithBounds(query.Get("limit"), cursorStr, minLimit, maxListRecordsLimit)
+		if err != nil {
+			return nil, err
+		}
+
+		// fieldsはカーソルに含めず、ページごとに指定する
+		fields, err := parseRecordFields(query.Get("fields"))
 		if err != nil {
 			return nil, err
 		}
 
+		// カーソルの位置はカーソルを発行した時のtime_fieldの日時
+		timeField, err := model.NewRecordTimeField(string(cursor.TimeField))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", errInvalidCursor, err)
+		}
+
 		pid := cursor.ProjectID
 		return &ListRecordsParams{
 			ProjectID:  &pid,
 			DateRange:  dateRange,
 			Tags:       tags,
+			Source:     cursor.Source,
 			Pagination: pagination,
+			Fields:     fields,
+			TimeField:  timeField,
 		}, nil
 	}
 
@@ -627,7 +1864,17 @@ func NewListRecordsParams(r *http.Request) (*ListRecordsParams, error) {
 
 	tags := model.NewTags(query.Get("tags"))
 
-	pagination, err := model.NewPagination(query.Get("limit"), "")
+	pagination, err := model.NewPaginationWithBounds(query.Get("limit"), "", minLimit, maxListRecordsLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := parseRecordFields(query.Get("fields"))
+	if err != nil {
+		return nil, err
+	}
+
+	timeField, err := model.NewRecordTimeField(query.Get("time_field"))
 	if err != nil {
 		return nil, err
 	}
@@ -636,7 +1883,11 @@ func NewListRecordsParams(r *http.Request) (*ListRecordsParams, error) {
 		ProjectID:  &pid,
 		DateRange:  dateRange,
 		Tags:       tags,
+		Source:     query.Get("source"),
+		ExternalID: strings.TrimSpace(query.Get("external_id")),
 		Pagination: pagination,
+		Fields:     fields,
+		TimeField:  timeField,
 	}, nil
 }
 
@@ -646,10 +1897,44 @@ type ListRecordsResponse struct {
 	Cursor *string         `json:"cursor,omitempty"`
 }
 
+// projectedListRecordsResponse はfieldsで指定したフィールドのみを含むレコード一覧のレスポンスです。
+type projectedListRecordsResponse struct {
+	Items  []map[string]json.RawMessage `json:"items"`
+	Cursor *string                      `json:"cursor,omitempty"`
+}
+
+// project はレコードをJSONに変換し、指定したフィールドのみを残したレスポンスを返します。
+// 省略可能なフィールド（value_floatなど）は値がない場合は含まれません。
+func (resp *ListRecordsResponse) project(fields []string) (*projectedListRecordsResponse, error) {
+	projected := &projectedListRecordsResponse{
+		Items:  make([]map[string]json.RawMessage, 0, len(resp.Items)),
+		Cursor: resp.Cursor,
+	}
+	for _, record := range resp.Items {
+		data, err := json.Marshal(record)
+		if err != nil {
+			return nil, err
+		}
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(data, &object); err != nil {
+			return nil, err
+		}
+		maps.DeleteFunc(object, func(key string, _ json.RawMessage) bool {
+			return !slices.Contains(fields, key)
+		})
+		projected.Items = append(projected.Items, object)
+	}
+	return projected, nil
+}
+
 // handleListRecords はプロジェクトに属するレコードの一覧を取得するハンドラーです。
 func (s *Server) handleListRecords(w http.ResponseWriter, r *http.Request) {
 	// パラメータを検証
-	params, err := NewListRecordsParams(r)
+	params, err := NewListRecordsParams(r, s.config.MinPageLimit)
+	if errors.Is(err, errInvalidCursor) {
+		writeJSONErrorCode(w, err.Error(), errorCodeInvalidCursor, http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		writeJSONError(w, err.Error(), http.StatusBadRequest)
 		return
@@ -661,12 +1946,12 @@ func (s *Server) handleListRecords(w http.ResponseWriter, r *http.Request) {
 	if params.Pagination.Cursor() != nil {
 		decodedCursor, err := model.DecodeRecordCursor(*params.Pagination.Cursor())
 		if err != nil {
-			writeJSONError(w, fmt.Sprintf("Invalid cursor: %v", err), http.StatusBadRequest)
+			writeJSONErrorCode(w, fmt.Sprintf("Invalid cursor: %v", err), errorCodeInvalidCursor, http.StatusBadRequest)
 			return
 		}
 		ts, err := time.Parse(time.RFC3339, decodedCursor.Timestamp)
 		if err != nil {
-			writeJSONError(w, "Invalid cursor timestamp", http.StatusBadRequest)
+			writeJSONErrorCode(w, "Invalid cursor timestamp", errorCodeInvalidCursor, http.StatusBadRequest)
 			return
 		}
 		cursorTimestamp = &ts
@@ -682,8 +1967,11 @@ func (s *Server) handleListRecords(w http.ResponseWriter, r *http.Request) {
 		To:              params.DateRange.To(),
 		Pagination:      params.Pagination,
 		Tags:            params.Tags.Values(),
+		Source:          params.Source,
+		ExternalID:      params.ExternalID,
 		CursorTimestamp: cursorTimestamp,
 		CursorID:        cursorID,
+		TimeField:       params.TimeField,
 	}
 
 	// レコードの取得（limit+1 件取得して次ページの有無を判定）
@@ -711,24 +1999,39 @@ func (s *Server) handleListRecords(w http.ResponseWriter, r *http.Request) {
 		// limit+1 件取得できた場合、次ページが存在する
 		response.Items = records[:originalLimit]
 		lastRecord := records[originalLimit-1]
+		position := lastRecord.Timestamp
+		if params.TimeField == model.RecordTimeFieldCreatedAt {
+			position = lastRecord.CreatedAt
+		}
 
 		// 次ページ用のカーソルをエンコード
 		cursor := model.EncodeRecordCursor(
-			lastRecord.Timestamp,
+			position,
 			lastRecord.ID,
 			projectID,
 			params.DateRange.From(),
 			params.DateRange.To(),
 			params.Tags.Values(),
+			params.Source,
+			params.TimeField,
 		)
 		response.Cursor = &cursor
 	}
 
-	// レスポンスの返却
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		log.Printf("Error encoding response: %v", err)
+	// fieldsが指定された場合はそのフィールドのみを返す
+	if len(params.Fields) > 0 {
+		projected, err := response.project(params.Fields)
+		if err != nil {
+			log.Printf("Error projecting records: %v", err)
+			writeJSONError(w, "Failed to encode records", http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, r, http.StatusOK, projected)
+		return
 	}
+
+	// レスポンスの返却
+	writeJSON(w, r, http.StatusOK, response)
 }
 
 // GetProjectParams represents parameters for getting project info.
@@ -768,32 +2071,35 @@ func (s *Server) handleGetProject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// レスポンスの設定
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-
 	// JSONとしてレスポンスを返す
-	if err := json.NewEncoder(w).Encode(project); err != nil {
-		log.Printf("Error encoding response: %v", err)
-	}
+	writeJSON(w, r, http.StatusOK, project)
 }
 
 // ListProjectsParams はプロジェクト一覧取得のパラメータです。
 type ListProjectsParams struct {
 	Pagination *model.Pagination
+	Sort       model.ProjectSort
 }
 
 // NewListProjectsParams はリクエストからプロジェクト一覧取得のパラメータを作成します。
-func NewListProjectsParams(r *http.Request) (*ListProjectsParams, error) {
+// minLimitより小さいlimitはminLimitに引き上げます（config.MinPageLimit）。
+func NewListProjectsParams(r *http.Request, minLimit int) (*ListProjectsParams, error) {
 	query := r.URL.Query()
 
-	pagination, err := model.NewPagination(query.Get("limit"), query.Get("cursor"))
+	pagination, err := model.NewPaginationWithBounds(query.Get("limit"), query.Get("cursor"), minLimit, maxListProjectsLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	// 並び順（未指定の場合は更新日時の降順）
+	sort, err := model.NewProjectSort(query.Get("sort"))
 	if err != nil {
 		return nil, err
 	}
 
 	return &ListProjectsParams{
 		Pagination: pagination,
+		Sort:       sort,
 	}, nil
 }
 
@@ -806,36 +2112,58 @@ type ListProjectsResponse struct {
 // handleListProjects はプロジェクト一覧取得をハンドリングします。
 func (s *Server) handleListProjects(w http.ResponseWriter, r *http.Request) {
 	// パラメータを検証
-	params, err := NewListProjectsParams(r)
+	params, err := NewListProjectsParams(r, s.config.MinPageLimit)
 	if err != nil {
 		writeJSONError(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
+	// プロジェクトの取得（limit+1 件取得して次ページの有無を判定）
+	originalLimit := params.Pagination.Limit()
+	storeParams := &store.ListProjectsParams{
+		Pagination: model.NewPaginationWithValues(originalLimit+1, params.Pagination.Cursor()),
+		Sort:       params.Sort,
+	}
+
 	// Decode cursor if present to extract position information
-	var cursorUpdatedAt *time.Time
-	var cursorName *string
 	if params.Pagination.Cursor() != nil {
 		decodedCursor, err := model.DecodeProjectCursor(*params.Pagination.Cursor())
 		if err != nil {
-			writeJSONError(w, fmt.Sprintf("Invalid cursor: %v", err), http.StatusBadRequest)
+			writeJSONErrorCode(w, fmt.Sprintf("Invalid cursor: %v", err), errorCodeInvalidCursor, http.StatusBadRequest)
 			return
 		}
-		updatedAt, err := time.Parse(time.RFC3339, decodedCursor.UpdatedAt)
-		if err != nil {
-			writeJSONError(w, "Invalid cursor updated_at", http.StatusBadRequest)
+		// 並び順ごとにカーソルの形式が異なるため、別の並び順のカーソルは受け付けない
+		cursorSort := decodedCursor.Sort
+		if cursorSort == "" {
+			cursorSort = model.ProjectSortUpdated
+		}
+		if cursorSort != params.Sort {
+			writeJSONErrorCode(w, "Invalid cursor: sort does not match", errorCodeInvalidCursor, http.StatusBadRequest)
 			return
 		}
-		cursorUpdatedAt = &updatedAt
-		cursorName = &decodedCursor.Name
-	}
-
-	// プロジェクトの取得（limit+1 件取得して次ページの有無を判定）
-	originalLimit := params.Pagination.Limit()
-	storeParams := &store.ListProjectsParams{
-		Pagination:      model.NewPaginationWithValues(originalLimit+1, params.Pagination.Cursor()),
-		CursorUpdatedAt: cursorUpdatedAt,
-		CursorName:      cursorName,
+		switch params.Sort {
+		case model.ProjectSortUpdated:
+			updatedAt, err := time.Parse(time.RFC3339, decodedCursor.UpdatedAt)
+			if err != nil {
+				writeJSONErrorCode(w, "Invalid cursor updated_at", errorCodeInvalidCursor, http.StatusBadRequest)
+				return
+			}
+			storeParams.CursorUpdatedAt = &updatedAt
+		case model.ProjectSortCreated:
+			createdAt, err := time.Parse(time.RFC3339, decodedCursor.CreatedAt)
+			if err != nil {
+				writeJSONErrorCode(w, "Invalid cursor created_at", errorCodeInvalidCursor, http.StatusBadRequest)
+				return
+			}
+			storeParams.CursorCreatedAt = &createdAt
+		case model.ProjectSortRecords:
+			if decodedCursor.RecordCount == nil {
+				writeJSONErrorCode(w, "Invalid cursor record_count", errorCodeInvalidCursor, http.StatusBadRequest)
+				return
+			}
+			storeParams.CursorRecordCount = decodedCursor.RecordCount
+		}
+		storeParams.CursorName = &decodedCursor.Name
 	}
 
 	projects, err := s.store.ListProjects(r.Context(), storeParams)
@@ -860,18 +2188,26 @@ func (s *Server) handleListProjects(w http.ResponseWriter, r *http.Request) {
 		lastProject := projects[originalLimit-1]
 
 		// 次ページ用のカーソルをエンコード
-		cursor := model.EncodeProjectCursor(
-			lastProject.UpdatedAt,
-			lastProject.Name,
-		)
+		cursor := model.EncodeSortedProjectCursor(params.Sort, lastProject)
 		response.Cursor = &cursor
 	}
 
 	// レスポンスの返却
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		log.Printf("Error encoding response: %v", err)
+	writeJSON(w, r, http.StatusOK, response)
+}
+
+// writeProjectLimitError はプロジェクト数が上限（config.MaxProjects）に達している場合の409を書き込みます。
+// 上限の確認は同時の作成で超えないよう、ストアが作成と同じトランザクション内で行います。
+func (s *Server) writeProjectLimitError(w http.ResponseWriter) {
+	writeJSONError(w, fmt.Sprintf("Project limit reached (max %d projects)", s.config.MaxProjects), http.StatusConflict)
+}
+
+// projectName はSOUGEN_NORMALIZE_PROJECT_NAMESが有効な場合にプロジェクト名を正規化します（model.NormalizeProjectName）。
+func (s *Server) projectName(name string) (string, error) {
+	if !s.config.NormalizeProjectNames {
+		return name, nil
 	}
+	return model.NormalizeProjectName(name)
 }
 
 // handleCreateProject はプロジェクト作成をハンドリングします。
@@ -884,36 +2220,75 @@ func (s *Server) handleCreateProject(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// JSONのパース
+	// スキーマ検証（すべての違反をまとめて報告）
+	if err := validateBody(createProjectSchema, body); err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	var projectData struct {
-		Name        string `json:"name"`
-		Description string `json:"description"`
+		Name              string  `json:"name"`
+		Description       string  `json:"description"`
+		TrackDefaultValue *int    `json:"track_default_value"`
+		Color             *string `json:"color"`
+		ValueType         string  `json:"value_type"`
+		RetentionDays     *int    `json:"retention_days"`
+
+		MinValue *float64 `json:"min_value"`
+		MaxValue *float64 `json:"max_value"`
+		Timezone *string  `json:"timezone"`
 	}
 	if err := json.Unmarshal(body, &projectData); err != nil {
 		writeJSONError(w, "Invalid JSON format", http.StatusBadRequest)
 		return
 	}
 
-	// プロジェクトの作成
-	project, err := model.NewProject(projectData.Name, projectData.Description)
+	// プロジェクトの作成（設定されている場合は名前を正規化）
+	name, err := s.projectName(projectData.Name)
+	if err != nil {
+		writeJSONError(w, fmt.Sprintf("Invalid project data: %v", err), http.StatusBadRequest)
+		return
+	}
+	project, err := model.NewProject(name, projectData.Description)
+	if err != nil {
+		writeJSONError(w, fmt.Sprintf("Invalid project data: %v", err), http.StatusBadRequest)
+		return
+	}
+	project.TrackDefaultValue = projectData.TrackDefaultValue
+	project.Color = projectData.Color
+	project.RetentionDays = projectData.RetentionDays
+	project.MinValue = projectData.MinValue
+	project.MaxValue = projectData.MaxValue
+	// 空文字列の場合はタイムゾーンを設定しない
+	if projectData.Timezone != nil && *projectData.Timezone != "" {
+		project.Timezone = projectData.Timezone
+	}
+	project.ValueType, err = model.NewValueType(projectData.ValueType)
 	if err != nil {
 		writeJSONError(w, fmt.Sprintf("Invalid project data: %v", err), http.StatusBadRequest)
 		return
 	}
+	if err := project.Validate(); err != nil {
+		writeJSONError(w, fmt.Sprintf("Invalid project data: %v", err), http.StatusBadRequest)
+		return
+	}
 
-	// データベースに保存
-	if err := s.store.CreateProject(r.Context(), project); err != nil {
+	// データベースに保存（プロジェクト数の上限を含めて確認）
+	if err := s.store.CreateProjectWithLimit(r.Context(), project, s.config.MaxProjects); err != nil {
+		if errors.Is(err, model.ErrProjectNameTaken) {
+			writeJSONError(w, fmt.Sprintf("Project name %q is already taken", project.Name), http.StatusConflict)
+			return
+		}
+		if errors.Is(err, model.ErrProjectLimitReached) {
+			s.writeProjectLimitError(w)
+			return
+		}
 		writeJSONError(w, fmt.Sprintf("Failed to create project: %v", err), http.StatusInternalServerError)
 		return
 	}
 
-	// レスポンスの設定
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-
 	// 作成されたプロジェクトをJSONとして返す
-	if err := json.NewEncoder(w).Encode(project); err != nil {
-		log.Printf("Error encoding response: %v", err)
-	}
+	writeJSON(w, r, http.StatusCreated, project)
 }
 
 // handleUpdateProject はプロジェクト更新をハンドリングします。
@@ -943,10 +2318,26 @@ func (s *Server) handleUpdateProject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// スキーマ検証（すべての違反をまとめて報告）
+	if err := validateBody(updateProjectSchema, body); err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	// JSONのパース（部分更新をサポートするためポインタ型を使用）
 	var updateData struct {
-		Name        *string `json:"name"`
-		Description *string `json:"description"`
+		Name              *string `json:"name"`
+		Description       *string `json:"description"`
+		TrackDefaultValue *int    `json:"track_default_value"`
+		Color             *string `json:"color"`
+		ValueType         *string `json:"value_type"`
+		RetentionDays     *int    `json:"retention_days"`
+
+		// 0も有効な境界値のため、nullによる解除と省略を区別できるよう生のJSONで受け取る
+		MinValue json.RawMessage `json:"min_value"`
+		MaxValue json.RawMessage `json:"max_value"`
+
+		Timezone *string `json:"timezone"`
 	}
 	if err := json.Unmarshal(body, &updateData); err != nil {
 		writeJSONError(w, "Invalid JSON format", http.StatusBadRequest)
@@ -955,11 +2346,64 @@ func (s *Server) handleUpdateProject(w http.ResponseWriter, r *http.Request) {
 
 	// プロジェクトの部分更新（指定されたフィールドのみ更新）
 	if updateData.Name != nil {
-		existingProject.Name = *updateData.Name
+		name, err := s.projectName(*updateData.Name)
+		if err != nil {
+			writeJSONError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		existingProject.Name = name
 	}
 	if updateData.Description != nil {
 		existingProject.Description = *updateData.Description
 	}
+	if updateData.TrackDefaultValue != nil {
+		existingProject.TrackDefaultValue = updateData.TrackDefaultValue
+	}
+	// 空文字列の場合は表示色を解除
+	if updateData.Color != nil {
+		if *updateData.Color == "" {
+			existingProject.Color = nil
+		} else {
+			existingProject.Color = updateData.Color
+		}
+	}
+	if updateData.ValueType != nil {
+		valueType, err := model.NewValueType(*updateData.ValueType)
+		if err != nil {
+			writeJSONError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		existingProject.ValueType = valueType
+	}
+	// 0の場合は保持期間を解除（無期限）
+	if updateData.RetentionDays != nil {
+		if *updateData.RetentionDays == 0 {
+			existingProject.RetentionDays = nil
+		} else {
+			existingProject.RetentionDays = updateData.RetentionDays
+		}
+	}
+	// nullの場合は値の下限・上限を解除
+	if updateData.MinValue != nil {
+		if err := json.Unmarshal(updateData.MinValue, &existingProject.MinValue); err != nil {
+			writeJSONError(w, "Invalid JSON format", http.StatusBadRequest)
+			return
+		}
+	}
+	if updateData.MaxValue != nil {
+		if err := json.Unmarshal(updateData.MaxValue, &existingProject.MaxValue); err != nil {
+			writeJSONError(w, "Invalid JSON format", http.StatusBadRequest)
+			return
+		}
+	}
+	// 空文字列の場合はタイムゾーンを解除（サーバーのタイムゾーン）
+	if updateData.Timezone != nil {
+		if *updateData.Timezone == "" {
+			existingProject.Timezone = nil
+		} else {
+			existingProject.Timezone = updateData.Timezone
+		}
+	}
 	existingProject.UpdatedAt = time.Now()
 
 	// バリデーション
@@ -970,23 +2414,23 @@ func (s *Server) handleUpdateProject(w http.ResponseWriter, r *http.Request) {
 
 	// データベースに保存
 	if err := s.store.UpdateProject(r.Context(), existingProject); err != nil {
+		if errors.Is(err, model.ErrProjectNameTaken) {
+			writeJSONError(w, fmt.Sprintf("Project name %q is already taken", existingProject.Name), http.StatusConflict)
+			return
+		}
 		writeJSONError(w, fmt.Sprintf("Failed to update project: %v", err), http.StatusInternalServerError)
 		return
 	}
-
-	// レスポンスの設定
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
+	s.graphCache.invalidateProject(existingProject.ID)
 
 	// 更新されたプロジェクトをJSONとして返す
-	if err := json.NewEncoder(w).Encode(existingProject); err != nil {
-		log.Printf("Error encoding response: %v", err)
-	}
+	writeJSON(w, r, http.StatusOK, existingProject)
 }
 
 // DeleteProjectParams represents parameters for deleting a project.
 type DeleteProjectParams struct {
 	ProjectID model.HexID
+	Report    bool // 削除結果（存在したか・削除したレコード数）を返すか
 }
 
 // NewDeleteProjectParams creates parameters for project deletion from HTTP request.
@@ -996,8 +2440,14 @@ func NewDeleteProjectParams(r *http.Request) (*DeleteProjectParams, error) {
 		return nil, fmt.Errorf("invalid project_id: %w", err)
 	}
 
+	report, err := parseBoolQuery(r.URL.Query(), "report")
+	if err != nil {
+		return nil, err
+	}
+
 	return &DeleteProjectParams{
 		ProjectID: projectID,
+		Report:    report,
 	}, nil
 }
 
@@ -1011,23 +2461,142 @@ func (s *Server) handleDeleteProject(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// プロジェクト削除の実行（べき等性：既に存在しない場合もエラーにしない）
-	err = s.store.DeleteProject(r.Context(), params.ProjectID)
+	result, err := s.store.DeleteProject(r.Context(), params.ProjectID)
 	if err != nil {
-		// プロジェクトが存在しない場合は成功とみなす（べき等性）
-		if errors.Is(err, model.ErrProjectNotFound) {
-			w.WriteHeader(http.StatusNoContent)
-			return
-		}
-		// その他のエラーの場合は500を返す
 		log.Printf("Error deleting project: %v", err)
 		writeJSONError(w, fmt.Sprintf("Failed to delete project: %v", err), http.StatusInternalServerError)
 		return
 	}
+	s.graphCache.invalidateProject(params.ProjectID)
+
+	// report=trueの場合は、存在しなかったプロジェクトも含めて削除結果を返す
+	if params.Report {
+		writeJSON(w, r, http.StatusOK, result)
+		return
+	}
 
 	// 成功した場合は204 No Contentを返す
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// CloneProjectParams represents parameters for cloning a project.
+type CloneProjectParams struct {
+	ProjectID   model.HexID
+	Name        string // 複製先のプロジェクト名
+	WithRecords bool   // レコードも複製するか
+}
+
+// NewCloneProjectParams creates parameters for project cloning from HTTP request.
+func NewCloneProjectParams(r *http.Request) (*CloneProjectParams, error) {
+	projectID, err := model.ParseHexID(r.PathValue("project_id"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid project_id: %w", err)
+	}
+
+	withRecords, err := parseBoolQuery(r.URL.Query(), "with_records")
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body")
+	}
+	// スキーマ検証（すべての違反をまとめて報告）
+	if err := validateBody(cloneProjectSchema, body); err != nil {
+		return nil, err
+	}
+	var cloneData struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(body, &cloneData); err != nil {
+		return nil, fmt.Errorf("invalid JSON format")
+	}
+
+	return &CloneProjectParams{
+		ProjectID:   projectID,
+		Name:        cloneData.Name,
+		WithRecords: withRecords,
+	}, nil
+}
+
+// handleCloneProject は既存のプロジェクトの設定（説明・trackの既定値・表示色・値の型）を引き継いだ
+// 新しいプロジェクトを作成するハンドラーです。with_records=trueの場合はレコードも複製します。
+func (s *Server) handleCloneProject(w http.ResponseWriter, r *http.Request) {
+	// パラメータを検証
+	params, err := NewCloneProjectParams(r)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// 複製元のプロジェクトを取得
+	source, err := s.store.GetProject(r.Context(), params.ProjectID)
+	if err != nil {
+		if errors.Is(err, model.ErrProjectNotFound) {
+			writeJSONError(w, fmt.Sprintf("Project with ID %s not found", params.ProjectID), http.StatusNotFound)
+		} else {
+			writeJSONError(w, fmt.Sprintf("Error retrieving project: %v", err), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// 複製先のプロジェクトを作成（設定されている場合は名前を正規化）
+	name, err := s.projectName(params.Name)
+	if err != nil {
+		writeJSONError(w, fmt.Sprintf("Invalid project data: %v", err), http.StatusBadRequest)
+		return
+	}
+	project, err := model.NewProject(name, source.Description)
+	if err != nil {
+		writeJSONError(w, fmt.Sprintf("Invalid project data: %v", err), http.StatusBadRequest)
+		return
+	}
+	if source.TrackDefaultValue != nil {
+		trackDefaultValue := *source.TrackDefaultValue
+		project.TrackDefaultValue = &trackDefaultValue
+	}
+	if source.Color != nil {
+		color := *source.Color
+		project.Color = &color
+	}
+	project.ValueType = source.RecordValueType()
+	if source.RetentionDays != nil {
+		retentionDays := *source.RetentionDays
+		project.RetentionDays = &retentionDays
+	}
+	if source.MinValue != nil {
+		minValue := *source.MinValue
+		project.MinValue = &minValue
+	}
+	if source.MaxValue != nil {
+		maxValue := *source.MaxValue
+		project.MaxValue = &maxValue
+	}
+	if source.Timezone != nil {
+		timezone := *source.Timezone
+		project.Timezone = &timezone
+	}
+
+	// データベースに保存（上限の確認とレコードの複製を含めて1つのトランザクションで実行）
+	if _, err := s.store.CloneProject(r.Context(), params.ProjectID, project, params.WithRecords, s.config.MaxProjects); err != nil {
+		switch {
+		case errors.Is(err, model.ErrProjectNameTaken):
+			writeJSONError(w, fmt.Sprintf("Project name %q is already taken", project.Name), http.StatusConflict)
+		case errors.Is(err, model.ErrProjectLimitReached):
+			s.writeProjectLimitError(w)
+		case errors.Is(err, model.ErrProjectNotFound):
+			writeJSONError(w, fmt.Sprintf("Project with ID %s not found", params.ProjectID), http.StatusNotFound)
+		default:
+			writeJSONError(w, fmt.Sprintf("Failed to clone project: %v", err), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// 作成されたプロジェクトをJSONとして返す
+	writeJSON(w, r, http.StatusCreated, project)
+}
+
 // handleBulkDeleteRecords は条件に一致するレコードをまとめて削除するハンドラーです。
 func (s *Server) handleBulkDeleteRecords(w http.ResponseWriter, r *http.Request) {
 	// リクエストボディの読み取り
@@ -1067,73 +2636,168 @@ func (s *Server) handleBulkDeleteRecords(w http.ResponseWriter, r *http.Request)
 		writeJSONError(w, "Failed to delete records", http.StatusInternalServerError)
 		return
 	}
+	s.graphCache.invalidateProject(deletionData.ProjectID)
 
 	// 削除結果をJSONで返す
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	response := map[string]int{
+	writeJSON(w, r, http.StatusOK, map[string]int{
 		"deleted_count": count,
-	}
-
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		log.Printf("Error encoding response: %v", err)
-	}
+	})
 }
 
 // GetProjectTagsParams represents parameters for getting project tags.
 type GetProjectTagsParams struct {
-	ProjectID model.HexID
+	ProjectID  model.HexID
+	Pagination *model.Pagination // nil if neither limit nor cursor is given (returns all tags)
 }
 
 // NewGetProjectTagsParams creates parameters for project tags retrieval from HTTP request.
-func NewGetProjectTagsParams(r *http.Request) (*GetProjectTagsParams, error) {
+// Limits below minLimit are raised to it (config.MinPageLimit).
+func NewGetProjectTagsParams(r *http.Request, minLimit int) (*GetProjectTagsParams, error) {
 	projectID, err := model.ParseHexID(r.PathValue("project_id"))
 	if err != nil {
 		return nil, fmt.Errorf("invalid project_id: %w", err)
 	}
 
+	// 互換性のため、limitもcursorも指定されていない場合はページネーションしない
+	query := r.URL.Query()
+	var pagination *model.Pagination
+	if query.Has("limit") || query.Has("cursor") {
+		pagination, err = model.NewPaginationWithBounds(query.Get("limit"), query.Get("cursor"), minLimit, maxListTagsLimit)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &GetProjectTagsParams{
-		ProjectID: projectID,
+		ProjectID:  projectID,
+		Pagination: pagination,
 	}, nil
 }
 
+// ListTagsResponse はページネーションされたタグ一覧のレスポンスです。
+type ListTagsResponse struct {
+	Items  []string `json:"items"`
+	Cursor *string  `json:"cursor,omitempty"`
+}
+
+// writeProjectTagsError はタグ取得時のエラーをレスポンスに変換します。
+// プロジェクトが存在しない場合は404を返します。
+func writeProjectTagsError(w http.ResponseWriter, err error, projectID model.HexID) {
+	if errors.Is(err, model.ErrProjectNotFound) {
+		writeJSONError(w, fmt.Sprintf("Project with ID %s not found", projectID), http.StatusNotFound)
+		return
+	}
+	log.Printf("Error retrieving project tags: %v", err)
+	writeJSONError(w, "Failed to retrieve project tags", http.StatusInternalServerError)
+}
+
 // handleGetProjectTags はプロジェクト内のタグ一覧を取得するハンドラーです。
+// プロジェクトが存在しない場合は404、タグのないプロジェクトの場合は空配列を返します。
 func (s *Server) handleGetProjectTags(w http.ResponseWriter, r *http.Request) {
 	// パラメータを検証
-	params, err := NewGetProjectTagsParams(r)
+	params, err := NewGetProjectTagsParams(r, s.config.MinPageLimit)
 	if err != nil {
 		writeJSONError(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// プロジェクトの存在確認
-	_, err = s.store.GetProject(r.Context(), params.ProjectID)
-	if err != nil {
-		if errors.Is(err, model.ErrProjectNotFound) {
-			writeJSONError(w, fmt.Sprintf("Project with ID %s not found", params.ProjectID), http.StatusNotFound)
-		} else {
-			writeJSONError(w, fmt.Sprintf("Error retrieving project: %v", err), http.StatusInternalServerError)
+	// ページネーションなしの場合は従来どおりタグの配列を返す
+	if params.Pagination == nil {
+		tags, err := s.store.GetProjectTags(r.Context(), &store.GetProjectTagsParams{
+			ProjectID: params.ProjectID,
+		})
+		if err != nil {
+			writeProjectTagsError(w, err, params.ProjectID)
+			return
 		}
+		// 空配列を返すためにnilチェック
+		if tags == nil {
+			tags = []string{}
+		}
+
+		// レスポンスの返却
+		writeJSON(w, r, http.StatusOK, tags)
 		return
 	}
 
-	// タグの取得
-	tags, err := s.store.GetProjectTags(r.Context(), params.ProjectID)
+	// Decode cursor if present to extract position information
+	var cursorTag *string
+	if params.Pagination.Cursor() != nil {
+		decodedCursor, err := model.DecodeTagCursor(*params.Pagination.Cursor())
+		if err != nil {
+			writeJSONErrorCode(w, fmt.Sprintf("Invalid cursor: %v", err), errorCodeInvalidCursor, http.StatusBadRequest)
+			return
+		}
+		cursorTag = &decodedCursor.Tag
+	}
+
+	// タグの取得（limit+1 件取得して次ページの有無を判定）
+	originalLimit := params.Pagination.Limit()
+	tags, err := s.store.GetProjectTags(r.Context(), &store.GetProjectTagsParams{
+		ProjectID:  params.ProjectID,
+		Pagination: model.NewPaginationWithValues(originalLimit+1, params.Pagination.Cursor()),
+		CursorTag:  cursorTag,
+	})
 	if err != nil {
-		log.Printf("Error retrieving project tags: %v", err)
-		writeJSONError(w, "Failed to retrieve project tags", http.StatusInternalServerError)
+		writeProjectTagsError(w, err, params.ProjectID)
 		return
 	}
 
-	// レスポンスの返却
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(tags); err != nil {
-		log.Printf("Error encoding response: %v", err)
+	// レスポンスの構築
+	response := &ListTagsResponse{
+		Items: tags,
+	}
+	// 空配列を返すためにnilチェック
+	if response.Items == nil {
+		response.Items = []string{}
+	}
+
+	// 次ページのカーソルを生成
+	if len(tags) > originalLimit {
+		// limit+1 件取得できた場合、次ページが存在する
+		response.Items = tags[:originalLimit]
+		cursor := model.EncodeTagCursor(tags[originalLimit-1])
+		response.Cursor = &cursor
 	}
+
+	// レスポンスの返却
+	writeJSON(w, r, http.StatusOK, response)
 }
 
 // Run はサーバーを指定されたアドレスで起動します。
+// config.RetentionSweepIntervalが正の場合は、保持期間を過ぎたレコードを削除するスイーパーも起動します。
 func (s *Server) Run(addr string) error {
 	log.Printf("Server starting on %s", addr)
-	return http.ListenAndServe(addr, s)
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	if s.config.RetentionSweepInterval > 0 {
+		s.sweeperDone.Add(1)
+		go func() {
+			defer s.sweeperDone.Done()
+			s.runRetentionSweeper(s.sweeperCtx, s.config.RetentionSweepInterval)
+		}()
+	}
+
+	return s.serve(ln)
+}
+
+// serve はlnで接続を受け付けます。
+// TLSの証明書と秘密鍵が設定されている場合はHTTPSで待ち受けます（HTTP/2はnet/httpが自動で有効にする）。
+func (s *Server) serve(ln net.Listener) error {
+	if s.config.TLSCertFile != "" && s.config.TLSKeyFile != "" {
+		return s.httpServer.ServeTLS(ln, s.config.TLSCertFile, s.config.TLSKeyFile)
+	}
+	return s.httpServer.Serve(ln)
+}
+
+// Shutdown は処理中のリクエストの完了を待ってサーバーを停止します。
+// 保持期間のスイーパーは停止し、実行中の削除の完了を待ちます。
+func (s *Server) Shutdown(ctx context.Context) error {
+	err := s.httpServer.Shutdown(ctx)
+	s.stopSweeper()
+	s.sweeperDone.Wait()
+	return err
 }
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/stsysd/sougen/model"
+)
+
+// ShiftRecordsParams represents parameters for copying a day's records to another day.
+type ShiftRecordsParams struct {
+	ProjectID model.HexID
+	FromDate  time.Time // 00:00:00 of the source day (local time)
+	ToDate    time.Time // 00:00:00 of the destination day (local time)
+}
+
+// NewShiftRecordsParams creates parameters for copying records from HTTP request.
+func NewShiftRecordsParams(r *http.Request) (*ShiftRecordsParams, error) {
+	projectID, err := model.ParseHexID(r.PathValue("project_id"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid project_id: %w", err)
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body")
+	}
+	// スキーマ検証（すべての違反をまとめて報告）
+	if err := validateBody(shiftRecordsSchema, body); err != nil {
+		return nil, err
+	}
+	var shiftData struct {
+		FromDate string `json:"from_date"`
+		ToDate   string `json:"to_date"`
+	}
+	if err := json.Unmarshal(body, &shiftData); err != nil {
+		return nil, fmt.Errorf("invalid JSON format")
+	}
+
+	// 日付はサーバーのタイムゾーンの暦日（YYYY-MM-DD）
+	fromDate, err := time.ParseInLocation("2006-01-02", shiftData.FromDate, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from_date: must be YYYY-MM-DD")
+	}
+	toDate, err := time.ParseInLocation("2006-01-02", shiftData.ToDate, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("invalid to_date: must be YYYY-MM-DD")
+	}
+	if fromDate.Equal(toDate) {
+		return nil, fmt.Errorf("to_date must be a different day from from_date")
+	}
+
+	return &ShiftRecordsParams{
+		ProjectID: projectID,
+		FromDate:  fromDate,
+		ToDate:    toDate,
+	}, nil
+}
+
+// handleShiftRecords はfrom_dateの日のレコードを同じ時刻・値・タグのままto_dateの日に複製するハンドラーです。
+// 複製は1つのトランザクションで行い、作成したレコード数を返します。
+func (s *Server) handleShiftRecords(w http.ResponseWriter, r *http.Request) {
+	// パラメータを検証
+	params, err := NewShiftRecordsParams(r)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	count, err := s.store.CopyDayRecords(r.Context(), params.ProjectID, params.FromDate, params.ToDate)
+	if err != nil {
+		var validationErr *model.ValidationError
+		switch {
+		case errors.As(err, &validationErr):
+			writeJSONError(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, model.ErrProjectNotFound):
+			writeJSONError(w, fmt.Sprintf("Project with ID %s not found", params.ProjectID), http.StatusNotFound)
+		default:
+			log.Printf("Error copying records: %v", err)
+			writeJSONError(w, "Failed to copy records", http.StatusInternalServerError)
+		}
+		return
+	}
+	s.graphCache.invalidateProject(params.ProjectID)
+
+	// 複製結果をJSONで返す
+	writeJSON(w, r, http.StatusOK, map[string]int{
+		"created_count": count,
+	})
+}
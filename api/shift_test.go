@@ -0,0 +1,70 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stsysd/sougen/model"
+)
+
+func TestShiftRecordsEndpoint(t *testing.T) {
+	mockStore := NewMockStore()
+	project, _ := model.NewProject("shift-project", "")
+	mockStore.CreateProject(context.Background(), project)
+	server := NewServer(mockStore, newTestConfig())
+
+	for _, hour := range []int{8, 20} {
+		record, _ := model.NewRecord(time.Date(2025, 6, 1, hour, 0, 0, 0, time.Local), project.ID, 1, []string{"routine"})
+		mockStore.CreateRecord(context.Background(), record)
+	}
+
+	do := func(target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		req.Header.Set("X-API-Key", testAPIKey)
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		return w
+	}
+	shiftURL := fmt.Sprintf("/api/v0/p/%s/records/shift", project.ID)
+
+	w := do(shiftURL, `{"from_date":"2025-06-01","to_date":"2025-06-02"}`)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"created_count":2`) {
+		t.Fatalf("Expected created_count 2, got %d: %s", w.Code, w.Body.String())
+	}
+	if len(mockStore.records) != 4 {
+		t.Errorf("Expected 4 records in total, got %d", len(mockStore.records))
+	}
+	copied := 0
+	for _, record := range mockStore.records {
+		if record.Timestamp.Format("2006-01-02") == "2025-06-02" {
+			copied++
+		}
+	}
+	if copied != 2 {
+		t.Errorf("Expected 2 records on the destination day, got %d", copied)
+	}
+
+	tests := []struct {
+		name   string
+		target string
+		body   string
+		status int
+	}{
+		{"same day", shiftURL, `{"from_date":"2025-06-01","to_date":"2025-06-01"}`, http.StatusBadRequest},
+		{"missing to_date", shiftURL, `{"from_date":"2025-06-01"}`, http.StatusBadRequest},
+		{"invalid date", shiftURL, `{"from_date":"2025-06-01T00:00:00Z","to_date":"2025-06-02"}`, http.StatusBadRequest},
+		{"missing project", fmt.Sprintf("/api/v0/p/%s/records/shift", model.NewHexID(999)), `{"from_date":"2025-06-01","to_date":"2025-06-02"}`, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := do(tt.target, tt.body); w.Code != tt.status {
+				t.Errorf("Expected status code %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+}
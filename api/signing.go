@@ -0,0 +1,98 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stsysd/sougen/model"
+)
+
+// 署名付きグラフURLのクエリパラメータ
+const (
+	graphSignatureParam = "sig" // HMAC-SHA256の署名（16進数）
+	graphExpiryParam    = "exp" // 有効期限（Unix時間の秒）
+)
+
+// SignGraphURL はAPIキーなしでグラフを取得できる署名付きURL（BasePathを含むパス）を返します。
+// formatは拡張子（"svg"・"json"・"png"）で、空の場合は拡張子のないパスになります。
+// 署名はパス（プロジェクトIDと形式）とparamsのすべてのパラメータ、有効期限expを対象とするため、
+// パラメータや形式を書き換えたURLや期限切れのURLは拒否されます。config.SigningSecretが必要です。
+func (s *Server) SignGraphURL(projectID model.HexID, format string, params url.Values, exp time.Time) string {
+	path := fmt.Sprintf("/p/%s/graph", projectID)
+	if format != "" {
+		path += "." + format
+	}
+	return s.signURL(path, params, exp)
+}
+
+// SignBadgeURL はAPIキーなしでバッジを取得できる署名付きURL（BasePathを含むパス）を返します。
+// グラフの署名付きURLとは署名が異なり、互いに流用できません。config.SigningSecretが必要です。
+func (s *Server) SignBadgeURL(projectID model.HexID, params url.Values, exp time.Time) string {
+	return s.signURL(fmt.Sprintf("/api/v0/p/%s/badge.json", projectID), params, exp)
+}
+
+// signURL はBasePathを除いたパスpathにparamsと有効期限exp、その署名を付けたURLを返します。
+func (s *Server) signURL(path string, params url.Values, exp time.Time) string {
+	query := url.Values{}
+	for key, values := range params {
+		query[key] = append([]string(nil), values...)
+	}
+	query.Del(graphSignatureParam)
+	query.Set(graphExpiryParam, strconv.FormatInt(exp.Unix(), 10))
+	query.Set(graphSignatureParam, s.graphSignature(path, query))
+	return fmt.Sprintf("%s%s?%s", s.config.BasePath, path, query.Encode())
+}
+
+// graphSignature はBasePathを除いたパスとクエリ（sigを除く）に対するHMAC-SHA256の署名を返します。
+// パスにはプロジェクトIDとエンドポイント・形式が含まれるため、署名は発行したURLでのみ有効です。
+// url.Values.Encodeはキーの順に並べるため、パラメータの順序に依存しません。
+func (s *Server) graphSignature(path string, query url.Values) string {
+	signed := url.Values{}
+	for key, values := range query {
+		if key != graphSignatureParam {
+			signed[key] = values
+		}
+	}
+	mac := hmac.New(sha256.New, []byte(s.config.SigningSecret))
+	mac.Write([]byte(path + "\n" + signed.Encode()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// graphAuthMiddleware はグラフの認証を行うミドルウェアです。
+// config.SigningSecretが設定されていない場合、グラフは従来どおり認証なしで公開します。
+// 設定されている場合は、有効期限内の正しい署名（sig・exp）を持つリクエストのみauthMiddlewareを省略し、
+// 署名の不一致や期限切れは403を返します。署名のないリクエストにはAPIキーが必要です。
+func (s *Server) graphAuthMiddleware(next http.Handler) http.Handler {
+	authenticated := s.authMiddleware(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.config.SigningSecret == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		query := r.URL.Query()
+		if !query.Has(graphSignatureParam) {
+			authenticated.ServeHTTP(w, r)
+			return
+		}
+
+		signature := s.graphSignature(strings.TrimPrefix(r.URL.Path, s.config.BasePath), query)
+		if !hmac.Equal([]byte(query.Get(graphSignatureParam)), []byte(signature)) {
+			writeJSONError(w, "Invalid signature", http.StatusForbidden)
+			return
+		}
+		exp, err := strconv.ParseInt(query.Get(graphExpiryParam), 10, 64)
+		if err != nil || time.Now().Unix() > exp {
+			writeJSONError(w, "Signature has expired", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stsysd/sougen/model"
+)
+
+func TestSignedGraphURL(t *testing.T) {
+	mockStore := NewMockStore()
+	project, _ := model.NewProject("test-project", "Test project")
+	mockStore.CreateProject(context.Background(), project)
+	cfg := newTestConfig()
+	cfg.SigningSecret = "test-secret"
+	server := NewServer(mockStore, cfg)
+
+	get := func(target string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		return w
+	}
+
+	params := url.Values{"theme": {"cividis"}, "from": {"2025-01-01"}, "to": {"2025-06-30"}}
+	signed := server.SignGraphURL(project.ID, "", params, time.Now().Add(time.Hour))
+
+	t.Run("valid signature", func(t *testing.T) {
+		if w := get(signed, nil); w.Code != http.StatusOK {
+			t.Errorf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		// 形式を指定して署名したパスも有効
+		svg := server.SignGraphURL(project.ID, "svg", params, time.Now().Add(time.Hour))
+		if !strings.Contains(svg, "/graph.svg?") {
+			t.Fatalf("Expected a graph.svg URL, got %q", svg)
+		}
+		if w := get(svg, nil); w.Code != http.StatusOK {
+			t.Errorf("Expected status code %d for graph.svg, got %d", http.StatusOK, w.Code)
+		}
+		badge := server.SignBadgeURL(project.ID, url.Values{"label": {"total"}}, time.Now().Add(time.Hour))
+		if w := get(badge, nil); w.Code != http.StatusOK {
+			t.Errorf("Expected status code %d for badge.json, got %d", http.StatusOK, w.Code)
+		}
+	})
+
+	t.Run("signature for another route", func(t *testing.T) {
+		// 署名はパスを含むため、別の形式やバッジには流用できない
+		png := strings.Replace(signed, "/graph?", "/graph.png?", 1)
+		if w := get(png, nil); w.Code != http.StatusForbidden {
+			t.Errorf("Expected status code %d for a graph signature on graph.png, got %d", http.StatusForbidden, w.Code)
+		}
+		badge := strings.Replace(strings.Replace(signed, "/p/", "/api/v0/p/", 1), "/graph?", "/badge.json?", 1)
+		if w := get(badge, nil); w.Code != http.StatusForbidden {
+			t.Errorf("Expected status code %d for a graph signature on badge.json, got %d", http.StatusForbidden, w.Code)
+		}
+	})
+
+	t.Run("tampered signature", func(t *testing.T) {
+		tampered := strings.Replace(signed, "theme=cividis", "theme=github", 1)
+		if w := get(tampered, nil); w.Code != http.StatusForbidden {
+			t.Errorf("Expected status code %d for tampered params, got %d", http.StatusForbidden, w.Code)
+		}
+		other := strings.Replace(signed, project.ID.String(), model.NewHexID(0x99).String(), 1)
+		if w := get(other, nil); w.Code != http.StatusForbidden {
+			t.Errorf("Expected status code %d for another project, got %d", http.StatusForbidden, w.Code)
+		}
+	})
+
+	t.Run("expired signature", func(t *testing.T) {
+		expired := server.SignGraphURL(project.ID, "", params, time.Now().Add(-time.Minute))
+		if w := get(expired, nil); w.Code != http.StatusForbidden {
+			t.Errorf("Expected status code %d, got %d", http.StatusForbidden, w.Code)
+		}
+	})
+
+	t.Run("unsigned request requires API key", func(t *testing.T) {
+		target := "/p/" + project.ID.String() + "/graph"
+		if w := get(target, nil); w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status code %d without API key, got %d", http.StatusUnauthorized, w.Code)
+		}
+		if w := get(target, map[string]string{"X-API-Key": testAPIKey}); w.Code != http.StatusOK {
+			t.Errorf("Expected status code %d with API key, got %d", http.StatusOK, w.Code)
+		}
+	})
+}
+
+func TestSignedGraphURLWithBasePath(t *testing.T) {
+	mockStore := NewMockStore()
+	project, _ := model.NewProject("test-project", "Test project")
+	mockStore.CreateProject(context.Background(), project)
+	cfg := newTestConfig()
+	cfg.SigningSecret = "test-secret"
+	cfg.BasePath = "/sougen"
+	server := NewServer(mockStore, cfg)
+
+	signed := server.SignGraphURL(project.ID, "json", nil, time.Now().Add(time.Hour))
+	if !strings.HasPrefix(signed, "/sougen/p/") {
+		t.Fatalf("Expected the signed URL to include the base path, got %q", signed)
+	}
+	req := httptest.NewRequest(http.MethodGet, signed, nil)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+}
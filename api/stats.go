@@ -0,0 +1,451 @@
+// Package api はsougenのAPIサーバー実装を提供します。
+package api
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/stsysd/sougen/model"
+	"github.com/stsysd/sougen/store"
+)
+
+// CompareParams represents parameters for comparing two consecutive date ranges.
+type CompareParams struct {
+	ProjectID model.HexID
+	DateRange *model.DateRange
+	Tags      *model.Tags
+	Weekdays  *model.Weekdays
+
+	Aggregation model.Aggregation // 日別集計の方法
+}
+
+// NewCompareParams creates parameters for range comparison from HTTP request.
+func NewCompareParams(r *http.Request) (*CompareParams, error) {
+	projectID, err := model.ParseHexID(r.PathValue("project_id"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid project_id: %w", err)
+	}
+
+	query := r.URL.Query()
+
+	dateRange, err := model.NewDateRange(query.Get("from"), query.Get("to"))
+	if err != nil {
+		return nil, err
+	}
+	if dateRange.From().After(dateRange.To()) {
+		return nil, fmt.Errorf("from must not be after to")
+	}
+
+	weekdays, err := model.NewWeekdays(query.Get("weekdays"))
+	if err != nil {
+		return nil, err
+	}
+
+	aggregation, err := model.NewAggregation(query.Get("agg"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &CompareParams{
+		ProjectID: projectID,
+		DateRange: dateRange,
+		Tags:      model.NewTags(query.Get("tags")),
+		Weekdays:  weekdays,
+
+		Aggregation: aggregation,
+	}, nil
+}
+
+// CompareResponse は期間比較エンドポイントのレスポンスです。
+type CompareResponse struct {
+	CurrentTotal       float64  `json:"current_total"` // floatのプロジェクトでは小数を含む
+	PreviousTotal      float64  `json:"previous_total"`
+	Delta              float64  `json:"delta"`
+	PercentChange      *float64 `json:"percent_change"` // 前期間の合計が0の場合はnull
+	ActiveDaysCurrent  int      `json:"active_days_current"`
+	ActiveDaysPrevious int      `json:"active_days_previous"`
+}
+
+// previousRange は指定期間の直前にある同じ日数の期間を返します。
+// 両期間は重複せず、前期間は指定期間の開始日の前日で終わります。
+func previousRange(from, to time.Time) (time.Time, time.Time) {
+	days := 0
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		days++
+	}
+	prevFrom := from.AddDate(0, 0, -days)
+	prevTo := from.Add(-time.Nanosecond)
+	return prevFrom, prevTo
+}
+
+// summarizeDailyTotals は日別集計から合計値とアクティブ日数を計算します。
+// weekdaysに含まれない曜日の集計は除外します。
+func summarizeDailyTotals(totals []*store.DailyTotal, weekdays *model.Weekdays) (total float64, activeDays int) {
+	for _, t := range totals {
+		if !weekdays.Contains(t.Date.Weekday()) {
+			continue
+		}
+		total += t.Value
+		if t.Value > 0 {
+			activeDays++
+		}
+	}
+	return total, activeDays
+}
+
+// handleCompare は指定期間と直前の同じ長さの期間の集計を比較するハンドラーです。
+func (s *Server) handleCompare(w http.ResponseWriter, r *http.Request) {
+	// パラメータを検証
+	params, err := NewCompareParams(r)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// プロジェクトの存在確認
+	project, err := s.store.GetProject(r.Context(), params.ProjectID)
+	if err != nil {
+		if errors.Is(err, model.ErrProjectNotFound) {
+			writeJSONError(w, fmt.Sprintf("Project with ID %s not found", params.ProjectID), http.StatusNotFound)
+		} else {
+			writeJSONError(w, fmt.Sprintf("Error retrieving project: %v", err), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// 期間の日付と日別集計の区切りはプロジェクトのタイムゾーン
+	loc := project.Location()
+	dateRange := params.DateRange.In(loc)
+	from := dateRange.From()
+	to := dateRange.To()
+	prevFrom, prevTo := previousRange(from, to)
+
+	// 今期間と前期間をそれぞれ日別集計
+	current, err := s.store.GetDailyTotals(r.Context(), &store.GetDailyTotalsParams{
+		ProjectID: params.ProjectID,
+		From:      from,
+		To:        to,
+		Tags:      params.Tags.Values(),
+
+		Aggregation: params.Aggregation,
+		ValueType:   project.RecordValueType(),
+		Location:    loc,
+	})
+	if err != nil {
+		log.Printf("Error retrieving daily totals: %v", err)
+		writeJSONError(w, "Failed to retrieve daily totals", http.StatusInternalServerError)
+		return
+	}
+	previous, err := s.store.GetDailyTotals(r.Context(), &store.GetDailyTotalsParams{
+		ProjectID: params.ProjectID,
+		From:      prevFrom,
+		To:        prevTo,
+		Tags:      params.Tags.Values(),
+
+		Aggregation: params.Aggregation,
+		ValueType:   project.RecordValueType(),
+		Location:    loc,
+	})
+	if err != nil {
+		log.Printf("Error retrieving daily totals: %v", err)
+		writeJSONError(w, "Failed to retrieve daily totals", http.StatusInternalServerError)
+		return
+	}
+
+	// レスポンスの構築
+	response := &CompareResponse{}
+	response.CurrentTotal, response.ActiveDaysCurrent = summarizeDailyTotals(current, params.Weekdays)
+	response.PreviousTotal, response.ActiveDaysPrevious = summarizeDailyTotals(previous, params.Weekdays)
+	response.Delta = response.CurrentTotal - response.PreviousTotal
+	// 前期間の合計が0の場合は変化率を定義できないためnullとする
+	if response.PreviousTotal != 0 {
+		percent := response.Delta / response.PreviousTotal * 100
+		response.PercentChange = &percent
+	}
+
+	// レスポンスの返却
+	writeJSON(w, r, http.StatusOK, response)
+}
+
+// TagBreakdownParams represents parameters for the per-tag breakdown.
+type TagBreakdownParams struct {
+	ProjectID model.HexID
+	DateRange *model.DateRange
+}
+
+// NewTagBreakdownParams creates parameters for the per-tag breakdown from HTTP request.
+func NewTagBreakdownParams(r *http.Request) (*TagBreakdownParams, error) {
+	projectID, err := model.ParseHexID(r.PathValue("project_id"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid project_id: %w", err)
+	}
+
+	query := r.URL.Query()
+
+	dateRange, err := model.NewDateRange(query.Get("from"), query.Get("to"))
+	if err != nil {
+		return nil, err
+	}
+	if dateRange.From().After(dateRange.To()) {
+		return nil, fmt.Errorf("from must not be after to")
+	}
+
+	return &TagBreakdownParams{
+		ProjectID: projectID,
+		DateRange: dateRange,
+	}, nil
+}
+
+// handleTagBreakdown は指定期間のレコード値と件数をタグごとに集計するハンドラーです。
+// 複数のタグを持つレコードはそれぞれのタグに計上されるため、合計はプロジェクト全体の合計と一致しないことがあります。
+func (s *Server) handleTagBreakdown(w http.ResponseWriter, r *http.Request) {
+	// パラメータを検証
+	params, err := NewTagBreakdownParams(r)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// プロジェクトの存在確認
+	if _, err := s.store.GetProject(r.Context(), params.ProjectID); err != nil {
+		if errors.Is(err, model.ErrProjectNotFound) {
+			writeJSONError(w, fmt.Sprintf("Project with ID %s not found", params.ProjectID), http.StatusNotFound)
+		} else {
+			writeJSONError(w, fmt.Sprintf("Error retrieving project: %v", err), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	breakdown, err := s.store.GetTagBreakdown(r.Context(), &store.GetTagBreakdownParams{
+		ProjectID: params.ProjectID,
+		From:      params.DateRange.From(),
+		To:        params.DateRange.To(),
+	})
+	if err != nil {
+		log.Printf("Error retrieving tag breakdown: %v", err)
+		writeJSONError(w, "Failed to retrieve tag breakdown", http.StatusInternalServerError)
+		return
+	}
+
+	// レスポンスの返却
+	writeJSON(w, r, http.StatusOK, breakdown)
+}
+
+// ヒストグラムの集計単位
+const (
+	histogramBucketValue = "value" // レコード値ごとのレコード数
+)
+
+// HistogramParams represents parameters for the record histogram.
+type HistogramParams struct {
+	ProjectID model.HexID
+	DateRange *model.DateRange
+	Tags      *model.Tags
+	Bucket    string // 集計単位（現在はhistogramBucketValueのみ）
+}
+
+// NewHistogramParams creates parameters for the record histogram from HTTP request.
+func NewHistogramParams(r *http.Request) (*HistogramParams, error) {
+	projectID, err := model.ParseHexID(r.PathValue("project_id"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid project_id: %w", err)
+	}
+
+	query := r.URL.Query()
+
+	bucket := query.Get("bucket")
+	if bucket == "" {
+		bucket = histogramBucketValue
+	}
+	if bucket != histogramBucketValue {
+		return nil, fmt.Errorf("invalid bucket: %s (must be value)", bucket)
+	}
+
+	dateRange, err := model.NewDateRange(query.Get("from"), query.Get("to"))
+	if err != nil {
+		return nil, err
+	}
+	if dateRange.From().After(dateRange.To()) {
+		return nil, fmt.Errorf("from must not be after to")
+	}
+
+	return &HistogramParams{
+		ProjectID: projectID,
+		DateRange: dateRange,
+		Tags:      model.NewTags(query.Get("tags")),
+		Bucket:    bucket,
+	}, nil
+}
+
+// HistogramResponse はレコードの分布のレスポンスです。
+type HistogramResponse struct {
+	Bucket  string              `json:"bucket"`
+	Buckets []*store.ValueCount `json:"buckets"` // 値の昇順
+}
+
+// handleHistogram は指定期間のレコード値の分布（値ごとのレコード数）を返すハンドラーです。
+func (s *Server) handleHistogram(w http.ResponseWriter, r *http.Request) {
+	// パラメータを検証
+	params, err := NewHistogramParams(r)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// プロジェクトの存在確認
+	if _, err := s.store.GetProject(r.Context(), params.ProjectID); err != nil {
+		if errors.Is(err, model.ErrProjectNotFound) {
+			writeJSONError(w, fmt.Sprintf("Project with ID %s not found", params.ProjectID), http.StatusNotFound)
+		} else {
+			writeJSONError(w, fmt.Sprintf("Error retrieving project: %v", err), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	counts, err := s.store.GetValueHistogram(r.Context(), &store.GetValueHistogramParams{
+		ProjectID: params.ProjectID,
+		From:      params.DateRange.From(),
+		To:        params.DateRange.To(),
+		Tags:      params.Tags.Values(),
+	})
+	if err != nil {
+		log.Printf("Error retrieving histogram: %v", err)
+		writeJSONError(w, "Failed to retrieve histogram", http.StatusInternalServerError)
+		return
+	}
+
+	// レスポンスの返却
+	writeJSON(w, r, http.StatusOK, &HistogramResponse{
+		Bucket:  params.Bucket,
+		Buckets: counts,
+	})
+}
+
+// top-daysで返す日数
+const (
+	defaultTopDays = 5
+	maxTopDays     = 366
+)
+
+// TopDaysParams represents parameters for the top days leaderboard.
+type TopDaysParams struct {
+	ProjectID model.HexID
+	DateRange *model.DateRange
+	Tags      *model.Tags
+	N         int // 返す日数
+}
+
+// NewTopDaysParams creates parameters for the top days leaderboard from HTTP request.
+func NewTopDaysParams(r *http.Request) (*TopDaysParams, error) {
+	projectID, err := model.ParseHexID(r.PathValue("project_id"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid project_id: %w", err)
+	}
+
+	query := r.URL.Query()
+
+	n, err := parsePositiveIntQuery(query, "n")
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		n = defaultTopDays
+	}
+	if n > maxTopDays {
+		return nil, fmt.Errorf("invalid n parameter: must be at most %d", maxTopDays)
+	}
+
+	dateRange, err := model.NewDateRange(query.Get("from"), query.Get("to"))
+	if err != nil {
+		return nil, err
+	}
+	if dateRange.From().After(dateRange.To()) {
+		return nil, fmt.Errorf("from must not be after to")
+	}
+
+	return &TopDaysParams{
+		ProjectID: projectID,
+		DateRange: dateRange,
+		Tags:      model.NewTags(query.Get("tags")),
+		N:         n,
+	}, nil
+}
+
+// TopDay はtop-daysの1日分の集計です。
+type TopDay struct {
+	Date  string  `json:"date"`  // YYYY-MM-DD
+	Total float64 `json:"total"` // その日のレコード値の合計（floatのプロジェクトでは小数を含む）
+}
+
+// TopDaysResponse はtop-daysエンドポイントのレスポンスです。
+type TopDaysResponse struct {
+	Days []*TopDay `json:"days"` // 合計の降順（同じ合計の場合は新しい日が先）
+}
+
+// topDays は日別集計から合計の大きい順にn日分を選びます。
+// 同じ合計の場合は新しい日を優先し、レコードのない日は含めません。
+func topDays(totals []*store.DailyTotal, n int) []*TopDay {
+	sorted := slices.Clone(totals)
+	slices.SortFunc(sorted, func(a, b *store.DailyTotal) int {
+		if c := cmp.Compare(b.Value, a.Value); c != 0 {
+			return c
+		}
+		return b.Date.Compare(a.Date)
+	})
+
+	days := []*TopDay{}
+	for _, total := range sorted {
+		if len(days) == n || total.Value <= 0 {
+			break
+		}
+		days = append(days, &TopDay{Date: total.Date.Format("2006-01-02"), Total: total.Value})
+	}
+	return days
+}
+
+// handleTopDays は指定期間でレコード値の合計が大きい日を返すハンドラーです。
+func (s *Server) handleTopDays(w http.ResponseWriter, r *http.Request) {
+	// パラメータを検証
+	params, err := NewTopDaysParams(r)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// プロジェクトの存在確認
+	project, err := s.store.GetProject(r.Context(), params.ProjectID)
+	if err != nil {
+		if errors.Is(err, model.ErrProjectNotFound) {
+			writeJSONError(w, fmt.Sprintf("Project with ID %s not found", params.ProjectID), http.StatusNotFound)
+		} else {
+			writeJSONError(w, fmt.Sprintf("Error retrieving project: %v", err), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// 期間の日付と日別集計の区切りはプロジェクトのタイムゾーン
+	loc := project.Location()
+	dateRange := params.DateRange.In(loc)
+	totals, err := s.store.GetDailyTotals(r.Context(), &store.GetDailyTotalsParams{
+		ProjectID: params.ProjectID,
+		From:      dateRange.From(),
+		To:        dateRange.To(),
+		Tags:      params.Tags.Values(),
+
+		ValueType: project.RecordValueType(),
+		Location:  loc,
+	})
+	if err != nil {
+		log.Printf("Error retrieving daily totals: %v", err)
+		writeJSONError(w, "Failed to retrieve daily totals", http.StatusInternalServerError)
+		return
+	}
+
+	// レスポンスの返却
+	writeJSON(w, r, http.StatusOK, &TopDaysResponse{Days: topDays(totals, params.N)})
+}
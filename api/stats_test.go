@@ -0,0 +1,511 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stsysd/sougen/model"
+	"github.com/stsysd/sougen/store"
+)
+
+func TestCompareEndpoint(t *testing.T) {
+	mockStore := NewMockStore()
+	server := NewServer(mockStore, newTestConfig())
+
+	project, _ := model.NewProject("compare-project", "")
+	mockStore.CreateProject(context.Background(), project)
+
+	// 今期間: 2025-06-08〜2025-06-14、前期間: 2025-06-01〜2025-06-07
+	records := []struct {
+		timestamp time.Time
+		value     int
+	}{
+		{time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local), 2},     // 前期間の初日
+		{time.Date(2025, 6, 7, 23, 59, 59, 0, time.Local), 3},  // 前期間の最終日
+		{time.Date(2025, 6, 8, 0, 0, 0, 0, time.Local), 4},     // 今期間の初日
+		{time.Date(2025, 6, 10, 12, 0, 0, 0, time.Local), 1},   // 今期間
+		{time.Date(2025, 6, 14, 23, 59, 59, 0, time.Local), 5}, // 今期間の最終日
+		{time.Date(2025, 6, 15, 0, 0, 0, 0, time.Local), 100},  // 範囲外
+	}
+	for _, rec := range records {
+		record, _ := model.NewRecord(rec.timestamp, project.ID, rec.value, nil)
+		mockStore.CreateRecord(context.Background(), record)
+	}
+
+	url := fmt.Sprintf("/api/v0/p/%s/compare?from=2025-06-08&to=2025-06-14", project.ID)
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	req.Header.Set("X-API-Key", testAPIKey)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var response CompareResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if response.CurrentTotal != 10 {
+		t.Errorf("Expected current_total 10, got %v", response.CurrentTotal)
+	}
+	if response.PreviousTotal != 5 {
+		t.Errorf("Expected previous_total 5, got %v", response.PreviousTotal)
+	}
+	if response.Delta != 5 {
+		t.Errorf("Expected delta 5, got %v", response.Delta)
+	}
+	if response.PercentChange == nil || *response.PercentChange != 100 {
+		t.Errorf("Expected percent_change 100, got %v", response.PercentChange)
+	}
+	if response.ActiveDaysCurrent != 3 {
+		t.Errorf("Expected active_days_current 3, got %d", response.ActiveDaysCurrent)
+	}
+	if response.ActiveDaysPrevious != 2 {
+		t.Errorf("Expected active_days_previous 2, got %d", response.ActiveDaysPrevious)
+	}
+}
+
+func TestCompareEndpointWithProjectTimezone(t *testing.T) {
+	mockStore := NewMockStore()
+	server := NewServer(mockStore, newTestConfig())
+
+	// サーバーのタイムゾーンとは日付がずれるUTC+14のタイムゾーン
+	timezone := "Pacific/Kiritimati"
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		t.Skipf("Timezone %s is not available: %v", timezone, err)
+	}
+	project, _ := model.NewProject("compare-project", "")
+	project.Timezone = &timezone
+	mockStore.CreateProject(context.Background(), project)
+
+	// 期間と日付の区切りはプロジェクトのタイムゾーンで判定する
+	records := []struct {
+		timestamp time.Time
+		value     int
+	}{
+		{time.Date(2025, 6, 7, 23, 30, 0, 0, loc), 3},   // 前期間の最終日
+		{time.Date(2025, 6, 8, 0, 30, 0, 0, loc), 4},    // 今期間の初日（UTCでは前日）
+		{time.Date(2025, 6, 10, 1, 0, 0, 0, loc), 1},    // UTCでは6/9だが、プロジェクトでは次と同じ日
+		{time.Date(2025, 6, 10, 23, 0, 0, 0, loc), 1},   // 今期間
+		{time.Date(2025, 6, 14, 23, 30, 0, 0, loc), 5},  // 今期間の最終日
+		{time.Date(2025, 6, 15, 0, 30, 0, 0, loc), 100}, // 範囲外（UTCでは6/14）
+	}
+	for _, rec := range records {
+		record, _ := model.NewRecord(rec.timestamp, project.ID, rec.value, nil)
+		mockStore.CreateRecord(context.Background(), record)
+	}
+
+	url := fmt.Sprintf("/api/v0/p/%s/compare?from=2025-06-08&to=2025-06-14", project.ID)
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	req.Header.Set("X-API-Key", testAPIKey)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var response CompareResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.CurrentTotal != 11 || response.ActiveDaysCurrent != 3 {
+		t.Errorf("Expected current_total 11 over 3 active days, got %v over %d", response.CurrentTotal, response.ActiveDaysCurrent)
+	}
+	if response.PreviousTotal != 3 || response.ActiveDaysPrevious != 1 {
+		t.Errorf("Expected previous_total 3 over 1 active day, got %v over %d", response.PreviousTotal, response.ActiveDaysPrevious)
+	}
+}
+
+func TestCompareEndpointWithEmptyPreviousRange(t *testing.T) {
+	mockStore := NewMockStore()
+	server := NewServer(mockStore, newTestConfig())
+
+	project, _ := model.NewProject("compare-project", "")
+	mockStore.CreateProject(context.Background(), project)
+
+	record, _ := model.NewRecord(time.Date(2025, 6, 10, 12, 0, 0, 0, time.Local), project.ID, 3, nil)
+	mockStore.CreateRecord(context.Background(), record)
+
+	url := fmt.Sprintf("/api/v0/p/%s/compare?from=2025-06-08&to=2025-06-14", project.ID)
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	req.Header.Set("X-API-Key", testAPIKey)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var response map[string]any
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	// 前期間の合計が0の場合、percent_changeはnull
+	if v, ok := response["percent_change"]; !ok || v != nil {
+		t.Errorf("Expected percent_change to be null, got %v", v)
+	}
+	if response["delta"] != float64(3) {
+		t.Errorf("Expected delta 3, got %v", response["delta"])
+	}
+}
+
+func TestCompareEndpointWithWeekdays(t *testing.T) {
+	mockStore := NewMockStore()
+	server := NewServer(mockStore, newTestConfig())
+
+	project, _ := model.NewProject("compare-project", "")
+	mockStore.CreateProject(context.Background(), project)
+
+	// 2025-06-14は土曜日、2025-06-15は日曜日
+	records := []struct {
+		timestamp time.Time
+		value     int
+	}{
+		{time.Date(2025, 6, 7, 12, 0, 0, 0, time.Local), 2},  // 前期間の土曜日
+		{time.Date(2025, 6, 9, 12, 0, 0, 0, time.Local), 50}, // 前期間の月曜日（除外）
+		{time.Date(2025, 6, 10, 12, 0, 0, 0, time.Local), 7}, // 今期間の火曜日（除外）
+		{time.Date(2025, 6, 14, 12, 0, 0, 0, time.Local), 3}, // 今期間の土曜日
+		{time.Date(2025, 6, 15, 12, 0, 0, 0, time.Local), 4}, // 今期間の日曜日
+	}
+	for _, rec := range records {
+		record, _ := model.NewRecord(rec.timestamp, project.ID, rec.value, nil)
+		mockStore.CreateRecord(context.Background(), record)
+	}
+
+	url := fmt.Sprintf("/api/v0/p/%s/compare?from=2025-06-10&to=2025-06-16&weekdays=sat,sun", project.ID)
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	req.Header.Set("X-API-Key", testAPIKey)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var response CompareResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if response.CurrentTotal != 7 {
+		t.Errorf("Expected current_total 7, got %v", response.CurrentTotal)
+	}
+	if response.ActiveDaysCurrent != 2 {
+		t.Errorf("Expected active_days_current 2, got %d", response.ActiveDaysCurrent)
+	}
+	if response.PreviousTotal != 2 {
+		t.Errorf("Expected previous_total 2, got %v", response.PreviousTotal)
+	}
+}
+
+func TestCompareEndpointWithAggregation(t *testing.T) {
+	mockStore := NewMockStore()
+	server := NewServer(mockStore, newTestConfig())
+
+	project, _ := model.NewProject("compare-project", "")
+	mockStore.CreateProject(context.Background(), project)
+
+	// 今期間の同じ日に3レコード、別の日に1レコード
+	for i, value := range []int{2, 6, 1} {
+		record, _ := model.NewRecord(time.Date(2025, 6, 14, 9+i, 0, 0, 0, time.Local), project.ID, value, nil)
+		mockStore.CreateRecord(context.Background(), record)
+	}
+	record, _ := model.NewRecord(time.Date(2025, 6, 15, 12, 0, 0, 0, time.Local), project.ID, 4, nil)
+	mockStore.CreateRecord(context.Background(), record)
+
+	tests := []struct {
+		agg      string
+		expected float64
+	}{
+		{"sum", 13},
+		{"max", 10},  // 6 + 4
+		{"count", 4}, // 3 + 1
+	}
+	for _, tt := range tests {
+		t.Run(tt.agg, func(t *testing.T) {
+			url := fmt.Sprintf("/api/v0/p/%s/compare?from=2025-06-10&to=2025-06-16&agg=%s", project.ID, tt.agg)
+			req := httptest.NewRequest(http.MethodGet, url, nil)
+			req.Header.Set("X-API-Key", testAPIKey)
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+			}
+			var response CompareResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.CurrentTotal != tt.expected {
+				t.Errorf("Expected current_total %v, got %v", tt.expected, response.CurrentTotal)
+			}
+			if response.ActiveDaysCurrent != 2 {
+				t.Errorf("Expected active_days_current 2, got %d", response.ActiveDaysCurrent)
+			}
+		})
+	}
+}
+
+func TestCompareEndpointErrors(t *testing.T) {
+	mockStore := NewMockStore()
+	server := NewServer(mockStore, newTestConfig())
+
+	project, _ := model.NewProject("compare-project", "")
+	mockStore.CreateProject(context.Background(), project)
+
+	tests := []struct {
+		name           string
+		url            string
+		expectedStatus int
+	}{
+		{"Invalid project_id", "/api/v0/p/invalid/compare", http.StatusBadRequest},
+		{"Non-existent project", "/api/v0/p/00000000000000ff/compare", http.StatusNotFound},
+		{"Invalid from", fmt.Sprintf("/api/v0/p/%s/compare?from=bad", project.ID), http.StatusBadRequest},
+		{"Reversed range", fmt.Sprintf("/api/v0/p/%s/compare?from=2025-06-14&to=2025-06-08", project.ID), http.StatusBadRequest},
+		{"Invalid weekdays", fmt.Sprintf("/api/v0/p/%s/compare?weekdays=sat,8", project.ID), http.StatusBadRequest},
+		{"Invalid agg", fmt.Sprintf("/api/v0/p/%s/compare?agg=median", project.ID), http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.url, nil)
+			req.Header.Set("X-API-Key", testAPIKey)
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestTagBreakdownEndpoint(t *testing.T) {
+	mockStore := NewMockStore()
+	project, _ := model.NewProject("breakdown-project", "")
+	mockStore.CreateProject(context.Background(), project)
+	server := NewServer(mockStore, newTestConfig())
+
+	for _, e := range []struct {
+		day   int
+		value int
+		tags  []string
+	}{
+		{1, 2, []string{"work", "meeting"}},
+		{2, 5, []string{"work"}},
+		{3, 4, []string{"study"}},
+	} {
+		record, _ := model.NewRecord(time.Date(2025, 6, e.day, 9, 0, 0, 0, time.Local), project.ID, e.value, e.tags)
+		mockStore.CreateRecord(context.Background(), record)
+	}
+
+	getBreakdown := func(projectID model.HexID, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v0/p/%s/tag-breakdown?%s", projectID, query), nil)
+		req.Header.Set("X-API-Key", testAPIKey)
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		return w
+	}
+
+	w := getBreakdown(project.ID, "from=2025-06-01&to=2025-06-30")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var breakdown []store.TagTotal
+	if err := json.NewDecoder(w.Body).Decode(&breakdown); err != nil {
+		t.Fatalf("Failed to decode response body: %v", err)
+	}
+	expected := []store.TagTotal{
+		{Tag: "work", TotalValue: 7, RecordCount: 2},
+		{Tag: "study", TotalValue: 4, RecordCount: 1},
+		{Tag: "meeting", TotalValue: 2, RecordCount: 1},
+	}
+	if !slices.Equal(breakdown, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, breakdown)
+	}
+
+	// 範囲外の日付は集計しない
+	w = getBreakdown(project.ID, "from=2025-06-02&to=2025-06-02")
+	if err := json.NewDecoder(w.Body).Decode(&breakdown); err != nil {
+		t.Fatalf("Failed to decode response body: %v", err)
+	}
+	if !slices.Equal(breakdown, []store.TagTotal{{Tag: "work", TotalValue: 5, RecordCount: 1}}) {
+		t.Errorf("Unexpected breakdown for a single day: %+v", breakdown)
+	}
+
+	if w := getBreakdown(project.ID, "from=2025-06-30&to=2025-06-01"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d for an inverted range, got %d", http.StatusBadRequest, w.Code)
+	}
+	if w := getBreakdown(model.NewHexID(999), ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d for a missing project, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestHistogramEndpoint(t *testing.T) {
+	mockStore := NewMockStore()
+	project, _ := model.NewProject("histogram-project", "")
+	mockStore.CreateProject(context.Background(), project)
+	server := NewServer(mockStore, newTestConfig())
+
+	for _, e := range []struct {
+		day   int
+		value int
+		tags  []string
+	}{
+		{1, 1, []string{"work"}},
+		{2, 1, nil},
+		{3, 3, []string{"work"}},
+		{4, 1, []string{"work"}},
+		{5, 2, nil},
+		{5, 3, nil},
+	} {
+		record, _ := model.NewRecord(time.Date(2025, 6, e.day, 9, 0, 0, 0, time.Local), project.ID, e.value, e.tags)
+		mockStore.CreateRecord(context.Background(), record)
+	}
+
+	getHistogram := func(projectID model.HexID, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v0/p/%s/histogram?%s", projectID, query), nil)
+		req.Header.Set("X-API-Key", testAPIKey)
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		return w
+	}
+	decode := func(w *httptest.ResponseRecorder) HistogramResponse {
+		t.Helper()
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var response HistogramResponse
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode response body: %v", err)
+		}
+		return response
+	}
+	equal := func(got []*store.ValueCount, expected []store.ValueCount) bool {
+		return slices.EqualFunc(got, expected, func(a *store.ValueCount, b store.ValueCount) bool { return *a == b })
+	}
+
+	response := decode(getHistogram(project.ID, "bucket=value&from=2025-06-01&to=2025-06-30"))
+	expected := []store.ValueCount{{Value: 1, RecordCount: 3}, {Value: 2, RecordCount: 1}, {Value: 3, RecordCount: 2}}
+	if response.Bucket != "value" || !equal(response.Buckets, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, response)
+	}
+
+	// タグと期間で絞り込む
+	response = decode(getHistogram(project.ID, "from=2025-06-01&to=2025-06-03&tags=work"))
+	expected = []store.ValueCount{{Value: 1, RecordCount: 1}, {Value: 3, RecordCount: 1}}
+	if !equal(response.Buckets, expected) {
+		t.Errorf("Expected %+v for filtered histogram, got %+v", expected, response.Buckets)
+	}
+
+	// 該当するレコードがない場合は空の配列
+	w := getHistogram(project.ID, "from=2025-01-01&to=2025-01-31")
+	if body := w.Body.String(); !strings.Contains(body, `"buckets":[]`) {
+		t.Errorf("Expected empty buckets array, got %s", body)
+	}
+
+	if w := getHistogram(project.ID, "bucket=day"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d for an unknown bucket, got %d", http.StatusBadRequest, w.Code)
+	}
+	if w := getHistogram(model.NewHexID(999), ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d for a missing project, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestTopDaysEndpoint(t *testing.T) {
+	mockStore := NewMockStore()
+	project, _ := model.NewProject("top-days-project", "")
+	mockStore.CreateProject(context.Background(), project)
+	server := NewServer(mockStore, newTestConfig())
+
+	for _, e := range []struct {
+		day   int
+		value int
+		tags  []string
+	}{
+		{1, 5, []string{"work"}},
+		{2, 2, nil},
+		{3, 3, []string{"work"}},
+		{3, 2, nil},
+		{4, 1, []string{"work"}},
+		{6, 5, nil},
+	} {
+		record, _ := model.NewRecord(time.Date(2025, 6, e.day, 9, 0, 0, 0, time.Local), project.ID, e.value, e.tags)
+		mockStore.CreateRecord(context.Background(), record)
+	}
+
+	getTopDays := func(projectID model.HexID, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v0/p/%s/top-days?%s", projectID, query), nil)
+		req.Header.Set("X-API-Key", testAPIKey)
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		return w
+	}
+	decode := func(w *httptest.ResponseRecorder) []TopDay {
+		t.Helper()
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var response struct {
+			Days []TopDay `json:"days"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode response body: %v", err)
+		}
+		return response.Days
+	}
+
+	// 合計の降順、同じ合計（6/1・6/3・6/6はいずれも5）は新しい日が先
+	got := decode(getTopDays(project.ID, "n=4&from=2025-06-01&to=2025-06-30"))
+	expected := []TopDay{{"2025-06-06", 5}, {"2025-06-03", 5}, {"2025-06-01", 5}, {"2025-06-02", 2}}
+	if !slices.Equal(got, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, got)
+	}
+
+	// タグと期間で絞り込む（既定は5日、レコードのない日は含めない）
+	got = decode(getTopDays(project.ID, "from=2025-06-02&to=2025-06-30&tags=work"))
+	expected = []TopDay{{"2025-06-03", 3}, {"2025-06-04", 1}}
+	if !slices.Equal(got, expected) {
+		t.Errorf("Expected %+v for filtered top days, got %+v", expected, got)
+	}
+
+	if w := getTopDays(project.ID, "n=0"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d for n=0, got %d", http.StatusBadRequest, w.Code)
+	}
+	if w := getTopDays(project.ID, "n=1000"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d for too large n, got %d", http.StatusBadRequest, w.Code)
+	}
+	if w := getTopDays(model.NewHexID(999), ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d for a missing project, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestPreviousRange(t *testing.T) {
+	from := time.Date(2025, 6, 8, 0, 0, 0, 0, time.Local)
+	to := time.Date(2025, 6, 14, 23, 59, 59, 999999999, time.Local)
+
+	prevFrom, prevTo := previousRange(from, to)
+
+	expectedFrom := time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local)
+	expectedTo := time.Date(2025, 6, 7, 23, 59, 59, 999999999, time.Local)
+	if !prevFrom.Equal(expectedFrom) {
+		t.Errorf("Expected previous from %v, got %v", expectedFrom, prevFrom)
+	}
+	if !prevTo.Equal(expectedTo) {
+		t.Errorf("Expected previous to %v, got %v", expectedTo, prevTo)
+	}
+	if !prevTo.Before(from) {
+		t.Errorf("Previous range must not overlap the current range")
+	}
+}
@@ -0,0 +1,187 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/stsysd/sougen/model"
+)
+
+// TagAliasParams represents parameters for a single tag alias.
+type TagAliasParams struct {
+	ProjectID model.HexID
+	Alias     string
+}
+
+// NewTagAliasParams creates parameters for a tag alias from HTTP request.
+func NewTagAliasParams(r *http.Request) (*TagAliasParams, error) {
+	projectID, err := model.ParseHexID(r.PathValue("project_id"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid project_id: %w", err)
+	}
+
+	return &TagAliasParams{
+		ProjectID: projectID,
+		Alias:     r.PathValue("alias"),
+	}, nil
+}
+
+// SetTagAliasParams represents parameters for creating or updating a tag alias.
+type SetTagAliasParams struct {
+	ProjectID model.HexID
+	TagAlias  *model.TagAlias
+}
+
+// NewSetTagAliasParams creates parameters for setting a tag alias from HTTP request.
+func NewSetTagAliasParams(r *http.Request) (*SetTagAliasParams, error) {
+	params, err := NewTagAliasParams(r)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body")
+	}
+	// スキーマ検証（すべての違反をまとめて報告）
+	if err := validateBody(tagAliasSchema, body); err != nil {
+		return nil, err
+	}
+	var aliasData struct {
+		Tag string `json:"tag"`
+	}
+	if err := json.Unmarshal(body, &aliasData); err != nil {
+		return nil, fmt.Errorf("invalid JSON format")
+	}
+
+	alias, err := model.NewTagAlias(params.Alias, aliasData.Tag)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SetTagAliasParams{
+		ProjectID: params.ProjectID,
+		TagAlias:  alias,
+	}, nil
+}
+
+// checkProjectExists はプロジェクトの存在を確認し、存在しない場合はエラーレスポンスを書き込んでfalseを返します。
+func (s *Server) checkProjectExists(w http.ResponseWriter, r *http.Request, projectID model.HexID) bool {
+	if _, err := s.store.GetProject(r.Context(), projectID); err != nil {
+		if errors.Is(err, model.ErrProjectNotFound) {
+			writeJSONError(w, fmt.Sprintf("Project with ID %s not found", projectID), http.StatusNotFound)
+		} else {
+			writeJSONError(w, fmt.Sprintf("Error retrieving project: %v", err), http.StatusInternalServerError)
+		}
+		return false
+	}
+	return true
+}
+
+// handleListTagAliases はプロジェクトのタグの別名の一覧を返すハンドラーです。
+func (s *Server) handleListTagAliases(w http.ResponseWriter, r *http.Request) {
+	// パラメータを検証
+	params, err := NewTagAliasParams(r)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// プロジェクトの存在確認
+	if !s.checkProjectExists(w, r, params.ProjectID) {
+		return
+	}
+
+	aliases, err := s.store.ListTagAliases(r.Context(), params.ProjectID)
+	if err != nil {
+		log.Printf("Error listing tag aliases: %v", err)
+		writeJSONError(w, "Failed to list tag aliases", http.StatusInternalServerError)
+		return
+	}
+
+	// レスポンスの返却
+	writeJSON(w, r, http.StatusOK, aliases)
+}
+
+// handleSetTagAlias はタグの別名を作成または更新するハンドラーです。
+// 別名の連鎖を避けるため、正規のタグに別名を指定したり、他の別名の正規のタグを別名にしたりはできません。
+// 既存のレコードのタグは書き換えません。
+func (s *Server) handleSetTagAlias(w http.ResponseWriter, r *http.Request) {
+	// パラメータを検証
+	params, err := NewSetTagAliasParams(r)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// プロジェクトの存在確認
+	if !s.checkProjectExists(w, r, params.ProjectID) {
+		return
+	}
+
+	// 別名の連鎖を拒否
+	aliases, err := s.store.ListTagAliases(r.Context(), params.ProjectID)
+	if err != nil {
+		log.Printf("Error listing tag aliases: %v", err)
+		writeJSONError(w, "Failed to list tag aliases", http.StatusInternalServerError)
+		return
+	}
+	for _, existing := range aliases {
+		if existing.Alias == params.TagAlias.Tag {
+			writeJSONError(w, fmt.Sprintf("tag %s is itself an alias of %s", existing.Alias, existing.Tag), http.StatusBadRequest)
+			return
+		}
+		if existing.Tag == params.TagAlias.Alias {
+			writeJSONError(w, fmt.Sprintf("alias %s is the canonical tag of %s", existing.Tag, existing.Alias), http.StatusBadRequest)
+			return
+		}
+	}
+
+	if err := s.store.SetTagAlias(r.Context(), params.ProjectID, params.TagAlias); err != nil {
+		if errors.Is(err, model.ErrProjectNotFound) {
+			writeJSONError(w, fmt.Sprintf("Project with ID %s not found", params.ProjectID), http.StatusNotFound)
+			return
+		}
+		log.Printf("Error setting tag alias: %v", err)
+		writeJSONError(w, "Failed to set tag alias", http.StatusInternalServerError)
+		return
+	}
+	// グラフのタグによる絞り込みは別名を正規のタグに置き換えるため、キャッシュを破棄
+	s.graphCache.invalidateProject(params.ProjectID)
+
+	// レスポンスの返却
+	writeJSON(w, r, http.StatusOK, params.TagAlias)
+}
+
+// handleDeleteTagAlias はタグの別名を削除するハンドラーです。
+func (s *Server) handleDeleteTagAlias(w http.ResponseWriter, r *http.Request) {
+	// パラメータを検証
+	params, err := NewTagAliasParams(r)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// プロジェクトの存在確認
+	if !s.checkProjectExists(w, r, params.ProjectID) {
+		return
+	}
+
+	if err := s.store.DeleteTagAlias(r.Context(), params.ProjectID, params.Alias); err != nil {
+		if errors.Is(err, model.ErrTagAliasNotFound) {
+			writeJSONError(w, fmt.Sprintf("Tag alias %s not found", params.Alias), http.StatusNotFound)
+			return
+		}
+		log.Printf("Error deleting tag alias: %v", err)
+		writeJSONError(w, "Failed to delete tag alias", http.StatusInternalServerError)
+		return
+	}
+	// グラフのタグによる絞り込みが変わるため、キャッシュを破棄
+	s.graphCache.invalidateProject(params.ProjectID)
+
+	w.WriteHeader(http.StatusNoContent)
+}
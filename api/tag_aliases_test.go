@@ -0,0 +1,100 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stsysd/sougen/model"
+)
+
+func TestTagAliasEndpoints(t *testing.T) {
+	mockStore := NewMockStore()
+	project, _ := model.NewProject("alias-project", "")
+	mockStore.CreateProject(context.Background(), project)
+	server := NewServer(mockStore, newTestConfig())
+
+	do := func(method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("X-API-Key", testAPIKey)
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		return w
+	}
+	aliasesURL := fmt.Sprintf("/api/v0/p/%s/t/aliases", project.ID)
+
+	// 別名の作成と更新
+	if w := do(http.MethodPut, aliasesURL+"/js", `{"tag":"ecmascript"}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	w := do(http.MethodPut, aliasesURL+"/js", `{"tag":"javascript"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var alias model.TagAlias
+	if err := json.NewDecoder(w.Body).Decode(&alias); err != nil {
+		t.Fatalf("Failed to decode response body: %v", err)
+	}
+	if alias != (model.TagAlias{Alias: "js", Tag: "javascript"}) {
+		t.Errorf("Unexpected alias: %+v", alias)
+	}
+
+	w = do(http.MethodGet, aliasesURL, "")
+	var aliases []model.TagAlias
+	if err := json.NewDecoder(w.Body).Decode(&aliases); err != nil {
+		t.Fatalf("Failed to decode response body: %v", err)
+	}
+	if !slices.Equal(aliases, []model.TagAlias{{Alias: "js", Tag: "javascript"}}) {
+		t.Errorf("Unexpected aliases: %+v", aliases)
+	}
+
+	// 別名で書き込んだレコードは正規のタグで見つかる
+	record, _ := model.NewRecord(time.Now(), project.ID, 1, []string{"js"})
+	mockStore.CreateRecord(context.Background(), record)
+	w = do(http.MethodGet, fmt.Sprintf("/api/v0/r?project_id=%s&tags=javascript", project.ID), "")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"tags":["javascript"]`) {
+		t.Errorf("Expected the record under the canonical tag, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// 不正な別名
+	tests := []struct {
+		name   string
+		target string
+		body   string
+		status int
+	}{
+		{"missing tag", aliasesURL + "/ts", `{}`, http.StatusBadRequest},
+		{"alias equal to tag", aliasesURL + "/ts", `{"tag":"ts"}`, http.StatusBadRequest},
+		{"tag is an alias", aliasesURL + "/ecma", `{"tag":"js"}`, http.StatusBadRequest},
+		{"alias is a canonical tag", aliasesURL + "/javascript", `{"tag":"jscript"}`, http.StatusBadRequest},
+		{"missing project", fmt.Sprintf("/api/v0/p/%s/t/aliases/ts", model.NewHexID(999)), `{"tag":"typescript"}`, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := do(http.MethodPut, tt.target, tt.body); w.Code != tt.status {
+				t.Errorf("Expected status code %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	// 削除
+	if w := do(http.MethodDelete, aliasesURL+"/js", ""); w.Code != http.StatusNoContent {
+		t.Errorf("Expected status code %d, got %d", http.StatusNoContent, w.Code)
+	}
+	if w := do(http.MethodDelete, aliasesURL+"/js", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d for a deleted alias, got %d", http.StatusNotFound, w.Code)
+	}
+	if w := do(http.MethodGet, fmt.Sprintf("/api/v0/p/%s/t/aliases", model.NewHexID(999)), ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d for a missing project, got %d", http.StatusNotFound, w.Code)
+	}
+	w = do(http.MethodDelete, fmt.Sprintf("/api/v0/p/%s/t/aliases/js", model.NewHexID(999)), "")
+	if w.Code != http.StatusNotFound || !strings.Contains(w.Body.String(), "Project with ID") {
+		t.Errorf("Expected project not found for a missing project, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/stsysd/sougen/model"
+	"github.com/stsysd/sougen/store"
+)
+
+// AddTagParams represents parameters for adding a tag to matching records.
+type AddTagParams struct {
+	ProjectID model.HexID
+	Tag       string
+	From      time.Time
+	To        time.Time
+	Tags      []string // only records with all of these tags
+}
+
+// NewAddTagParams creates parameters for adding a tag from HTTP request.
+func NewAddTagParams(r *http.Request, maxFilterTags int) (*AddTagParams, error) {
+	projectID, err := model.ParseHexID(r.PathValue("project_id"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid project_id: %w", err)
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body")
+	}
+	// スキーマ検証（すべての違反をまとめて報告）
+	if err := validateBody(addTagSchema, body); err != nil {
+		return nil, err
+	}
+	var addTagData struct {
+		Tag    string          `json:"tag"`
+		Filter json.RawMessage `json:"filter"`
+	}
+	if err := json.Unmarshal(body, &addTagData); err != nil {
+		return nil, fmt.Errorf("invalid JSON format")
+	}
+
+	if err := model.ValidateTag(addTagData.Tag); err != nil {
+		return nil, err
+	}
+
+	// 絞り込み条件（省略した場合はプロジェクトのすべてのレコード）
+	var filter struct {
+		From string   `json:"from"`
+		To   string   `json:"to"`
+		Tags []string `json:"tags"`
+	}
+	if len(addTagData.Filter) > 0 && string(addTagData.Filter) != "null" {
+		if err := validateBody(recordFilterSchema, addTagData.Filter); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(addTagData.Filter, &filter); err != nil {
+			return nil, fmt.Errorf("invalid JSON format")
+		}
+	}
+	if maxFilterTags > 0 && len(filter.Tags) > maxFilterTags {
+		return nil, fmt.Errorf("too many filter tags (max %d tags)", maxFilterTags)
+	}
+
+	// from/toはエクスポートと同様にそれぞれ指定された場合のみ期間を絞り込む
+	from, to, err := parseExportRange(url.Values{"from": {filter.From}, "to": {filter.To}})
+	if err != nil {
+		return nil, err
+	}
+	if from.After(to) {
+		return nil, fmt.Errorf("from must not be after to")
+	}
+
+	return &AddTagParams{
+		ProjectID: projectID,
+		Tag:       addTagData.Tag,
+		From:      from,
+		To:        to,
+		Tags:      filter.Tags,
+	}, nil
+}
+
+// handleAddTag は条件に一致するレコードにタグをまとめて追加するハンドラーです。
+// すでにタグを持つレコードはスキップし、タグを追加したレコード数を返します。
+func (s *Server) handleAddTag(w http.ResponseWriter, r *http.Request) {
+	// パラメータを検証
+	params, err := NewAddTagParams(r, s.config.MaxFilterTags)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// プロジェクトの存在確認
+	if !s.checkProjectExists(w, r, params.ProjectID) {
+		return
+	}
+
+	count, err := s.store.AddTag(r.Context(), &store.AddTagParams{
+		ProjectID: params.ProjectID,
+		Tag:       params.Tag,
+		From:      params.From,
+		To:        params.To,
+		Tags:      params.Tags,
+	})
+	if err != nil {
+		log.Printf("Error adding tag to records: %v", err)
+		writeJSONError(w, "Failed to add tag", http.StatusInternalServerError)
+		return
+	}
+	s.graphCache.invalidateProject(params.ProjectID)
+
+	// 追加結果をJSONで返す
+	writeJSON(w, r, http.StatusOK, map[string]int{
+		"updated_count": count,
+	})
+}
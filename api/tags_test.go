@@ -0,0 +1,85 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stsysd/sougen/model"
+)
+
+func TestAddTagEndpoint(t *testing.T) {
+	mockStore := NewMockStore()
+	project, _ := model.NewProject("add-tag-project", "")
+	mockStore.CreateProject(context.Background(), project)
+	server := NewServer(mockStore, newTestConfig())
+
+	do := func(target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		req.Header.Set("X-API-Key", testAPIKey)
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		return w
+	}
+	addURL := fmt.Sprintf("/api/v0/p/%s/t/add", project.ID)
+
+	// 6/2〜6/3のレコードのみ対象
+	var records []*model.Record
+	for _, day := range []int{1, 2, 3, 4} {
+		record, _ := model.NewRecord(time.Date(2025, 6, day, 12, 0, 0, 0, time.Local), project.ID, 1, []string{"work"})
+		mockStore.CreateRecord(context.Background(), record)
+		records = append(records, record)
+	}
+
+	w := do(addURL, `{"tag":"review","filter":{"from":"2025-06-02","to":"2025-06-03"}}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var response map[string]int
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response body: %v", err)
+	}
+	if response["updated_count"] != 2 {
+		t.Errorf("Expected updated_count 2, got %v", response)
+	}
+	for i, record := range records {
+		want := i == 1 || i == 2
+		if got := slices.Contains(record.Tags, "review"); got != want {
+			t.Errorf("Record %d: expected review tag %v, got tags %v", i, want, record.Tags)
+		}
+	}
+
+	// すでにタグを持つレコードはスキップ（フィルタ省略時はすべてのレコード）
+	w = do(addURL, `{"tag":"review"}`)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"updated_count":2`) {
+		t.Errorf("Expected updated_count 2 without filter, got %d: %s", w.Code, w.Body.String())
+	}
+
+	tests := []struct {
+		name   string
+		target string
+		body   string
+		status int
+	}{
+		{"missing tag", addURL, `{}`, http.StatusBadRequest},
+		{"invalid tag", addURL, `{"tag":"bad tag"}`, http.StatusBadRequest},
+		{"filter not an object", addURL, `{"tag":"x","filter":"2025"}`, http.StatusBadRequest},
+		{"invalid filter tags", addURL, `{"tag":"x","filter":{"tags":"work"}}`, http.StatusBadRequest},
+		{"invalid date", addURL, `{"tag":"x","filter":{"from":"June"}}`, http.StatusBadRequest},
+		{"from after to", addURL, `{"tag":"x","filter":{"from":"2025-06-03","to":"2025-06-02"}}`, http.StatusBadRequest},
+		{"missing project", fmt.Sprintf("/api/v0/p/%s/t/add", model.NewHexID(999)), `{"tag":"x"}`, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := do(tt.target, tt.body); w.Code != tt.status {
+				t.Errorf("Expected status code %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/template"
+)
+
+// templateFuncs はボディ変換テンプレートで使える関数です。
+// JSONをデコードした値（map[string]any, []any, float64など）を前提に、nilを安全に扱います。
+var templateFuncs = template.FuncMap{
+	"len":     templateLen,
+	"index":   templateIndex,
+	"default": templateDefault,
+}
+
+// templateLen は文字列・配列・オブジェクトの長さを返します。nilの場合は0を返します。
+func templateLen(v any) (int, error) {
+	switch v := v.(type) {
+	case nil:
+		return 0, nil
+	case string:
+		return len(v), nil
+	case []any:
+		return len(v), nil
+	case map[string]any:
+		return len(v), nil
+	default:
+		return 0, fmt.Errorf("len of %T is not supported", v)
+	}
+}
+
+// templateIndex はオブジェクトのキーまたは配列の添字で値を順にたどります。
+// 存在しないキーや途中のnullはnilを返します（defaultと組み合わせて使用）。配列の範囲外はエラーです。
+func templateIndex(item any, keys ...any) (any, error) {
+	for _, key := range keys {
+		switch v := item.(type) {
+		case nil:
+			return nil, nil
+		case map[string]any:
+			k, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("object key must be a string, got %T", key)
+			}
+			item = v[k]
+		case []any:
+			i, ok := templateIndexInt(key)
+			if !ok {
+				return nil, fmt.Errorf("array index must be an integer, got %v", key)
+			}
+			if i < 0 || i >= len(v) {
+				return nil, fmt.Errorf("array index %d out of range (length %d)", i, len(v))
+			}
+			item = v[i]
+		default:
+			return nil, fmt.Errorf("cannot index %T", item)
+		}
+	}
+	return item, nil
+}
+
+// templateIndexInt はテンプレートの整数リテラルまたはJSONの数値を配列の添字に変換します。
+func templateIndexInt(key any) (int, bool) {
+	switch k := key.(type) {
+	case int:
+		return k, true
+	case float64:
+		if k != float64(int(k)) {
+			return 0, false
+		}
+		return int(k), true
+	default:
+		return 0, false
+	}
+}
+
+// templateDefault はvalueがnull・空文字列・空の配列/オブジェクトの場合にfallbackを返します。
+// 例: {{default 1 (index . "size")}}
+func templateDefault(fallback, value any) any {
+	switch v := value.(type) {
+	case nil:
+		return fallback
+	case string:
+		if v == "" {
+			return fallback
+		}
+	case []any:
+		if len(v) == 0 {
+			return fallback
+		}
+	case map[string]any:
+		if len(v) == 0 {
+			return fallback
+		}
+	}
+	return value
+}
+
+// transformRequestBody はJSONのリクエストボディをtext/templateで変換します。
+// テンプレートにはデコードしたボディがドット（.）として渡されます。
+// 存在しないフィールドの参照（{{.missing.field}}など）は誤ったレコードを作らないようエラーとします。
+// 省略可能なフィールドはindexとdefaultで扱います。
+// 外部サービスのペイロードをレコード作成用のJSON（project_id, timestamp, valueなど）に変換する用途を想定しています。
+// 変換結果がJSONとして不正な場合はエラーを返します。
+func (s *Server) transformRequestBody(r io.Reader, tmpl string) (string, error) {
+	t, err := template.New("body").Funcs(templateFuncs).Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+
+	var data any
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return "", fmt.Errorf("invalid request body: %w", err)
+	}
+
+	var sb strings.Builder
+	if err := t.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	result := sb.String()
+	if !json.Valid([]byte(result)) {
+		return "", fmt.Errorf("template output is not valid JSON")
+	}
+	return result, nil
+}
+
+// applyRequestTemplate はtemplateクエリパラメータが指定されている場合にリクエストボディを変換します。
+func (s *Server) applyRequestTemplate(r *http.Request) error {
+	tmpl := r.URL.Query().Get("template")
+	if tmpl == "" {
+		return nil
+	}
+	body, err := s.transformRequestBody(r.Body, tmpl)
+	if err != nil {
+		return err
+	}
+	r.Body = io.NopCloser(bytes.NewBufferString(body))
+	return nil
+}
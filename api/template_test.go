@@ -0,0 +1,166 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stsysd/sougen/model"
+)
+
+func TestTransformRequestBody(t *testing.T) {
+	server := NewServer(NewMockStore(), newTestConfig())
+
+	tests := []struct {
+		name        string
+		body        string
+		template    string
+		expected    string
+		expectError bool
+	}{
+		{
+			name:     "Map fields",
+			body:     `{"head_commit": {"timestamp": "2025-06-10T09:00:00Z"}, "size": 3}`,
+			template: `{"timestamp": "{{.head_commit.timestamp}}", "value": {{.size}}}`,
+			expected: `{"timestamp": "2025-06-10T09:00:00Z", "value": 3}`,
+		},
+		{
+			name:        "Missing nested field",
+			body:        `{"size": 3}`,
+			template:    `{"value": {{.nonexistent.field}}}`,
+			expectError: true,
+		},
+		{
+			name:        "Missing field",
+			body:        `{"head_commit": {}}`,
+			template:    `{"timestamp": "{{.head_commit.timestamp}}"}`,
+			expectError: true,
+		},
+		{
+			name:     "Default for missing field",
+			body:     `{"size": 3}`,
+			template: `{"value": {{default 1 (index . "nonexistent" "field")}}}`,
+			expected: `{"value": 1}`,
+		},
+		{
+			name:     "Default keeps present value",
+			body:     `{"stats": {"size": 4}}`,
+			template: `{"value": {{default 1 (index . "stats" "size")}}}`,
+			expected: `{"value": 4}`,
+		},
+		{
+			name:     "Default for empty string",
+			body:     `{"source": ""}`,
+			template: `{"source": "{{default "webhook" .source}}"}`,
+			expected: `{"source": "webhook"}`,
+		},
+		{
+			name:     "Index into array and len",
+			body:     `{"commits": [{"id": "a"}, {"id": "b"}]}`,
+			template: `{"tags": ["{{index .commits 1 "id"}}"], "value": {{len .commits}}}`,
+			expected: `{"tags": ["b"], "value": 2}`,
+		},
+		{
+			name:     "Len of null",
+			body:     `{"commits": null}`,
+			template: `{"value": {{len .commits}}}`,
+			expected: `{"value": 0}`,
+		},
+		{
+			name:        "Invalid template syntax",
+			body:        `{"size": 3}`,
+			template:    `{"value": {{.size}`,
+			expectError: true,
+		},
+		{
+			name:        "Execution error",
+			body:        `{"commits": []}`,
+			template:    `{"value": {{index .commits 5}}}`,
+			expectError: true,
+		},
+		{
+			name:        "Output is not JSON",
+			body:        `{"size": 3}`,
+			template:    `value={{.size}}`,
+			expectError: true,
+		},
+		{
+			name:        "Body is not JSON",
+			body:        `size=3`,
+			template:    `{"value": 1}`,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := server.transformRequestBody(strings.NewReader(tt.body), tt.template)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error, got %q", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestCreateRecordWithTemplate(t *testing.T) {
+	mockStore := NewMockStore()
+	project, _ := model.NewProject("template-project", "")
+	mockStore.CreateProject(context.Background(), project)
+	server := NewServer(mockStore, newTestConfig())
+
+	createRecord := func(template, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v0/r?template="+url.QueryEscape(template), strings.NewReader(body))
+		req.Header.Set("X-API-Key", testAPIKey)
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		return w
+	}
+
+	template := fmt.Sprintf(`{"project_id": "%s", "timestamp": "{{.head_commit.timestamp}}", "value": {{len .commits}}}`, project.ID)
+	w := createRecord(template, `{"commits": [{}, {}, {}], "head_commit": {"timestamp": "2025-06-10T09:00:00Z"}}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var record model.Record
+	if err := json.NewDecoder(w.Body).Decode(&record); err != nil {
+		t.Fatalf("Failed to decode response body: %v", err)
+	}
+	if record.Value != 3 {
+		t.Errorf("Expected value 3, got %d", record.Value)
+	}
+	if record.Timestamp.UTC().Format("2006-01-02T15:04:05Z") != "2025-06-10T09:00:00Z" {
+		t.Errorf("Unexpected timestamp: %v", record.Timestamp)
+	}
+
+	errorCases := []struct {
+		name     string
+		template string
+		body     string
+	}{
+		{"Invalid template syntax", `{{.commits`, `{"commits": []}`},
+		{"Execution error", `{{index .commits 1}}`, `{"commits": []}`},
+		{"Output is not JSON", `{{len .commits}} commits`, `{"commits": []}`},
+		{"Missing nested field", `{"value": {{.nonexistent.field}}}`, `{"commits": []}`},
+	}
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if w := createRecord(tc.template, tc.body); w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+// Package api はsougenのAPIサーバー実装を提供します。
+package api
+
+import (
+	"net/http"
+
+	"github.com/stsysd/sougen/heatmap"
+)
+
+// ThemeResponse はグラフのテーマ1件分です。
+// Colorsはレベル0（値が0のセル）から順の色です。
+type ThemeResponse struct {
+	Name   string   `json:"name"`
+	Colors []string `json:"colors"`
+}
+
+// handleListThemes はthemeパラメータに指定できるテーマの一覧を名前順で返すハンドラーです。
+// テーマは静的なため認証は不要です。
+func (s *Server) handleListThemes(w http.ResponseWriter, r *http.Request) {
+	names := heatmap.ThemeNames()
+	response := make([]ThemeResponse, 0, len(names))
+	for _, name := range names {
+		colors, _ := heatmap.ThemeColors(name)
+		response = append(response, ThemeResponse{Name: name, Colors: colors})
+	}
+
+	// レスポンスの返却
+	writeJSON(w, r, http.StatusOK, response)
+}
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestListThemesEndpoint(t *testing.T) {
+	server := NewServer(NewMockStore(), newTestConfig())
+
+	// APIキーなしで取得できる
+	req := httptest.NewRequest(http.MethodGet, "/api/v0/themes", nil)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var themes []ThemeResponse
+	if err := json.NewDecoder(w.Body).Decode(&themes); err != nil {
+		t.Fatalf("Failed to decode response body: %v", err)
+	}
+	names := make([]string, 0, len(themes))
+	for _, theme := range themes {
+		names = append(names, theme.Name)
+		if len(theme.Colors) != 6 {
+			t.Errorf("Expected 6 colors for theme %q, got %v", theme.Name, theme.Colors)
+		}
+	}
+	if !slices.Equal(names, []string{"cividis", "github"}) {
+		t.Errorf("Expected the known themes in name order, got %v", names)
+	}
+}
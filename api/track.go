@@ -0,0 +1,134 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/stsysd/sougen/model"
+)
+
+// transparentGIF は1x1の透明なGIF画像です（pixel=trueの応答）。
+var transparentGIF = []byte{
+	0x47, 0x49, 0x46, 0x38, 0x39, 0x61, 0x01, 0x00, 0x01, 0x00, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0xff, 0xff, 0xff, 0x21, 0xf9, 0x04, 0x01, 0x00, 0x00, 0x00, 0x00, 0x2c, 0x00, 0x00, 0x00, 0x00,
+	0x01, 0x00, 0x01, 0x00, 0x00, 0x02, 0x02, 0x44, 0x01, 0x00, 0x3b,
+}
+
+// TrackParams represents parameters for recording a value via a GET request.
+type TrackParams struct {
+	ProjectID model.HexID
+	Value     *model.Value // パスで指定された値（nilの場合はプロジェクトのtrackの既定値）
+	Tags      *model.Tags
+	Pixel     bool // 204の代わりに1x1の透明なGIFを返すか
+}
+
+// NewTrackParams creates parameters for recording a value from HTTP request.
+func NewTrackParams(r *http.Request) (*TrackParams, error) {
+	projectID, err := model.ParseHexID(r.PathValue("project_id"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid project_id: %w", err)
+	}
+
+	var value *model.Value
+	if valueStr := r.PathValue("value"); valueStr != "" {
+		v, err := strconv.Atoi(valueStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value: must be an integer")
+		}
+		value, err = model.NewValue(&v)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	query := r.URL.Query()
+	pixel, err := parseBoolQuery(query, "pixel")
+	if err != nil {
+		return nil, err
+	}
+
+	return &TrackParams{
+		ProjectID: projectID,
+		Value:     value,
+		Tags:      model.NewTags(query.Get("tags")),
+		Pixel:     pixel,
+	}, nil
+}
+
+// trackAuthMiddleware はGETでの記録の認証を行うミドルウェアです。
+// グラフのtrackと同様にconfig.SigningSecretが設定されていない場合は認証なしで受け付け、
+// 設定されている場合はAPIキーが必要です。署名はグラフのパラメータのみを対象とするため、署名付きURLでは記録できません。
+func (s *Server) trackAuthMiddleware(next http.Handler) http.Handler {
+	authenticated := s.authMiddleware(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.config.SigningSecret == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		authenticated.ServeHTTP(w, r)
+	})
+}
+
+// handleTrack はGETのみ送信できるクライアント（スマートボタンやビーコン）のために、
+// パスで指定された値（省略時はプロジェクトのtrackの既定値）のレコードを現在時刻で作成するハンドラーです。
+// 成功時は204、pixel=trueの場合は1x1の透明なGIFを返します。
+func (s *Server) handleTrack(w http.ResponseWriter, r *http.Request) {
+	// パラメータを検証
+	params, err := NewTrackParams(r)
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// プロジェクトの存在確認
+	project, err := s.store.GetProject(r.Context(), params.ProjectID)
+	if err != nil {
+		if errors.Is(err, model.ErrProjectNotFound) {
+			writeJSONError(w, fmt.Sprintf("Project with ID %s not found", params.ProjectID), http.StatusNotFound)
+		} else {
+			writeJSONError(w, fmt.Sprintf("Error retrieving project: %v", err), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// 新しいレコードの作成
+	value := project.TrackValue()
+	if params.Value != nil {
+		value = params.Value.Int()
+	}
+	record, err := model.NewRecord(time.Now(), params.ProjectID, value, params.Tags.Values())
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	record.Source = model.RecordSourceTrack
+	if err := s.checkNewRecord(project, record); err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// レコードの保存
+	if err := s.store.CreateRecord(r.Context(), record); err != nil {
+		log.Printf("Error creating record: %v", err)
+		writeJSONError(w, "Failed to create record", http.StatusInternalServerError)
+		return
+	}
+	s.graphCache.invalidateProject(record.ProjectID)
+	s.notifyRecordCreated(record)
+
+	// ビーコンがキャッシュされると記録されないため、キャッシュを禁止する
+	w.Header().Set("Cache-Control", "no-store")
+	if !params.Pixel {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.Header().Set("Content-Type", "image/gif")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(transparentGIF); err != nil {
+		log.Printf("Error writing response: %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image/gif"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/stsysd/sougen/model"
+)
+
+func TestTrackEndpoint(t *testing.T) {
+	mockStore := NewMockStore()
+	project, _ := model.NewProject("track-project", "")
+	defaultValue := 3
+	project.TrackDefaultValue = &defaultValue
+	mockStore.CreateProject(context.Background(), project)
+	server := NewServer(mockStore, newTestConfig())
+
+	get := func(server *Server, target, apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if apiKey != "" {
+			req.Header.Set("X-API-Key", apiKey)
+		}
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		return w
+	}
+	trackURL := fmt.Sprintf("/p/%s/track", project.ID)
+	records := func() []*model.Record {
+		var records []*model.Record
+		for _, record := range mockStore.records {
+			records = append(records, record)
+		}
+		slices.SortFunc(records, func(a, b *model.Record) int { return int(a.ID.ToInt64() - b.ID.ToInt64()) })
+		return records
+	}
+
+	// パスの値とタグでレコードを作成し、204を返す
+	w := get(server, trackURL+"/5?tags=button,home", "")
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusNoContent, w.Code, w.Body.String())
+	}
+	if w.Header().Get("Cache-Control") != "no-store" {
+		t.Errorf("Expected Cache-Control: no-store, got %q", w.Header().Get("Cache-Control"))
+	}
+	created := records()
+	if len(created) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(created))
+	}
+	if created[0].Value != 5 || !created[0].HasSameTags([]string{"button", "home"}) || created[0].Source != model.RecordSourceTrack {
+		t.Errorf("Unexpected record: %+v", created[0])
+	}
+
+	// 値を省略するとプロジェクトのtrackの既定値、pixel=trueでは透明なGIFを返す
+	w = get(server, trackURL+"?pixel=true", "")
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "image/gif" {
+		t.Fatalf("Expected a GIF, got %d (%s)", w.Code, w.Header().Get("Content-Type"))
+	}
+	img, err := gif.Decode(bytes.NewReader(w.Body.Bytes()))
+	if err != nil {
+		t.Fatalf("Failed to decode GIF: %v", err)
+	}
+	if bounds := img.Bounds(); bounds.Dx() != 1 || bounds.Dy() != 1 {
+		t.Errorf("Expected a 1x1 image, got %v", bounds)
+	}
+	if _, _, _, a := img.At(0, 0).RGBA(); a != 0 {
+		t.Errorf("Expected a transparent pixel, got alpha %d", a)
+	}
+	if created := records(); len(created) != 2 || created[1].Value != defaultValue {
+		t.Errorf("Expected a record with the default value %d, got %+v", defaultValue, created)
+	}
+
+	// 不正な値と存在しないプロジェクト
+	if w := get(server, trackURL+"/abc", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d for an invalid value, got %d", http.StatusBadRequest, w.Code)
+	}
+	if w := get(server, "/p/ffff/track/1", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d for a missing project, got %d", http.StatusNotFound, w.Code)
+	}
+
+	// 署名の秘密鍵が設定されている場合はAPIキーが必要
+	cfg := newTestConfig()
+	cfg.SigningSecret = "test-secret"
+	signedServer := NewServer(mockStore, cfg)
+	if w := get(signedServer, trackURL+"/1", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status code %d without an API key, got %d", http.StatusUnauthorized, w.Code)
+	}
+	if w := get(signedServer, trackURL+"/1", testAPIKey); w.Code != http.StatusNoContent {
+		t.Errorf("Expected status code %d with an API key, got %d", http.StatusNoContent, w.Code)
+	}
+	if created := records(); len(created) != 3 {
+		t.Errorf("Expected 3 records, got %d", len(created))
+	}
+}
@@ -0,0 +1,191 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// fieldType はリクエストボディのフィールドに期待するJSONの型です。
+type fieldType int
+
+const (
+	fieldString      fieldType = iota // 文字列
+	fieldInteger                      // 整数
+	fieldNumber                       // 数値（小数を含む）
+	fieldStringArray                  // 文字列の配列
+	fieldObject                       // オブジェクト
+)
+
+// String はエラーメッセージ用の型名を返します。
+func (t fieldType) String() string {
+	switch t {
+	case fieldString:
+		return "string"
+	case fieldInteger:
+		return "integer"
+	case fieldNumber:
+		return "number"
+	case fieldStringArray:
+		return "array of strings"
+	case fieldObject:
+		return "object"
+	default:
+		return "unknown"
+	}
+}
+
+// fieldSpec はリクエストボディの1フィールドの定義です。
+type fieldSpec struct {
+	Name     string
+	Type     fieldType
+	Required bool
+}
+
+// bodySchema はリクエストボディ（JSONオブジェクト）の定義です。
+// 定義にないフィールドは検証しません。必須でないフィールドはnullを許可します。
+type bodySchema []fieldSpec
+
+// createRecordSchema はレコード作成リクエストのスキーマです。
+var createRecordSchema = bodySchema{
+	{Name: "project_id", Type: fieldString, Required: true},
+	{Name: "timestamp", Type: fieldString},
+	{Name: "value", Type: fieldInteger},
+	{Name: "tags", Type: fieldStringArray},
+	{Name: "source", Type: fieldString},
+	{Name: "value_float", Type: fieldNumber},
+	{Name: "timestamp_unix", Type: fieldInteger},
+	{Name: "external_id", Type: fieldString},
+}
+
+// createProjectSchema はプロジェクト作成リクエストのスキーマです。
+var createProjectSchema = bodySchema{
+	{Name: "name", Type: fieldString, Required: true},
+	{Name: "description", Type: fieldString},
+	{Name: "track_default_value", Type: fieldInteger},
+	{Name: "color", Type: fieldString},
+	{Name: "value_type", Type: fieldString},
+	{Name: "retention_days", Type: fieldInteger},
+	{Name: "min_value", Type: fieldNumber},
+	{Name: "max_value", Type: fieldNumber},
+	{Name: "timezone", Type: fieldString},
+}
+
+// updateProjectSchema はプロジェクト更新リクエストのスキーマです。
+var updateProjectSchema = bodySchema{
+	{Name: "name", Type: fieldString},
+	{Name: "description", Type: fieldString},
+	{Name: "track_default_value", Type: fieldInteger},
+	{Name: "color", Type: fieldString},
+	{Name: "value_type", Type: fieldString},
+	{Name: "retention_days", Type: fieldInteger},
+	{Name: "min_value", Type: fieldNumber},
+	{Name: "max_value", Type: fieldNumber},
+	{Name: "timezone", Type: fieldString},
+}
+
+// cloneProjectSchema はプロジェクト複製リクエストのスキーマです。
+var cloneProjectSchema = bodySchema{
+	{Name: "name", Type: fieldString, Required: true},
+}
+
+// tagAliasSchema はタグの別名の設定リクエストのスキーマです。
+var tagAliasSchema = bodySchema{
+	{Name: "tag", Type: fieldString, Required: true},
+}
+
+// addTagSchema はタグの一括追加リクエストのスキーマです。
+var addTagSchema = bodySchema{
+	{Name: "tag", Type: fieldString, Required: true},
+	{Name: "filter", Type: fieldObject},
+}
+
+// shiftRecordsSchema は日単位のレコードの複製リクエストのスキーマです。
+var shiftRecordsSchema = bodySchema{
+	{Name: "from_date", Type: fieldString, Required: true},
+	{Name: "to_date", Type: fieldString, Required: true},
+}
+
+// recordFilterSchema はリクエストボディでレコードを絞り込む条件（filter）のスキーマです。
+var recordFilterSchema = bodySchema{
+	{Name: "from", Type: fieldString},
+	{Name: "to", Type: fieldString},
+	{Name: "tags", Type: fieldStringArray},
+}
+
+// BodyValidationError はリクエストボディのスキーマ違反をまとめたエラーです。
+type BodyValidationError struct {
+	Violations []string
+}
+
+// Error はすべての違反をまとめたメッセージを返します。
+func (e *BodyValidationError) Error() string {
+	return "invalid request body: " + strings.Join(e.Violations, "; ")
+}
+
+// validateBody はリクエストボディをスキーマに照らして検証し、
+// 違反があればすべてをまとめたBodyValidationErrorを返します。
+func validateBody(schema bodySchema, body []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var object map[string]any
+	if err := decoder.Decode(&object); err != nil || object == nil {
+		return &BodyValidationError{Violations: []string{"body: must be a JSON object"}}
+	}
+
+	var violations []string
+	for _, field := range schema {
+		value, exists := object[field.Name]
+		if !exists || value == nil {
+			if field.Required {
+				violations = append(violations, fmt.Sprintf("%s: is required", field.Name))
+			}
+			continue
+		}
+		if !matchesFieldType(field.Type, value) {
+			violations = append(violations, fmt.Sprintf("%s: must be %s", field.Name, field.Type))
+		}
+	}
+
+	if len(violations) > 0 {
+		return &BodyValidationError{Violations: violations}
+	}
+	return nil
+}
+
+// matchesFieldType はデコード済みのJSON値が期待する型かどうかを判定します。
+func matchesFieldType(t fieldType, value any) bool {
+	switch t {
+	case fieldString:
+		_, ok := value.(string)
+		return ok
+	case fieldInteger:
+		n, ok := value.(json.Number)
+		if !ok {
+			return false
+		}
+		_, err := n.Int64()
+		return err == nil
+	case fieldNumber:
+		_, ok := value.(json.Number)
+		return ok
+	case fieldStringArray:
+		items, ok := value.([]any)
+		if !ok {
+			return false
+		}
+		for _, item := range items {
+			if _, ok := item.(string); !ok {
+				return false
+			}
+		}
+		return true
+	case fieldObject:
+		_, ok := value.(map[string]any)
+		return ok
+	default:
+		return false
+	}
+}
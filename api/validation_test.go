@@ -0,0 +1,161 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stsysd/sougen/model"
+)
+
+func TestValidateBody(t *testing.T) {
+	tests := []struct {
+		name       string
+		schema     bodySchema
+		body       string
+		violations []string
+	}{
+		{
+			name:   "Valid record",
+			schema: createRecordSchema,
+			body:   `{"project_id": "0000000000000001", "value": 3, "tags": ["a", "b"], "timestamp": "2025-06-01T00:00:00Z"}`,
+		},
+		{
+			name:   "Optional fields may be null",
+			schema: createRecordSchema,
+			body:   `{"project_id": "0000000000000001", "value": null, "tags": null}`,
+		},
+		{
+			name:   "Multiple violations",
+			schema: createRecordSchema,
+			body:   `{"value": "3", "tags": "a,b", "timestamp": 20250601}`,
+			violations: []string{
+				"project_id: is required",
+				"timestamp: must be string",
+				"value: must be integer",
+				"tags: must be array of strings",
+			},
+		},
+		{
+			name:       "Non-integer number",
+			schema:     createRecordSchema,
+			body:       `{"project_id": "0000000000000001", "value": 1.5}`,
+			violations: []string{"value: must be integer"},
+		},
+		{
+			name:       "Array with non-string item",
+			schema:     createRecordSchema,
+			body:       `{"project_id": "0000000000000001", "tags": ["a", 1]}`,
+			violations: []string{"tags: must be array of strings"},
+		},
+		{
+			name:       "Not an object",
+			schema:     createProjectSchema,
+			body:       `["name"]`,
+			violations: []string{"body: must be a JSON object"},
+		},
+		{
+			name:       "Malformed JSON",
+			schema:     createProjectSchema,
+			body:       `{"name": `,
+			violations: []string{"body: must be a JSON object"},
+		},
+		{
+			name:       "Project fields",
+			schema:     createProjectSchema,
+			body:       `{"name": 1, "description": false, "track_default_value": "3"}`,
+			violations: []string{"name: must be string", "description: must be string", "track_default_value: must be integer"},
+		},
+		{
+			name:   "Update allows empty object",
+			schema: updateProjectSchema,
+			body:   `{}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateBody(tt.schema, []byte(tt.body))
+			if len(tt.violations) == 0 {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+
+			var validationErr *BodyValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Expected BodyValidationError, got %v", err)
+			}
+			if !slices.Equal(validationErr.Violations, tt.violations) {
+				t.Errorf("Expected violations %v, got %v", tt.violations, validationErr.Violations)
+			}
+		})
+	}
+}
+
+func TestRequestBodyValidationErrors(t *testing.T) {
+	mockStore := NewMockStore()
+	project, _ := model.NewProject("test-project", "Test project")
+	mockStore.CreateProject(context.Background(), project)
+	server := NewServer(mockStore, newTestConfig())
+
+	tests := []struct {
+		name     string
+		method   string
+		url      string
+		body     string
+		expected []string
+	}{
+		{
+			name:     "Create record",
+			method:   http.MethodPost,
+			url:      "/api/v0/r",
+			body:     `{"value": "1", "tags": [1, 2]}`,
+			expected: []string{"project_id: is required", "value: must be integer", "tags: must be array of strings"},
+		},
+		{
+			name:     "Create project",
+			method:   http.MethodPost,
+			url:      "/api/v0/p",
+			body:     `{"description": 1, "track_default_value": true}`,
+			expected: []string{"name: is required", "description: must be string", "track_default_value: must be integer"},
+		},
+		{
+			name:     "Update project",
+			method:   http.MethodPut,
+			url:      fmt.Sprintf("/api/v0/p/%s", project.ID),
+			body:     `{"name": ["x"], "description": 2}`,
+			expected: []string{"name: must be string", "description: must be string"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.url, strings.NewReader(tc.body))
+			req.Header.Set("X-API-Key", testAPIKey)
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("Expected status code %d, got %d", http.StatusBadRequest, w.Code)
+			}
+
+			var response ErrorResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response body: %v", err)
+			}
+			for _, violation := range tc.expected {
+				if !strings.Contains(response.Error, violation) {
+					t.Errorf("Expected error to contain %q, got %q", violation, response.Error)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+// Package api はsougenのAPIサーバー実装を提供します。
+package api
+
+import (
+	"log"
+	"net/http"
+)
+
+// Version はアプリケーションのバージョンです。
+// ビルド時に -ldflags "-X github.com/stsysd/sougen/api.Version=..." で上書きできます。
+var Version = "dev"
+
+// VersionResponse はバージョン情報エンドポイントのレスポンスです。
+type VersionResponse struct {
+	Version       string `json:"version"`
+	SchemaVersion int64  `json:"schema_version"`
+}
+
+// handleVersion はアプリケーションと適用済みスキーマのバージョンを返すハンドラーです。
+func (s *Server) handleVersion(w http.ResponseWriter, r *http.Request) {
+	schemaVersion, err := s.store.SchemaVersion(r.Context())
+	if err != nil {
+		log.Printf("Error retrieving schema version: %v", err)
+		writeJSONError(w, "Failed to retrieve schema version", http.StatusInternalServerError)
+		return
+	}
+
+	// レスポンスの返却
+	writeJSON(w, r, http.StatusOK, VersionResponse{
+		Version:       Version,
+		SchemaVersion: schemaVersion,
+	})
+}
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVersionEndpoint(t *testing.T) {
+	server := NewServer(NewMockStore(), newTestConfig())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v0/version", nil)
+	req.Header.Set("X-API-Key", testAPIKey)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response VersionResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Version != Version {
+		t.Errorf("Expected version %q, got %q", Version, response.Version)
+	}
+}
+
+func TestVersionEndpointRequiresAuth(t *testing.T) {
+	server := NewServer(NewMockStore(), newTestConfig())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v0/version", nil)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
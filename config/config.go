@@ -4,8 +4,51 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stsysd/sougen/heatmap"
+)
+
+// 認証に使用するヘッダーの指定
+const (
+	AuthHeaderAny    = ""          // X-API-KeyとAuthorization: Bearerのどちらも受け付ける
+	AuthHeaderAPIKey = "x-api-key" // X-API-Keyヘッダーのみ
+	AuthHeaderBearer = "bearer"    // Authorization: Bearerヘッダーのみ
+)
+
+// 期間内にレコードがないグラフの応答
+const (
+	EmptyGraphPlaceholder = "placeholder" // 値が0のセルだけのグラフを返す
+	EmptyGraphNoContent   = "no_content"  // 204 No Contentを返す
+	EmptyGraphError       = "error"       // 404とJSONのエラーを返す
+)
+
+// IsEmptyGraphBehavior は文字列が空のグラフの応答（EmptyGraphPlaceholderなど）かどうかを判定します。
+func IsEmptyGraphBehavior(s string) bool {
+	switch s {
+	case EmptyGraphPlaceholder, EmptyGraphNoContent, EmptyGraphError:
+		return true
+	}
+	return false
+}
+
+// JSONレスポンスのフィールド名の形式
+const (
+	JSONNamingSnake = "snake" // snake_case（例: project_id）
+	JSONNamingCamel = "camel" // camelCase（例: projectId）
 )
 
+// IsJSONNaming は文字列がJSONレスポンスのフィールド名の形式（JSONNamingSnakeなど）かどうかを判定します。
+func IsJSONNaming(s string) bool {
+	switch s {
+	case JSONNamingSnake, JSONNamingCamel:
+		return true
+	}
+	return false
+}
+
 // Config はアプリケーション全体の設定を保持します。
 type Config struct {
 	// データディレクトリのパス
@@ -14,8 +57,90 @@ type Config struct {
 	// HTTPサーバーのポート
 	Port string
 
+	// TLSの証明書と秘密鍵のファイルパス（両方設定した場合はHTTPS（HTTP/2対応）で待ち受ける、空の場合はHTTP）
+	TLSCertFile string
+	TLSKeyFile  string
+
+	// すべてのルートの前に付けるパス（例: "/sougen"、空の場合はルート直下）
+	// リバースプロキシの配下で公開する場合に使用する
+	BasePath string
+
 	// API認証キー
 	APIKey string
+
+	// 認証に使用するヘッダー（AuthHeaderAny、AuthHeaderAPIKey、AuthHeaderBearer）
+	AuthHeader string
+
+	// レコード作成時に通知するWebhookのURL（空の場合は通知しない）
+	WebhookURL string
+
+	// Webhookにまとめて送信するレコード数（1以下の場合はレコードごとに送信）
+	WebhookBatchSize int
+
+	// Webhookのバッファをフラッシュする間隔
+	WebhookBatchInterval time.Duration
+
+	// themeパラメータが指定されない場合に使用するグラフのテーマ
+	DefaultTheme string
+
+	// 描画済みグラフをキャッシュする最大件数（0の場合はキャッシュしない）
+	GraphCacheSize int
+
+	// 描画済みグラフのキャッシュの有効期間
+	GraphCacheTTL time.Duration
+
+	// クライアントIPをX-Forwarded-For/X-Real-IPヘッダーから取得するか
+	// リバースプロキシの背後で動作する場合のみ有効にする（ヘッダーは偽装可能なため既定は無効）
+	UseForwardedFor bool
+
+	// 1リクエストあたりのデータベース処理の制限時間（0の場合は制限しない）
+	DBTimeout time.Duration
+
+	// 作成できるプロジェクトの最大数（0の場合は無制限）
+	MaxProjects int
+
+	// 保持期間を過ぎたレコードを削除する間隔（0の場合は削除しない）
+	RetentionSweepInterval time.Duration
+
+	// tagsパラメータで指定できるフィルタ用タグの最大数（0の場合は無制限）
+	MaxFilterTags int
+
+	// 一覧APIのlimitの最小値（これより小さいlimitは黙ってこの値に引き上げる。1以下の場合は制限しない）
+	MinPageLimit int
+
+	// プロジェクトの作成・更新時に名前を正規化するか（前後の空白の除去と連続する空白の統合、"/"と制御文字は拒否）
+	NormalizeProjectNames bool
+
+	// プロジェクト名の重複を大文字・小文字（ASCII）を区別せずに判定するか（名前は入力どおりに保存する）
+	CaseInsensitiveProjectNames bool
+
+	// trackで存在しないプロジェクトにアクセスされた場合にプロジェクトを自動作成するか
+	// グラフは認証なしで公開されるため既定は無効
+	AutoCreateProjectsOnTrack bool
+
+	// 署名付きグラフURLのHMACに使用する秘密鍵（空の場合はグラフを認証なしで公開する）
+	// 設定した場合、グラフには有効な署名またはAPIキーが必要になる
+	SigningSecret string
+
+	// レコードの日時として受け付ける最も古い日時（ゼロ値の場合は制限しない）
+	MinTimestamp time.Time
+
+	// レコードの日時として受け付ける現在時刻からの未来方向の許容幅（0の場合は制限しない）
+	MaxTimestampFuture time.Duration
+
+	// SQLiteの接続ごとに設定するPRAGMA synchronousの値（OFF、NORMAL、FULL、EXTRA）
+	SQLiteSynchronous string
+
+	// 同時に処理するリクエストの最大数（0の場合は無制限）
+	// 超過したリクエストは503で拒否する（/healthzは対象外）
+	MaxConcurrentRequests int
+
+	// on_emptyパラメータが指定されない場合の、期間内にレコードがないグラフの応答
+	// （EmptyGraphPlaceholder、EmptyGraphNoContent、EmptyGraphError）
+	EmptyGraphBehavior string
+
+	// namingパラメータが指定されない場合のJSONレスポンスのフィールド名の形式（JSONNamingSnake、JSONNamingCamel）
+	JSONNaming string
 }
 
 // NewConfig は環境変数から設定を読み込み、Configインスタンスを生成します。
@@ -32,6 +157,19 @@ func NewConfig() *Config {
 		port = "8080"
 	}
 
+	// TLSの設定（証明書と秘密鍵は両方指定する）
+	tlsCertFile := os.Getenv("SOUGEN_TLS_CERT_FILE")
+	tlsKeyFile := os.Getenv("SOUGEN_TLS_KEY_FILE")
+	if (tlsCertFile == "") != (tlsKeyFile == "") {
+		panic("SOUGEN_TLS_CERT_FILE and SOUGEN_TLS_KEY_FILE must be set together")
+	}
+
+	// ベースパスの設定（末尾のスラッシュは取り除き、先頭にスラッシュを付ける）
+	basePath := strings.TrimRight(os.Getenv("SOUGEN_BASE_PATH"), "/")
+	if basePath != "" && !strings.HasPrefix(basePath, "/") {
+		basePath = "/" + basePath
+	}
+
 	// API認証キーの設定
 	apiKey := os.Getenv("SOUGEN_API_KEY")
 	if apiKey == "" {
@@ -39,9 +177,242 @@ func NewConfig() *Config {
 		panic("SOUGEN_API_KEY is not set")
 	}
 
+	// 認証ヘッダーの設定
+	authHeader := strings.ToLower(os.Getenv("SOUGEN_AUTH_HEADER"))
+	switch authHeader {
+	case AuthHeaderAny, AuthHeaderAPIKey, AuthHeaderBearer:
+	default:
+		panic("SOUGEN_AUTH_HEADER must be x-api-key or bearer")
+	}
+
+	// Webhook通知の設定
+	webhookURL := os.Getenv("SOUGEN_WEBHOOK_URL")
+
+	webhookBatchSize := 1
+	if v := os.Getenv("SOUGEN_WEBHOOK_BATCH_SIZE"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			panic("SOUGEN_WEBHOOK_BATCH_SIZE must be a positive integer")
+		}
+		webhookBatchSize = n
+	}
+
+	var webhookBatchInterval time.Duration
+	if v := os.Getenv("SOUGEN_WEBHOOK_BATCH_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			panic("SOUGEN_WEBHOOK_BATCH_INTERVAL must be a positive duration (e.g. 5s)")
+		}
+		webhookBatchInterval = d
+	}
+
+	// グラフのデフォルトテーマの設定
+	defaultTheme := os.Getenv("SOUGEN_DEFAULT_THEME")
+	if defaultTheme == "" {
+		defaultTheme = heatmap.DefaultTheme
+	}
+	if _, ok := heatmap.ThemeColors(defaultTheme); !ok {
+		panic("SOUGEN_DEFAULT_THEME must be one of: " + strings.Join(heatmap.ThemeNames(), ", "))
+	}
+
+	// グラフキャッシュの設定
+	graphCacheSize := 0
+	if v := os.Getenv("SOUGEN_GRAPH_CACHE_SIZE"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			panic("SOUGEN_GRAPH_CACHE_SIZE must be a non-negative integer")
+		}
+		graphCacheSize = n
+	}
+
+	graphCacheTTL := time.Minute
+	if v := os.Getenv("SOUGEN_GRAPH_CACHE_TTL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			panic("SOUGEN_GRAPH_CACHE_TTL must be a positive duration (e.g. 1m)")
+		}
+		graphCacheTTL = d
+	}
+
+	// プロキシヘッダーの信頼設定
+	useForwardedFor := false
+	if v := os.Getenv("SOUGEN_USE_FORWARDED_FOR"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			panic("SOUGEN_USE_FORWARDED_FOR must be a boolean")
+		}
+		useForwardedFor = b
+	}
+
+	// データベース処理のタイムアウトの設定
+	dbTimeout := 10 * time.Second
+	if v := os.Getenv("SOUGEN_DB_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			panic("SOUGEN_DB_TIMEOUT must be a non-negative duration (e.g. 10s, 0 disables the timeout)")
+		}
+		dbTimeout = d
+	}
+
+	// プロジェクト数の上限
+	maxProjects := 0
+	if v := os.Getenv("SOUGEN_MAX_PROJECTS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			panic("SOUGEN_MAX_PROJECTS must be a non-negative integer")
+		}
+		maxProjects = n
+	}
+
+	// レコード保持期間のスイープ間隔
+	retentionSweepInterval := time.Hour
+	if v := os.Getenv("SOUGEN_RETENTION_SWEEP_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			panic("SOUGEN_RETENTION_SWEEP_INTERVAL must be a non-negative duration (e.g. 1h, 0 disables the sweeper)")
+		}
+		retentionSweepInterval = d
+	}
+
+	// フィルタ用タグ数の上限
+	maxFilterTags := 50
+	if v := os.Getenv("SOUGEN_MAX_FILTER_TAGS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			panic("SOUGEN_MAX_FILTER_TAGS must be a non-negative integer")
+		}
+		maxFilterTags = n
+	}
+
+	// 一覧APIのlimitの最小値（既定は1、つまり制限なし）
+	minPageLimit := 1
+	if v := os.Getenv("SOUGEN_MIN_PAGE_LIMIT"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			panic("SOUGEN_MIN_PAGE_LIMIT must be a positive integer")
+		}
+		minPageLimit = n
+	}
+
+	// プロジェクト名の正規化の設定
+	normalizeProjectNames := false
+	if v := os.Getenv("SOUGEN_NORMALIZE_PROJECT_NAMES"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			panic("SOUGEN_NORMALIZE_PROJECT_NAMES must be a boolean")
+		}
+		normalizeProjectNames = b
+	}
+	caseInsensitiveProjectNames := false
+	if v := os.Getenv("SOUGEN_CASE_INSENSITIVE_PROJECT_NAMES"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			panic("SOUGEN_CASE_INSENSITIVE_PROJECT_NAMES must be a boolean")
+		}
+		caseInsensitiveProjectNames = b
+	}
+
+	// trackでのプロジェクト自動作成の設定
+	autoCreateProjectsOnTrack := false
+	if v := os.Getenv("SOUGEN_AUTO_CREATE_PROJECTS_ON_TRACK"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			panic("SOUGEN_AUTO_CREATE_PROJECTS_ON_TRACK must be a boolean")
+		}
+		autoCreateProjectsOnTrack = b
+	}
+
+	// 署名付きグラフURLの秘密鍵
+	signingSecret := os.Getenv("SOUGEN_SIGNING_SECRET")
+
+	// レコードの日時の範囲（年の入力ミスなどでグラフの範囲が歪むのを防ぐ）
+	minTimestamp := time.Unix(0, 0).UTC()
+	if v := os.Getenv("SOUGEN_MIN_TIMESTAMP"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			panic("SOUGEN_MIN_TIMESTAMP must be an RFC3339 timestamp (e.g. 1970-01-01T00:00:00Z)")
+		}
+		minTimestamp = t
+	}
+
+	maxTimestampFuture := 24 * time.Hour
+	if v := os.Getenv("SOUGEN_MAX_TIMESTAMP_FUTURE"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			panic("SOUGEN_MAX_TIMESTAMP_FUTURE must be a non-negative duration (e.g. 24h, 0 disables the limit)")
+		}
+		maxTimestampFuture = d
+	}
+
+	// SQLiteの同期モードの設定
+	sqliteSynchronous := strings.ToUpper(os.Getenv("SOUGEN_SQLITE_SYNCHRONOUS"))
+	switch sqliteSynchronous {
+	case "":
+		sqliteSynchronous = "NORMAL"
+	case "OFF", "NORMAL", "FULL", "EXTRA":
+	default:
+		panic("SOUGEN_SQLITE_SYNCHRONOUS must be OFF, NORMAL, FULL or EXTRA")
+	}
+
+	// 同時リクエスト数の上限
+	maxConcurrentRequests := 0
+	if v := os.Getenv("SOUGEN_MAX_CONCURRENT_REQUESTS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			panic("SOUGEN_MAX_CONCURRENT_REQUESTS must be a non-negative integer")
+		}
+		maxConcurrentRequests = n
+	}
+
+	// 空のグラフの応答の設定
+	emptyGraphBehavior := strings.ToLower(os.Getenv("SOUGEN_EMPTY_GRAPH_BEHAVIOR"))
+	if emptyGraphBehavior == "" {
+		emptyGraphBehavior = EmptyGraphPlaceholder
+	}
+	if !IsEmptyGraphBehavior(emptyGraphBehavior) {
+		panic("SOUGEN_EMPTY_GRAPH_BEHAVIOR must be placeholder, no_content or error")
+	}
+
+	// JSONレスポンスのフィールド名の形式の設定
+	jsonNaming := strings.ToLower(os.Getenv("SOUGEN_JSON_NAMING"))
+	if jsonNaming == "" {
+		jsonNaming = JSONNamingSnake
+	}
+	if !IsJSONNaming(jsonNaming) {
+		panic("SOUGEN_JSON_NAMING must be snake or camel")
+	}
+
 	return &Config{
-		DataDir: dataDir,
-		Port:    port,
-		APIKey:  apiKey,
+		DataDir:              dataDir,
+		Port:                 port,
+		TLSCertFile:          tlsCertFile,
+		TLSKeyFile:           tlsKeyFile,
+		BasePath:             basePath,
+		APIKey:               apiKey,
+		AuthHeader:           authHeader,
+		WebhookURL:           webhookURL,
+		WebhookBatchSize:     webhookBatchSize,
+		WebhookBatchInterval: webhookBatchInterval,
+		DefaultTheme:         defaultTheme,
+		GraphCacheSize:       graphCacheSize,
+		GraphCacheTTL:        graphCacheTTL,
+		UseForwardedFor:      useForwardedFor,
+		DBTimeout:            dbTimeout,
+		MaxProjects:          maxProjects,
+
+		RetentionSweepInterval:    retentionSweepInterval,
+		MaxFilterTags:             maxFilterTags,
+		MinPageLimit:              minPageLimit,
+		AutoCreateProjectsOnTrack: autoCreateProjectsOnTrack,
+		SigningSecret:             signingSecret,
+		MinTimestamp:              minTimestamp,
+		MaxTimestampFuture:        maxTimestampFuture,
+		SQLiteSynchronous:         sqliteSynchronous,
+		MaxConcurrentRequests:     maxConcurrentRequests,
+		EmptyGraphBehavior:        emptyGraphBehavior,
+		JSONNaming:                jsonNaming,
+
+		NormalizeProjectNames:       normalizeProjectNames,
+		CaseInsensitiveProjectNames: caseInsensitiveProjectNames,
 	}
 }
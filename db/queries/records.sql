@@ -1,13 +1,16 @@
 -- name: CreateRecord :execresult
-INSERT INTO records (project_id, value, timestamp)
-VALUES (?, ?, ?);
+-- A record whose external_id already exists in the project is skipped (no rows affected)
+INSERT INTO records (project_id, value, timestamp, source, value_float, created_at, external_id)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (project_id, external_id) DO NOTHING;
 
 -- name: CreateRecordTag :exec
 INSERT INTO tags (record_id, tag, order_index)
 VALUES (?, ?, ?);
 
 -- name: GetRecord :one
-SELECT id, project_id, value, timestamp
+-- Includes soft-deleted records; callers check deleted_at
+SELECT id, project_id, value, timestamp, source, value_float, deleted_at, created_at, external_id
 FROM records
 WHERE id = ?;
 
@@ -17,7 +20,19 @@ FROM tags
 WHERE record_id = ?
 ORDER BY order_index;
 
+-- name: RecordExists :one
+SELECT EXISTS (SELECT 1 FROM records WHERE id = ? AND deleted_at IS NULL);
+
 -- name: DeleteRecord :execresult
+-- Soft delete: the record is hidden until restored or purged
+UPDATE records SET deleted_at = ?
+WHERE id = ? AND deleted_at IS NULL;
+
+-- name: RestoreRecord :execresult
+UPDATE records SET deleted_at = NULL
+WHERE id = ?;
+
+-- name: PurgeRecord :execresult
 DELETE FROM records WHERE id = ?;
 
 -- name: ListRecords :many
@@ -29,6 +44,10 @@ SELECT
     r.project_id,
     r.value,
     r.timestamp,
+    r.source,
+    r.value_float,
+    r.created_at,
+    r.external_id,
     COALESCE((
         SELECT GROUP_CONCAT(tag, ' ')
         FROM (
@@ -39,7 +58,9 @@ SELECT
         )
     ), '') as tags
 FROM records r
-WHERE r.timestamp BETWEEN ? AND ? AND r.project_id = ?
+WHERE r.timestamp BETWEEN ? AND ? AND r.project_id = ? AND r.deleted_at IS NULL
+  AND (? = '' OR r.source = ?)
+  AND (? = '' OR r.external_id = ?)
   AND (? IS NULL OR r.timestamp < ? OR (r.timestamp = ? AND r.id > ?))
 ORDER BY r.timestamp DESC, r.id
 LIMIT ?;
@@ -54,6 +75,10 @@ SELECT
     r.project_id,
     r.value,
     r.timestamp,
+    r.source,
+    r.value_float,
+    r.created_at,
+    r.external_id,
     COALESCE((
         SELECT GROUP_CONCAT(tag, ' ')
         FROM (
@@ -65,55 +90,539 @@ SELECT
     ), '') as all_tags
 FROM records r
 INNER JOIN tags t ON r.id = t.record_id
-WHERE r.timestamp BETWEEN ? AND ? AND r.project_id = ?
+WHERE r.timestamp BETWEEN ? AND ? AND r.project_id = ? AND r.deleted_at IS NULL
   AND t.tag IN (sqlc.slice(tags))
+  AND (? = '' OR r.source = ?)
+  AND (? = '' OR r.external_id = ?)
   AND (? IS NULL OR r.timestamp < ? OR (r.timestamp = ? AND r.id > ?))
-GROUP BY r.id, r.project_id, r.value, r.timestamp
+GROUP BY r.id, r.project_id, r.value, r.timestamp, r.source, r.value_float, r.created_at, r.external_id
 HAVING COUNT(DISTINCT t.tag) = CAST(? AS INTEGER)
 ORDER BY r.timestamp DESC, r.id
 LIMIT ?;
 
+-- name: ListRecordsAsc :many
+-- Same as ListRecords but oldest first; the cursor advances towards newer records
+SELECT
+    r.id,
+    r.project_id,
+    r.value,
+    r.timestamp,
+    r.source,
+    r.value_float,
+    r.created_at,
+    r.external_id,
+    COALESCE((
+        SELECT GROUP_CONCAT(tag, ' ')
+        FROM (
+            SELECT tag
+            FROM tags
+            WHERE record_id = r.id
+            ORDER BY order_index
+        )
+    ), '') as tags
+FROM records r
+WHERE r.timestamp BETWEEN ? AND ? AND r.project_id = ? AND r.deleted_at IS NULL
+  AND (? = '' OR r.source = ?)
+  AND (? = '' OR r.external_id = ?)
+  AND (? IS NULL OR r.timestamp > ? OR (r.timestamp = ? AND r.id > ?))
+ORDER BY r.timestamp, r.id
+LIMIT ?;
+
+-- name: ListRecordsWithTagsAsc :many
+-- Same as ListRecordsWithTags but oldest first; the cursor advances towards newer records
+SELECT
+    r.id,
+    r.project_id,
+    r.value,
+    r.timestamp,
+    r.source,
+    r.value_float,
+    r.created_at,
+    r.external_id,
+    COALESCE((
+        SELECT GROUP_CONCAT(tag, ' ')
+        FROM (
+            SELECT tag
+            FROM tags
+            WHERE record_id = r.id
+            ORDER BY order_index
+        )
+    ), '') as all_tags
+FROM records r
+INNER JOIN tags t ON r.id = t.record_id
+WHERE r.timestamp BETWEEN ? AND ? AND r.project_id = ? AND r.deleted_at IS NULL
+  AND t.tag IN (sqlc.slice(tags))
+  AND (? = '' OR r.source = ?)
+  AND (? = '' OR r.external_id = ?)
+  AND (? IS NULL OR r.timestamp > ? OR (r.timestamp = ? AND r.id > ?))
+GROUP BY r.id, r.project_id, r.value, r.timestamp, r.source, r.value_float, r.created_at, r.external_id
+HAVING COUNT(DISTINCT t.tag) = CAST(? AS INTEGER)
+ORDER BY r.timestamp, r.id
+LIMIT ?;
+
+-- name: ListRecordsByCreatedAt :many
+-- Same as ListRecords but filters and orders by created_at (when the record was logged)
+SELECT
+    r.id,
+    r.project_id,
+    r.value,
+    r.timestamp,
+    r.source,
+    r.value_float,
+    r.created_at,
+    r.external_id,
+    COALESCE((
+        SELECT GROUP_CONCAT(tag, ' ')
+        FROM (
+            SELECT tag
+            FROM tags
+            WHERE record_id = r.id
+            ORDER BY order_index
+        )
+    ), '') as tags
+FROM records r
+WHERE r.created_at BETWEEN ? AND ? AND r.project_id = ? AND r.deleted_at IS NULL
+  AND (? = '' OR r.source = ?)
+  AND (? = '' OR r.external_id = ?)
+  AND (? IS NULL OR r.created_at < ? OR (r.created_at = ? AND r.id > ?))
+ORDER BY r.created_at DESC, r.id
+LIMIT ?;
+
+-- name: ListRecordsWithTagsByCreatedAt :many
+-- Same as ListRecordsWithTags but filters and orders by created_at
+SELECT
+    r.id,
+    r.project_id,
+    r.value,
+    r.timestamp,
+    r.source,
+    r.value_float,
+    r.created_at,
+    r.external_id,
+    COALESCE((
+        SELECT GROUP_CONCAT(tag, ' ')
+        FROM (
+            SELECT tag
+            FROM tags
+            WHERE record_id = r.id
+            ORDER BY order_index
+        )
+    ), '') as all_tags
+FROM records r
+INNER JOIN tags t ON r.id = t.record_id
+WHERE r.created_at BETWEEN ? AND ? AND r.project_id = ? AND r.deleted_at IS NULL
+  AND t.tag IN (sqlc.slice(tags))
+  AND (? = '' OR r.source = ?)
+  AND (? = '' OR r.external_id = ?)
+  AND (? IS NULL OR r.created_at < ? OR (r.created_at = ? AND r.id > ?))
+GROUP BY r.id, r.project_id, r.value, r.timestamp, r.source, r.value_float, r.created_at, r.external_id
+HAVING COUNT(DISTINCT t.tag) = CAST(? AS INTEGER)
+ORDER BY r.created_at DESC, r.id
+LIMIT ?;
+
+-- name: ListRecordsByCreatedAtAsc :many
+-- Same as ListRecordsByCreatedAt but oldest first
+SELECT
+    r.id,
+    r.project_id,
+    r.value,
+    r.timestamp,
+    r.source,
+    r.value_float,
+    r.created_at,
+    r.external_id,
+    COALESCE((
+        SELECT GROUP_CONCAT(tag, ' ')
+        FROM (
+            SELECT tag
+            FROM tags
+            WHERE record_id = r.id
+            ORDER BY order_index
+        )
+    ), '') as tags
+FROM records r
+WHERE r.created_at BETWEEN ? AND ? AND r.project_id = ? AND r.deleted_at IS NULL
+  AND (? = '' OR r.source = ?)
+  AND (? = '' OR r.external_id = ?)
+  AND (? IS NULL OR r.created_at > ? OR (r.created_at = ? AND r.id > ?))
+ORDER BY r.created_at, r.id
+LIMIT ?;
+
+-- name: ListRecordsWithTagsByCreatedAtAsc :many
+-- Same as ListRecordsWithTagsByCreatedAt but oldest first
+SELECT
+    r.id,
+    r.project_id,
+    r.value,
+    r.timestamp,
+    r.source,
+    r.value_float,
+    r.created_at,
+    r.external_id,
+    COALESCE((
+        SELECT GROUP_CONCAT(tag, ' ')
+        FROM (
+            SELECT tag
+            FROM tags
+            WHERE record_id = r.id
+            ORDER BY order_index
+        )
+    ), '') as all_tags
+FROM records r
+INNER JOIN tags t ON r.id = t.record_id
+WHERE r.created_at BETWEEN ? AND ? AND r.project_id = ? AND r.deleted_at IS NULL
+  AND t.tag IN (sqlc.slice(tags))
+  AND (? = '' OR r.source = ?)
+  AND (? = '' OR r.external_id = ?)
+  AND (? IS NULL OR r.created_at > ? OR (r.created_at = ? AND r.id > ?))
+GROUP BY r.id, r.project_id, r.value, r.timestamp, r.source, r.value_float, r.created_at, r.external_id
+HAVING COUNT(DISTINCT t.tag) = CAST(? AS INTEGER)
+ORDER BY r.created_at, r.id
+LIMIT ?;
 
 -- name: DeleteRecordsUntil :execresult
 DELETE FROM records WHERE timestamp < ?;
 
 -- name: UpdateRecord :execresult
-UPDATE records SET project_id = ?, value = ?, timestamp = ?
-WHERE id = ?;
+UPDATE records SET project_id = ?, value = ?, timestamp = ?, value_float = ?
+WHERE id = ? AND deleted_at IS NULL;
+
+-- name: IncrementRecordValue :one
+-- The result must stay >= 1 and within the optional bounds (min_value/max_value of the project)
+UPDATE records SET value = value + sqlc.arg(delta)
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
+  AND value + sqlc.arg(delta) >= 1
+  AND (sqlc.narg(min_value) IS NULL OR value + sqlc.arg(delta) >= CAST(sqlc.narg(min_value) AS REAL))
+  AND (sqlc.narg(max_value) IS NULL OR value + sqlc.arg(delta) <= CAST(sqlc.narg(max_value) AS REAL))
+RETURNING value;
+
+-- name: UpdateRecordWithIncrement :one
+-- Same as IncrementRecordValue, also updating the other columns in the same statement
+UPDATE records SET project_id = sqlc.arg(project_id), timestamp = sqlc.arg(timestamp), value = value + sqlc.arg(delta)
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
+  AND value + sqlc.arg(delta) >= 1
+  AND (sqlc.narg(min_value) IS NULL OR value + sqlc.arg(delta) >= CAST(sqlc.narg(min_value) AS REAL))
+  AND (sqlc.narg(max_value) IS NULL OR value + sqlc.arg(delta) <= CAST(sqlc.narg(max_value) AS REAL))
+RETURNING value;
+
+-- name: FindDailyRecord :one
+-- The record that a daily upsert adds to: the oldest one of the project and source in the day
+SELECT id
+FROM records
+WHERE timestamp BETWEEN sqlc.arg(day_start) AND sqlc.arg(day_end)
+  AND project_id = sqlc.arg(project_id) AND source = sqlc.arg(source) AND deleted_at IS NULL
+ORDER BY timestamp, id
+LIMIT 1;
 
 -- name: DeleteRecordTags :exec
 DELETE FROM tags WHERE record_id = ?;
 
+-- name: DeleteOrphanTags :execresult
+DELETE FROM tags WHERE record_id NOT IN (SELECT id FROM records);
+
 -- name: DeleteRecordsUntilByProject :execresult
 DELETE FROM records WHERE project_id = ? AND timestamp < ?;
 
 -- name: CreateProject :execresult
-INSERT INTO projects (name, description, created_at, updated_at)
-VALUES (?, ?, ?, ?);
+INSERT INTO projects (name, description, created_at, updated_at, track_default_value, color, value_type, retention_days, min_value, max_value, timezone)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: CreateProjectWithID :execresult
+INSERT INTO projects (id, name, description, created_at, updated_at, track_default_value, color, value_type, retention_days, min_value, max_value, timezone)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
 
 -- name: GetProject :one
-SELECT id, name, description, created_at, updated_at
+SELECT id, name, description, created_at, updated_at, track_default_value, color, value_type, retention_days, min_value, max_value, timezone, changed_at
+FROM projects
+WHERE id = ?;
+
+-- name: GetProjectLastModified :one
+-- The later of the project's own update and the last change to its records, tags or tag aliases
+SELECT CAST(MAX(updated_at, changed_at) AS TEXT) AS last_modified
 FROM projects
 WHERE id = ?;
 
 -- name: UpdateProject :execresult
-UPDATE projects SET name = ?, description = ?, updated_at = ?
+UPDATE projects SET name = ?, description = ?, updated_at = ?, track_default_value = ?, color = ?, value_type = ?, retention_days = ?, min_value = ?, max_value = ?, timezone = ?
 WHERE id = ?;
 
--- name: DeleteProject :exec
+-- name: DeleteProject :execresult
 DELETE FROM projects WHERE id = ?;
 
+-- name: CountProjectRecords :one
+-- Includes soft-deleted records, which the cascade removes as well
+SELECT COUNT(*) FROM records WHERE project_id = ?;
+
 -- name: ListProjects :many
 -- Cursor-based pagination: uses cursor_updated_at and cursor_name for pagination
-SELECT id, name, description, created_at, updated_at
+SELECT id, name, description, created_at, updated_at, track_default_value, color, value_type, retention_days, min_value, max_value, timezone, changed_at
 FROM projects
 WHERE ? IS NULL OR updated_at < ? OR (updated_at = ? AND name > ?)
 ORDER BY updated_at DESC, name
 LIMIT ?;
 
+-- name: ListProjectsByName :many
+-- Cursor-based pagination: uses cursor_name for pagination
+SELECT id, name, description, created_at, updated_at, track_default_value, color, value_type, retention_days, min_value, max_value, timezone, changed_at
+FROM projects
+WHERE sqlc.narg(cursor_name) IS NULL OR name > sqlc.narg(cursor_name)
+ORDER BY name
+LIMIT sqlc.arg(limit);
+
+-- name: ListProjectsByCreatedAt :many
+-- Cursor-based pagination: uses cursor_created_at and cursor_name for pagination
+SELECT id, name, description, created_at, updated_at, track_default_value, color, value_type, retention_days, min_value, max_value, timezone, changed_at
+FROM projects
+WHERE sqlc.narg(cursor_created_at) IS NULL
+    OR created_at < sqlc.narg(cursor_created_at)
+    OR (created_at = sqlc.narg(cursor_created_at) AND name > sqlc.arg(cursor_name))
+ORDER BY created_at DESC, name
+LIMIT sqlc.arg(limit);
+
+-- name: ListProjectsByRecordCount :many
+-- Cursor-based pagination: uses cursor_record_count and cursor_name for pagination
+WITH counted AS (
+    SELECT p.id, p.name, p.description, p.created_at, p.updated_at, p.track_default_value, p.color, p.value_type, p.retention_days, p.min_value, p.max_value, p.timezone,
+        COUNT(r.id) AS record_count
+    FROM projects p
+    LEFT JOIN records r ON r.project_id = p.id AND r.deleted_at IS NULL
+    GROUP BY p.id
+)
+SELECT id, name, description, created_at, updated_at, track_default_value, color, value_type, retention_days, min_value, max_value, timezone, record_count
+FROM counted
+WHERE sqlc.narg(cursor_record_count) IS NULL
+    OR record_count < sqlc.narg(cursor_record_count)
+    OR (record_count = sqlc.narg(cursor_record_count) AND name > sqlc.arg(cursor_name))
+ORDER BY record_count DESC, name
+LIMIT sqlc.arg(limit);
+
+-- name: ProjectExists :one
+SELECT EXISTS (SELECT 1 FROM projects WHERE id = ?);
+
+-- name: CountProjects :one
+SELECT COUNT(*) FROM projects;
+
+-- name: ListProjectRetentions :many
+SELECT id, retention_days
+FROM projects
+WHERE retention_days IS NOT NULL
+ORDER BY id;
+
 -- name: GetProjectTags :many
 SELECT DISTINCT tag
 FROM tags t
 JOIN records r ON t.record_id = r.id
-WHERE r.project_id = ?
-ORDER BY tag;
+WHERE r.project_id = ? AND r.deleted_at IS NULL AND (? IS NULL OR tag > ?)
+ORDER BY tag
+LIMIT ?;
+
+-- name: GetProjectSummary :one
+SELECT
+    COUNT(*) AS record_count,
+    CAST(COALESCE(SUM(value), 0) AS INTEGER) AS total_value,
+    CAST(COALESCE(MIN(timestamp), '') AS TEXT) AS first_record_at,
+    CAST(COALESCE(MAX(timestamp), '') AS TEXT) AS last_record_at
+FROM records
+WHERE project_id = ? AND deleted_at IS NULL;
+
+-- name: ListProjectRecordIDs :many
+SELECT id FROM records WHERE project_id = ? AND deleted_at IS NULL ORDER BY id;
+
+-- name: CopyRecord :execresult
+INSERT INTO records (project_id, value, timestamp, source, value_float, created_at)
+SELECT sqlc.arg(project_id), r.value, r.timestamp, r.source, r.value_float, r.created_at
+FROM records r
+WHERE r.id = sqlc.arg(source_id);
+
+-- name: CopyRecordTags :exec
+INSERT INTO tags (record_id, tag, order_index)
+SELECT sqlc.arg(record_id), t.tag, t.order_index
+FROM tags t
+WHERE t.record_id = sqlc.arg(source_record_id);
+
+-- name: GetTagBreakdown :many
+-- Note: BETWEEN clause must come first due to sqlc bug with SQLite parameter handling
+-- A record with multiple tags contributes to each of its tags
+SELECT
+    t.tag,
+    CAST(COALESCE(SUM(COALESCE(r.value_float, r.value)), 0) AS REAL) AS total_value,
+    COUNT(DISTINCT r.id) AS record_count
+FROM records r
+INNER JOIN tags t ON r.id = t.record_id
+WHERE r.timestamp BETWEEN ? AND ? AND r.project_id = ? AND r.deleted_at IS NULL
+GROUP BY t.tag
+ORDER BY total_value DESC, t.tag;
+
+-- name: GetValueHistogram :many
+-- Note: BETWEEN clause must come first due to sqlc bug with SQLite parameter handling
+-- Float projects keep the fractional value, so each distinct value is its own bucket
+SELECT
+    CAST(COALESCE(r.value_float, r.value) AS REAL) AS value,
+    COUNT(*) AS record_count
+FROM records r
+WHERE r.timestamp BETWEEN ? AND ? AND r.project_id = ? AND r.deleted_at IS NULL
+GROUP BY COALESCE(r.value_float, r.value)
+ORDER BY value;
+
+-- name: GetValueHistogramWithTags :many
+-- Note: BETWEEN clause must come first due to sqlc bug with SQLite parameter handling
+-- Counts only records that have all of the specified tags
+SELECT
+    CAST(COALESCE(r.value_float, r.value) AS REAL) AS value,
+    COUNT(*) AS record_count
+FROM records r
+WHERE r.timestamp BETWEEN ? AND ? AND r.project_id = ? AND r.deleted_at IS NULL
+  AND r.id IN (
+    SELECT record_id
+    FROM tags
+    WHERE tag IN (sqlc.slice(tags))
+    GROUP BY record_id
+    HAVING COUNT(DISTINCT tag) = CAST(? AS INTEGER)
+  )
+GROUP BY COALESCE(r.value_float, r.value)
+ORDER BY value;
+
+-- name: ListTagAliases :many
+SELECT alias, tag
+FROM tag_aliases
+WHERE project_id = ?
+ORDER BY alias;
+
+-- name: UpsertTagAlias :exec
+INSERT INTO tag_aliases (project_id, alias, tag)
+VALUES (?, ?, ?)
+ON CONFLICT (project_id, alias) DO UPDATE SET tag = excluded.tag;
+
+-- name: DeleteTagAlias :execresult
+DELETE FROM tag_aliases
+WHERE project_id = ? AND alias = ?;
+
+-- name: AddTagToRecords :execresult
+-- Note: BETWEEN clause must come first due to sqlc bug with SQLite parameter handling
+-- INSERT OR IGNORE skips records that already have the tag (PRIMARY KEY (record_id, tag));
+-- the tag is appended after the record's existing tags
+INSERT OR IGNORE INTO tags (record_id, tag, order_index)
+SELECT
+    r.id,
+    sqlc.arg(tag),
+    COALESCE((SELECT MAX(order_index) FROM tags WHERE record_id = r.id), -1) + 1
+FROM records r
+WHERE r.timestamp BETWEEN sqlc.arg(from_timestamp) AND sqlc.arg(to_timestamp) AND r.project_id = sqlc.arg(project_id) AND r.deleted_at IS NULL;
+
+-- name: AddTagToRecordsWithTags :execresult
+-- Note: BETWEEN clause must come first due to sqlc bug with SQLite parameter handling
+-- Only records that have all of the filter tags get the tag
+-- Note: positional parameters are used because sqlc numbers named ones, which breaks sqlc.slice
+INSERT OR IGNORE INTO tags (record_id, tag, order_index)
+SELECT
+    r.id,
+    ?,
+    COALESCE((SELECT MAX(order_index) FROM tags WHERE record_id = r.id), -1) + 1
+FROM records r
+WHERE r.timestamp BETWEEN ? AND ? AND r.project_id = ? AND r.deleted_at IS NULL
+  AND r.id IN (
+    SELECT record_id
+    FROM tags
+    WHERE tags.tag IN (sqlc.slice(filter_tags))
+    GROUP BY record_id
+    HAVING COUNT(DISTINCT tags.tag) = CAST(? AS INTEGER)
+  );
+
+-- name: ListRecordTimestamps :many
+-- Note: BETWEEN clause must come first due to sqlc bug with SQLite parameter handling
+SELECT id, timestamp
+FROM records
+WHERE timestamp BETWEEN ? AND ? AND project_id = ? AND deleted_at IS NULL
+ORDER BY timestamp, id;
+
+-- name: CopyRecordToTimestamp :execresult
+INSERT INTO records (project_id, value, timestamp, source, value_float, created_at)
+SELECT r.project_id, r.value, sqlc.arg(timestamp), r.source, r.value_float, sqlc.arg(created_at)
+FROM records r
+WHERE r.id = sqlc.arg(source_id);
+
+-- name: ListRecordsForDay :many
+-- All records of a project in the half-open range [day_start, day_end) oldest first,
+-- served by idx_records_project_id_timestamp
+SELECT
+    r.id,
+    r.project_id,
+    r.value,
+    r.timestamp,
+    r.source,
+    r.value_float,
+    r.created_at,
+    r.external_id,
+    COALESCE((
+        SELECT GROUP_CONCAT(tag, ' ')
+        FROM (
+            SELECT tag
+            FROM tags
+            WHERE record_id = r.id
+            ORDER BY order_index
+        )
+    ), '') as tags
+FROM records r
+WHERE r.project_id = ? AND r.timestamp >= ? AND r.timestamp < ? AND r.deleted_at IS NULL
+ORDER BY r.timestamp, r.id;
+
+-- name: ListRecordsForDayWithTags :many
+-- Same as ListRecordsForDay but only records that have all of the specified tags
+SELECT
+    r.id,
+    r.project_id,
+    r.value,
+    r.timestamp,
+    r.source,
+    r.value_float,
+    r.created_at,
+    r.external_id,
+    COALESCE((
+        SELECT GROUP_CONCAT(tag, ' ')
+        FROM (
+            SELECT tag
+            FROM tags
+            WHERE record_id = r.id
+            ORDER BY order_index
+        )
+    ), '') as tags
+FROM records r
+INNER JOIN tags t ON r.id = t.record_id
+WHERE r.project_id = ? AND r.timestamp >= ? AND r.timestamp < ? AND r.deleted_at IS NULL
+  AND t.tag IN (sqlc.slice(tags))
+GROUP BY r.id, r.project_id, r.value, r.timestamp, r.source, r.value_float, r.created_at, r.external_id
+HAVING COUNT(DISTINCT t.tag) = CAST(? AS INTEGER)
+ORDER BY r.timestamp, r.id;
+
+-- name: ListRecentRecords :many
+-- The newest records of all projects (recent activity feed), served by idx_records_timestamp
+SELECT
+    r.id,
+    r.project_id,
+    r.value,
+    r.timestamp,
+    r.source,
+    r.value_float,
+    r.created_at,
+    r.external_id,
+    COALESCE((
+        SELECT GROUP_CONCAT(tag, ' ')
+        FROM (
+            SELECT tag
+            FROM tags
+            WHERE record_id = r.id
+            ORDER BY order_index
+        )
+    ), '') as tags
+FROM records r
+WHERE r.deleted_at IS NULL
+ORDER BY r.timestamp DESC, r.id DESC
+LIMIT ?;
+
+-- name: ProjectNameTakenIgnoringCase :one
+-- Whether another project has the same name ignoring ASCII case (case-insensitive project names)
+SELECT EXISTS(SELECT 1 FROM projects WHERE name = ? COLLATE NOCASE AND id != ?);
+
+-- name: GetRecordIDByExternalID :one
+-- Includes soft-deleted records, which still hold their external_id
+SELECT id FROM records WHERE project_id = ? AND external_id = ?;
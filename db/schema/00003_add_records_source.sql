@@ -0,0 +1,11 @@
+-- +goose Up
+-- Add source column to records table to distinguish how each record was written
+-- (e.g. "api" for the REST API, "track" for the graph access counter)
+ALTER TABLE records ADD COLUMN source TEXT NOT NULL DEFAULT 'api';
+
+CREATE INDEX idx_records_project_id_source ON records(project_id, source);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_records_project_id_source;
+
+ALTER TABLE records DROP COLUMN source;
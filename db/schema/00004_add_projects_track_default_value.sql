@@ -0,0 +1,7 @@
+-- +goose Up
+-- Add track_default_value column to projects table
+-- Used as the record value for graph access counter (track) when no value is given (NULL means 1)
+ALTER TABLE projects ADD COLUMN track_default_value INTEGER;
+
+-- +goose Down
+ALTER TABLE projects DROP COLUMN track_default_value;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Add color column to projects table
+-- Hex color (#RGB or #RRGGBB) used by dashboards to color-code projects (NULL means unset)
+ALTER TABLE projects ADD COLUMN color TEXT;
+
+-- +goose Down
+ALTER TABLE projects DROP COLUMN color;
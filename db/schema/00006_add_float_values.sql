@@ -0,0 +1,12 @@
+-- +goose Up
+-- Add value_type column to projects table
+-- 'int' (default) keeps integer values; 'float' allows fractional values stored in records.value_float
+ALTER TABLE projects ADD COLUMN value_type TEXT NOT NULL DEFAULT 'int';
+
+-- Add value_float column to records table
+-- Fractional value for records of float projects (NULL means the integer value column is used)
+ALTER TABLE records ADD COLUMN value_float REAL;
+
+-- +goose Down
+ALTER TABLE records DROP COLUMN value_float;
+ALTER TABLE projects DROP COLUMN value_type;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Add deleted_at column to records table for soft deletion
+-- NULL means the record is active; soft-deleted records can be restored or purged
+ALTER TABLE records ADD COLUMN deleted_at TEXT;
+
+-- +goose Down
+ALTER TABLE records DROP COLUMN deleted_at;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Add retention_days column to projects table
+-- Records older than this many days are deleted by the retention sweeper (NULL means keep forever)
+ALTER TABLE projects ADD COLUMN retention_days INTEGER;
+
+-- +goose Down
+ALTER TABLE projects DROP COLUMN retention_days;
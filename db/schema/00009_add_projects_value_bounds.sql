@@ -0,0 +1,9 @@
+-- +goose Up
+-- Add min_value/max_value columns to projects table
+-- Record values outside these bounds are rejected (NULL means unlimited)
+ALTER TABLE projects ADD COLUMN min_value REAL;
+ALTER TABLE projects ADD COLUMN max_value REAL;
+
+-- +goose Down
+ALTER TABLE projects DROP COLUMN max_value;
+ALTER TABLE projects DROP COLUMN min_value;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- Tag aliases per project: tags written as an alias are stored as the canonical tag
+CREATE TABLE tag_aliases (
+	project_id INTEGER NOT NULL,
+	alias TEXT NOT NULL,
+	tag TEXT NOT NULL,
+	PRIMARY KEY (project_id, alias),
+	FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS tag_aliases;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Add timezone column to projects table
+-- IANA time zone name used for day bucketing of the project's graphs (NULL means the server's local time zone)
+ALTER TABLE projects ADD COLUMN timezone TEXT;
+
+-- +goose Down
+ALTER TABLE projects DROP COLUMN timezone;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- Add created_at column to records table
+-- When the record was logged, as opposed to timestamp (when the activity happened)
+-- Existing records have no logging time, so they are backfilled with their timestamp
+ALTER TABLE records ADD COLUMN created_at TEXT NOT NULL DEFAULT '';
+UPDATE records SET created_at = timestamp;
+CREATE INDEX idx_records_project_id_created_at ON records(project_id, created_at);
+
+-- +goose Down
+DROP INDEX idx_records_project_id_created_at;
+ALTER TABLE records DROP COLUMN created_at;
@@ -0,0 +1,6 @@
+-- +goose Up
+-- Index for the cross-project recent activity feed (newest records of all projects)
+CREATE INDEX idx_records_timestamp ON records(timestamp);
+
+-- +goose Down
+DROP INDEX idx_records_timestamp;
@@ -0,0 +1,10 @@
+-- +goose Up
+-- Add external_id column to records table
+-- The ID of the record in the external system it was imported from (NULL for records created here)
+-- Unique per project so that re-importing the same record is a no-op; NULLs never conflict
+ALTER TABLE records ADD COLUMN external_id TEXT;
+CREATE UNIQUE INDEX idx_records_project_id_external_id ON records(project_id, external_id);
+
+-- +goose Down
+DROP INDEX idx_records_project_id_external_id;
+ALTER TABLE records DROP COLUMN external_id;
@@ -0,0 +1,18 @@
+-- +goose Up
+-- Store every date-time column in UTC (RFC3339 with "Z")
+-- Columns are compared and sorted as strings, so values written with other offsets
+-- (e.g. time.Now() in the server timezone) fell outside day ranges and sorted out of order
+-- strftime converts an offset suffix to UTC; values it cannot parse are left unchanged
+UPDATE records SET timestamp = strftime('%Y-%m-%dT%H:%M:%SZ', timestamp)
+WHERE strftime('%Y-%m-%dT%H:%M:%SZ', timestamp) IS NOT NULL;
+UPDATE records SET created_at = strftime('%Y-%m-%dT%H:%M:%SZ', created_at)
+WHERE strftime('%Y-%m-%dT%H:%M:%SZ', created_at) IS NOT NULL;
+UPDATE records SET deleted_at = strftime('%Y-%m-%dT%H:%M:%SZ', deleted_at)
+WHERE strftime('%Y-%m-%dT%H:%M:%SZ', deleted_at) IS NOT NULL;
+UPDATE projects SET created_at = strftime('%Y-%m-%dT%H:%M:%SZ', created_at)
+WHERE strftime('%Y-%m-%dT%H:%M:%SZ', created_at) IS NOT NULL;
+UPDATE projects SET updated_at = strftime('%Y-%m-%dT%H:%M:%SZ', updated_at)
+WHERE strftime('%Y-%m-%dT%H:%M:%SZ', updated_at) IS NOT NULL;
+
+-- +goose Down
+-- The original offsets are not kept; UTC values remain valid RFC3339
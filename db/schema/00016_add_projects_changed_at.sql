@@ -0,0 +1,85 @@
+-- +goose Up
+-- Add changed_at column to projects table
+-- When the project's records, their tags or the project's tag aliases last changed (UTC, RFC3339)
+-- Used with updated_at as the Last-Modified of graphs; unlike MAX(records.timestamp) it also moves
+-- forward on deletes, purges, back-dated inserts, edits and alias changes
+ALTER TABLE projects ADD COLUMN changed_at TEXT NOT NULL DEFAULT '';
+
+-- Existing projects are backfilled with their latest logging or deletion time, capped at the present
+UPDATE projects SET changed_at = MIN(
+    MAX(
+        COALESCE((SELECT MAX(created_at) FROM records WHERE project_id = projects.id), ''),
+        COALESCE((SELECT MAX(deleted_at) FROM records WHERE project_id = projects.id), '')
+    ),
+    strftime('%Y-%m-%dT%H:%M:%SZ', 'now')
+);
+
+-- Every write goes through these triggers, including cascades and bulk statements
+-- +goose StatementBegin
+CREATE TRIGGER trg_records_insert_changed_at AFTER INSERT ON records
+BEGIN
+    UPDATE projects SET changed_at = strftime('%Y-%m-%dT%H:%M:%SZ', 'now') WHERE id = NEW.project_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER trg_records_update_changed_at AFTER UPDATE ON records
+BEGIN
+    UPDATE projects SET changed_at = strftime('%Y-%m-%dT%H:%M:%SZ', 'now') WHERE id IN (OLD.project_id, NEW.project_id);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER trg_records_delete_changed_at AFTER DELETE ON records
+BEGIN
+    UPDATE projects SET changed_at = strftime('%Y-%m-%dT%H:%M:%SZ', 'now') WHERE id = OLD.project_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER trg_tags_insert_changed_at AFTER INSERT ON tags
+BEGIN
+    UPDATE projects SET changed_at = strftime('%Y-%m-%dT%H:%M:%SZ', 'now')
+    WHERE id = (SELECT project_id FROM records WHERE id = NEW.record_id);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER trg_tags_delete_changed_at AFTER DELETE ON tags
+BEGIN
+    UPDATE projects SET changed_at = strftime('%Y-%m-%dT%H:%M:%SZ', 'now')
+    WHERE id = (SELECT project_id FROM records WHERE id = OLD.record_id);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER trg_tag_aliases_insert_changed_at AFTER INSERT ON tag_aliases
+BEGIN
+    UPDATE projects SET changed_at = strftime('%Y-%m-%dT%H:%M:%SZ', 'now') WHERE id = NEW.project_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER trg_tag_aliases_update_changed_at AFTER UPDATE ON tag_aliases
+BEGIN
+    UPDATE projects SET changed_at = strftime('%Y-%m-%dT%H:%M:%SZ', 'now') WHERE id = NEW.project_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER trg_tag_aliases_delete_changed_at AFTER DELETE ON tag_aliases
+BEGIN
+    UPDATE projects SET changed_at = strftime('%Y-%m-%dT%H:%M:%SZ', 'now') WHERE id = OLD.project_id;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER IF EXISTS trg_tag_aliases_delete_changed_at;
+DROP TRIGGER IF EXISTS trg_tag_aliases_update_changed_at;
+DROP TRIGGER IF EXISTS trg_tag_aliases_insert_changed_at;
+DROP TRIGGER IF EXISTS trg_tags_delete_changed_at;
+DROP TRIGGER IF EXISTS trg_tags_insert_changed_at;
+DROP TRIGGER IF EXISTS trg_records_delete_changed_at;
+DROP TRIGGER IF EXISTS trg_records_update_changed_at;
+DROP TRIGGER IF EXISTS trg_records_insert_changed_at;
+ALTER TABLE projects DROP COLUMN changed_at;
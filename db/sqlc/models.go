@@ -4,19 +4,36 @@
 
 package sqlc
 
+import (
+	"database/sql"
+)
+
 type Project struct {
-	ID          int64  `db:"id" json:"id"`
-	Name        string `db:"name" json:"name"`
-	Description string `db:"description" json:"description"`
-	CreatedAt   string `db:"created_at" json:"created_at"`
-	UpdatedAt   string `db:"updated_at" json:"updated_at"`
+	ID                int64           `db:"id" json:"id"`
+	Name              string          `db:"name" json:"name"`
+	Description       string          `db:"description" json:"description"`
+	CreatedAt         string          `db:"created_at" json:"created_at"`
+	UpdatedAt         string          `db:"updated_at" json:"updated_at"`
+	TrackDefaultValue sql.NullInt64   `db:"track_default_value" json:"track_default_value"`
+	Color             sql.NullString  `db:"color" json:"color"`
+	ValueType         string          `db:"value_type" json:"value_type"`
+	RetentionDays     sql.NullInt64   `db:"retention_days" json:"retention_days"`
+	MinValue          sql.NullFloat64 `db:"min_value" json:"min_value"`
+	MaxValue          sql.NullFloat64 `db:"max_value" json:"max_value"`
+	Timezone          sql.NullString  `db:"timezone" json:"timezone"`
+	ChangedAt         string          `db:"changed_at" json:"changed_at"`
 }
 
 type Record struct {
-	ID        int64  `db:"id" json:"id"`
-	ProjectID int64  `db:"project_id" json:"project_id"`
-	Value     int64  `db:"value" json:"value"`
-	Timestamp string `db:"timestamp" json:"timestamp"`
+	ID         int64           `db:"id" json:"id"`
+	ProjectID  int64           `db:"project_id" json:"project_id"`
+	Value      int64           `db:"value" json:"value"`
+	Timestamp  string          `db:"timestamp" json:"timestamp"`
+	Source     string          `db:"source" json:"source"`
+	ValueFloat sql.NullFloat64 `db:"value_float" json:"value_float"`
+	DeletedAt  sql.NullString  `db:"deleted_at" json:"deleted_at"`
+	CreatedAt  string          `db:"created_at" json:"created_at"`
+	ExternalID sql.NullString  `db:"external_id" json:"external_id"`
 }
 
 type Tag struct {
@@ -24,3 +41,9 @@ type Tag struct {
 	Tag        string `db:"tag" json:"tag"`
 	OrderIndex int64  `db:"order_index" json:"order_index"`
 }
+
+type TagAlias struct {
+	ProjectID int64  `db:"project_id" json:"project_id"`
+	Alias     string `db:"alias" json:"alias"`
+	Tag       string `db:"tag" json:"tag"`
+}
@@ -10,31 +10,108 @@ import (
 )
 
 type Querier interface {
+	// Note: BETWEEN clause must come first due to sqlc bug with SQLite parameter handling
+	// INSERT OR IGNORE skips records that already have the tag (PRIMARY KEY (record_id, tag));
+	// the tag is appended after the record's existing tags
+	AddTagToRecords(ctx context.Context, arg AddTagToRecordsParams) (sql.Result, error)
+	// Note: BETWEEN clause must come first due to sqlc bug with SQLite parameter handling
+	// Only records that have all of the filter tags get the tag
+	// Note: positional parameters are used because sqlc numbers named ones, which breaks sqlc.slice
+	AddTagToRecordsWithTags(ctx context.Context, arg AddTagToRecordsWithTagsParams) (sql.Result, error)
+	CopyRecord(ctx context.Context, arg CopyRecordParams) (sql.Result, error)
+	CopyRecordTags(ctx context.Context, arg CopyRecordTagsParams) error
+	CopyRecordToTimestamp(ctx context.Context, arg CopyRecordToTimestampParams) (sql.Result, error)
+	// Includes soft-deleted records, which the cascade removes as well
+	CountProjectRecords(ctx context.Context, projectID int64) (int64, error)
+	CountProjects(ctx context.Context) (int64, error)
 	CreateProject(ctx context.Context, arg CreateProjectParams) (sql.Result, error)
+	CreateProjectWithID(ctx context.Context, arg CreateProjectWithIDParams) (sql.Result, error)
+	// A record whose external_id already exists in the project is skipped (no rows affected)
 	CreateRecord(ctx context.Context, arg CreateRecordParams) (sql.Result, error)
 	CreateRecordTag(ctx context.Context, arg CreateRecordTagParams) error
-	DeleteProject(ctx context.Context, id int64) error
-	DeleteRecord(ctx context.Context, id int64) (sql.Result, error)
+	DeleteOrphanTags(ctx context.Context) (sql.Result, error)
+	DeleteProject(ctx context.Context, id int64) (sql.Result, error)
+	// Soft delete: the record is hidden until restored or purged
+	DeleteRecord(ctx context.Context, arg DeleteRecordParams) (sql.Result, error)
 	DeleteRecordTags(ctx context.Context, recordID int64) error
 	DeleteRecordsUntil(ctx context.Context, timestamp string) (sql.Result, error)
 	DeleteRecordsUntilByProject(ctx context.Context, arg DeleteRecordsUntilByProjectParams) (sql.Result, error)
+	DeleteTagAlias(ctx context.Context, arg DeleteTagAliasParams) (sql.Result, error)
+	// The record that a daily upsert adds to: the oldest one of the project and source in the day
+	FindDailyRecord(ctx context.Context, arg FindDailyRecordParams) (int64, error)
 	GetProject(ctx context.Context, id int64) (Project, error)
-	GetProjectTags(ctx context.Context, projectID int64) ([]string, error)
+	// The later of the project's own update and the last change to its records, tags or tag aliases
+	GetProjectLastModified(ctx context.Context, id int64) (string, error)
+	GetProjectSummary(ctx context.Context, projectID int64) (GetProjectSummaryRow, error)
+	GetProjectTags(ctx context.Context, arg GetProjectTagsParams) ([]string, error)
+	// Includes soft-deleted records; callers check deleted_at
 	GetRecord(ctx context.Context, id int64) (Record, error)
+	// Includes soft-deleted records, which still hold their external_id
+	GetRecordIDByExternalID(ctx context.Context, arg GetRecordIDByExternalIDParams) (int64, error)
 	GetRecordTags(ctx context.Context, recordID int64) ([]string, error)
+	// Note: BETWEEN clause must come first due to sqlc bug with SQLite parameter handling
+	// A record with multiple tags contributes to each of its tags
+	GetTagBreakdown(ctx context.Context, arg GetTagBreakdownParams) ([]GetTagBreakdownRow, error)
+	// Note: BETWEEN clause must come first due to sqlc bug with SQLite parameter handling
+	// Float projects keep the fractional value, so each distinct value is its own bucket
+	GetValueHistogram(ctx context.Context, arg GetValueHistogramParams) ([]GetValueHistogramRow, error)
+	// Note: BETWEEN clause must come first due to sqlc bug with SQLite parameter handling
+	// Counts only records that have all of the specified tags
+	GetValueHistogramWithTags(ctx context.Context, arg GetValueHistogramWithTagsParams) ([]GetValueHistogramWithTagsRow, error)
+	// The result must stay >= 1 and within the optional bounds (min_value/max_value of the project)
+	IncrementRecordValue(ctx context.Context, arg IncrementRecordValueParams) (int64, error)
+	ListProjectRecordIDs(ctx context.Context, projectID int64) ([]int64, error)
+	ListProjectRetentions(ctx context.Context) ([]ListProjectRetentionsRow, error)
 	// Cursor-based pagination: uses cursor_updated_at and cursor_name for pagination
 	ListProjects(ctx context.Context, arg ListProjectsParams) ([]Project, error)
+	// Cursor-based pagination: uses cursor_created_at and cursor_name for pagination
+	ListProjectsByCreatedAt(ctx context.Context, arg ListProjectsByCreatedAtParams) ([]Project, error)
+	// Cursor-based pagination: uses cursor_name for pagination
+	ListProjectsByName(ctx context.Context, arg ListProjectsByNameParams) ([]Project, error)
+	// Cursor-based pagination: uses cursor_record_count and cursor_name for pagination
+	ListProjectsByRecordCount(ctx context.Context, arg ListProjectsByRecordCountParams) ([]ListProjectsByRecordCountRow, error)
+	// The newest records of all projects (recent activity feed), served by idx_records_timestamp
+	ListRecentRecords(ctx context.Context, limit int64) ([]ListRecentRecordsRow, error)
+	// Note: BETWEEN clause must come first due to sqlc bug with SQLite parameter handling
+	ListRecordTimestamps(ctx context.Context, arg ListRecordTimestampsParams) ([]ListRecordTimestampsRow, error)
 	// Note: BETWEEN clause must come first due to sqlc bug with SQLite parameter handling
 	// Optimized query to avoid n+1 problem by using GROUP_CONCAT for tags
 	// Cursor-based pagination: uses cursor_timestamp and cursor_id for pagination
 	ListRecords(ctx context.Context, arg ListRecordsParams) ([]ListRecordsRow, error)
+	// Same as ListRecords but oldest first; the cursor advances towards newer records
+	ListRecordsAsc(ctx context.Context, arg ListRecordsAscParams) ([]ListRecordsAscRow, error)
+	// Same as ListRecords but filters and orders by created_at (when the record was logged)
+	ListRecordsByCreatedAt(ctx context.Context, arg ListRecordsByCreatedAtParams) ([]ListRecordsByCreatedAtRow, error)
+	// Same as ListRecordsByCreatedAt but oldest first
+	ListRecordsByCreatedAtAsc(ctx context.Context, arg ListRecordsByCreatedAtAscParams) ([]ListRecordsByCreatedAtAscRow, error)
+	// All records of a project in the half-open range [day_start, day_end) oldest first,
+	// served by idx_records_project_id_timestamp
+	ListRecordsForDay(ctx context.Context, arg ListRecordsForDayParams) ([]ListRecordsForDayRow, error)
+	// Same as ListRecordsForDay but only records that have all of the specified tags
+	ListRecordsForDayWithTags(ctx context.Context, arg ListRecordsForDayWithTagsParams) ([]ListRecordsForDayWithTagsRow, error)
 	// Note: BETWEEN clause must come first due to sqlc bug with SQLite parameter handling
 	// Returns records that have all of the specified tags
 	// Optimized query to avoid n+1 problem by using GROUP_CONCAT for all tags
 	// Cursor-based pagination: uses cursor_timestamp and cursor_id for pagination
 	ListRecordsWithTags(ctx context.Context, arg ListRecordsWithTagsParams) ([]ListRecordsWithTagsRow, error)
+	// Same as ListRecordsWithTags but oldest first; the cursor advances towards newer records
+	ListRecordsWithTagsAsc(ctx context.Context, arg ListRecordsWithTagsAscParams) ([]ListRecordsWithTagsAscRow, error)
+	// Same as ListRecordsWithTags but filters and orders by created_at
+	ListRecordsWithTagsByCreatedAt(ctx context.Context, arg ListRecordsWithTagsByCreatedAtParams) ([]ListRecordsWithTagsByCreatedAtRow, error)
+	// Same as ListRecordsWithTagsByCreatedAt but oldest first
+	ListRecordsWithTagsByCreatedAtAsc(ctx context.Context, arg ListRecordsWithTagsByCreatedAtAscParams) ([]ListRecordsWithTagsByCreatedAtAscRow, error)
+	ListTagAliases(ctx context.Context, projectID int64) ([]ListTagAliasesRow, error)
+	ProjectExists(ctx context.Context, id int64) (int64, error)
+	// Whether another project has the same name ignoring ASCII case (case-insensitive project names)
+	ProjectNameTakenIgnoringCase(ctx context.Context, arg ProjectNameTakenIgnoringCaseParams) (int64, error)
+	PurgeRecord(ctx context.Context, id int64) (sql.Result, error)
+	RecordExists(ctx context.Context, id int64) (int64, error)
+	RestoreRecord(ctx context.Context, id int64) (sql.Result, error)
 	UpdateProject(ctx context.Context, arg UpdateProjectParams) (sql.Result, error)
 	UpdateRecord(ctx context.Context, arg UpdateRecordParams) (sql.Result, error)
+	// Same as IncrementRecordValue, also updating the other columns in the same statement
+	UpdateRecordWithIncrement(ctx context.Context, arg UpdateRecordWithIncrementParams) (int64, error)
+	UpsertTagAlias(ctx context.Context, arg UpsertTagAliasParams) error
 }
 
 var _ Querier = (*Queries)(nil)
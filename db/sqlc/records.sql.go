@@ -11,16 +11,173 @@ import (
 	"strings"
 )
 
+const addTagToRecords = `-- name: AddTagToRecords :execresult
+INSERT OR IGNORE INTO tags (record_id, tag, order_index)
+SELECT
+    r.id,
+    ?1,
+    COALESCE((SELECT MAX(order_index) FROM tags WHERE record_id = r.id), -1) + 1
+FROM records r
+WHERE r.timestamp BETWEEN ?2 AND ?3 AND r.project_id = ?4 AND r.deleted_at IS NULL
+`
+
+type AddTagToRecordsParams struct {
+	Tag           string `db:"tag" json:"tag"`
+	FromTimestamp string `db:"from_timestamp" json:"from_timestamp"`
+	ToTimestamp   string `db:"to_timestamp" json:"to_timestamp"`
+	ProjectID     int64  `db:"project_id" json:"project_id"`
+}
+
+// Note: BETWEEN clause must come first due to sqlc bug with SQLite parameter handling
+// INSERT OR IGNORE skips records that already have the tag (PRIMARY KEY (record_id, tag));
+// the tag is appended after the record's existing tags
+func (q *Queries) AddTagToRecords(ctx context.Context, arg AddTagToRecordsParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, addTagToRecords,
+		arg.Tag,
+		arg.FromTimestamp,
+		arg.ToTimestamp,
+		arg.ProjectID,
+	)
+}
+
+const addTagToRecordsWithTags = `-- name: AddTagToRecordsWithTags :execresult
+INSERT OR IGNORE INTO tags (record_id, tag, order_index)
+SELECT
+    r.id,
+    ?,
+    COALESCE((SELECT MAX(order_index) FROM tags WHERE record_id = r.id), -1) + 1
+FROM records r
+WHERE r.timestamp BETWEEN ? AND ? AND r.project_id = ? AND r.deleted_at IS NULL
+  AND r.id IN (
+    SELECT record_id
+    FROM tags
+    WHERE tags.tag IN (/*SLICE:filter_tags*/?)
+    GROUP BY record_id
+    HAVING COUNT(DISTINCT tags.tag) = CAST(? AS INTEGER)
+  )
+`
+
+type AddTagToRecordsWithTagsParams struct {
+	Tag         string   `db:"tag" json:"tag"`
+	Timestamp   string   `db:"timestamp" json:"timestamp"`
+	Timestamp_2 string   `db:"timestamp_2" json:"timestamp_2"`
+	ProjectID   int64    `db:"project_id" json:"project_id"`
+	FilterTags  []string `db:"filter_tags" json:"filter_tags"`
+	Column6     int64    `db:"column_6" json:"column_6"`
+}
+
+// Note: BETWEEN clause must come first due to sqlc bug with SQLite parameter handling
+// Only records that have all of the filter tags get the tag
+// Note: positional parameters are used because sqlc numbers named ones, which breaks sqlc.slice
+func (q *Queries) AddTagToRecordsWithTags(ctx context.Context, arg AddTagToRecordsWithTagsParams) (sql.Result, error) {
+	query := addTagToRecordsWithTags
+	var queryParams []interface{}
+	queryParams = append(queryParams, arg.Tag)
+	queryParams = append(queryParams, arg.Timestamp)
+	queryParams = append(queryParams, arg.Timestamp_2)
+	queryParams = append(queryParams, arg.ProjectID)
+	if len(arg.FilterTags) > 0 {
+		for _, v := range arg.FilterTags {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:filter_tags*/?", strings.Repeat(",?", len(arg.FilterTags))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:filter_tags*/?", "NULL", 1)
+	}
+	queryParams = append(queryParams, arg.Column6)
+	return q.db.ExecContext(ctx, query, queryParams...)
+}
+
+const copyRecord = `-- name: CopyRecord :execresult
+INSERT INTO records (project_id, value, timestamp, source, value_float, created_at)
+SELECT ?1, r.value, r.timestamp, r.source, r.value_float, r.created_at
+FROM records r
+WHERE r.id = ?2
+`
+
+type CopyRecordParams struct {
+	ProjectID int64 `db:"project_id" json:"project_id"`
+	SourceID  int64 `db:"source_id" json:"source_id"`
+}
+
+func (q *Queries) CopyRecord(ctx context.Context, arg CopyRecordParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, copyRecord, arg.ProjectID, arg.SourceID)
+}
+
+const copyRecordTags = `-- name: CopyRecordTags :exec
+INSERT INTO tags (record_id, tag, order_index)
+SELECT ?1, t.tag, t.order_index
+FROM tags t
+WHERE t.record_id = ?2
+`
+
+type CopyRecordTagsParams struct {
+	RecordID       int64 `db:"record_id" json:"record_id"`
+	SourceRecordID int64 `db:"source_record_id" json:"source_record_id"`
+}
+
+func (q *Queries) CopyRecordTags(ctx context.Context, arg CopyRecordTagsParams) error {
+	_, err := q.db.ExecContext(ctx, copyRecordTags, arg.RecordID, arg.SourceRecordID)
+	return err
+}
+
+const copyRecordToTimestamp = `-- name: CopyRecordToTimestamp :execresult
+INSERT INTO records (project_id, value, timestamp, source, value_float, created_at)
+SELECT r.project_id, r.value, ?1, r.source, r.value_float, ?2
+FROM records r
+WHERE r.id = ?3
+`
+
+type CopyRecordToTimestampParams struct {
+	Timestamp string `db:"timestamp" json:"timestamp"`
+	CreatedAt string `db:"created_at" json:"created_at"`
+	SourceID  int64  `db:"source_id" json:"source_id"`
+}
+
+func (q *Queries) CopyRecordToTimestamp(ctx context.Context, arg CopyRecordToTimestampParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, copyRecordToTimestamp, arg.Timestamp, arg.CreatedAt, arg.SourceID)
+}
+
+const countProjectRecords = `-- name: CountProjectRecords :one
+SELECT COUNT(*) FROM records WHERE project_id = ?
+`
+
+// Includes soft-deleted records, which the cascade removes as well
+func (q *Queries) CountProjectRecords(ctx context.Context, projectID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countProjectRecords, projectID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countProjects = `-- name: CountProjects :one
+SELECT COUNT(*) FROM projects
+`
+
+func (q *Queries) CountProjects(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countProjects)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createProject = `-- name: CreateProject :execresult
-INSERT INTO projects (name, description, created_at, updated_at)
-VALUES (?, ?, ?, ?)
+INSERT INTO projects (name, description, created_at, updated_at, track_default_value, color, value_type, retention_days, min_value, max_value, timezone)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 `
 
 type CreateProjectParams struct {
-	Name        string `db:"name" json:"name"`
-	Description string `db:"description" json:"description"`
-	CreatedAt   string `db:"created_at" json:"created_at"`
-	UpdatedAt   string `db:"updated_at" json:"updated_at"`
+	Name              string          `db:"name" json:"name"`
+	Description       string          `db:"description" json:"description"`
+	CreatedAt         string          `db:"created_at" json:"created_at"`
+	UpdatedAt         string          `db:"updated_at" json:"updated_at"`
+	TrackDefaultValue sql.NullInt64   `db:"track_default_value" json:"track_default_value"`
+	Color             sql.NullString  `db:"color" json:"color"`
+	ValueType         string          `db:"value_type" json:"value_type"`
+	RetentionDays     sql.NullInt64   `db:"retention_days" json:"retention_days"`
+	MinValue          sql.NullFloat64 `db:"min_value" json:"min_value"`
+	MaxValue          sql.NullFloat64 `db:"max_value" json:"max_value"`
+	Timezone          sql.NullString  `db:"timezone" json:"timezone"`
 }
 
 func (q *Queries) CreateProject(ctx context.Context, arg CreateProjectParams) (sql.Result, error) {
@@ -29,22 +186,80 @@ func (q *Queries) CreateProject(ctx context.Context, arg CreateProjectParams) (s
 		arg.Description,
 		arg.CreatedAt,
 		arg.UpdatedAt,
+		arg.TrackDefaultValue,
+		arg.Color,
+		arg.ValueType,
+		arg.RetentionDays,
+		arg.MinValue,
+		arg.MaxValue,
+		arg.Timezone,
+	)
+}
+
+const createProjectWithID = `-- name: CreateProjectWithID :execresult
+INSERT INTO projects (id, name, description, created_at, updated_at, track_default_value, color, value_type, retention_days, min_value, max_value, timezone)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+type CreateProjectWithIDParams struct {
+	ID                int64           `db:"id" json:"id"`
+	Name              string          `db:"name" json:"name"`
+	Description       string          `db:"description" json:"description"`
+	CreatedAt         string          `db:"created_at" json:"created_at"`
+	UpdatedAt         string          `db:"updated_at" json:"updated_at"`
+	TrackDefaultValue sql.NullInt64   `db:"track_default_value" json:"track_default_value"`
+	Color             sql.NullString  `db:"color" json:"color"`
+	ValueType         string          `db:"value_type" json:"value_type"`
+	RetentionDays     sql.NullInt64   `db:"retention_days" json:"retention_days"`
+	MinValue          sql.NullFloat64 `db:"min_value" json:"min_value"`
+	MaxValue          sql.NullFloat64 `db:"max_value" json:"max_value"`
+	Timezone          sql.NullString  `db:"timezone" json:"timezone"`
+}
+
+func (q *Queries) CreateProjectWithID(ctx context.Context, arg CreateProjectWithIDParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, createProjectWithID,
+		arg.ID,
+		arg.Name,
+		arg.Description,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.TrackDefaultValue,
+		arg.Color,
+		arg.ValueType,
+		arg.RetentionDays,
+		arg.MinValue,
+		arg.MaxValue,
+		arg.Timezone,
 	)
 }
 
 const createRecord = `-- name: CreateRecord :execresult
-INSERT INTO records (project_id, value, timestamp)
-VALUES (?, ?, ?)
+INSERT INTO records (project_id, value, timestamp, source, value_float, created_at, external_id)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (project_id, external_id) DO NOTHING
 `
 
 type CreateRecordParams struct {
-	ProjectID int64  `db:"project_id" json:"project_id"`
-	Value     int64  `db:"value" json:"value"`
-	Timestamp string `db:"timestamp" json:"timestamp"`
+	ProjectID  int64           `db:"project_id" json:"project_id"`
+	Value      int64           `db:"value" json:"value"`
+	Timestamp  string          `db:"timestamp" json:"timestamp"`
+	Source     string          `db:"source" json:"source"`
+	ValueFloat sql.NullFloat64 `db:"value_float" json:"value_float"`
+	CreatedAt  string          `db:"created_at" json:"created_at"`
+	ExternalID sql.NullString  `db:"external_id" json:"external_id"`
 }
 
+// A record whose external_id already exists in the project is skipped (no rows affected)
 func (q *Queries) CreateRecord(ctx context.Context, arg CreateRecordParams) (sql.Result, error) {
-	return q.db.ExecContext(ctx, createRecord, arg.ProjectID, arg.Value, arg.Timestamp)
+	return q.db.ExecContext(ctx, createRecord,
+		arg.ProjectID,
+		arg.Value,
+		arg.Timestamp,
+		arg.Source,
+		arg.ValueFloat,
+		arg.CreatedAt,
+		arg.ExternalID,
+	)
 }
 
 const createRecordTag = `-- name: CreateRecordTag :exec
@@ -63,21 +278,35 @@ func (q *Queries) CreateRecordTag(ctx context.Context, arg CreateRecordTagParams
 	return err
 }
 
-const deleteProject = `-- name: DeleteProject :exec
+const deleteOrphanTags = `-- name: DeleteOrphanTags :execresult
+DELETE FROM tags WHERE record_id NOT IN (SELECT id FROM records)
+`
+
+func (q *Queries) DeleteOrphanTags(ctx context.Context) (sql.Result, error) {
+	return q.db.ExecContext(ctx, deleteOrphanTags)
+}
+
+const deleteProject = `-- name: DeleteProject :execresult
 DELETE FROM projects WHERE id = ?
 `
 
-func (q *Queries) DeleteProject(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deleteProject, id)
-	return err
+func (q *Queries) DeleteProject(ctx context.Context, id int64) (sql.Result, error) {
+	return q.db.ExecContext(ctx, deleteProject, id)
 }
 
 const deleteRecord = `-- name: DeleteRecord :execresult
-DELETE FROM records WHERE id = ?
+UPDATE records SET deleted_at = ?
+WHERE id = ? AND deleted_at IS NULL
 `
 
-func (q *Queries) DeleteRecord(ctx context.Context, id int64) (sql.Result, error) {
-	return q.db.ExecContext(ctx, deleteRecord, id)
+type DeleteRecordParams struct {
+	DeletedAt sql.NullString `db:"deleted_at" json:"deleted_at"`
+	ID        int64          `db:"id" json:"id"`
+}
+
+// Soft delete: the record is hidden until restored or purged
+func (q *Queries) DeleteRecord(ctx context.Context, arg DeleteRecordParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, deleteRecord, arg.DeletedAt, arg.ID)
 }
 
 const deleteRecordTags = `-- name: DeleteRecordTags :exec
@@ -110,8 +339,51 @@ func (q *Queries) DeleteRecordsUntilByProject(ctx context.Context, arg DeleteRec
 	return q.db.ExecContext(ctx, deleteRecordsUntilByProject, arg.ProjectID, arg.Timestamp)
 }
 
+const deleteTagAlias = `-- name: DeleteTagAlias :execresult
+DELETE FROM tag_aliases
+WHERE project_id = ? AND alias = ?
+`
+
+type DeleteTagAliasParams struct {
+	ProjectID int64  `db:"project_id" json:"project_id"`
+	Alias     string `db:"alias" json:"alias"`
+}
+
+func (q *Queries) DeleteTagAlias(ctx context.Context, arg DeleteTagAliasParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, deleteTagAlias, arg.ProjectID, arg.Alias)
+}
+
+const findDailyRecord = `-- name: FindDailyRecord :one
+SELECT id
+FROM records
+WHERE timestamp BETWEEN ?1 AND ?2
+  AND project_id = ?3 AND source = ?4 AND deleted_at IS NULL
+ORDER BY timestamp, id
+LIMIT 1
+`
+
+type FindDailyRecordParams struct {
+	DayStart  string `db:"day_start" json:"day_start"`
+	DayEnd    string `db:"day_end" json:"day_end"`
+	ProjectID int64  `db:"project_id" json:"project_id"`
+	Source    string `db:"source" json:"source"`
+}
+
+// The record that a daily upsert adds to: the oldest one of the project and source in the day
+func (q *Queries) FindDailyRecord(ctx context.Context, arg FindDailyRecordParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, findDailyRecord,
+		arg.DayStart,
+		arg.DayEnd,
+		arg.ProjectID,
+		arg.Source,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
 const getProject = `-- name: GetProject :one
-SELECT id, name, description, created_at, updated_at
+SELECT id, name, description, created_at, updated_at, track_default_value, color, value_type, retention_days, min_value, max_value, timezone, changed_at
 FROM projects
 WHERE id = ?
 `
@@ -125,6 +397,57 @@ func (q *Queries) GetProject(ctx context.Context, id int64) (Project, error) {
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.TrackDefaultValue,
+		&i.Color,
+		&i.ValueType,
+		&i.RetentionDays,
+		&i.MinValue,
+		&i.MaxValue,
+		&i.Timezone,
+		&i.ChangedAt,
+	)
+	return i, err
+}
+
+const getProjectLastModified = `-- name: GetProjectLastModified :one
+SELECT CAST(MAX(updated_at, changed_at) AS TEXT) AS last_modified
+FROM projects
+WHERE id = ?
+`
+
+// The later of the project's own update and the last change to its records, tags or tag aliases
+func (q *Queries) GetProjectLastModified(ctx context.Context, id int64) (string, error) {
+	row := q.db.QueryRowContext(ctx, getProjectLastModified, id)
+	var last_modified string
+	err := row.Scan(&last_modified)
+	return last_modified, err
+}
+
+const getProjectSummary = `-- name: GetProjectSummary :one
+SELECT
+    COUNT(*) AS record_count,
+    CAST(COALESCE(SUM(value), 0) AS INTEGER) AS total_value,
+    CAST(COALESCE(MIN(timestamp), '') AS TEXT) AS first_record_at,
+    CAST(COALESCE(MAX(timestamp), '') AS TEXT) AS last_record_at
+FROM records
+WHERE project_id = ? AND deleted_at IS NULL
+`
+
+type GetProjectSummaryRow struct {
+	RecordCount   int64  `db:"record_count" json:"record_count"`
+	TotalValue    int64  `db:"total_value" json:"total_value"`
+	FirstRecordAt string `db:"first_record_at" json:"first_record_at"`
+	LastRecordAt  string `db:"last_record_at" json:"last_record_at"`
+}
+
+func (q *Queries) GetProjectSummary(ctx context.Context, projectID int64) (GetProjectSummaryRow, error) {
+	row := q.db.QueryRowContext(ctx, getProjectSummary, projectID)
+	var i GetProjectSummaryRow
+	err := row.Scan(
+		&i.RecordCount,
+		&i.TotalValue,
+		&i.FirstRecordAt,
+		&i.LastRecordAt,
 	)
 	return i, err
 }
@@ -133,12 +456,25 @@ const getProjectTags = `-- name: GetProjectTags :many
 SELECT DISTINCT tag
 FROM tags t
 JOIN records r ON t.record_id = r.id
-WHERE r.project_id = ?
+WHERE r.project_id = ? AND r.deleted_at IS NULL AND (? IS NULL OR tag > ?)
 ORDER BY tag
+LIMIT ?
 `
 
-func (q *Queries) GetProjectTags(ctx context.Context, projectID int64) ([]string, error) {
-	rows, err := q.db.QueryContext(ctx, getProjectTags, projectID)
+type GetProjectTagsParams struct {
+	ProjectID int64       `db:"project_id" json:"project_id"`
+	Column2   interface{} `db:"column_2" json:"column_2"`
+	Tag       string      `db:"tag" json:"tag"`
+	Limit     int64       `db:"limit" json:"limit"`
+}
+
+func (q *Queries) GetProjectTags(ctx context.Context, arg GetProjectTagsParams) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, getProjectTags,
+		arg.ProjectID,
+		arg.Column2,
+		arg.Tag,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -161,11 +497,12 @@ func (q *Queries) GetProjectTags(ctx context.Context, projectID int64) ([]string
 }
 
 const getRecord = `-- name: GetRecord :one
-SELECT id, project_id, value, timestamp
+SELECT id, project_id, value, timestamp, source, value_float, deleted_at, created_at, external_id
 FROM records
 WHERE id = ?
 `
 
+// Includes soft-deleted records; callers check deleted_at
 func (q *Queries) GetRecord(ctx context.Context, id int64) (Record, error) {
 	row := q.db.QueryRowContext(ctx, getRecord, id)
 	var i Record
@@ -174,10 +511,32 @@ func (q *Queries) GetRecord(ctx context.Context, id int64) (Record, error) {
 		&i.ProjectID,
 		&i.Value,
 		&i.Timestamp,
+		&i.Source,
+		&i.ValueFloat,
+		&i.DeletedAt,
+		&i.CreatedAt,
+		&i.ExternalID,
 	)
 	return i, err
 }
 
+const getRecordIDByExternalID = `-- name: GetRecordIDByExternalID :one
+SELECT id FROM records WHERE project_id = ? AND external_id = ?
+`
+
+type GetRecordIDByExternalIDParams struct {
+	ProjectID  int64          `db:"project_id" json:"project_id"`
+	ExternalID sql.NullString `db:"external_id" json:"external_id"`
+}
+
+// Includes soft-deleted records, which still hold their external_id
+func (q *Queries) GetRecordIDByExternalID(ctx context.Context, arg GetRecordIDByExternalIDParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getRecordIDByExternalID, arg.ProjectID, arg.ExternalID)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
 const getRecordTags = `-- name: GetRecordTags :many
 SELECT tag
 FROM tags
@@ -208,45 +567,42 @@ func (q *Queries) GetRecordTags(ctx context.Context, recordID int64) ([]string,
 	return items, nil
 }
 
-const listProjects = `-- name: ListProjects :many
-SELECT id, name, description, created_at, updated_at
-FROM projects
-WHERE ? IS NULL OR updated_at < ? OR (updated_at = ? AND name > ?)
-ORDER BY updated_at DESC, name
-LIMIT ?
+const getTagBreakdown = `-- name: GetTagBreakdown :many
+SELECT
+    t.tag,
+    CAST(COALESCE(SUM(COALESCE(r.value_float, r.value)), 0) AS REAL) AS total_value,
+    COUNT(DISTINCT r.id) AS record_count
+FROM records r
+INNER JOIN tags t ON r.id = t.record_id
+WHERE r.timestamp BETWEEN ? AND ? AND r.project_id = ? AND r.deleted_at IS NULL
+GROUP BY t.tag
+ORDER BY total_value DESC, t.tag
 `
 
-type ListProjectsParams struct {
-	Column1     interface{} `db:"column_1" json:"column_1"`
-	UpdatedAt   string      `db:"updated_at" json:"updated_at"`
-	UpdatedAt_2 string      `db:"updated_at_2" json:"updated_at_2"`
-	Name        string      `db:"name" json:"name"`
-	Limit       int64       `db:"limit" json:"limit"`
+type GetTagBreakdownParams struct {
+	Timestamp   string `db:"timestamp" json:"timestamp"`
+	Timestamp_2 string `db:"timestamp_2" json:"timestamp_2"`
+	ProjectID   int64  `db:"project_id" json:"project_id"`
 }
 
-// Cursor-based pagination: uses cursor_updated_at and cursor_name for pagination
-func (q *Queries) ListProjects(ctx context.Context, arg ListProjectsParams) ([]Project, error) {
-	rows, err := q.db.QueryContext(ctx, listProjects,
-		arg.Column1,
-		arg.UpdatedAt,
-		arg.UpdatedAt_2,
-		arg.Name,
-		arg.Limit,
-	)
+type GetTagBreakdownRow struct {
+	Tag         string  `db:"tag" json:"tag"`
+	TotalValue  float64 `db:"total_value" json:"total_value"`
+	RecordCount int64   `db:"record_count" json:"record_count"`
+}
+
+// Note: BETWEEN clause must come first due to sqlc bug with SQLite parameter handling
+// A record with multiple tags contributes to each of its tags
+func (q *Queries) GetTagBreakdown(ctx context.Context, arg GetTagBreakdownParams) ([]GetTagBreakdownRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTagBreakdown, arg.Timestamp, arg.Timestamp_2, arg.ProjectID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []Project{}
+	items := []GetTagBreakdownRow{}
 	for rows.Next() {
-		var i Project
-		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.Description,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-		); err != nil {
+		var i GetTagBreakdownRow
+		if err := rows.Scan(&i.Tag, &i.TotalValue, &i.RecordCount); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -260,75 +616,39 @@ func (q *Queries) ListProjects(ctx context.Context, arg ListProjectsParams) ([]P
 	return items, nil
 }
 
-const listRecords = `-- name: ListRecords :many
+const getValueHistogram = `-- name: GetValueHistogram :many
 SELECT
-    r.id,
-    r.project_id,
-    r.value,
-    r.timestamp,
-    COALESCE((
-        SELECT GROUP_CONCAT(tag, ' ')
-        FROM (
-            SELECT tag
-            FROM tags
-            WHERE record_id = r.id
-            ORDER BY order_index
-        )
-    ), '') as tags
+    CAST(COALESCE(r.value_float, r.value) AS REAL) AS value,
+    COUNT(*) AS record_count
 FROM records r
-WHERE r.timestamp BETWEEN ? AND ? AND r.project_id = ?
-  AND (? IS NULL OR r.timestamp < ? OR (r.timestamp = ? AND r.id > ?))
-ORDER BY r.timestamp DESC, r.id
-LIMIT ?
+WHERE r.timestamp BETWEEN ? AND ? AND r.project_id = ? AND r.deleted_at IS NULL
+GROUP BY COALESCE(r.value_float, r.value)
+ORDER BY value
 `
 
-type ListRecordsParams struct {
-	Timestamp   string      `db:"timestamp" json:"timestamp"`
-	Timestamp_2 string      `db:"timestamp_2" json:"timestamp_2"`
-	ProjectID   int64       `db:"project_id" json:"project_id"`
-	Column4     interface{} `db:"column_4" json:"column_4"`
-	Timestamp_3 string      `db:"timestamp_3" json:"timestamp_3"`
-	Timestamp_4 string      `db:"timestamp_4" json:"timestamp_4"`
-	ID          int64       `db:"id" json:"id"`
-	Limit       int64       `db:"limit" json:"limit"`
+type GetValueHistogramParams struct {
+	Timestamp   string `db:"timestamp" json:"timestamp"`
+	Timestamp_2 string `db:"timestamp_2" json:"timestamp_2"`
+	ProjectID   int64  `db:"project_id" json:"project_id"`
 }
 
-type ListRecordsRow struct {
-	ID        int64       `db:"id" json:"id"`
-	ProjectID int64       `db:"project_id" json:"project_id"`
-	Value     int64       `db:"value" json:"value"`
-	Timestamp string      `db:"timestamp" json:"timestamp"`
-	Tags      interface{} `db:"tags" json:"tags"`
+type GetValueHistogramRow struct {
+	Value       float64 `db:"value" json:"value"`
+	RecordCount int64   `db:"record_count" json:"record_count"`
 }
 
 // Note: BETWEEN clause must come first due to sqlc bug with SQLite parameter handling
-// Optimized query to avoid n+1 problem by using GROUP_CONCAT for tags
-// Cursor-based pagination: uses cursor_timestamp and cursor_id for pagination
-func (q *Queries) ListRecords(ctx context.Context, arg ListRecordsParams) ([]ListRecordsRow, error) {
-	rows, err := q.db.QueryContext(ctx, listRecords,
-		arg.Timestamp,
-		arg.Timestamp_2,
-		arg.ProjectID,
-		arg.Column4,
-		arg.Timestamp_3,
-		arg.Timestamp_4,
-		arg.ID,
-		arg.Limit,
-	)
+// Float projects keep the fractional value, so each distinct value is its own bucket
+func (q *Queries) GetValueHistogram(ctx context.Context, arg GetValueHistogramParams) ([]GetValueHistogramRow, error) {
+	rows, err := q.db.QueryContext(ctx, getValueHistogram, arg.Timestamp, arg.Timestamp_2, arg.ProjectID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []ListRecordsRow{}
+	items := []GetValueHistogramRow{}
 	for rows.Next() {
-		var i ListRecordsRow
-		if err := rows.Scan(
-			&i.ID,
-			&i.ProjectID,
-			&i.Value,
-			&i.Timestamp,
-			&i.Tags,
-		); err != nil {
+		var i GetValueHistogramRow
+		if err := rows.Scan(&i.Value, &i.RecordCount); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -342,59 +662,40 @@ func (q *Queries) ListRecords(ctx context.Context, arg ListRecordsParams) ([]Lis
 	return items, nil
 }
 
-const listRecordsWithTags = `-- name: ListRecordsWithTags :many
+const getValueHistogramWithTags = `-- name: GetValueHistogramWithTags :many
 SELECT
-    r.id,
-    r.project_id,
-    r.value,
-    r.timestamp,
-    COALESCE((
-        SELECT GROUP_CONCAT(tag, ' ')
-        FROM (
-            SELECT tag
-            FROM tags
-            WHERE record_id = r.id
-            ORDER BY order_index
-        )
-    ), '') as all_tags
+    CAST(COALESCE(r.value_float, r.value) AS REAL) AS value,
+    COUNT(*) AS record_count
 FROM records r
-INNER JOIN tags t ON r.id = t.record_id
-WHERE r.timestamp BETWEEN ? AND ? AND r.project_id = ?
-  AND t.tag IN (/*SLICE:tags*/?)
-  AND (? IS NULL OR r.timestamp < ? OR (r.timestamp = ? AND r.id > ?))
-GROUP BY r.id, r.project_id, r.value, r.timestamp
-HAVING COUNT(DISTINCT t.tag) = CAST(? AS INTEGER)
-ORDER BY r.timestamp DESC, r.id
-LIMIT ?
+WHERE r.timestamp BETWEEN ? AND ? AND r.project_id = ? AND r.deleted_at IS NULL
+  AND r.id IN (
+    SELECT record_id
+    FROM tags
+    WHERE tag IN (/*SLICE:tags*/?)
+    GROUP BY record_id
+    HAVING COUNT(DISTINCT tag) = CAST(? AS INTEGER)
+  )
+GROUP BY COALESCE(r.value_float, r.value)
+ORDER BY value
 `
 
-type ListRecordsWithTagsParams struct {
-	Timestamp   string      `db:"timestamp" json:"timestamp"`
-	Timestamp_2 string      `db:"timestamp_2" json:"timestamp_2"`
-	ProjectID   int64       `db:"project_id" json:"project_id"`
-	Tags        []string    `db:"tags" json:"tags"`
-	Column5     interface{} `db:"column_5" json:"column_5"`
-	Timestamp_3 string      `db:"timestamp_3" json:"timestamp_3"`
-	Timestamp_4 string      `db:"timestamp_4" json:"timestamp_4"`
-	ID          int64       `db:"id" json:"id"`
-	Column9     int64       `db:"column_9" json:"column_9"`
-	Limit       int64       `db:"limit" json:"limit"`
+type GetValueHistogramWithTagsParams struct {
+	Timestamp   string   `db:"timestamp" json:"timestamp"`
+	Timestamp_2 string   `db:"timestamp_2" json:"timestamp_2"`
+	ProjectID   int64    `db:"project_id" json:"project_id"`
+	Tags        []string `db:"tags" json:"tags"`
+	Column5     int64    `db:"column_5" json:"column_5"`
 }
 
-type ListRecordsWithTagsRow struct {
-	ID        int64       `db:"id" json:"id"`
-	ProjectID int64       `db:"project_id" json:"project_id"`
-	Value     int64       `db:"value" json:"value"`
-	Timestamp string      `db:"timestamp" json:"timestamp"`
-	AllTags   interface{} `db:"all_tags" json:"all_tags"`
+type GetValueHistogramWithTagsRow struct {
+	Value       float64 `db:"value" json:"value"`
+	RecordCount int64   `db:"record_count" json:"record_count"`
 }
 
 // Note: BETWEEN clause must come first due to sqlc bug with SQLite parameter handling
-// Returns records that have all of the specified tags
-// Optimized query to avoid n+1 problem by using GROUP_CONCAT for all tags
-// Cursor-based pagination: uses cursor_timestamp and cursor_id for pagination
-func (q *Queries) ListRecordsWithTags(ctx context.Context, arg ListRecordsWithTagsParams) ([]ListRecordsWithTagsRow, error) {
-	query := listRecordsWithTags
+// Counts only records that have all of the specified tags
+func (q *Queries) GetValueHistogramWithTags(ctx context.Context, arg GetValueHistogramWithTagsParams) ([]GetValueHistogramWithTagsRow, error) {
+	query := getValueHistogramWithTags
 	var queryParams []interface{}
 	queryParams = append(queryParams, arg.Timestamp)
 	queryParams = append(queryParams, arg.Timestamp_2)
@@ -408,24 +709,1517 @@ func (q *Queries) ListRecordsWithTags(ctx context.Context, arg ListRecordsWithTa
 		query = strings.Replace(query, "/*SLICE:tags*/?", "NULL", 1)
 	}
 	queryParams = append(queryParams, arg.Column5)
-	queryParams = append(queryParams, arg.Timestamp_3)
-	queryParams = append(queryParams, arg.Timestamp_4)
-	queryParams = append(queryParams, arg.ID)
-	queryParams = append(queryParams, arg.Column9)
-	queryParams = append(queryParams, arg.Limit)
 	rows, err := q.db.QueryContext(ctx, query, queryParams...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []ListRecordsWithTagsRow{}
+	items := []GetValueHistogramWithTagsRow{}
 	for rows.Next() {
-		var i ListRecordsWithTagsRow
+		var i GetValueHistogramWithTagsRow
+		if err := rows.Scan(&i.Value, &i.RecordCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const incrementRecordValue = `-- name: IncrementRecordValue :one
+UPDATE records SET value = value + ?1
+WHERE id = ?2 AND deleted_at IS NULL
+  AND value + ?1 >= 1
+  AND (?3 IS NULL OR value + ?1 >= CAST(?3 AS REAL))
+  AND (?4 IS NULL OR value + ?1 <= CAST(?4 AS REAL))
+RETURNING value
+`
+
+type IncrementRecordValueParams struct {
+	Delta    int64       `db:"delta" json:"delta"`
+	ID       int64       `db:"id" json:"id"`
+	MinValue interface{} `db:"min_value" json:"min_value"`
+	MaxValue interface{} `db:"max_value" json:"max_value"`
+}
+
+// The result must stay >= 1 and within the optional bounds (min_value/max_value of the project)
+func (q *Queries) IncrementRecordValue(ctx context.Context, arg IncrementRecordValueParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, incrementRecordValue,
+		arg.Delta,
+		arg.ID,
+		arg.MinValue,
+		arg.MaxValue,
+	)
+	var value int64
+	err := row.Scan(&value)
+	return value, err
+}
+
+const listProjectRecordIDs = `-- name: ListProjectRecordIDs :many
+SELECT id FROM records WHERE project_id = ? AND deleted_at IS NULL ORDER BY id
+`
+
+func (q *Queries) ListProjectRecordIDs(ctx context.Context, projectID int64) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, listProjectRecordIDs, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProjectRetentions = `-- name: ListProjectRetentions :many
+SELECT id, retention_days
+FROM projects
+WHERE retention_days IS NOT NULL
+ORDER BY id
+`
+
+type ListProjectRetentionsRow struct {
+	ID            int64         `db:"id" json:"id"`
+	RetentionDays sql.NullInt64 `db:"retention_days" json:"retention_days"`
+}
+
+func (q *Queries) ListProjectRetentions(ctx context.Context) ([]ListProjectRetentionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listProjectRetentions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListProjectRetentionsRow{}
+	for rows.Next() {
+		var i ListProjectRetentionsRow
+		if err := rows.Scan(&i.ID, &i.RetentionDays); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProjects = `-- name: ListProjects :many
+SELECT id, name, description, created_at, updated_at, track_default_value, color, value_type, retention_days, min_value, max_value, timezone, changed_at
+FROM projects
+WHERE ? IS NULL OR updated_at < ? OR (updated_at = ? AND name > ?)
+ORDER BY updated_at DESC, name
+LIMIT ?
+`
+
+type ListProjectsParams struct {
+	Column1     interface{} `db:"column_1" json:"column_1"`
+	UpdatedAt   string      `db:"updated_at" json:"updated_at"`
+	UpdatedAt_2 string      `db:"updated_at_2" json:"updated_at_2"`
+	Name        string      `db:"name" json:"name"`
+	Limit       int64       `db:"limit" json:"limit"`
+}
+
+// Cursor-based pagination: uses cursor_updated_at and cursor_name for pagination
+func (q *Queries) ListProjects(ctx context.Context, arg ListProjectsParams) ([]Project, error) {
+	rows, err := q.db.QueryContext(ctx, listProjects,
+		arg.Column1,
+		arg.UpdatedAt,
+		arg.UpdatedAt_2,
+		arg.Name,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Project{}
+	for rows.Next() {
+		var i Project
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.TrackDefaultValue,
+			&i.Color,
+			&i.ValueType,
+			&i.RetentionDays,
+			&i.MinValue,
+			&i.MaxValue,
+			&i.Timezone,
+			&i.ChangedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProjectsByCreatedAt = `-- name: ListProjectsByCreatedAt :many
+SELECT id, name, description, created_at, updated_at, track_default_value, color, value_type, retention_days, min_value, max_value, timezone, changed_at
+FROM projects
+WHERE ?1 IS NULL
+    OR created_at < ?1
+    OR (created_at = ?1 AND name > ?2)
+ORDER BY created_at DESC, name
+LIMIT ?3
+`
+
+type ListProjectsByCreatedAtParams struct {
+	CursorCreatedAt interface{} `db:"cursor_created_at" json:"cursor_created_at"`
+	CursorName      string      `db:"cursor_name" json:"cursor_name"`
+	Limit           int64       `db:"limit" json:"limit"`
+}
+
+// Cursor-based pagination: uses cursor_created_at and cursor_name for pagination
+func (q *Queries) ListProjectsByCreatedAt(ctx context.Context, arg ListProjectsByCreatedAtParams) ([]Project, error) {
+	rows, err := q.db.QueryContext(ctx, listProjectsByCreatedAt, arg.CursorCreatedAt, arg.CursorName, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Project{}
+	for rows.Next() {
+		var i Project
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.TrackDefaultValue,
+			&i.Color,
+			&i.ValueType,
+			&i.RetentionDays,
+			&i.MinValue,
+			&i.MaxValue,
+			&i.Timezone,
+			&i.ChangedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProjectsByName = `-- name: ListProjectsByName :many
+SELECT id, name, description, created_at, updated_at, track_default_value, color, value_type, retention_days, min_value, max_value, timezone, changed_at
+FROM projects
+WHERE ?1 IS NULL OR name > ?1
+ORDER BY name
+LIMIT ?2
+`
+
+type ListProjectsByNameParams struct {
+	CursorName interface{} `db:"cursor_name" json:"cursor_name"`
+	Limit      int64       `db:"limit" json:"limit"`
+}
+
+// Cursor-based pagination: uses cursor_name for pagination
+func (q *Queries) ListProjectsByName(ctx context.Context, arg ListProjectsByNameParams) ([]Project, error) {
+	rows, err := q.db.QueryContext(ctx, listProjectsByName, arg.CursorName, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Project{}
+	for rows.Next() {
+		var i Project
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.TrackDefaultValue,
+			&i.Color,
+			&i.ValueType,
+			&i.RetentionDays,
+			&i.MinValue,
+			&i.MaxValue,
+			&i.Timezone,
+			&i.ChangedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProjectsByRecordCount = `-- name: ListProjectsByRecordCount :many
+WITH counted AS (
+    SELECT p.id, p.name, p.description, p.created_at, p.updated_at, p.track_default_value, p.color, p.value_type, p.retention_days, p.min_value, p.max_value, p.timezone,
+        COUNT(r.id) AS record_count
+    FROM projects p
+    LEFT JOIN records r ON r.project_id = p.id AND r.deleted_at IS NULL
+    GROUP BY p.id
+)
+SELECT id, name, description, created_at, updated_at, track_default_value, color, value_type, retention_days, min_value, max_value, timezone, record_count
+FROM counted
+WHERE ?1 IS NULL
+    OR record_count < ?1
+    OR (record_count = ?1 AND name > ?2)
+ORDER BY record_count DESC, name
+LIMIT ?3
+`
+
+type ListProjectsByRecordCountParams struct {
+	CursorRecordCount interface{} `db:"cursor_record_count" json:"cursor_record_count"`
+	CursorName        string      `db:"cursor_name" json:"cursor_name"`
+	Limit             int64       `db:"limit" json:"limit"`
+}
+
+type ListProjectsByRecordCountRow struct {
+	ID                int64           `db:"id" json:"id"`
+	Name              string          `db:"name" json:"name"`
+	Description       string          `db:"description" json:"description"`
+	CreatedAt         string          `db:"created_at" json:"created_at"`
+	UpdatedAt         string          `db:"updated_at" json:"updated_at"`
+	TrackDefaultValue sql.NullInt64   `db:"track_default_value" json:"track_default_value"`
+	Color             sql.NullString  `db:"color" json:"color"`
+	ValueType         string          `db:"value_type" json:"value_type"`
+	RetentionDays     sql.NullInt64   `db:"retention_days" json:"retention_days"`
+	MinValue          sql.NullFloat64 `db:"min_value" json:"min_value"`
+	MaxValue          sql.NullFloat64 `db:"max_value" json:"max_value"`
+	Timezone          sql.NullString  `db:"timezone" json:"timezone"`
+	RecordCount       int64           `db:"record_count" json:"record_count"`
+}
+
+// Cursor-based pagination: uses cursor_record_count and cursor_name for pagination
+func (q *Queries) ListProjectsByRecordCount(ctx context.Context, arg ListProjectsByRecordCountParams) ([]ListProjectsByRecordCountRow, error) {
+	rows, err := q.db.QueryContext(ctx, listProjectsByRecordCount, arg.CursorRecordCount, arg.CursorName, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListProjectsByRecordCountRow{}
+	for rows.Next() {
+		var i ListProjectsByRecordCountRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.TrackDefaultValue,
+			&i.Color,
+			&i.ValueType,
+			&i.RetentionDays,
+			&i.MinValue,
+			&i.MaxValue,
+			&i.Timezone,
+			&i.RecordCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRecentRecords = `-- name: ListRecentRecords :many
+SELECT
+    r.id,
+    r.project_id,
+    r.value,
+    r.timestamp,
+    r.source,
+    r.value_float,
+    r.created_at,
+    r.external_id,
+    COALESCE((
+        SELECT GROUP_CONCAT(tag, ' ')
+        FROM (
+            SELECT tag
+            FROM tags
+            WHERE record_id = r.id
+            ORDER BY order_index
+        )
+    ), '') as tags
+FROM records r
+WHERE r.deleted_at IS NULL
+ORDER BY r.timestamp DESC, r.id DESC
+LIMIT ?
+`
+
+type ListRecentRecordsRow struct {
+	ID         int64           `db:"id" json:"id"`
+	ProjectID  int64           `db:"project_id" json:"project_id"`
+	Value      int64           `db:"value" json:"value"`
+	Timestamp  string          `db:"timestamp" json:"timestamp"`
+	Source     string          `db:"source" json:"source"`
+	ValueFloat sql.NullFloat64 `db:"value_float" json:"value_float"`
+	CreatedAt  string          `db:"created_at" json:"created_at"`
+	ExternalID sql.NullString  `db:"external_id" json:"external_id"`
+	Tags       interface{}     `db:"tags" json:"tags"`
+}
+
+// The newest records of all projects (recent activity feed), served by idx_records_timestamp
+func (q *Queries) ListRecentRecords(ctx context.Context, limit int64) ([]ListRecentRecordsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listRecentRecords, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListRecentRecordsRow{}
+	for rows.Next() {
+		var i ListRecentRecordsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.Value,
+			&i.Timestamp,
+			&i.Source,
+			&i.ValueFloat,
+			&i.CreatedAt,
+			&i.ExternalID,
+			&i.Tags,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRecordTimestamps = `-- name: ListRecordTimestamps :many
+SELECT id, timestamp
+FROM records
+WHERE timestamp BETWEEN ? AND ? AND project_id = ? AND deleted_at IS NULL
+ORDER BY timestamp, id
+`
+
+type ListRecordTimestampsParams struct {
+	Timestamp   string `db:"timestamp" json:"timestamp"`
+	Timestamp_2 string `db:"timestamp_2" json:"timestamp_2"`
+	ProjectID   int64  `db:"project_id" json:"project_id"`
+}
+
+type ListRecordTimestampsRow struct {
+	ID        int64  `db:"id" json:"id"`
+	Timestamp string `db:"timestamp" json:"timestamp"`
+}
+
+// Note: BETWEEN clause must come first due to sqlc bug with SQLite parameter handling
+func (q *Queries) ListRecordTimestamps(ctx context.Context, arg ListRecordTimestampsParams) ([]ListRecordTimestampsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listRecordTimestamps, arg.Timestamp, arg.Timestamp_2, arg.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListRecordTimestampsRow{}
+	for rows.Next() {
+		var i ListRecordTimestampsRow
+		if err := rows.Scan(&i.ID, &i.Timestamp); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRecords = `-- name: ListRecords :many
+SELECT
+    r.id,
+    r.project_id,
+    r.value,
+    r.timestamp,
+    r.source,
+    r.value_float,
+    r.created_at,
+    r.external_id,
+    COALESCE((
+        SELECT GROUP_CONCAT(tag, ' ')
+        FROM (
+            SELECT tag
+            FROM tags
+            WHERE record_id = r.id
+            ORDER BY order_index
+        )
+    ), '') as tags
+FROM records r
+WHERE r.timestamp BETWEEN ? AND ? AND r.project_id = ? AND r.deleted_at IS NULL
+  AND (? = '' OR r.source = ?)
+  AND (? = '' OR r.external_id = ?)
+  AND (? IS NULL OR r.timestamp < ? OR (r.timestamp = ? AND r.id > ?))
+ORDER BY r.timestamp DESC, r.id
+LIMIT ?
+`
+
+type ListRecordsParams struct {
+	Timestamp   string         `db:"timestamp" json:"timestamp"`
+	Timestamp_2 string         `db:"timestamp_2" json:"timestamp_2"`
+	ProjectID   int64          `db:"project_id" json:"project_id"`
+	Column4     interface{}    `db:"column_4" json:"column_4"`
+	Source      string         `db:"source" json:"source"`
+	Column6     interface{}    `db:"column_6" json:"column_6"`
+	ExternalID  sql.NullString `db:"external_id" json:"external_id"`
+	Column8     interface{}    `db:"column_8" json:"column_8"`
+	Timestamp_3 string         `db:"timestamp_3" json:"timestamp_3"`
+	Timestamp_4 string         `db:"timestamp_4" json:"timestamp_4"`
+	ID          int64          `db:"id" json:"id"`
+	Limit       int64          `db:"limit" json:"limit"`
+}
+
+type ListRecordsRow struct {
+	ID         int64           `db:"id" json:"id"`
+	ProjectID  int64           `db:"project_id" json:"project_id"`
+	Value      int64           `db:"value" json:"value"`
+	Timestamp  string          `db:"timestamp" json:"timestamp"`
+	Source     string          `db:"source" json:"source"`
+	ValueFloat sql.NullFloat64 `db:"value_float" json:"value_float"`
+	CreatedAt  string          `db:"created_at" json:"created_at"`
+	ExternalID sql.NullString  `db:"external_id" json:"external_id"`
+	Tags       interface{}     `db:"tags" json:"tags"`
+}
+
+// Note: BETWEEN clause must come first due to sqlc bug with SQLite parameter handling
+// Optimized query to avoid n+1 problem by using GROUP_CONCAT for tags
+// Cursor-based pagination: uses cursor_timestamp and cursor_id for pagination
+func (q *Queries) ListRecords(ctx context.Context, arg ListRecordsParams) ([]ListRecordsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listRecords,
+		arg.Timestamp,
+		arg.Timestamp_2,
+		arg.ProjectID,
+		arg.Column4,
+		arg.Source,
+		arg.Column6,
+		arg.ExternalID,
+		arg.Column8,
+		arg.Timestamp_3,
+		arg.Timestamp_4,
+		arg.ID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListRecordsRow{}
+	for rows.Next() {
+		var i ListRecordsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.Value,
+			&i.Timestamp,
+			&i.Source,
+			&i.ValueFloat,
+			&i.CreatedAt,
+			&i.ExternalID,
+			&i.Tags,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRecordsAsc = `-- name: ListRecordsAsc :many
+SELECT
+    r.id,
+    r.project_id,
+    r.value,
+    r.timestamp,
+    r.source,
+    r.value_float,
+    r.created_at,
+    r.external_id,
+    COALESCE((
+        SELECT GROUP_CONCAT(tag, ' ')
+        FROM (
+            SELECT tag
+            FROM tags
+            WHERE record_id = r.id
+            ORDER BY order_index
+        )
+    ), '') as tags
+FROM records r
+WHERE r.timestamp BETWEEN ? AND ? AND r.project_id = ? AND r.deleted_at IS NULL
+  AND (? = '' OR r.source = ?)
+  AND (? = '' OR r.external_id = ?)
+  AND (? IS NULL OR r.timestamp > ? OR (r.timestamp = ? AND r.id > ?))
+ORDER BY r.timestamp, r.id
+LIMIT ?
+`
+
+type ListRecordsAscParams struct {
+	Timestamp   string         `db:"timestamp" json:"timestamp"`
+	Timestamp_2 string         `db:"timestamp_2" json:"timestamp_2"`
+	ProjectID   int64          `db:"project_id" json:"project_id"`
+	Column4     interface{}    `db:"column_4" json:"column_4"`
+	Source      string         `db:"source" json:"source"`
+	Column6     interface{}    `db:"column_6" json:"column_6"`
+	ExternalID  sql.NullString `db:"external_id" json:"external_id"`
+	Column8     interface{}    `db:"column_8" json:"column_8"`
+	Timestamp_3 string         `db:"timestamp_3" json:"timestamp_3"`
+	Timestamp_4 string         `db:"timestamp_4" json:"timestamp_4"`
+	ID          int64          `db:"id" json:"id"`
+	Limit       int64          `db:"limit" json:"limit"`
+}
+
+type ListRecordsAscRow struct {
+	ID         int64           `db:"id" json:"id"`
+	ProjectID  int64           `db:"project_id" json:"project_id"`
+	Value      int64           `db:"value" json:"value"`
+	Timestamp  string          `db:"timestamp" json:"timestamp"`
+	Source     string          `db:"source" json:"source"`
+	ValueFloat sql.NullFloat64 `db:"value_float" json:"value_float"`
+	CreatedAt  string          `db:"created_at" json:"created_at"`
+	ExternalID sql.NullString  `db:"external_id" json:"external_id"`
+	Tags       interface{}     `db:"tags" json:"tags"`
+}
+
+// Same as ListRecords but oldest first; the cursor advances towards newer records
+func (q *Queries) ListRecordsAsc(ctx context.Context, arg ListRecordsAscParams) ([]ListRecordsAscRow, error) {
+	rows, err := q.db.QueryContext(ctx, listRecordsAsc,
+		arg.Timestamp,
+		arg.Timestamp_2,
+		arg.ProjectID,
+		arg.Column4,
+		arg.Source,
+		arg.Column6,
+		arg.ExternalID,
+		arg.Column8,
+		arg.Timestamp_3,
+		arg.Timestamp_4,
+		arg.ID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListRecordsAscRow{}
+	for rows.Next() {
+		var i ListRecordsAscRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.Value,
+			&i.Timestamp,
+			&i.Source,
+			&i.ValueFloat,
+			&i.CreatedAt,
+			&i.ExternalID,
+			&i.Tags,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRecordsByCreatedAt = `-- name: ListRecordsByCreatedAt :many
+SELECT
+    r.id,
+    r.project_id,
+    r.value,
+    r.timestamp,
+    r.source,
+    r.value_float,
+    r.created_at,
+    r.external_id,
+    COALESCE((
+        SELECT GROUP_CONCAT(tag, ' ')
+        FROM (
+            SELECT tag
+            FROM tags
+            WHERE record_id = r.id
+            ORDER BY order_index
+        )
+    ), '') as tags
+FROM records r
+WHERE r.created_at BETWEEN ? AND ? AND r.project_id = ? AND r.deleted_at IS NULL
+  AND (? = '' OR r.source = ?)
+  AND (? = '' OR r.external_id = ?)
+  AND (? IS NULL OR r.created_at < ? OR (r.created_at = ? AND r.id > ?))
+ORDER BY r.created_at DESC, r.id
+LIMIT ?
+`
+
+type ListRecordsByCreatedAtParams struct {
+	CreatedAt   string         `db:"created_at" json:"created_at"`
+	CreatedAt_2 string         `db:"created_at_2" json:"created_at_2"`
+	ProjectID   int64          `db:"project_id" json:"project_id"`
+	Column4     interface{}    `db:"column_4" json:"column_4"`
+	Source      string         `db:"source" json:"source"`
+	Column6     interface{}    `db:"column_6" json:"column_6"`
+	ExternalID  sql.NullString `db:"external_id" json:"external_id"`
+	Column8     interface{}    `db:"column_8" json:"column_8"`
+	CreatedAt_3 string         `db:"created_at_3" json:"created_at_3"`
+	CreatedAt_4 string         `db:"created_at_4" json:"created_at_4"`
+	ID          int64          `db:"id" json:"id"`
+	Limit       int64          `db:"limit" json:"limit"`
+}
+
+type ListRecordsByCreatedAtRow struct {
+	ID         int64           `db:"id" json:"id"`
+	ProjectID  int64           `db:"project_id" json:"project_id"`
+	Value      int64           `db:"value" json:"value"`
+	Timestamp  string          `db:"timestamp" json:"timestamp"`
+	Source     string          `db:"source" json:"source"`
+	ValueFloat sql.NullFloat64 `db:"value_float" json:"value_float"`
+	CreatedAt  string          `db:"created_at" json:"created_at"`
+	ExternalID sql.NullString  `db:"external_id" json:"external_id"`
+	Tags       interface{}     `db:"tags" json:"tags"`
+}
+
+// Same as ListRecords but filters and orders by created_at (when the record was logged)
+func (q *Queries) ListRecordsByCreatedAt(ctx context.Context, arg ListRecordsByCreatedAtParams) ([]ListRecordsByCreatedAtRow, error) {
+	rows, err := q.db.QueryContext(ctx, listRecordsByCreatedAt,
+		arg.CreatedAt,
+		arg.CreatedAt_2,
+		arg.ProjectID,
+		arg.Column4,
+		arg.Source,
+		arg.Column6,
+		arg.ExternalID,
+		arg.Column8,
+		arg.CreatedAt_3,
+		arg.CreatedAt_4,
+		arg.ID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListRecordsByCreatedAtRow{}
+	for rows.Next() {
+		var i ListRecordsByCreatedAtRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.Value,
+			&i.Timestamp,
+			&i.Source,
+			&i.ValueFloat,
+			&i.CreatedAt,
+			&i.ExternalID,
+			&i.Tags,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRecordsByCreatedAtAsc = `-- name: ListRecordsByCreatedAtAsc :many
+SELECT
+    r.id,
+    r.project_id,
+    r.value,
+    r.timestamp,
+    r.source,
+    r.value_float,
+    r.created_at,
+    r.external_id,
+    COALESCE((
+        SELECT GROUP_CONCAT(tag, ' ')
+        FROM (
+            SELECT tag
+            FROM tags
+            WHERE record_id = r.id
+            ORDER BY order_index
+        )
+    ), '') as tags
+FROM records r
+WHERE r.created_at BETWEEN ? AND ? AND r.project_id = ? AND r.deleted_at IS NULL
+  AND (? = '' OR r.source = ?)
+  AND (? = '' OR r.external_id = ?)
+  AND (? IS NULL OR r.created_at > ? OR (r.created_at = ? AND r.id > ?))
+ORDER BY r.created_at, r.id
+LIMIT ?
+`
+
+type ListRecordsByCreatedAtAscParams struct {
+	CreatedAt   string         `db:"created_at" json:"created_at"`
+	CreatedAt_2 string         `db:"created_at_2" json:"created_at_2"`
+	ProjectID   int64          `db:"project_id" json:"project_id"`
+	Column4     interface{}    `db:"column_4" json:"column_4"`
+	Source      string         `db:"source" json:"source"`
+	Column6     interface{}    `db:"column_6" json:"column_6"`
+	ExternalID  sql.NullString `db:"external_id" json:"external_id"`
+	Column8     interface{}    `db:"column_8" json:"column_8"`
+	CreatedAt_3 string         `db:"created_at_3" json:"created_at_3"`
+	CreatedAt_4 string         `db:"created_at_4" json:"created_at_4"`
+	ID          int64          `db:"id" json:"id"`
+	Limit       int64          `db:"limit" json:"limit"`
+}
+
+type ListRecordsByCreatedAtAscRow struct {
+	ID         int64           `db:"id" json:"id"`
+	ProjectID  int64           `db:"project_id" json:"project_id"`
+	Value      int64           `db:"value" json:"value"`
+	Timestamp  string          `db:"timestamp" json:"timestamp"`
+	Source     string          `db:"source" json:"source"`
+	ValueFloat sql.NullFloat64 `db:"value_float" json:"value_float"`
+	CreatedAt  string          `db:"created_at" json:"created_at"`
+	ExternalID sql.NullString  `db:"external_id" json:"external_id"`
+	Tags       interface{}     `db:"tags" json:"tags"`
+}
+
+// Same as ListRecordsByCreatedAt but oldest first
+func (q *Queries) ListRecordsByCreatedAtAsc(ctx context.Context, arg ListRecordsByCreatedAtAscParams) ([]ListRecordsByCreatedAtAscRow, error) {
+	rows, err := q.db.QueryContext(ctx, listRecordsByCreatedAtAsc,
+		arg.CreatedAt,
+		arg.CreatedAt_2,
+		arg.ProjectID,
+		arg.Column4,
+		arg.Source,
+		arg.Column6,
+		arg.ExternalID,
+		arg.Column8,
+		arg.CreatedAt_3,
+		arg.CreatedAt_4,
+		arg.ID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListRecordsByCreatedAtAscRow{}
+	for rows.Next() {
+		var i ListRecordsByCreatedAtAscRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.Value,
+			&i.Timestamp,
+			&i.Source,
+			&i.ValueFloat,
+			&i.CreatedAt,
+			&i.ExternalID,
+			&i.Tags,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRecordsForDay = `-- name: ListRecordsForDay :many
+SELECT
+    r.id,
+    r.project_id,
+    r.value,
+    r.timestamp,
+    r.source,
+    r.value_float,
+    r.created_at,
+    r.external_id,
+    COALESCE((
+        SELECT GROUP_CONCAT(tag, ' ')
+        FROM (
+            SELECT tag
+            FROM tags
+            WHERE record_id = r.id
+            ORDER BY order_index
+        )
+    ), '') as tags
+FROM records r
+WHERE r.project_id = ? AND r.timestamp >= ? AND r.timestamp < ? AND r.deleted_at IS NULL
+ORDER BY r.timestamp, r.id
+`
+
+type ListRecordsForDayParams struct {
+	ProjectID   int64  `db:"project_id" json:"project_id"`
+	Timestamp   string `db:"timestamp" json:"timestamp"`
+	Timestamp_2 string `db:"timestamp_2" json:"timestamp_2"`
+}
+
+type ListRecordsForDayRow struct {
+	ID         int64           `db:"id" json:"id"`
+	ProjectID  int64           `db:"project_id" json:"project_id"`
+	Value      int64           `db:"value" json:"value"`
+	Timestamp  string          `db:"timestamp" json:"timestamp"`
+	Source     string          `db:"source" json:"source"`
+	ValueFloat sql.NullFloat64 `db:"value_float" json:"value_float"`
+	CreatedAt  string          `db:"created_at" json:"created_at"`
+	ExternalID sql.NullString  `db:"external_id" json:"external_id"`
+	Tags       interface{}     `db:"tags" json:"tags"`
+}
+
+// All records of a project in the half-open range [day_start, day_end) oldest first,
+// served by idx_records_project_id_timestamp
+func (q *Queries) ListRecordsForDay(ctx context.Context, arg ListRecordsForDayParams) ([]ListRecordsForDayRow, error) {
+	rows, err := q.db.QueryContext(ctx, listRecordsForDay, arg.ProjectID, arg.Timestamp, arg.Timestamp_2)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListRecordsForDayRow{}
+	for rows.Next() {
+		var i ListRecordsForDayRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.Value,
+			&i.Timestamp,
+			&i.Source,
+			&i.ValueFloat,
+			&i.CreatedAt,
+			&i.ExternalID,
+			&i.Tags,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRecordsForDayWithTags = `-- name: ListRecordsForDayWithTags :many
+SELECT
+    r.id,
+    r.project_id,
+    r.value,
+    r.timestamp,
+    r.source,
+    r.value_float,
+    r.created_at,
+    r.external_id,
+    COALESCE((
+        SELECT GROUP_CONCAT(tag, ' ')
+        FROM (
+            SELECT tag
+            FROM tags
+            WHERE record_id = r.id
+            ORDER BY order_index
+        )
+    ), '') as tags
+FROM records r
+INNER JOIN tags t ON r.id = t.record_id
+WHERE r.project_id = ? AND r.timestamp >= ? AND r.timestamp < ? AND r.deleted_at IS NULL
+  AND t.tag IN (/*SLICE:tags*/?)
+GROUP BY r.id, r.project_id, r.value, r.timestamp, r.source, r.value_float, r.created_at, r.external_id
+HAVING COUNT(DISTINCT t.tag) = CAST(? AS INTEGER)
+ORDER BY r.timestamp, r.id
+`
+
+type ListRecordsForDayWithTagsParams struct {
+	ProjectID   int64    `db:"project_id" json:"project_id"`
+	Timestamp   string   `db:"timestamp" json:"timestamp"`
+	Timestamp_2 string   `db:"timestamp_2" json:"timestamp_2"`
+	Tags        []string `db:"tags" json:"tags"`
+	Column5     int64    `db:"column_5" json:"column_5"`
+}
+
+type ListRecordsForDayWithTagsRow struct {
+	ID         int64           `db:"id" json:"id"`
+	ProjectID  int64           `db:"project_id" json:"project_id"`
+	Value      int64           `db:"value" json:"value"`
+	Timestamp  string          `db:"timestamp" json:"timestamp"`
+	Source     string          `db:"source" json:"source"`
+	ValueFloat sql.NullFloat64 `db:"value_float" json:"value_float"`
+	CreatedAt  string          `db:"created_at" json:"created_at"`
+	ExternalID sql.NullString  `db:"external_id" json:"external_id"`
+	Tags       interface{}     `db:"tags" json:"tags"`
+}
+
+// Same as ListRecordsForDay but only records that have all of the specified tags
+func (q *Queries) ListRecordsForDayWithTags(ctx context.Context, arg ListRecordsForDayWithTagsParams) ([]ListRecordsForDayWithTagsRow, error) {
+	query := listRecordsForDayWithTags
+	var queryParams []interface{}
+	queryParams = append(queryParams, arg.ProjectID)
+	queryParams = append(queryParams, arg.Timestamp)
+	queryParams = append(queryParams, arg.Timestamp_2)
+	if len(arg.Tags) > 0 {
+		for _, v := range arg.Tags {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:tags*/?", strings.Repeat(",?", len(arg.Tags))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:tags*/?", "NULL", 1)
+	}
+	queryParams = append(queryParams, arg.Column5)
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListRecordsForDayWithTagsRow{}
+	for rows.Next() {
+		var i ListRecordsForDayWithTagsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.Value,
+			&i.Timestamp,
+			&i.Source,
+			&i.ValueFloat,
+			&i.CreatedAt,
+			&i.ExternalID,
+			&i.Tags,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRecordsWithTags = `-- name: ListRecordsWithTags :many
+SELECT
+    r.id,
+    r.project_id,
+    r.value,
+    r.timestamp,
+    r.source,
+    r.value_float,
+    r.created_at,
+    r.external_id,
+    COALESCE((
+        SELECT GROUP_CONCAT(tag, ' ')
+        FROM (
+            SELECT tag
+            FROM tags
+            WHERE record_id = r.id
+            ORDER BY order_index
+        )
+    ), '') as all_tags
+FROM records r
+INNER JOIN tags t ON r.id = t.record_id
+WHERE r.timestamp BETWEEN ? AND ? AND r.project_id = ? AND r.deleted_at IS NULL
+  AND t.tag IN (/*SLICE:tags*/?)
+  AND (? = '' OR r.source = ?)
+  AND (? = '' OR r.external_id = ?)
+  AND (? IS NULL OR r.timestamp < ? OR (r.timestamp = ? AND r.id > ?))
+GROUP BY r.id, r.project_id, r.value, r.timestamp, r.source, r.value_float, r.created_at, r.external_id
+HAVING COUNT(DISTINCT t.tag) = CAST(? AS INTEGER)
+ORDER BY r.timestamp DESC, r.id
+LIMIT ?
+`
+
+type ListRecordsWithTagsParams struct {
+	Timestamp   string         `db:"timestamp" json:"timestamp"`
+	Timestamp_2 string         `db:"timestamp_2" json:"timestamp_2"`
+	ProjectID   int64          `db:"project_id" json:"project_id"`
+	Tags        []string       `db:"tags" json:"tags"`
+	Column5     interface{}    `db:"column_5" json:"column_5"`
+	Source      string         `db:"source" json:"source"`
+	Column7     interface{}    `db:"column_7" json:"column_7"`
+	ExternalID  sql.NullString `db:"external_id" json:"external_id"`
+	Column9     interface{}    `db:"column_9" json:"column_9"`
+	Timestamp_3 string         `db:"timestamp_3" json:"timestamp_3"`
+	Timestamp_4 string         `db:"timestamp_4" json:"timestamp_4"`
+	ID          int64          `db:"id" json:"id"`
+	Column13    int64          `db:"column_13" json:"column_13"`
+	Limit       int64          `db:"limit" json:"limit"`
+}
+
+type ListRecordsWithTagsRow struct {
+	ID         int64           `db:"id" json:"id"`
+	ProjectID  int64           `db:"project_id" json:"project_id"`
+	Value      int64           `db:"value" json:"value"`
+	Timestamp  string          `db:"timestamp" json:"timestamp"`
+	Source     string          `db:"source" json:"source"`
+	ValueFloat sql.NullFloat64 `db:"value_float" json:"value_float"`
+	CreatedAt  string          `db:"created_at" json:"created_at"`
+	ExternalID sql.NullString  `db:"external_id" json:"external_id"`
+	AllTags    interface{}     `db:"all_tags" json:"all_tags"`
+}
+
+// Note: BETWEEN clause must come first due to sqlc bug with SQLite parameter handling
+// Returns records that have all of the specified tags
+// Optimized query to avoid n+1 problem by using GROUP_CONCAT for all tags
+// Cursor-based pagination: uses cursor_timestamp and cursor_id for pagination
+func (q *Queries) ListRecordsWithTags(ctx context.Context, arg ListRecordsWithTagsParams) ([]ListRecordsWithTagsRow, error) {
+	query := listRecordsWithTags
+	var queryParams []interface{}
+	queryParams = append(queryParams, arg.Timestamp)
+	queryParams = append(queryParams, arg.Timestamp_2)
+	queryParams = append(queryParams, arg.ProjectID)
+	if len(arg.Tags) > 0 {
+		for _, v := range arg.Tags {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:tags*/?", strings.Repeat(",?", len(arg.Tags))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:tags*/?", "NULL", 1)
+	}
+	queryParams = append(queryParams, arg.Column5)
+	queryParams = append(queryParams, arg.Source)
+	queryParams = append(queryParams, arg.Column7)
+	queryParams = append(queryParams, arg.ExternalID)
+	queryParams = append(queryParams, arg.Column9)
+	queryParams = append(queryParams, arg.Timestamp_3)
+	queryParams = append(queryParams, arg.Timestamp_4)
+	queryParams = append(queryParams, arg.ID)
+	queryParams = append(queryParams, arg.Column13)
+	queryParams = append(queryParams, arg.Limit)
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListRecordsWithTagsRow{}
+	for rows.Next() {
+		var i ListRecordsWithTagsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.Value,
+			&i.Timestamp,
+			&i.Source,
+			&i.ValueFloat,
+			&i.CreatedAt,
+			&i.ExternalID,
+			&i.AllTags,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRecordsWithTagsAsc = `-- name: ListRecordsWithTagsAsc :many
+SELECT
+    r.id,
+    r.project_id,
+    r.value,
+    r.timestamp,
+    r.source,
+    r.value_float,
+    r.created_at,
+    r.external_id,
+    COALESCE((
+        SELECT GROUP_CONCAT(tag, ' ')
+        FROM (
+            SELECT tag
+            FROM tags
+            WHERE record_id = r.id
+            ORDER BY order_index
+        )
+    ), '') as all_tags
+FROM records r
+INNER JOIN tags t ON r.id = t.record_id
+WHERE r.timestamp BETWEEN ? AND ? AND r.project_id = ? AND r.deleted_at IS NULL
+  AND t.tag IN (/*SLICE:tags*/?)
+  AND (? = '' OR r.source = ?)
+  AND (? = '' OR r.external_id = ?)
+  AND (? IS NULL OR r.timestamp > ? OR (r.timestamp = ? AND r.id > ?))
+GROUP BY r.id, r.project_id, r.value, r.timestamp, r.source, r.value_float, r.created_at, r.external_id
+HAVING COUNT(DISTINCT t.tag) = CAST(? AS INTEGER)
+ORDER BY r.timestamp, r.id
+LIMIT ?
+`
+
+type ListRecordsWithTagsAscParams struct {
+	Timestamp   string         `db:"timestamp" json:"timestamp"`
+	Timestamp_2 string         `db:"timestamp_2" json:"timestamp_2"`
+	ProjectID   int64          `db:"project_id" json:"project_id"`
+	Tags        []string       `db:"tags" json:"tags"`
+	Column5     interface{}    `db:"column_5" json:"column_5"`
+	Source      string         `db:"source" json:"source"`
+	Column7     interface{}    `db:"column_7" json:"column_7"`
+	ExternalID  sql.NullString `db:"external_id" json:"external_id"`
+	Column9     interface{}    `db:"column_9" json:"column_9"`
+	Timestamp_3 string         `db:"timestamp_3" json:"timestamp_3"`
+	Timestamp_4 string         `db:"timestamp_4" json:"timestamp_4"`
+	ID          int64          `db:"id" json:"id"`
+	Column13    int64          `db:"column_13" json:"column_13"`
+	Limit       int64          `db:"limit" json:"limit"`
+}
+
+type ListRecordsWithTagsAscRow struct {
+	ID         int64           `db:"id" json:"id"`
+	ProjectID  int64           `db:"project_id" json:"project_id"`
+	Value      int64           `db:"value" json:"value"`
+	Timestamp  string          `db:"timestamp" json:"timestamp"`
+	Source     string          `db:"source" json:"source"`
+	ValueFloat sql.NullFloat64 `db:"value_float" json:"value_float"`
+	CreatedAt  string          `db:"created_at" json:"created_at"`
+	ExternalID sql.NullString  `db:"external_id" json:"external_id"`
+	AllTags    interface{}     `db:"all_tags" json:"all_tags"`
+}
+
+// Same as ListRecordsWithTags but oldest first; the cursor advances towards newer records
+func (q *Queries) ListRecordsWithTagsAsc(ctx context.Context, arg ListRecordsWithTagsAscParams) ([]ListRecordsWithTagsAscRow, error) {
+	query := listRecordsWithTagsAsc
+	var queryParams []interface{}
+	queryParams = append(queryParams, arg.Timestamp)
+	queryParams = append(queryParams, arg.Timestamp_2)
+	queryParams = append(queryParams, arg.ProjectID)
+	if len(arg.Tags) > 0 {
+		for _, v := range arg.Tags {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:tags*/?", strings.Repeat(",?", len(arg.Tags))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:tags*/?", "NULL", 1)
+	}
+	queryParams = append(queryParams, arg.Column5)
+	queryParams = append(queryParams, arg.Source)
+	queryParams = append(queryParams, arg.Column7)
+	queryParams = append(queryParams, arg.ExternalID)
+	queryParams = append(queryParams, arg.Column9)
+	queryParams = append(queryParams, arg.Timestamp_3)
+	queryParams = append(queryParams, arg.Timestamp_4)
+	queryParams = append(queryParams, arg.ID)
+	queryParams = append(queryParams, arg.Column13)
+	queryParams = append(queryParams, arg.Limit)
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListRecordsWithTagsAscRow{}
+	for rows.Next() {
+		var i ListRecordsWithTagsAscRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.Value,
+			&i.Timestamp,
+			&i.Source,
+			&i.ValueFloat,
+			&i.CreatedAt,
+			&i.ExternalID,
+			&i.AllTags,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRecordsWithTagsByCreatedAt = `-- name: ListRecordsWithTagsByCreatedAt :many
+SELECT
+    r.id,
+    r.project_id,
+    r.value,
+    r.timestamp,
+    r.source,
+    r.value_float,
+    r.created_at,
+    r.external_id,
+    COALESCE((
+        SELECT GROUP_CONCAT(tag, ' ')
+        FROM (
+            SELECT tag
+            FROM tags
+            WHERE record_id = r.id
+            ORDER BY order_index
+        )
+    ), '') as all_tags
+FROM records r
+INNER JOIN tags t ON r.id = t.record_id
+WHERE r.created_at BETWEEN ? AND ? AND r.project_id = ? AND r.deleted_at IS NULL
+  AND t.tag IN (/*SLICE:tags*/?)
+  AND (? = '' OR r.source = ?)
+  AND (? = '' OR r.external_id = ?)
+  AND (? IS NULL OR r.created_at < ? OR (r.created_at = ? AND r.id > ?))
+GROUP BY r.id, r.project_id, r.value, r.timestamp, r.source, r.value_float, r.created_at, r.external_id
+HAVING COUNT(DISTINCT t.tag) = CAST(? AS INTEGER)
+ORDER BY r.created_at DESC, r.id
+LIMIT ?
+`
+
+type ListRecordsWithTagsByCreatedAtParams struct {
+	CreatedAt   string         `db:"created_at" json:"created_at"`
+	CreatedAt_2 string         `db:"created_at_2" json:"created_at_2"`
+	ProjectID   int64          `db:"project_id" json:"project_id"`
+	Tags        []string       `db:"tags" json:"tags"`
+	Column5     interface{}    `db:"column_5" json:"column_5"`
+	Source      string         `db:"source" json:"source"`
+	Column7     interface{}    `db:"column_7" json:"column_7"`
+	ExternalID  sql.NullString `db:"external_id" json:"external_id"`
+	Column9     interface{}    `db:"column_9" json:"column_9"`
+	CreatedAt_3 string         `db:"created_at_3" json:"created_at_3"`
+	CreatedAt_4 string         `db:"created_at_4" json:"created_at_4"`
+	ID          int64          `db:"id" json:"id"`
+	Column13    int64          `db:"column_13" json:"column_13"`
+	Limit       int64          `db:"limit" json:"limit"`
+}
+
+type ListRecordsWithTagsByCreatedAtRow struct {
+	ID         int64           `db:"id" json:"id"`
+	ProjectID  int64           `db:"project_id" json:"project_id"`
+	Value      int64           `db:"value" json:"value"`
+	Timestamp  string          `db:"timestamp" json:"timestamp"`
+	Source     string          `db:"source" json:"source"`
+	ValueFloat sql.NullFloat64 `db:"value_float" json:"value_float"`
+	CreatedAt  string          `db:"created_at" json:"created_at"`
+	ExternalID sql.NullString  `db:"external_id" json:"external_id"`
+	AllTags    interface{}     `db:"all_tags" json:"all_tags"`
+}
+
+// Same as ListRecordsWithTags but filters and orders by created_at
+func (q *Queries) ListRecordsWithTagsByCreatedAt(ctx context.Context, arg ListRecordsWithTagsByCreatedAtParams) ([]ListRecordsWithTagsByCreatedAtRow, error) {
+	query := listRecordsWithTagsByCreatedAt
+	var queryParams []interface{}
+	queryParams = append(queryParams, arg.CreatedAt)
+	queryParams = append(queryParams, arg.CreatedAt_2)
+	queryParams = append(queryParams, arg.ProjectID)
+	if len(arg.Tags) > 0 {
+		for _, v := range arg.Tags {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:tags*/?", strings.Repeat(",?", len(arg.Tags))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:tags*/?", "NULL", 1)
+	}
+	queryParams = append(queryParams, arg.Column5)
+	queryParams = append(queryParams, arg.Source)
+	queryParams = append(queryParams, arg.Column7)
+	queryParams = append(queryParams, arg.ExternalID)
+	queryParams = append(queryParams, arg.Column9)
+	queryParams = append(queryParams, arg.CreatedAt_3)
+	queryParams = append(queryParams, arg.CreatedAt_4)
+	queryParams = append(queryParams, arg.ID)
+	queryParams = append(queryParams, arg.Column13)
+	queryParams = append(queryParams, arg.Limit)
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListRecordsWithTagsByCreatedAtRow{}
+	for rows.Next() {
+		var i ListRecordsWithTagsByCreatedAtRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.Value,
+			&i.Timestamp,
+			&i.Source,
+			&i.ValueFloat,
+			&i.CreatedAt,
+			&i.ExternalID,
+			&i.AllTags,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRecordsWithTagsByCreatedAtAsc = `-- name: ListRecordsWithTagsByCreatedAtAsc :many
+SELECT
+    r.id,
+    r.project_id,
+    r.value,
+    r.timestamp,
+    r.source,
+    r.value_float,
+    r.created_at,
+    r.external_id,
+    COALESCE((
+        SELECT GROUP_CONCAT(tag, ' ')
+        FROM (
+            SELECT tag
+            FROM tags
+            WHERE record_id = r.id
+            ORDER BY order_index
+        )
+    ), '') as all_tags
+FROM records r
+INNER JOIN tags t ON r.id = t.record_id
+WHERE r.created_at BETWEEN ? AND ? AND r.project_id = ? AND r.deleted_at IS NULL
+  AND t.tag IN (/*SLICE:tags*/?)
+  AND (? = '' OR r.source = ?)
+  AND (? = '' OR r.external_id = ?)
+  AND (? IS NULL OR r.created_at > ? OR (r.created_at = ? AND r.id > ?))
+GROUP BY r.id, r.project_id, r.value, r.timestamp, r.source, r.value_float, r.created_at, r.external_id
+HAVING COUNT(DISTINCT t.tag) = CAST(? AS INTEGER)
+ORDER BY r.created_at, r.id
+LIMIT ?
+`
+
+type ListRecordsWithTagsByCreatedAtAscParams struct {
+	CreatedAt   string         `db:"created_at" json:"created_at"`
+	CreatedAt_2 string         `db:"created_at_2" json:"created_at_2"`
+	ProjectID   int64          `db:"project_id" json:"project_id"`
+	Tags        []string       `db:"tags" json:"tags"`
+	Column5     interface{}    `db:"column_5" json:"column_5"`
+	Source      string         `db:"source" json:"source"`
+	Column7     interface{}    `db:"column_7" json:"column_7"`
+	ExternalID  sql.NullString `db:"external_id" json:"external_id"`
+	Column9     interface{}    `db:"column_9" json:"column_9"`
+	CreatedAt_3 string         `db:"created_at_3" json:"created_at_3"`
+	CreatedAt_4 string         `db:"created_at_4" json:"created_at_4"`
+	ID          int64          `db:"id" json:"id"`
+	Column13    int64          `db:"column_13" json:"column_13"`
+	Limit       int64          `db:"limit" json:"limit"`
+}
+
+type ListRecordsWithTagsByCreatedAtAscRow struct {
+	ID         int64           `db:"id" json:"id"`
+	ProjectID  int64           `db:"project_id" json:"project_id"`
+	Value      int64           `db:"value" json:"value"`
+	Timestamp  string          `db:"timestamp" json:"timestamp"`
+	Source     string          `db:"source" json:"source"`
+	ValueFloat sql.NullFloat64 `db:"value_float" json:"value_float"`
+	CreatedAt  string          `db:"created_at" json:"created_at"`
+	ExternalID sql.NullString  `db:"external_id" json:"external_id"`
+	AllTags    interface{}     `db:"all_tags" json:"all_tags"`
+}
+
+// Same as ListRecordsWithTagsByCreatedAt but oldest first
+func (q *Queries) ListRecordsWithTagsByCreatedAtAsc(ctx context.Context, arg ListRecordsWithTagsByCreatedAtAscParams) ([]ListRecordsWithTagsByCreatedAtAscRow, error) {
+	query := listRecordsWithTagsByCreatedAtAsc
+	var queryParams []interface{}
+	queryParams = append(queryParams, arg.CreatedAt)
+	queryParams = append(queryParams, arg.CreatedAt_2)
+	queryParams = append(queryParams, arg.ProjectID)
+	if len(arg.Tags) > 0 {
+		for _, v := range arg.Tags {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:tags*/?", strings.Repeat(",?", len(arg.Tags))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:tags*/?", "NULL", 1)
+	}
+	queryParams = append(queryParams, arg.Column5)
+	queryParams = append(queryParams, arg.Source)
+	queryParams = append(queryParams, arg.Column7)
+	queryParams = append(queryParams, arg.ExternalID)
+	queryParams = append(queryParams, arg.Column9)
+	queryParams = append(queryParams, arg.CreatedAt_3)
+	queryParams = append(queryParams, arg.CreatedAt_4)
+	queryParams = append(queryParams, arg.ID)
+	queryParams = append(queryParams, arg.Column13)
+	queryParams = append(queryParams, arg.Limit)
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListRecordsWithTagsByCreatedAtAscRow{}
+	for rows.Next() {
+		var i ListRecordsWithTagsByCreatedAtAscRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.ProjectID,
 			&i.Value,
 			&i.Timestamp,
+			&i.Source,
+			&i.ValueFloat,
+			&i.CreatedAt,
+			&i.ExternalID,
 			&i.AllTags,
 		); err != nil {
 			return nil, err
@@ -441,16 +2235,114 @@ func (q *Queries) ListRecordsWithTags(ctx context.Context, arg ListRecordsWithTa
 	return items, nil
 }
 
+const listTagAliases = `-- name: ListTagAliases :many
+SELECT alias, tag
+FROM tag_aliases
+WHERE project_id = ?
+ORDER BY alias
+`
+
+type ListTagAliasesRow struct {
+	Alias string `db:"alias" json:"alias"`
+	Tag   string `db:"tag" json:"tag"`
+}
+
+func (q *Queries) ListTagAliases(ctx context.Context, projectID int64) ([]ListTagAliasesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listTagAliases, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListTagAliasesRow{}
+	for rows.Next() {
+		var i ListTagAliasesRow
+		if err := rows.Scan(&i.Alias, &i.Tag); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const projectExists = `-- name: ProjectExists :one
+SELECT EXISTS (SELECT 1 FROM projects WHERE id = ?)
+`
+
+func (q *Queries) ProjectExists(ctx context.Context, id int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, projectExists, id)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const projectNameTakenIgnoringCase = `-- name: ProjectNameTakenIgnoringCase :one
+SELECT EXISTS(SELECT 1 FROM projects WHERE name = ? COLLATE NOCASE AND id != ?)
+`
+
+type ProjectNameTakenIgnoringCaseParams struct {
+	Name string `db:"name" json:"name"`
+	ID   int64  `db:"id" json:"id"`
+}
+
+// Whether another project has the same name ignoring ASCII case (case-insensitive project names)
+func (q *Queries) ProjectNameTakenIgnoringCase(ctx context.Context, arg ProjectNameTakenIgnoringCaseParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, projectNameTakenIgnoringCase, arg.Name, arg.ID)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const purgeRecord = `-- name: PurgeRecord :execresult
+DELETE FROM records WHERE id = ?
+`
+
+func (q *Queries) PurgeRecord(ctx context.Context, id int64) (sql.Result, error) {
+	return q.db.ExecContext(ctx, purgeRecord, id)
+}
+
+const recordExists = `-- name: RecordExists :one
+SELECT EXISTS (SELECT 1 FROM records WHERE id = ? AND deleted_at IS NULL)
+`
+
+func (q *Queries) RecordExists(ctx context.Context, id int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, recordExists, id)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const restoreRecord = `-- name: RestoreRecord :execresult
+UPDATE records SET deleted_at = NULL
+WHERE id = ?
+`
+
+func (q *Queries) RestoreRecord(ctx context.Context, id int64) (sql.Result, error) {
+	return q.db.ExecContext(ctx, restoreRecord, id)
+}
+
 const updateProject = `-- name: UpdateProject :execresult
-UPDATE projects SET name = ?, description = ?, updated_at = ?
+UPDATE projects SET name = ?, description = ?, updated_at = ?, track_default_value = ?, color = ?, value_type = ?, retention_days = ?, min_value = ?, max_value = ?, timezone = ?
 WHERE id = ?
 `
 
 type UpdateProjectParams struct {
-	Name        string `db:"name" json:"name"`
-	Description string `db:"description" json:"description"`
-	UpdatedAt   string `db:"updated_at" json:"updated_at"`
-	ID          int64  `db:"id" json:"id"`
+	Name              string          `db:"name" json:"name"`
+	Description       string          `db:"description" json:"description"`
+	UpdatedAt         string          `db:"updated_at" json:"updated_at"`
+	TrackDefaultValue sql.NullInt64   `db:"track_default_value" json:"track_default_value"`
+	Color             sql.NullString  `db:"color" json:"color"`
+	ValueType         string          `db:"value_type" json:"value_type"`
+	RetentionDays     sql.NullInt64   `db:"retention_days" json:"retention_days"`
+	MinValue          sql.NullFloat64 `db:"min_value" json:"min_value"`
+	MaxValue          sql.NullFloat64 `db:"max_value" json:"max_value"`
+	Timezone          sql.NullString  `db:"timezone" json:"timezone"`
+	ID                int64           `db:"id" json:"id"`
 }
 
 func (q *Queries) UpdateProject(ctx context.Context, arg UpdateProjectParams) (sql.Result, error) {
@@ -458,20 +2350,28 @@ func (q *Queries) UpdateProject(ctx context.Context, arg UpdateProjectParams) (s
 		arg.Name,
 		arg.Description,
 		arg.UpdatedAt,
+		arg.TrackDefaultValue,
+		arg.Color,
+		arg.ValueType,
+		arg.RetentionDays,
+		arg.MinValue,
+		arg.MaxValue,
+		arg.Timezone,
 		arg.ID,
 	)
 }
 
 const updateRecord = `-- name: UpdateRecord :execresult
-UPDATE records SET project_id = ?, value = ?, timestamp = ?
-WHERE id = ?
+UPDATE records SET project_id = ?, value = ?, timestamp = ?, value_float = ?
+WHERE id = ? AND deleted_at IS NULL
 `
 
 type UpdateRecordParams struct {
-	ProjectID int64  `db:"project_id" json:"project_id"`
-	Value     int64  `db:"value" json:"value"`
-	Timestamp string `db:"timestamp" json:"timestamp"`
-	ID        int64  `db:"id" json:"id"`
+	ProjectID  int64           `db:"project_id" json:"project_id"`
+	Value      int64           `db:"value" json:"value"`
+	Timestamp  string          `db:"timestamp" json:"timestamp"`
+	ValueFloat sql.NullFloat64 `db:"value_float" json:"value_float"`
+	ID         int64           `db:"id" json:"id"`
 }
 
 func (q *Queries) UpdateRecord(ctx context.Context, arg UpdateRecordParams) (sql.Result, error) {
@@ -479,6 +2379,57 @@ func (q *Queries) UpdateRecord(ctx context.Context, arg UpdateRecordParams) (sql
 		arg.ProjectID,
 		arg.Value,
 		arg.Timestamp,
+		arg.ValueFloat,
+		arg.ID,
+	)
+}
+
+const updateRecordWithIncrement = `-- name: UpdateRecordWithIncrement :one
+UPDATE records SET project_id = ?1, timestamp = ?2, value = value + ?3
+WHERE id = ?4 AND deleted_at IS NULL
+  AND value + ?3 >= 1
+  AND (?5 IS NULL OR value + ?3 >= CAST(?5 AS REAL))
+  AND (?6 IS NULL OR value + ?3 <= CAST(?6 AS REAL))
+RETURNING value
+`
+
+type UpdateRecordWithIncrementParams struct {
+	ProjectID int64       `db:"project_id" json:"project_id"`
+	Timestamp string      `db:"timestamp" json:"timestamp"`
+	Delta     int64       `db:"delta" json:"delta"`
+	ID        int64       `db:"id" json:"id"`
+	MinValue  interface{} `db:"min_value" json:"min_value"`
+	MaxValue  interface{} `db:"max_value" json:"max_value"`
+}
+
+// Same as IncrementRecordValue, also updating the other columns in the same statement
+func (q *Queries) UpdateRecordWithIncrement(ctx context.Context, arg UpdateRecordWithIncrementParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, updateRecordWithIncrement,
+		arg.ProjectID,
+		arg.Timestamp,
+		arg.Delta,
 		arg.ID,
+		arg.MinValue,
+		arg.MaxValue,
 	)
+	var value int64
+	err := row.Scan(&value)
+	return value, err
+}
+
+const upsertTagAlias = `-- name: UpsertTagAlias :exec
+INSERT INTO tag_aliases (project_id, alias, tag)
+VALUES (?, ?, ?)
+ON CONFLICT (project_id, alias) DO UPDATE SET tag = excluded.tag
+`
+
+type UpsertTagAliasParams struct {
+	ProjectID int64  `db:"project_id" json:"project_id"`
+	Alias     string `db:"alias" json:"alias"`
+	Tag       string `db:"tag" json:"tag"`
+}
+
+func (q *Queries) UpsertTagAlias(ctx context.Context, arg UpsertTagAliasParams) error {
+	_, err := q.db.ExecContext(ctx, upsertTagAlias, arg.ProjectID, arg.Alias, arg.Tag)
+	return err
 }
@@ -1,24 +1,331 @@
 package heatmap
 
 import (
+	"fmt"
+	"html"
+	"image/color"
+	"strconv"
+	"strings"
 	"time"
 )
 
+// DefaultColors is the default 6-level palette: gray for zero followed by five greens.
+var DefaultColors = []string{"#f0f0f0", "#c6e48b", "#7bc96f", "#239a3b", "#196127", "#0d4429"}
+
 // Data holds the date and value for each day.
 type Data struct {
 	Date  time.Time
-	Value int
+	Value float64 // may be fractional for projects with float values
 }
 
 // Options configures rendering parameters.
 type Options struct {
 	CellSize    int       // size of each day cell (px)
 	CellPadding int       // padding between cells (px)
+	CellRadius  int       // corner radius of each cell (px, 0 means square cells)
+	MonthGap    int       // extra space before the first week column of each month in the yearly view (px)
 	Colors      []string  // array of N CSS colors for levels 0..N-1
 	FontSize    int       // font size for month labels (px)
-	FontFamily  string    // font family for labels
+	FontFamily  string    // font family for labels (empty means DefaultFontFamily)
+	FontDataURI string    // font file as a data URI (e.g. "data:font/woff2;base64,...") embedded with @font-face
 	ProjectName string    // project name for title
 	Tags        []string  // tags filter for title
 	From        time.Time // start date for rendering (required)
 	To          time.Time // end date for rendering (required)
+
+	Now            time.Time // current time used to determine "today" (zero means time.Now())
+	HighlightToday bool      // outline today's cell
+	Responsive     bool      // scale to the container width (viewBox with width="100%") instead of fixed pixels
+
+	MinNonZeroLevel int // lowest level for non-zero values, so sparse activity stays visible (0 means no minimum)
+
+	ShowValues bool // print each non-zero value inside its cell (skipped when CellSize is below minValueCellSize)
+
+	MaxWidth  int // shrink CellSize/CellPadding so the SVG is at most this wide (px, 0 means no limit)
+	MaxHeight int // shrink CellSize/CellPadding so the SVG is at most this tall (px, 0 means no limit)
+
+	FormatNumbers bool   // group digits with thousands separators in tooltips and cell values (e.g. "12,345")
+	Locale        string // BCP 47 language tag selecting the separators for FormatNumbers (empty means "en")
+
+	CellStroke      string // border color drawn around every cell (empty means borderless cells)
+	CellStrokeWidth int    // border width for CellStroke (px, 0 means 1)
+
+	WeekendTint string // hex color blended into Saturday and Sunday cells of the yearly view, which also get data-weekend="true" (empty means no tint)
+
+	ClampToNow bool // end the grid at Now instead of To when To is in the future (never before From)
+
+	// Gradient colors non-zero cells on a continuous scale instead of by level:
+	// each fill is interpolated in RGB from GradientStart to GradientEnd by the value
+	// relative to the auto-scaling maximum. Zero keeps the level 0 color, and levels
+	// (data-level, the legend) are still computed as usual.
+	Gradient      bool
+	GradientStart string // hex color of the smallest values (empty means the level 1 color)
+	GradientEnd   string // hex color of the largest value (empty means the last palette color)
+
+	// Experimental: color each non-zero day of the yearly view and the sparkline by its
+	// dominant tag instead of by intensity. Days without a dominant tag, or whose tag has
+	// no color, keep the intensity color. The weekly view ignores these.
+	TagColors map[string]string // CSS color of each tag (see AssignTagColors)
+	DayTags   map[string]string // dominant tag of each day keyed by "2006-01-02" (see DominantTags)
+}
+
+// DefaultFontFamily is a cross-platform sans-serif font stack used when FontFamily is empty.
+const DefaultFontFamily = `-apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif`
+
+// embeddedFontFamily is the font-family name of the font embedded from FontDataURI.
+const embeddedFontFamily = "sougen-embedded"
+
+// todayStrokeColor is the outline color for today's cell.
+const todayStrokeColor = "#333"
+
+// palette returns the colors to render with.
+// Level 0 is reserved for zero values, so at least 2 colors are required;
+// shorter palettes fall back to DefaultColors.
+func (o *Options) palette() []string {
+	if len(o.Colors) < 2 {
+		return DefaultColors
+	}
+	return o.Colors
+}
+
+// cellRadiusAttrs returns the rx/ry attributes for rounded cells, or "" for square cells.
+func (o *Options) cellRadiusAttrs() string {
+	if o.CellRadius <= 0 {
+		return ""
+	}
+	return fmt.Sprintf(` rx="%d" ry="%d"`, o.CellRadius, o.CellRadius)
+}
+
+// cellStrokeAttrs returns the stroke attributes for today's outline or the CellStroke border,
+// or "" for borderless cells. The today outline takes precedence over the border.
+func (o *Options) cellStrokeAttrs(isToday bool) string {
+	if o.HighlightToday && isToday {
+		return fmt.Sprintf(` stroke="%s" stroke-width="1" data-today="true"`, todayStrokeColor)
+	}
+	if o.CellStroke == "" {
+		return ""
+	}
+	width := o.CellStrokeWidth
+	if width <= 0 {
+		width = 1
+	}
+	return fmt.Sprintf(` stroke="%s" stroke-width="%d"`, html.EscapeString(o.CellStroke), width)
+}
+
+// fitted returns options whose CellSize and CellPadding are scaled down proportionally
+// so that size, which computes the SVG dimensions for the given options, stays within
+// MaxWidth and MaxHeight. Cells never shrink below 1px, so the result may still exceed
+// the limits when even 1px cells do not fit. The receiver is not modified.
+func (o *Options) fitted(size func(*Options) (int, int)) *Options {
+	fits := func(opts *Options) bool {
+		width, height := size(opts)
+		return (o.MaxWidth <= 0 || width <= o.MaxWidth) && (o.MaxHeight <= 0 || height <= o.MaxHeight)
+	}
+	if fits(o) || o.CellSize <= 1 {
+		return o
+	}
+
+	fit := *o
+	for cellSize := o.CellSize - 1; cellSize >= 1; cellSize-- {
+		fit.CellSize = cellSize
+		fit.CellPadding = o.CellPadding * cellSize / o.CellSize
+		fit.CellRadius = min(o.CellRadius, cellSize/2)
+		if fits(&fit) {
+			break
+		}
+	}
+	return &fit
+}
+
+// minValueCellSize is the smallest CellSize (px) at which ShowValues prints values;
+// smaller cells cannot fit legible text.
+const minValueCellSize = 16
+
+// valueText returns a <text> element showing value centered in the cell at (x, y),
+// or "" when values are hidden, the value is zero, or the cell is too small.
+func (o *Options) valueText(x, y int, value float64, fill string) string {
+	if !o.ShowValues || value == 0 || o.CellSize < minValueCellSize {
+		return ""
+	}
+	center := o.CellSize / 2
+	return fmt.Sprintf(`  <text x="%d" y="%d" text-anchor="middle" dominant-baseline="central" font-family="%s" font-size="%d" fill="%s" pointer-events="none">%s</text>`+"\n",
+		x+center, y+center, html.EscapeString(o.fontFamily()), o.CellSize/2, contrastColor(fill), o.displayValue(value))
+}
+
+// weekendTintWeight is how strongly WeekendTint is blended into a weekend cell's level color.
+const weekendTintWeight = 0.25
+
+// isWeekend reports whether the date falls on Saturday or Sunday.
+func isWeekend(date time.Time) bool {
+	weekday := date.Weekday()
+	return weekday == time.Saturday || weekday == time.Sunday
+}
+
+// weekendFill returns the level color of a weekend cell blended with WeekendTint.
+// The fill is returned unchanged when either color is not a hex color.
+func (o *Options) weekendFill(fill string) string {
+	base, ok := parseHexColor(fill)
+	if !ok {
+		return fill
+	}
+	tint, ok := parseHexColor(o.WeekendTint)
+	if !ok {
+		return fill
+	}
+	blend := func(b, t uint8) uint8 {
+		return uint8(float64(b)*(1-weekendTintWeight) + float64(t)*weekendTintWeight + 0.5)
+	}
+	return fmt.Sprintf("#%02x%02x%02x", blend(base.R, tint.R), blend(base.G, tint.G), blend(base.B, tint.B))
+}
+
+// contrastColor returns black or white, whichever reads better on the given hex color.
+// Colors that cannot be parsed are treated as light.
+func contrastColor(fill string) string {
+	rgb, ok := parseHexColor(fill)
+	if !ok {
+		return "#000"
+	}
+	r, g, b := float64(rgb.R), float64(rgb.G), float64(rgb.B)
+	// perceived brightness (ITU-R BT.601 luma)
+	if 0.299*r+0.587*g+0.114*b >= 128 {
+		return "#000"
+	}
+	return "#fff"
+}
+
+// parseHexColor parses a "#rgb" or "#rrggbb" color into an opaque RGBA color.
+func parseHexColor(s string) (color.RGBA, bool) {
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	rgb, err := strconv.ParseUint(hex, 16, 32)
+	if len(hex) != 6 || err != nil {
+		return color.RGBA{}, false
+	}
+	return color.RGBA{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: 0xff}, true
+}
+
+// svgOpenTag returns the opening <svg> element for the given content size.
+// Responsive graphs keep the size only in the viewBox so they scale with their container.
+func (o *Options) svgOpenTag(width, height int) string {
+	if o.Responsive {
+		return fmt.Sprintf(`<svg viewBox="0 0 %d %d" width="100%%" preserveAspectRatio="xMinYMin meet" xmlns="http://www.w3.org/2000/svg">`+"\n", width, height)
+	}
+	return fmt.Sprintf(`<svg width="%d" height="%d" xmlns="http://www.w3.org/2000/svg">`+"\n", width, height)
+}
+
+// fontFamily returns the font stack for labels.
+// An embedded font comes first so the stack still applies when it fails to load.
+func (o *Options) fontFamily() string {
+	family := o.FontFamily
+	if family == "" {
+		family = DefaultFontFamily
+	}
+	if o.FontDataURI != "" {
+		family = `"` + embeddedFontFamily + `", ` + family
+	}
+	return family
+}
+
+// styleElement returns the <style> element for labels and the title.
+// SVGs shown via <img> cannot load external fonts, so FontDataURI is inlined as an @font-face.
+func (o *Options) styleElement() string {
+	fontFace := ""
+	if o.FontDataURI != "" {
+		fontFace = fmt.Sprintf(`@font-face{font-family:"%s";src:url("%s")}`, embeddedFontFamily, o.FontDataURI)
+	}
+	family := o.fontFamily()
+	return fmt.Sprintf(`  <style>%s.label{font-family:%s;font-size:%dpx;fill:#666}.title{font-family:%s;font-size:%dpx;fill:#333;font-weight:bold}</style>`+"\n",
+		fontFace, family, o.FontSize, family, o.FontSize)
+}
+
+// now returns the current time used for rendering.
+func (o *Options) now() time.Time {
+	if o.Now.IsZero() {
+		return time.Now()
+	}
+	return o.Now
+}
+
+// clamped returns a copy of the options with To truncated to now (but not before From)
+// when ClampToNow is set and To is in the future, or the options themselves otherwise.
+func (o *Options) clamped() *Options {
+	now := o.now()
+	if !o.ClampToNow || o.To.IsZero() || !o.To.After(now) {
+		return o
+	}
+	clamped := *o
+	clamped.To = now
+	if clamped.To.Before(o.From) {
+		clamped.To = o.From
+	}
+	return &clamped
+}
+
+// formatValue formats a cell value without trailing zeros (e.g. "3", "5.3").
+func formatValue(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// numberSeparators holds the thousands and decimal separators of a locale.
+type numberSeparators struct {
+	group   string
+	decimal string
+}
+
+// localeSeparators maps a language (the primary subtag of a locale) to its separators.
+// Languages not listed use the English separators.
+var localeSeparators = map[string]numberSeparators{
+	"en": {",", "."},
+	"ja": {",", "."},
+	"zh": {",", "."},
+	"ko": {",", "."},
+	"de": {".", ","},
+	"es": {".", ","},
+	"it": {".", ","},
+	"nl": {".", ","},
+	"pt": {".", ","},
+	"fr": {"\u202f", ","},
+	"ru": {"\u00a0", ","},
+}
+
+// separators returns the separators for Locale, ignoring region and other subtags.
+func (o *Options) separators() numberSeparators {
+	language, _, _ := strings.Cut(strings.ToLower(strings.ReplaceAll(o.Locale, "_", "-")), "-")
+	if seps, ok := localeSeparators[language]; ok {
+		return seps
+	}
+	return localeSeparators["en"]
+}
+
+// displayValue formats a value for human-readable text (tooltips and cell values).
+// Unlike formatValue, which is used for machine-readable attributes, it groups digits
+// with the locale's separators when FormatNumbers is set.
+func (o *Options) displayValue(value float64) string {
+	text := formatValue(value)
+	if !o.FormatNumbers {
+		return text
+	}
+	seps := o.separators()
+
+	sign := ""
+	if strings.HasPrefix(text, "-") {
+		sign, text = "-", text[1:]
+	}
+	integer, fraction, hasFraction := strings.Cut(text, ".")
+
+	var sb strings.Builder
+	sb.WriteString(sign)
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			sb.WriteString(seps.group)
+		}
+		sb.WriteRune(digit)
+	}
+	if hasFraction {
+		sb.WriteString(seps.decimal)
+		sb.WriteString(fraction)
+	}
+	return sb.String()
 }
@@ -0,0 +1,44 @@
+package heatmap
+
+import (
+	"fmt"
+	"math"
+)
+
+// gradientFill returns the fill of a cell in Gradient mode: non-zero values are
+// interpolated from GradientStart to GradientEnd by value/(sup-1), so the largest
+// value of the auto-scaling range gets GradientEnd. Zero keeps the level 0 color,
+// and fill is returned unchanged when Gradient is off.
+func (o *Options) gradientFill(value, sup float64, fill string) string {
+	if !o.Gradient || value == 0 {
+		return fill
+	}
+	colors := o.palette()
+	start, end := o.GradientStart, o.GradientEnd
+	if start == "" {
+		start = colors[1]
+	}
+	if end == "" {
+		end = colors[len(colors)-1]
+	}
+	return interpolateColor(start, end, value/max(sup-1, 1))
+}
+
+// interpolateColor linearly interpolates between two hex colors in RGB,
+// returning a at t <= 0 and b at t >= 1. a is returned when either color
+// is not a hex color.
+func interpolateColor(a, b string, t float64) string {
+	from, ok := parseHexColor(a)
+	if !ok {
+		return a
+	}
+	to, ok := parseHexColor(b)
+	if !ok {
+		return a
+	}
+	t = math.Max(0, math.Min(1, t))
+	lerp := func(x, y uint8) uint8 {
+		return uint8(float64(x) + (float64(y)-float64(x))*t + 0.5)
+	}
+	return fmt.Sprintf("#%02x%02x%02x", lerp(from.R, to.R), lerp(from.G, to.G), lerp(from.B, to.B))
+}
@@ -0,0 +1,78 @@
+package heatmap
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInterpolateColor(t *testing.T) {
+	tests := []struct {
+		a, b string
+		t    float64
+		want string
+	}{
+		{"#000000", "#ffffff", 0, "#000000"},
+		{"#000000", "#ffffff", 1, "#ffffff"},
+		{"#000000", "#ffffff", 0.5, "#808080"},
+		{"#c6e48b", "#0d4429", 0.25, "#98bc73"},
+		{"#000", "#fff", 0.5, "#808080"}, // short hex colors
+		{"#000000", "#ffffff", -1, "#000000"},
+		{"#000000", "#ffffff", 2, "#ffffff"},
+		{"red", "#ffffff", 0.5, "red"}, // not hex colors
+		{"#000000", "blue", 0.5, "#000000"},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s-%s@%g", tt.a, tt.b, tt.t), func(t *testing.T) {
+			if got := interpolateColor(tt.a, tt.b, tt.t); got != tt.want {
+				t.Errorf("interpolateColor(%q, %q, %g) = %q, want %q", tt.a, tt.b, tt.t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateYearlyHeatmapSVG_Gradient(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 6, d, 0, 0, 0, 0, time.UTC) }
+	data := []Data{{Date: day(2), Value: 4}, {Date: day(3), Value: 10}, {Date: day(4), Value: 1}}
+	opts := &Options{
+		CellSize:      10,
+		CellPadding:   2,
+		FontSize:      10,
+		Colors:        DefaultColors,
+		From:          day(1),
+		To:            day(7),
+		Gradient:      true,
+		GradientStart: "#000000",
+		GradientEnd:   "#ffffff",
+	}
+
+	svg := GenerateYearlyHeatmapSVG(data, opts)
+
+	// the largest value gets the end color, the others are interpolated and zero stays gray
+	fills := map[string]string{
+		"2025-06-01": DefaultColors[0],
+		"2025-06-02": interpolateColor("#000000", "#ffffff", 0.4),
+		"2025-06-03": "#ffffff",
+		"2025-06-04": interpolateColor("#000000", "#ffffff", 0.1),
+	}
+	for date, fill := range fills {
+		if !strings.Contains(svg, fmt.Sprintf(`fill="%s" data-date="%s"`, fill, date)) {
+			t.Errorf("Expected %s to have fill %s", date, fill)
+		}
+	}
+
+	// the matrix uses the same colors but keeps the levels
+	m := YearlyMatrix(data, opts)
+	cell := m.Rows[1][0] // 2025-06-02 (Monday)
+	if cell.Color != fills["2025-06-02"] || cell.Level == 0 {
+		t.Errorf("Expected gradient color with a level, got %+v", cell)
+	}
+
+	// without colors the palette's level 1 and last colors are used
+	opts.GradientStart, opts.GradientEnd = "", ""
+	svg = GenerateYearlyHeatmapSVG(data, opts)
+	if !strings.Contains(svg, fmt.Sprintf(`fill="%s" data-date="2025-06-03"`, DefaultColors[len(DefaultColors)-1])) {
+		t.Errorf("Expected the largest value to have the last palette color")
+	}
+}
@@ -0,0 +1,62 @@
+package heatmap
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+)
+
+// EncodeHTML writes the matrix as an HTML fragment: a <div> CSS grid of cells in the
+// same order as the SVG, for dashboards that style the cells with plain CSS.
+// Each cell has the classes "sougen-cell" and "sougen-level-N" and the data-date
+// (RFC 3339, as in the JSON matrix), data-value and data-level attributes. The level
+// colors are set as the custom properties --sougen-level-N on the grid, so pages can
+// override them; cells colored otherwise (by tag) get an inline background color.
+// Cells after To are rendered as empty "sougen-blank" placeholders to keep the layout.
+func (m *Matrix) EncodeHTML(w io.Writer, opts *Options) error {
+	columns := 0
+	if len(m.Rows) > 0 {
+		columns = len(m.Rows[0])
+	}
+	opts = opts.fitted(func(o *Options) (int, int) {
+		return columns*(o.CellSize+o.CellPadding) + o.CellPadding, len(m.Rows)*(o.CellSize+o.CellPadding) + o.CellPadding
+	})
+	colors := opts.palette()
+
+	var sb strings.Builder
+	sb.WriteString("<style>\n")
+	sb.WriteString(fmt.Sprintf(".sougen-heatmap{display:grid;grid-template-columns:repeat(%d,%dpx);grid-auto-rows:%dpx;gap:%dpx;padding:%dpx}\n",
+		columns, opts.CellSize, opts.CellSize, opts.CellPadding, opts.CellPadding))
+	sb.WriteString(fmt.Sprintf(".sougen-cell{border-radius:%dpx}\n", max(opts.CellRadius, 0)))
+	for level := range colors {
+		sb.WriteString(fmt.Sprintf(".sougen-level-%d{background-color:var(--sougen-level-%d)}\n", level, level))
+	}
+	sb.WriteString("</style>\n")
+
+	vars := make([]string, len(colors))
+	for level, color := range colors {
+		vars[level] = fmt.Sprintf("--sougen-level-%d:%s", level, color)
+	}
+	sb.WriteString(fmt.Sprintf(`<div class="sougen-heatmap" style="%s">`+"\n", html.EscapeString(strings.Join(vars, ";"))))
+	for _, cells := range m.Rows {
+		for _, cell := range cells {
+			if cell == nil {
+				sb.WriteString(`  <div class="sougen-blank"></div>` + "\n")
+				continue
+			}
+			style := ""
+			if cell.Color != colors[cell.Level] {
+				style = fmt.Sprintf(` style="background-color:%s"`, html.EscapeString(cell.Color))
+			}
+			sb.WriteString(fmt.Sprintf(`  <div class="sougen-cell sougen-level-%d" data-date="%s" data-value="%s" data-level="%d" title="%s"%s></div>`+"\n",
+				cell.Level, cell.Date.Format(time.RFC3339), formatValue(cell.Value), cell.Level,
+				html.EscapeString(cell.Date.Format("2006-01-02")+": "+opts.displayValue(cell.Value)), style))
+		}
+	}
+	sb.WriteString("</div>\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
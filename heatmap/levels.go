@@ -0,0 +1,106 @@
+package heatmap
+
+import "math"
+
+// minSupValue is the lower bound of the auto-scaling maximum, so that sparse
+// data does not jump straight to the darkest colors.
+const minSupValue = 5.0
+
+// supValue returns the exclusive upper bound used for auto-scaling:
+// the largest data value plus one, but at least minSupValue.
+func supValue(data []Data) float64 {
+	sup := minSupValue
+	for _, d := range data {
+		if d.Value+1 > sup {
+			sup = d.Value + 1
+		}
+	}
+	return sup
+}
+
+// levelThresholds returns the lower bounds of levels 2..levels-1.
+// Values from 1 up to supValue are spread linearly over levels 1..levels-2,
+// so threshold k is 1 + k*(supValue-1)/(levels-2).
+// Palettes with only two colors have no thresholds (every positive value is level 1).
+func levelThresholds(supValue float64, levels int) []float64 {
+	if levels <= 2 || supValue <= 1 {
+		return nil
+	}
+	thresholds := make([]float64, 0, levels-2)
+	for k := 1; k <= levels-2; k++ {
+		thresholds = append(thresholds, 1+float64(k)*(supValue-1)/float64(levels-2))
+	}
+	return thresholds
+}
+
+// cellLevel maps a cell value to a palette index.
+// Zero uses level 0; positive values (including fractions below 1) start at
+// level 1 and move up one level for each threshold they reach.
+func cellLevel(value float64, thresholds []float64) int {
+	if value == 0 {
+		return 0
+	}
+	level := 1
+	for _, t := range thresholds {
+		if value < t {
+			break
+		}
+		level++
+	}
+	return level
+}
+
+// minLevel returns the lowest palette index for non-zero values,
+// clamped to the available levels.
+func (o *Options) minLevel(levels int) int {
+	return max(1, min(o.MinNonZeroLevel, levels-1))
+}
+
+// level maps a cell value to a palette index, raising non-zero values to MinNonZeroLevel.
+func (o *Options) level(value float64, thresholds []float64, levels int) int {
+	level := cellLevel(value, thresholds)
+	if level == 0 {
+		return 0
+	}
+	return max(level, o.minLevel(levels))
+}
+
+// LegendLevel describes the values rendered with one palette color.
+// Level 0 is exactly zero; any other level covers Min <= value < Max
+// (the lowest non-zero level also includes fractions between 0 and 1, and Max is +Inf for the last level).
+// Levels below MinNonZeroLevel are never used and have Min == Max == 0.
+type LegendLevel struct {
+	Level int
+	Min   float64
+	Max   float64
+	Color string
+}
+
+// Legend returns the value range of each color level for the given data,
+// using the same auto-scaling as GenerateYearlyHeatmapSVG and GenerateWeeklyHeatmapSVG.
+// Data should be aggregated per cell the same way as when rendering.
+func Legend(data []Data, opts *Options) []LegendLevel {
+	if opts == nil {
+		opts = &Options{}
+	}
+	colors := opts.palette()
+	thresholds := levelThresholds(supValue(data), len(colors))
+
+	minLevel := opts.minLevel(len(colors))
+
+	legend := make([]LegendLevel, 0, len(colors))
+	legend = append(legend, LegendLevel{Level: 0, Min: 0, Max: 0, Color: colors[0]})
+	for level := 1; level < len(colors); level++ {
+		entry := LegendLevel{Level: level, Min: 0, Max: math.Inf(1), Color: colors[level]}
+		if level > minLevel && level-2 < len(thresholds) {
+			entry.Min = thresholds[level-2]
+		}
+		if level < minLevel {
+			entry.Max = 0
+		} else if level-1 < len(thresholds) {
+			entry.Max = thresholds[level-1]
+		}
+		legend = append(legend, entry)
+	}
+	return legend
+}
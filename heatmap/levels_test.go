@@ -0,0 +1,95 @@
+package heatmap
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestLegend(t *testing.T) {
+	day := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		values   []float64
+		colors   []string
+		minLevel int
+	}{
+		{"Empty data", nil, nil, 0},
+		{"Small values", []float64{1, 2, 3}, nil, 0},
+		{"Large values", []float64{1, 17, 250}, nil, 0},
+		{"Fractional values", []float64{0.25, 1.5, 7.75}, nil, 0},
+		{"Two-color palette", []float64{1, 40}, []string{"#eeeeee", "#000000"}, 0},
+		{"Minimum level", []float64{1, 2, 9, 30}, nil, 3},
+		{"Minimum level above palette", []float64{1, 30}, nil, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := make([]Data, 0, len(tt.values))
+			for i, v := range tt.values {
+				data = append(data, Data{Date: day.AddDate(0, 0, i), Value: v})
+			}
+			opts := &Options{Colors: tt.colors, MinNonZeroLevel: tt.minLevel}
+			colors := opts.palette()
+
+			legend := Legend(data, opts)
+			if len(legend) != len(colors) {
+				t.Fatalf("Expected %d levels, got %d", len(colors), len(legend))
+			}
+
+			// Level 0 is exactly zero and the rest are contiguous up to +Inf
+			if legend[0].Min != 0 || legend[0].Max != 0 {
+				t.Errorf("Expected level 0 to cover only zero, got [%v, %v)", legend[0].Min, legend[0].Max)
+			}
+			if legend[1].Min != 0 {
+				t.Errorf("Expected level 1 to start at 0, got %v", legend[1].Min)
+			}
+			for i := 2; i < len(legend); i++ {
+				if legend[i].Min != legend[i-1].Max {
+					t.Errorf("Expected level %d to start at %v, got %v", i, legend[i-1].Max, legend[i].Min)
+				}
+			}
+			if last := legend[len(legend)-1]; !math.IsInf(last.Max, 1) {
+				t.Errorf("Expected the last level to be unbounded, got %v", last.Max)
+			}
+
+			// Every data value falls into the level the SVG uses for it
+			thresholds := levelThresholds(supValue(data), len(colors))
+			for _, d := range data {
+				level := opts.level(d.Value, thresholds, len(colors))
+				entry := legend[level]
+				if entry.Level != level || entry.Color != colors[level] {
+					t.Errorf("Unexpected legend entry for level %d: %+v", level, entry)
+				}
+				if d.Value < entry.Min || d.Value >= entry.Max {
+					t.Errorf("Expected value %v within level %d [%v, %v)", d.Value, level, entry.Min, entry.Max)
+				}
+			}
+		})
+	}
+}
+
+func TestOptionsLevelMinNonZeroLevel(t *testing.T) {
+	thresholds := levelThresholds(supValue(nil), len(DefaultColors))
+
+	tests := []struct {
+		minLevel int
+		value    float64
+		expected int
+	}{
+		{0, 0, 0},
+		{0, 1, 1},
+		{2, 0, 0}, // zero stays at level 0
+		{2, 1, 2},
+		{2, 0.5, 2},
+		{2, 4, 4}, // already above the minimum
+		{9, 1, len(DefaultColors) - 1},
+	}
+	for _, tt := range tests {
+		opts := &Options{MinNonZeroLevel: tt.minLevel}
+		if level := opts.level(tt.value, thresholds, len(DefaultColors)); level != tt.expected {
+			t.Errorf("MinNonZeroLevel=%d, value=%v: expected level %d, got %d", tt.minLevel, tt.value, tt.expected, level)
+		}
+	}
+}
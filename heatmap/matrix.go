@@ -0,0 +1,193 @@
+package heatmap
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"time"
+)
+
+// Cell is a single cell of a heatmap grid.
+type Cell struct {
+	Date  time.Time `json:"date"` // start of the day (or of the 4-hour slot in the weekly view)
+	Value float64   `json:"value"`
+	Level int       `json:"level"`
+	Color string    `json:"color"`
+}
+
+// Matrix is a heatmap grid laid out like the corresponding SVG, in row-major order:
+// the yearly view has 7 rows (Sunday to Saturday) and a column per week, the weekly
+// view has 6 rows (4-hour slots) and a column per day, and the sparkline has a single
+// row of days. Cells after To are nil, just as the SVGs leave them blank.
+type Matrix struct {
+	Rows [][]*Cell `json:"rows"`
+}
+
+// newMatrix returns an empty matrix with the given dimensions.
+func newMatrix(rows, columns int) *Matrix {
+	m := &Matrix{Rows: make([][]*Cell, rows)}
+	for i := range m.Rows {
+		m.Rows[i] = make([]*Cell, columns)
+	}
+	return m
+}
+
+// cellBuilder levels values with the same auto-scaling as the SVG generators.
+type cellBuilder struct {
+	opts       *Options
+	colors     []string
+	sup        float64
+	thresholds []float64
+}
+
+func newCellBuilder(data []Data, opts *Options) *cellBuilder {
+	colors := opts.palette()
+	sup := supValue(data)
+	return &cellBuilder{
+		opts:       opts,
+		colors:     colors,
+		sup:        sup,
+		thresholds: levelThresholds(sup, len(colors)),
+	}
+}
+
+func (b *cellBuilder) cell(date time.Time, value float64) *Cell {
+	level := b.opts.level(value, b.thresholds, len(b.colors))
+	return &Cell{Date: date, Value: value, Level: level, Color: b.opts.gradientFill(value, b.sup, b.colors[level])}
+}
+
+// dayCell is cell for a whole day, colored by its dominant tag when TagColors is set.
+func (b *cellBuilder) dayCell(date time.Time, value float64) *Cell {
+	c := b.cell(date, value)
+	c.Color = b.opts.tagFill(date.Format("2006-01-02"), value, c.Color)
+	return c
+}
+
+// YearlyMatrix returns the cells of GenerateYearlyHeatmapSVG.
+// It returns an empty matrix when From or To is not set.
+func YearlyMatrix(data []Data, opts *Options) *Matrix {
+	if opts == nil || opts.From.IsZero() || opts.To.IsZero() {
+		return &Matrix{}
+	}
+	opts = opts.clamped()
+
+	valueMap := make(map[string]float64, len(data))
+	for _, d := range data {
+		valueMap[d.Date.Format("2006-01-02")] += d.Value
+	}
+
+	firstSunday := opts.From.AddDate(0, 0, -int(opts.From.Weekday()))
+	weeks := int(opts.To.Sub(firstSunday).Hours()/24/7) + 1
+
+	b := newCellBuilder(data, opts)
+	m := newMatrix(7, weeks)
+	for w := range weeks {
+		for i := range 7 {
+			current := firstSunday.Add(time.Duration(w*7+i) * 24 * time.Hour)
+			if current.After(opts.To) {
+				continue
+			}
+			m.Rows[i][w] = b.dayCell(current, valueMap[current.Format("2006-01-02")])
+		}
+	}
+	return m
+}
+
+// WeeklyMatrix returns the cells of GenerateWeeklyHeatmapSVG.
+// It returns an empty matrix when From or To is not set.
+func WeeklyMatrix(data []Data, opts *Options) *Matrix {
+	if opts == nil || opts.From.IsZero() || opts.To.IsZero() {
+		return &Matrix{}
+	}
+	opts = opts.clamped()
+
+	valueMap := make(map[string]float64, len(data))
+	for _, d := range data {
+		valueMap[slotKey(d.Date, d.Date.Hour()/4)] += d.Value
+	}
+
+	weekday := int(opts.From.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	firstMonday := opts.From.AddDate(0, 0, -(weekday - 1))
+	days := max(int(opts.To.Sub(firstMonday).Hours()/24)+1, 28)
+
+	b := newCellBuilder(data, opts)
+	m := newMatrix(6, days)
+	for d := range days {
+		current := firstMonday.Add(time.Duration(d) * 24 * time.Hour)
+		if current.After(opts.To) {
+			continue
+		}
+		for slot := range 6 {
+			start := time.Date(current.Year(), current.Month(), current.Day(), slot*4, 0, 0, 0, current.Location())
+			m.Rows[slot][d] = b.cell(start, valueMap[slotKey(current, slot)])
+		}
+	}
+	return m
+}
+
+// slotKey identifies a 4-hour slot of a day.
+func slotKey(date time.Time, slot int) string {
+	return fmt.Sprintf("%s-%d", date.Format("2006-01-02"), slot)
+}
+
+// SparklineMatrix returns the cells of GenerateSparklineSVG.
+// It returns an empty matrix when From or To is not set.
+func SparklineMatrix(data []Data, opts *Options) *Matrix {
+	if opts == nil || opts.From.IsZero() || opts.To.IsZero() {
+		return &Matrix{}
+	}
+	opts = opts.clamped()
+
+	valueMap := make(map[string]float64, len(data))
+	for _, d := range data {
+		valueMap[d.Date.Format("2006-01-02")] += d.Value
+	}
+
+	days := max(int(opts.To.Sub(opts.From).Hours()/24)+1, 1)
+
+	b := newCellBuilder(data, opts)
+	m := newMatrix(1, days)
+	for d := range days {
+		current := opts.From.Add(time.Duration(d) * 24 * time.Hour)
+		m.Rows[0][d] = b.dayCell(current, valueMap[current.Format("2006-01-02")])
+	}
+	return m
+}
+
+// EncodePNG writes the matrix as a PNG image of plain square cells on a transparent
+// background, using CellSize and CellPadding (shrunk to fit MaxWidth/MaxHeight).
+// Labels, titles and rounded corners are only available in the SVGs.
+func (m *Matrix) EncodePNG(w io.Writer, opts *Options) error {
+	columns := 0
+	if len(m.Rows) > 0 {
+		columns = len(m.Rows[0])
+	}
+	size := func(o *Options) (int, int) {
+		return columns*(o.CellSize+o.CellPadding) + o.CellPadding, len(m.Rows)*(o.CellSize+o.CellPadding) + o.CellPadding
+	}
+	opts = opts.fitted(size)
+	width, height := size(opts)
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for row, cells := range m.Rows {
+		for column, cell := range cells {
+			if cell == nil {
+				continue
+			}
+			fill, ok := parseHexColor(cell.Color)
+			if !ok {
+				fill = color.RGBA{0x99, 0x99, 0x99, 0xff}
+			}
+			x := opts.CellPadding + column*(opts.CellSize+opts.CellPadding)
+			y := opts.CellPadding + row*(opts.CellSize+opts.CellPadding)
+			draw.Draw(img, image.Rect(x, y, x+opts.CellSize, y+opts.CellSize), image.NewUniform(fill), image.Point{}, draw.Src)
+		}
+	}
+	return png.Encode(w, img)
+}
@@ -0,0 +1,130 @@
+package heatmap
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestYearlyMatrix(t *testing.T) {
+	data := []Data{
+		{Date: time.Date(2025, 5, 20, 0, 0, 0, 0, time.UTC), Value: 4},
+		{Date: time.Date(2025, 5, 21, 0, 0, 0, 0, time.UTC), Value: 1},
+	}
+	opts := &Options{
+		Colors: DefaultColors,
+		From:   time.Date(2025, 5, 19, 0, 0, 0, 0, time.UTC), // Monday
+		To:     time.Date(2025, 5, 28, 0, 0, 0, 0, time.UTC), // Wednesday
+	}
+
+	m := YearlyMatrix(data, opts)
+
+	// columns start on the Sunday before From, like the SVG
+	if len(m.Rows) != 7 || len(m.Rows[0]) != 2 {
+		t.Fatalf("Expected 7x2 matrix, got %dx%d", len(m.Rows), len(m.Rows[0]))
+	}
+	tuesday := m.Rows[2][0]
+	if tuesday == nil || !tuesday.Date.Equal(data[0].Date) || tuesday.Value != 4 {
+		t.Fatalf("Unexpected cell for 2025-05-20: %+v", tuesday)
+	}
+	if tuesday.Color != DefaultColors[tuesday.Level] || tuesday.Level <= m.Rows[3][0].Level {
+		t.Errorf("Expected the larger value to have a higher level: %+v vs %+v", tuesday, m.Rows[3][0])
+	}
+	if m.Rows[0][0].Level != 0 || m.Rows[0][0].Color != DefaultColors[0] {
+		t.Errorf("Expected zero cell at level 0, got %+v", m.Rows[0][0])
+	}
+	// cells after To are left empty
+	if m.Rows[4][1] != nil {
+		t.Errorf("Expected no cell after To, got %+v", m.Rows[4][1])
+	}
+}
+
+func TestWeeklyMatrix(t *testing.T) {
+	data := []Data{{Date: time.Date(2025, 5, 21, 10, 0, 0, 0, time.UTC), Value: 3}}
+	opts := &Options{
+		Colors: DefaultColors,
+		From:   time.Date(2025, 5, 19, 0, 0, 0, 0, time.UTC),
+		To:     time.Date(2025, 5, 25, 0, 0, 0, 0, time.UTC),
+	}
+
+	m := WeeklyMatrix(data, opts)
+
+	if len(m.Rows) != 6 || len(m.Rows[0]) != 28 {
+		t.Fatalf("Expected 6x28 matrix, got %dx%d", len(m.Rows), len(m.Rows[0]))
+	}
+	cell := m.Rows[2][2]
+	if cell == nil || cell.Value != 3 || !cell.Date.Equal(time.Date(2025, 5, 21, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected cell for 2025-05-21 08:00-12:00: %+v", cell)
+	}
+}
+
+func TestMatrixEncodePNG(t *testing.T) {
+	opts := &Options{
+		CellSize:    10,
+		CellPadding: 2,
+		Colors:      DefaultColors,
+		From:        time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+		To:          time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC),
+	}
+	m := SparklineMatrix([]Data{{Date: opts.From, Value: 5}}, opts)
+
+	var buf bytes.Buffer
+	if err := m.EncodePNG(&buf, opts); err != nil {
+		t.Fatalf("Failed to encode PNG: %v", err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("Failed to decode PNG: %v", err)
+	}
+	if size := img.Bounds().Size(); size.X != 122 || size.Y != 14 {
+		t.Errorf("Expected 122x14 image, got %v", size)
+	}
+	want, _ := parseHexColor(m.Rows[0][0].Color)
+	r, g, b, _ := img.At(5, 5).RGBA()
+	if uint8(r>>8) != want.R || uint8(g>>8) != want.G || uint8(b>>8) != want.B {
+		t.Errorf("Expected first cell color %s, got %v", m.Rows[0][0].Color, img.At(5, 5))
+	}
+}
+
+func TestMatrixEncodeHTML(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 6, d, 0, 0, 0, 0, time.UTC) }
+	opts := &Options{
+		CellSize:    10,
+		CellPadding: 2,
+		Colors:      DefaultColors,
+		From:        day(1), // Sunday
+		To:          day(10),
+		TagColors:   map[string]string{"work": "#4e79a7"},
+		DayTags:     map[string]string{"2025-06-03": "work"},
+	}
+	m := YearlyMatrix([]Data{{Date: day(2), Value: 5}, {Date: day(3), Value: 5}}, opts)
+
+	var buf bytes.Buffer
+	if err := m.EncodeHTML(&buf, opts); err != nil {
+		t.Fatalf("Failed to encode HTML: %v", err)
+	}
+	fragment := buf.String()
+
+	if !strings.Contains(fragment, "grid-template-columns:repeat(2,10px)") {
+		t.Errorf("Expected a 2-column grid, got %s", fragment)
+	}
+	// one div per cell in row-major order, with placeholders after To
+	if cells, blanks := strings.Count(fragment, `class="sougen-cell `), strings.Count(fragment, `class="sougen-blank"`); cells != 10 || blanks != 4 {
+		t.Errorf("Expected 10 cells and 4 blanks, got %d and %d", cells, blanks)
+	}
+	top := m.Rows[1][0].Level
+	monday := fmt.Sprintf(`<div class="sougen-cell sougen-level-%d" data-date="2025-06-02T00:00:00Z" data-value="5" data-level="%d"`, top, top)
+	if !strings.Contains(fragment, monday) {
+		t.Errorf("Expected %s in %s", monday, fragment)
+	}
+	if !strings.Contains(fragment, fmt.Sprintf("--sougen-level-%d:%s", top, DefaultColors[top])) {
+		t.Errorf("Expected the level colors as custom properties, got %s", fragment)
+	}
+	// only cells colored by tag have an inline color
+	if strings.Count(fragment, `style="background-color:`) != 1 || !strings.Contains(fragment, `style="background-color:#4e79a7"`) {
+		t.Errorf("Expected one inline tag color, got %s", fragment)
+	}
+}
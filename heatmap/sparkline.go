@@ -0,0 +1,85 @@
+package heatmap
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// GenerateSparklineSVG returns a compact SVG with a single row of daily cells
+// from opts.From to opts.To, for inline use such as badges.
+// It has no title or labels; days are bucketed and leveled the same way as the yearly heatmap.
+func GenerateSparklineSVG(data []Data, opts *Options) string {
+	// default options
+	if opts == nil {
+		opts = &Options{
+			CellSize:    12,
+			CellPadding: 2,
+			FontSize:    10,
+			FontFamily:  "sans-serif",
+			Colors:      DefaultColors,
+		}
+	}
+
+	// From/Toが設定されていない場合は空文字列を返す（ClampToNowの場合は現在時刻まで）
+	opts = opts.clamped()
+	startDate := opts.From
+	endDate := opts.To
+	if startDate.IsZero() || endDate.IsZero() {
+		return ""
+	}
+
+	// map date string to value
+	valueMap := make(map[string]float64, len(data))
+	for _, d := range data {
+		key := d.Date.Format("2006-01-02")
+		valueMap[key] += d.Value
+	}
+
+	// one cell per day, at least one
+	oneDay := 24 * time.Hour
+	days := int(endDate.Sub(startDate).Hours()/24) + 1
+	if days < 1 {
+		days = 1
+	}
+
+	// compute dimensions, shrinking cells to fit MaxWidth/MaxHeight
+	size := func(o *Options) (int, int) {
+		width := days*(o.CellSize+o.CellPadding) + o.CellPadding
+		height := o.CellSize + 2*o.CellPadding
+		return width, height
+	}
+	opts = opts.fitted(size)
+	width, height := size(opts)
+
+	var sb strings.Builder
+	sb.WriteString(opts.svgOpenTag(width, height))
+
+	// auto-scale level thresholds to the maximum value
+	colors := opts.palette()
+	sup := supValue(data)
+	thresholds := levelThresholds(sup, len(colors))
+	todayKey := opts.now().Format("2006-01-02")
+
+	for d := range days {
+		current := startDate.Add(time.Duration(d) * oneDay)
+		key := current.Format("2006-01-02")
+		value := valueMap[key] // 存在しない場合は0
+		level := opts.level(value, thresholds, len(colors))
+		fill := opts.tagFill(key, value, opts.gradientFill(value, sup, colors[level]))
+		x := opts.CellPadding + d*(opts.CellSize+opts.CellPadding)
+		y := opts.CellPadding
+
+		// 今日のセルは枠線で強調（塗り色とスケーリングには影響しない）、それ以外はCellStrokeの枠線
+		extraAttrs := opts.cellRadiusAttrs() + opts.cellStrokeAttrs(key == todayKey)
+
+		sb.WriteString(fmt.Sprintf(`  <rect x="%d" y="%d" width="%d" height="%d" fill="%s" data-date="%s" data-value="%s"%s>`+"\n",
+			x, y, opts.CellSize, opts.CellSize, fill, key, formatValue(value), extraAttrs))
+		sb.WriteString(fmt.Sprintf(`    <title>%s: %s</title>`+"\n", current.Format("2006年01月02日"), opts.displayValue(value)))
+		sb.WriteString(`  </rect>` + "\n")
+		sb.WriteString(opts.valueText(x, y, value, fill))
+	}
+
+	sb.WriteString(`</svg>`)
+	return sb.String()
+}
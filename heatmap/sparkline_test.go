@@ -0,0 +1,55 @@
+package heatmap
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerateSparklineSVG_SingleRow(t *testing.T) {
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	data := []Data{
+		{Date: time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC), Value: 3},
+		{Date: time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC), Value: 2},
+		{Date: time.Date(2025, 6, 20, 0, 0, 0, 0, time.UTC), Value: 1},
+	}
+	opts := &Options{
+		CellSize:    10,
+		CellPadding: 2,
+		FontSize:    10,
+		Colors:      DefaultColors,
+		ProjectName: "Test Project",
+		From:        from,
+		To:          to,
+	}
+
+	svg := GenerateSparklineSVG(data, opts)
+
+	// 高さはセル1行分（セル + 上下のパディング）
+	if !strings.Contains(svg, `width="362" height="14"`) {
+		t.Errorf("Expected a single row of 30 cells (362x14), got: %s", svg[:strings.Index(svg, "\n")])
+	}
+	if got := strings.Count(svg, "<rect"); got != 30 {
+		t.Errorf("Expected 30 cells, got %d", got)
+	}
+	for _, cell := range []string{
+		`x="2" y="2" width="10" height="10" fill="#f0f0f0" data-date="2025-06-01"`,
+		`data-date="2025-06-10" data-value="5"`,
+		`data-date="2025-06-30" data-value="0"`,
+	} {
+		if !strings.Contains(svg, cell) {
+			t.Errorf("Expected SVG to contain %q", cell)
+		}
+	}
+	// タイトルやラベルは描画しない
+	if strings.Contains(svg, "Test Project") || strings.Contains(svg, `class="label"`) {
+		t.Error("Expected no title or labels in sparkline")
+	}
+}
+
+func TestGenerateSparklineSVG_NilOptions(t *testing.T) {
+	if svg := GenerateSparklineSVG([]Data{{Date: time.Now(), Value: 1}}, nil); svg != "" {
+		t.Error("Expected empty SVG with nil options (From/To not set)")
+	}
+}
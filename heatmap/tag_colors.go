@@ -0,0 +1,85 @@
+package heatmap
+
+import (
+	"maps"
+	"slices"
+	"time"
+)
+
+// TagPalette is a categorical palette for coloring cells by tag (Tableau 10),
+// used by AssignTagColors for tags without an explicit color.
+var TagPalette = []string{"#4e79a7", "#f28e2b", "#e15759", "#76b7b2", "#59a14f", "#edc948", "#b07aa1", "#ff9da7", "#9c755f", "#bab0ac"}
+
+// TagData holds the total value of one tag on a day.
+type TagData struct {
+	Date  time.Time
+	Tag   string
+	Value float64
+}
+
+// DominantTags returns the highest-value tag of each day, keyed by "2006-01-02".
+// Values of the same tag and day are summed; ties go to the tag that sorts first,
+// and days whose tags total 0 have no entry.
+func DominantTags(data []TagData) map[string]string {
+	totals := make(map[string]map[string]float64)
+	for _, d := range data {
+		key := d.Date.Format("2006-01-02")
+		if totals[key] == nil {
+			totals[key] = make(map[string]float64)
+		}
+		totals[key][d.Tag] += d.Value
+	}
+
+	dominant := make(map[string]string, len(totals))
+	for key, tags := range totals {
+		best, bestValue := "", 0.0
+		for _, tag := range slices.Sorted(maps.Keys(tags)) {
+			if value := tags[tag]; value > bestValue {
+				best, bestValue = tag, value
+			}
+		}
+		if best != "" {
+			dominant[key] = best
+		}
+	}
+	return dominant
+}
+
+// AssignTagColors returns a color for every dominant tag in dayTags: the color
+// from explicit when set, otherwise the next TagPalette color in tag name order.
+func AssignTagColors(dayTags map[string]string, explicit map[string]string) map[string]string {
+	colors := maps.Clone(explicit)
+	if colors == nil {
+		colors = make(map[string]string)
+	}
+	next := 0
+	for _, tag := range slices.Sorted(maps.Keys(valueSet(dayTags))) {
+		if _, ok := colors[tag]; ok {
+			continue
+		}
+		colors[tag] = TagPalette[next%len(TagPalette)]
+		next++
+	}
+	return colors
+}
+
+// valueSet returns the set of values of m.
+func valueSet(m map[string]string) map[string]bool {
+	set := make(map[string]bool, len(m))
+	for _, v := range m {
+		set[v] = true
+	}
+	return set
+}
+
+// tagFill returns the TagColors color of the dominant tag of the day with the given key,
+// or fill when the day has no value, no dominant tag, or no color for its tag.
+func (o *Options) tagFill(key string, value float64, fill string) string {
+	if value == 0 || len(o.TagColors) == 0 {
+		return fill
+	}
+	if color, ok := o.TagColors[o.DayTags[key]]; ok {
+		return color
+	}
+	return fill
+}
@@ -0,0 +1,91 @@
+package heatmap
+
+import (
+	"maps"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDominantTags(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+	data := []TagData{
+		// 同じ日の同じタグは合計する
+		{Date: day(1), Tag: "work", Value: 2},
+		{Date: day(1), Tag: "work", Value: 2},
+		{Date: day(1), Tag: "home", Value: 3},
+		// 同点は名前順で先のタグ
+		{Date: day(2), Tag: "work", Value: 1},
+		{Date: day(2), Tag: "home", Value: 1},
+		// 合計が0の日は含まない
+		{Date: day(3), Tag: "work", Value: 0},
+	}
+
+	expected := map[string]string{"2025-01-01": "work", "2025-01-02": "home"}
+	if got := DominantTags(data); !maps.Equal(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestAssignTagColors(t *testing.T) {
+	dayTags := map[string]string{"2025-01-01": "work", "2025-01-02": "home", "2025-01-03": "gym"}
+
+	// 明示された色を優先し、残りのタグには名前順にTagPaletteの色を割り当てる
+	colors := AssignTagColors(dayTags, map[string]string{"work": "#000000"})
+	expected := map[string]string{"work": "#000000", "gym": TagPalette[0], "home": TagPalette[1]}
+	if !maps.Equal(colors, expected) {
+		t.Errorf("Expected %v, got %v", expected, colors)
+	}
+}
+
+func TestGenerateYearlyHeatmapSVG_TagColors(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+	data := []Data{
+		{Date: day(5), Value: 3},
+		{Date: day(6), Value: 3},
+		{Date: day(7), Value: 3},
+	}
+	opts := &Options{
+		CellSize:    12,
+		CellPadding: 2,
+		FontSize:    10,
+		Colors:      DefaultColors,
+		From:        day(1),
+		To:          day(10),
+
+		TagColors: map[string]string{"work": "#4e79a7", "home": "#f28e2b"},
+		DayTags:   DominantTags([]TagData{{Date: day(5), Tag: "work", Value: 3}, {Date: day(6), Tag: "home", Value: 3}}),
+	}
+
+	svg := GenerateYearlyHeatmapSVG(data, opts)
+	for _, expected := range []string{
+		// 異なるタグの日は異なる色
+		`fill="#4e79a7" data-date="2025-01-05"`,
+		`fill="#f28e2b" data-date="2025-01-06"`,
+		// 値が0の日はレベル0
+		`fill="` + DefaultColors[0] + `" data-date="2025-01-08"`,
+	} {
+		if !strings.Contains(svg, expected) {
+			t.Errorf("Expected SVG to contain %q", expected)
+		}
+	}
+
+	// JSON・PNGのセルも同じ色（2025-01-05からの週は2列目）
+	matrix := YearlyMatrix(data, opts)
+	if cell := matrix.Rows[day(6).Weekday()][1]; cell.Color != "#f28e2b" {
+		t.Errorf("Expected the matrix cell of 2025-01-06 to use the tag color, got %+v", cell)
+	}
+	// タグのない日は値の大きさによる色
+	untagged := matrix.Rows[day(7).Weekday()][1]
+	if untagged.Level == 0 || untagged.Color != DefaultColors[untagged.Level] {
+		t.Errorf("Expected the untagged day to keep the intensity color, got %+v", untagged)
+	}
+	if !strings.Contains(svg, `fill="`+untagged.Color+`" data-date="2025-01-07"`) {
+		t.Errorf("Expected the untagged day in the SVG to use %s", untagged.Color)
+	}
+
+	// スパークラインも同じ色
+	if !strings.Contains(GenerateSparklineSVG(data, opts), `fill="#4e79a7" data-date="2025-01-05"`) {
+		t.Error("Expected the sparkline to use the tag color")
+	}
+}
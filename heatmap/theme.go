@@ -0,0 +1,31 @@
+package heatmap
+
+import (
+	"maps"
+	"slices"
+)
+
+// DefaultTheme is the theme used when none is specified.
+const DefaultTheme = "github"
+
+// themes maps theme names to 6-level palettes (level 0 is used for zero values).
+var themes = map[string][]string{
+	"github": DefaultColors,
+	// Derived from the cividis colormap (reversed so that higher values are darker);
+	// readable for the most common forms of color vision deficiency.
+	"cividis": {"#f0f0f0", "#ffea46", "#bcaf6f", "#7c7b78", "#414d6b", "#00204d"},
+}
+
+// ThemeColors returns the palette of the named theme.
+func ThemeColors(name string) ([]string, bool) {
+	colors, ok := themes[name]
+	if !ok {
+		return nil, false
+	}
+	return slices.Clone(colors), true
+}
+
+// ThemeNames returns the names of all registered themes in sorted order.
+func ThemeNames() []string {
+	return slices.Sorted(maps.Keys(themes))
+}
@@ -0,0 +1,35 @@
+package heatmap
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestThemeColors(t *testing.T) {
+	for _, name := range ThemeNames() {
+		colors, ok := ThemeColors(name)
+		if !ok {
+			t.Fatalf("Expected theme %q to exist", name)
+		}
+		if len(colors) != len(DefaultColors) {
+			t.Errorf("Expected theme %q to have %d levels, got %d", name, len(DefaultColors), len(colors))
+		}
+	}
+
+	if colors, _ := ThemeColors(DefaultTheme); !slices.Equal(colors, DefaultColors) {
+		t.Errorf("Expected default theme to use DefaultColors, got %v", colors)
+	}
+
+	if _, ok := ThemeColors("unknown"); ok {
+		t.Error("Expected unknown theme to be missing")
+	}
+}
+
+func TestThemeColorsReturnsCopy(t *testing.T) {
+	colors, _ := ThemeColors(DefaultTheme)
+	colors[0] = "#000000"
+
+	if DefaultColors[0] == "#000000" {
+		t.Error("Expected ThemeColors to return a copy of the palette")
+	}
+}
@@ -17,11 +17,12 @@ func GenerateWeeklyHeatmapSVG(data []Data, opts *Options) string {
 			CellPadding: 2,
 			FontSize:    10,
 			FontFamily:  "sans-serif",
-			Colors:      []string{"#f0f0f0", "#c6e48b", "#7bc96f", "#239a3b", "#196127", "#0d4429"},
+			Colors:      DefaultColors,
 		}
 	}
 
-	// determine date range from options
+	// determine date range from options (ending at now with ClampToNow)
+	opts = opts.clamped()
 	startDate := opts.From
 	endDate := opts.To
 
@@ -32,7 +33,7 @@ func GenerateWeeklyHeatmapSVG(data []Data, opts *Options) string {
 
 	// map date+hour to value
 	// key format: "2006-01-02-slot" where slot is 0-5
-	valueMap := make(map[string]int, len(data))
+	valueMap := make(map[string]float64, len(data))
 	for _, d := range data {
 		hour := d.Date.Hour()
 		slot := hour / 4 // 0-5 for 6 time slots
@@ -64,15 +65,20 @@ func GenerateWeeklyHeatmapSVG(data []Data, opts *Options) string {
 	}
 
 	// calculate width considering extra spacing between weeks
+	// (twice the padding between Sunday and Monday), shrinking cells to fit MaxWidth/MaxHeight
 	weeks := (days + 6) / 7
+	size := func(o *Options) (int, int) {
+		width := days*(o.CellSize+o.CellPadding) + o.CellPadding + (weeks-1)*o.CellPadding*2
+		height := 6*(o.CellSize+o.CellPadding) + o.CellPadding + o.FontSize + 4 + titleHeight
+		return width, height
+	}
+	opts = opts.fitted(size)
 	weekSpacing := opts.CellPadding * 2 // extra spacing between Sunday and Monday
-	width := days*(opts.CellSize+opts.CellPadding) + opts.CellPadding + (weeks-1)*weekSpacing
-	height := 6*(opts.CellSize+opts.CellPadding) + opts.CellPadding + opts.FontSize + 4 + titleHeight
+	width, height := size(opts)
 
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf(`<svg width="%d" height="%d" xmlns="http://www.w3.org/2000/svg">`+"\n", width, height))
-	sb.WriteString(fmt.Sprintf(`  <style>.label{font-family:%s;font-size:%dpx;fill:#666}.title{font-family:%s;font-size:%dpx;fill:#333;font-weight:bold}</style>`+"\n",
-		opts.FontFamily, opts.FontSize, opts.FontFamily, opts.FontSize))
+	sb.WriteString(opts.svgOpenTag(width, height))
+	sb.WriteString(opts.styleElement())
 
 	// render title if project name or tags are provided
 	if opts.ProjectName != "" || len(opts.Tags) > 0 {
@@ -97,15 +103,13 @@ func GenerateWeeklyHeatmapSVG(data []Data, opts *Options) string {
 	dateLabelY := opts.FontSize + titleHeight
 	oneDay := 24 * time.Hour
 
-	// find the maximum value for auto-scaling
-	supValue := 5
-	for _, d := range data {
-		if d.Value+1 > supValue {
-			supValue = d.Value + 1
-		}
-	}
-
-	levels := len(opts.Colors)
+	// auto-scale level thresholds to the maximum value
+	colors := opts.palette()
+	sup := supValue(data)
+	thresholds := levelThresholds(sup, len(colors))
+	now := opts.now()
+	todayKey := now.Format("2006-01-02")
+	todaySlot := now.Hour() / 4
 
 	// draw cells
 	for d := 0; d < days; d++ {
@@ -138,34 +142,25 @@ func GenerateWeeklyHeatmapSVG(data []Data, opts *Options) string {
 			key := fmt.Sprintf("%s-%d", dateKey, slot)
 			value := valueMap[key] // 存在しない場合は0
 
-			level := 0
-			// 0値の場合は常にレベル0（薄いグレー）を使用
-			if value == 0 {
-				level = 0
-			} else if supValue > 1 {
-				// 1以上の値を1からlevels-1の範囲に分散
-				level = ((value-1)*(levels-2))/(supValue-1) + 1
-				if level >= levels {
-					level = levels - 1
-				}
-				if level < 1 {
-					level = 1
-				}
-			} else {
-				level = 1
-			}
+			// 0値は常にレベル0（薄いグレー）、それ以外は閾値に応じてMinNonZeroLevel（既定は1）以上のレベルに分散
+			level := opts.level(value, thresholds, len(colors))
+			fill := opts.gradientFill(value, sup, colors[level])
 
 			y := opts.CellPadding + opts.FontSize + 4 + titleHeight + slot*(opts.CellSize+opts.CellPadding)
 
+			// 現在の時間帯のセルは枠線で強調（塗り色とスケーリングには影響しない）、それ以外はCellStrokeの枠線
+			extraAttrs := opts.cellRadiusAttrs() + opts.cellStrokeAttrs(dateKey == todayKey && slot == todaySlot)
+
 			// 各セルに矩形と、その中にtitle要素（ツールチップ）を追加
-			sb.WriteString(fmt.Sprintf(`  <rect x="%d" y="%d" width="%d" height="%d" fill="%s" data-date="%s" data-slot="%d" data-value="%d">`+"\n",
-				x, y, opts.CellSize, opts.CellSize, opts.Colors[level], dateKey, slot, value))
+			sb.WriteString(fmt.Sprintf(`  <rect x="%d" y="%d" width="%d" height="%d" fill="%s" data-date="%s" data-slot="%d" data-value="%s"%s>`+"\n",
+				x, y, opts.CellSize, opts.CellSize, fill, dateKey, slot, formatValue(value), extraAttrs))
 
 			// 日付と時間帯をフォーマットして表示用の文字列を作成
 			displayDate := current.Format("2006年01月02日")
 			timeSlotLabel := fmt.Sprintf("%02d:00-%02d:00", slot*4, (slot+1)*4)
-			sb.WriteString(fmt.Sprintf(`    <title>%s %s: %d</title>`+"\n", displayDate, timeSlotLabel, value))
+			sb.WriteString(fmt.Sprintf(`    <title>%s %s: %s</title>`+"\n", displayDate, timeSlotLabel, opts.displayValue(value)))
 			sb.WriteString(`  </rect>` + "\n")
+			sb.WriteString(opts.valueText(x, y, value, fill))
 		}
 	}
 
@@ -314,3 +314,115 @@ func TestGenerateWeeklyHeatmapSVG_EndDateOnSunday(t *testing.T) {
 		t.Error("Future date 2025-05-26 should not be included")
 	}
 }
+
+func TestGenerateWeeklyHeatmapSVG_ShortPaletteFallback(t *testing.T) {
+	// 2色未満のパレットでもパニックせずデフォルトパレットで描画される
+	data := []Data{
+		{Date: time.Date(2025, 5, 21, 10, 0, 0, 0, time.UTC), Value: 3},
+	}
+	opts := &Options{
+		CellSize:    12,
+		CellPadding: 2,
+		FontSize:    10,
+		FontFamily:  "sans-serif",
+		Colors:      []string{"#000000"},
+		From:        time.Date(2025, 5, 19, 0, 0, 0, 0, time.UTC),
+		To:          time.Date(2025, 5, 25, 0, 0, 0, 0, time.UTC),
+	}
+
+	svg := GenerateWeeklyHeatmapSVG(data, opts)
+
+	if !strings.Contains(svg, `fill="`+DefaultColors[0]+`"`) {
+		t.Error("Expected default zero color to be used")
+	}
+	if strings.Contains(svg, `fill="#000000"`) {
+		t.Error("Expected short palette to be ignored")
+	}
+}
+
+func TestGenerateWeeklyHeatmapSVG_CellRadius(t *testing.T) {
+	opts := &Options{
+		CellSize:    12,
+		CellPadding: 2,
+		CellRadius:  2,
+		FontSize:    10,
+		FontFamily:  "sans-serif",
+		Colors:      DefaultColors,
+		From:        time.Date(2025, 5, 19, 0, 0, 0, 0, time.UTC),
+		To:          time.Date(2025, 5, 25, 0, 0, 0, 0, time.UTC),
+	}
+
+	svg := GenerateWeeklyHeatmapSVG(nil, opts)
+
+	if count := strings.Count(svg, ` rx="2" ry="2"`); count != strings.Count(svg, "<rect ") {
+		t.Errorf("Expected every cell to be rounded, got %d of %d", count, strings.Count(svg, "<rect "))
+	}
+}
+
+func TestGenerateWeeklyHeatmapSVG_CellStroke(t *testing.T) {
+	opts := &Options{
+		CellSize:    12,
+		CellPadding: 2,
+		FontSize:    10,
+		FontFamily:  "sans-serif",
+		Colors:      DefaultColors,
+		From:        time.Date(2025, 5, 19, 0, 0, 0, 0, time.UTC),
+		To:          time.Date(2025, 5, 25, 0, 0, 0, 0, time.UTC),
+	}
+
+	// 未設定時は枠線なし
+	if svg := GenerateWeeklyHeatmapSVG(nil, opts); strings.Contains(svg, "stroke=") {
+		t.Error("Expected no stroke when CellStroke is unset")
+	}
+
+	opts.CellStroke = "#ccc"
+	opts.CellStrokeWidth = 2
+	svg := GenerateWeeklyHeatmapSVG(nil, opts)
+
+	if count := strings.Count(svg, ` stroke="#ccc" stroke-width="2"`); count != strings.Count(svg, "<rect ") {
+		t.Errorf("Expected every cell to have a border, got %d of %d", count, strings.Count(svg, "<rect "))
+	}
+}
+
+func TestGenerateWeeklyHeatmapSVG_HighlightToday(t *testing.T) {
+	opts := &Options{
+		CellSize:       12,
+		CellPadding:    2,
+		FontSize:       10,
+		FontFamily:     "sans-serif",
+		Colors:         DefaultColors,
+		From:           time.Date(2025, 5, 19, 0, 0, 0, 0, time.UTC),
+		To:             time.Date(2025, 5, 25, 0, 0, 0, 0, time.UTC),
+		Now:            time.Date(2025, 5, 21, 10, 0, 0, 0, time.UTC),
+		HighlightToday: true,
+	}
+
+	svg := GenerateWeeklyHeatmapSVG(nil, opts)
+
+	if count := strings.Count(svg, `data-today="true"`); count != 1 {
+		t.Fatalf("Expected exactly 1 today marker, got %d", count)
+	}
+	// 10時はスロット2（8-12時）
+	if !strings.Contains(svg, `data-date="2025-05-21" data-slot="2" data-value="0" stroke=`) {
+		t.Error("Expected the current time slot of today to be outlined")
+	}
+}
+
+func TestGenerateWeeklyHeatmapSVG_Responsive(t *testing.T) {
+	opts := &Options{
+		CellSize:    12,
+		CellPadding: 2,
+		FontSize:    10,
+		FontFamily:  "sans-serif",
+		Colors:      DefaultColors,
+		From:        time.Date(2025, 5, 19, 0, 0, 0, 0, time.UTC),
+		To:          time.Date(2025, 5, 25, 0, 0, 0, 0, time.UTC),
+		Responsive:  true,
+	}
+
+	svg := GenerateWeeklyHeatmapSVG(nil, opts)
+
+	if !strings.Contains(svg, `<svg viewBox="0 0 `) || !strings.Contains(svg, `width="100%"`) {
+		t.Errorf("Expected a responsive root element, got %s", svg[:strings.Index(svg, "\n")])
+	}
+}
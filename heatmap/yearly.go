@@ -18,11 +18,12 @@ func GenerateYearlyHeatmapSVG(data []Data, opts *Options) string {
 			CellPadding: 2,
 			FontSize:    10,
 			FontFamily:  "sans-serif",
-			Colors:      []string{"#f0f0f0", "#c6e48b", "#7bc96f", "#239a3b", "#196127", "#0d4429"},
+			Colors:      DefaultColors,
 		}
 	}
 
-	// determine date range from options
+	// determine date range from options (ending at now with ClampToNow)
+	opts = opts.clamped()
 	startDate := opts.From
 	endDate := opts.To
 
@@ -33,7 +34,7 @@ func GenerateYearlyHeatmapSVG(data []Data, opts *Options) string {
 
 	// map date string to value
 	// Aggregates values for duplicate dates (same date can appear multiple times)
-	valueMap := make(map[string]int, len(data))
+	valueMap := make(map[string]float64, len(data))
 	for _, d := range data {
 		key := d.Date.Format("2006-01-02")
 		valueMap[key] += d.Value
@@ -48,18 +49,49 @@ func GenerateYearlyHeatmapSVG(data []Data, opts *Options) string {
 	dayDiff := endDate.Sub(firstSunday).Hours() / 24
 	weeks := int(dayDiff/7) + 1 // add 1 to ensure we have enough columns
 
-	// compute dimensions
+	// find the week columns where a new month starts;
+	// MonthGap is inserted before each of them except the first column
+	oneDay := 24 * time.Hour
+	monthStarts := make([]bool, weeks)
+	monthGaps := 0
+	lastMonth := -1
+	for w := range weeks {
+		current := firstSunday.Add(time.Duration(w*7) * oneDay)
+		if current.Day() <= 7 && int(current.Month())-1 != lastMonth {
+			monthStarts[w] = true
+			if w > 0 {
+				monthGaps++
+			}
+			lastMonth = int(current.Month()) - 1
+		}
+	}
+
+	// compute dimensions, shrinking cells to fit MaxWidth/MaxHeight
 	titleHeight := 0
 	if opts.ProjectName != "" || len(opts.Tags) > 0 {
 		titleHeight = opts.FontSize + 8 // title text + padding
 	}
-	width := weeks*(opts.CellSize+opts.CellPadding) + opts.CellPadding
-	height := 7*(opts.CellSize+opts.CellPadding) + opts.CellPadding + opts.FontSize + 4 + titleHeight
+	size := func(o *Options) (int, int) {
+		width := weeks*(o.CellSize+o.CellPadding) + o.CellPadding + monthGaps*o.MonthGap
+		height := 7*(o.CellSize+o.CellPadding) + o.CellPadding + o.FontSize + 4 + titleHeight
+		return width, height
+	}
+	opts = opts.fitted(size)
+	width, height := size(opts)
+
+	// x offset of each week column
+	columnX := make([]int, weeks)
+	gapOffset := 0
+	for w := range weeks {
+		if monthStarts[w] && w > 0 {
+			gapOffset += opts.MonthGap
+		}
+		columnX[w] = opts.CellPadding + w*(opts.CellSize+opts.CellPadding) + gapOffset
+	}
 
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf(`<svg width="%d" height="%d" xmlns="http://www.w3.org/2000/svg">`+"\n", width, height))
-	sb.WriteString(fmt.Sprintf(`  <style>.label{font-family:%s;font-size:%dpx;fill:#666}.title{font-family:%s;font-size:%dpx;fill:#333;font-weight:bold}</style>`+"\n",
-		opts.FontFamily, opts.FontSize, opts.FontFamily, opts.FontSize))
+	sb.WriteString(opts.svgOpenTag(width, height))
+	sb.WriteString(opts.styleElement())
 
 	// render title if project name or tags are provided
 	if opts.ProjectName != "" || len(opts.Tags) > 0 {
@@ -82,29 +114,22 @@ func GenerateYearlyHeatmapSVG(data []Data, opts *Options) string {
 
 	// month labels
 	months := []string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"}
-	lastMonth := -1
-	oneDay := 24 * time.Hour
 	monthLabelY := opts.FontSize + titleHeight
 	for w := range weeks {
-		x := opts.CellPadding + w*(opts.CellSize+opts.CellPadding)
-		current := firstSunday.Add(time.Duration(w*7) * oneDay)
-		if current.Day() <= 7 && int(current.Month())-1 != lastMonth {
+		if monthStarts[w] {
+			current := firstSunday.Add(time.Duration(w*7) * oneDay)
 			sb.WriteString(fmt.Sprintf(`  <text x="%d" y="%d" class="label">%s</text>`+"\n",
-				x, monthLabelY, months[current.Month()-1]))
-			lastMonth = int(current.Month()) - 1
+				columnX[w], monthLabelY, months[current.Month()-1]))
 		}
 	}
 
-	// find the maximum value for auto-scaling
-	supValue := 5
-	for _, d := range data {
-		if d.Value+1 > supValue {
-			supValue = d.Value + 1
-		}
-	}
+	// auto-scale level thresholds to the maximum value
+	colors := opts.palette()
+	sup := supValue(data)
+	thresholds := levelThresholds(sup, len(colors))
 
 	// draw cells with 0 value special handling
-	levels := len(opts.Colors)
+	todayKey := opts.now().Format("2006-01-02")
 	for w := range weeks {
 		for i := range 7 {
 			current := firstSunday.Add(time.Duration(w*7+i) * oneDay)
@@ -116,34 +141,30 @@ func GenerateYearlyHeatmapSVG(data []Data, opts *Options) string {
 
 			key := current.Format("2006-01-02")
 			value := valueMap[key] // 存在しない場合は0
-			level := 0
-
-			// 0値の場合は常にレベル0（薄いグレー）を使用
-			if value == 0 {
-				level = 0
-			} else if supValue > 1 {
-				// 1以上の値を1からlevels-1の範囲に分散
-				level = ((value-1)*(levels-2))/(supValue-1) + 1
-				if level >= levels {
-					level = levels - 1
-				}
-				if level < 1 {
-					level = 1
-				}
-			} else {
-				level = 1
-			}
-			x := opts.CellPadding + w*(opts.CellSize+opts.CellPadding)
+			// 0値は常にレベル0（薄いグレー）、それ以外は閾値に応じてMinNonZeroLevel（既定は1）以上のレベルに分散
+			level := opts.level(value, thresholds, len(colors))
+			x := columnX[w]
 			y := opts.CellPadding + opts.FontSize + 4 + titleHeight + i*(opts.CellSize+opts.CellPadding)
 
+			// 今日のセルは枠線で強調（塗り色とスケーリングには影響しない）、それ以外はCellStrokeの枠線
+			extraAttrs := opts.cellRadiusAttrs() + opts.cellStrokeAttrs(key == todayKey)
+
+			// 週末のセルはWeekendTintを混ぜた色にし、CSSで指定できるよう属性を付ける
+			fill := opts.tagFill(key, value, opts.gradientFill(value, sup, colors[level]))
+			if opts.WeekendTint != "" && isWeekend(current) {
+				fill = opts.weekendFill(fill)
+				extraAttrs += ` data-weekend="true"`
+			}
+
 			// 各セルに矩形と、その中にtitle要素（ツールチップ）を追加
-			sb.WriteString(fmt.Sprintf(`  <rect x="%d" y="%d" width="%d" height="%d" fill="%s" data-date="%s" data-value="%d">`+"\n",
-				x, y, opts.CellSize, opts.CellSize, opts.Colors[level], key, value))
+			sb.WriteString(fmt.Sprintf(`  <rect x="%d" y="%d" width="%d" height="%d" fill="%s" data-date="%s" data-value="%s"%s>`+"\n",
+				x, y, opts.CellSize, opts.CellSize, fill, key, formatValue(value), extraAttrs))
 
 			// 日付をフォーマットして表示用の文字列を作成
 			displayDate := current.Format("2006年01月02日")
-			sb.WriteString(fmt.Sprintf(`    <title>%s: %d</title>`+"\n", displayDate, value))
+			sb.WriteString(fmt.Sprintf(`    <title>%s: %s</title>`+"\n", displayDate, opts.displayValue(value)))
 			sb.WriteString(`  </rect>` + "\n")
+			sb.WriteString(opts.valueText(x, y, value, fill))
 		}
 	}
 
@@ -1,6 +1,8 @@
 package heatmap
 
 import (
+	"fmt"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -82,3 +84,512 @@ func TestGenerateYearlyHeatmapSVG_EndDateOnSunday(t *testing.T) {
 		t.Error("Future date 2025-01-06 should not be included")
 	}
 }
+
+func TestGenerateYearlyHeatmapSVG_ShortPaletteFallback(t *testing.T) {
+	// 2色未満のパレットはデフォルトパレットにフォールバックする
+	data := []Data{
+		{Date: time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC), Value: 3},
+	}
+
+	for _, colors := range [][]string{nil, {"#000000"}} {
+		opts := &Options{
+			CellSize:    12,
+			CellPadding: 2,
+			FontSize:    10,
+			FontFamily:  "sans-serif",
+			Colors:      colors,
+			From:        time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			To:          time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+		}
+
+		svg := GenerateYearlyHeatmapSVG(data, opts)
+
+		if !strings.Contains(svg, `fill="`+DefaultColors[0]+`" data-date="2025-01-01"`) {
+			t.Errorf("Expected zero day to use default zero color with palette %v", colors)
+		}
+		if strings.Contains(svg, `fill="`+DefaultColors[0]+`" data-date="2025-01-05"`) {
+			t.Errorf("Expected non-zero day not to use zero color with palette %v", colors)
+		}
+	}
+}
+
+func TestGenerateYearlyHeatmapSVG_CellRadius(t *testing.T) {
+	data := []Data{
+		{Date: time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC), Value: 3},
+	}
+	opts := &Options{
+		CellSize:    12,
+		CellPadding: 2,
+		FontSize:    10,
+		FontFamily:  "sans-serif",
+		Colors:      DefaultColors,
+		From:        time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:          time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+	}
+
+	// デフォルトは角丸なし
+	svg := GenerateYearlyHeatmapSVG(data, opts)
+	if strings.Contains(svg, ` rx="`) {
+		t.Error("Expected square cells by default")
+	}
+
+	opts.CellRadius = 2
+	svg = GenerateYearlyHeatmapSVG(data, opts)
+	if count := strings.Count(svg, ` rx="2" ry="2"`); count != strings.Count(svg, "<rect ") {
+		t.Errorf("Expected every cell to be rounded, got %d of %d", count, strings.Count(svg, "<rect "))
+	}
+}
+
+func TestGenerateYearlyHeatmapSVG_HighlightToday(t *testing.T) {
+	data := []Data{
+		{Date: time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC), Value: 2},
+	}
+	opts := &Options{
+		CellSize:    12,
+		CellPadding: 2,
+		FontSize:    10,
+		FontFamily:  "sans-serif",
+		Colors:      DefaultColors,
+		From:        time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:          time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+		Now:         time.Date(2025, 1, 10, 15, 0, 0, 0, time.UTC),
+	}
+
+	// 無効時は枠線なし
+	svg := GenerateYearlyHeatmapSVG(data, opts)
+	if strings.Contains(svg, `data-today`) {
+		t.Error("Expected no today marker when HighlightToday is disabled")
+	}
+	plain := svg
+
+	opts.HighlightToday = true
+	svg = GenerateYearlyHeatmapSVG(data, opts)
+
+	if count := strings.Count(svg, `data-today="true"`); count != 1 {
+		t.Fatalf("Expected exactly 1 today marker, got %d", count)
+	}
+	if !strings.Contains(svg, `data-date="2025-01-10" data-value="2" stroke="`) {
+		t.Error("Expected today's cell to be outlined without changing its fill")
+	}
+	// 枠線以外の出力は変わらない
+	if strings.ReplaceAll(svg, ` stroke="`+todayStrokeColor+`" stroke-width="1" data-today="true"`, "") != plain {
+		t.Error("Expected highlight to only add the outline attributes")
+	}
+}
+
+func TestGenerateYearlyHeatmapSVG_FractionalValues(t *testing.T) {
+	// 小数の値は合計してから段階に分散される
+	data := []Data{
+		{Date: time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC), Value: 0.5},
+		{Date: time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC), Value: 1.25},
+		{Date: time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC), Value: 2.5},
+		{Date: time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), Value: 9.5},
+	}
+
+	opts := &Options{
+		CellSize:    12,
+		CellPadding: 2,
+		FontSize:    10,
+		FontFamily:  "sans-serif",
+		Colors:      DefaultColors,
+		From:        time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:          time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+	}
+
+	svg := GenerateYearlyHeatmapSVG(data, opts)
+
+	for _, expected := range []string{
+		// 1未満の正の値も最低段階で表示される
+		`fill="#c6e48b" data-date="2025-01-05" data-value="0.5"`,
+		`fill="#7bc96f" data-date="2025-01-10" data-value="3.75"`,
+		`fill="#196127" data-date="2025-01-15" data-value="9.5"`,
+		`<title>2025年01月10日: 3.75</title>`,
+		// 整数値は小数点なしで表示される
+		`data-date="2025-01-01" data-value="0"`,
+	} {
+		if !strings.Contains(svg, expected) {
+			t.Errorf("Expected SVG to contain %q", expected)
+		}
+	}
+}
+
+func TestGenerateYearlyHeatmapSVG_FontStack(t *testing.T) {
+	opts := &Options{
+		CellSize:    12,
+		CellPadding: 2,
+		FontSize:    10,
+		Colors:      DefaultColors,
+		From:        time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:          time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+	}
+
+	// FontFamily未指定の場合はクロスプラットフォームのフォントスタックを使用
+	svg := GenerateYearlyHeatmapSVG(nil, opts)
+	if !strings.Contains(svg, `.label{font-family:`+DefaultFontFamily+`;`) {
+		t.Errorf("Expected the style block to include the default font stack, got %s", svg)
+	}
+	if strings.Contains(svg, "@font-face") {
+		t.Error("Expected no @font-face without FontDataURI")
+	}
+
+	// 指定されたFontFamilyはそのまま使用
+	opts.FontFamily = "sans-serif"
+	svg = GenerateYearlyHeatmapSVG(nil, opts)
+	if !strings.Contains(svg, `.label{font-family:sans-serif;`) {
+		t.Error("Expected FontFamily to be used verbatim")
+	}
+
+	// FontDataURIを指定するとフォントを埋め込み、スタックの先頭に追加
+	opts.FontDataURI = "data:font/woff2;base64,d09GMgABAAAAAA=="
+	svg = GenerateYearlyHeatmapSVG(nil, opts)
+	if !strings.Contains(svg, `@font-face{font-family:"sougen-embedded";src:url("data:font/woff2;base64,d09GMgABAAAAAA==")}`) {
+		t.Errorf("Expected an embedded @font-face, got %s", svg)
+	}
+	if !strings.Contains(svg, `.title{font-family:"sougen-embedded", sans-serif;`) {
+		t.Error("Expected the embedded font to come first in the font stack")
+	}
+}
+
+func TestGenerateYearlyHeatmapSVG_Responsive(t *testing.T) {
+	opts := &Options{
+		CellSize:    12,
+		CellPadding: 2,
+		FontSize:    10,
+		FontFamily:  "sans-serif",
+		Colors:      DefaultColors,
+		From:        time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:          time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+	}
+
+	// デフォルトは固定サイズ（<img>での埋め込み向け）
+	fixed := GenerateYearlyHeatmapSVG(nil, opts)
+	if strings.Contains(fixed, "viewBox") || strings.Contains(fixed, `width="100%"`) {
+		t.Error("Expected fixed pixel dimensions by default")
+	}
+
+	opts.Responsive = true
+	svg := GenerateYearlyHeatmapSVG(nil, opts)
+	var width, height int
+	if _, err := fmt.Sscanf(fixed, `<svg width="%d" height="%d"`, &width, &height); err != nil {
+		t.Fatalf("Failed to parse fixed dimensions: %v", err)
+	}
+	if !strings.Contains(svg, fmt.Sprintf(`viewBox="0 0 %d %d"`, width, height)) {
+		t.Errorf("Expected viewBox matching the fixed size %dx%d, got %s", width, height, svg[:strings.Index(svg, "\n")])
+	}
+	if !strings.Contains(svg, `width="100%"`) || strings.Contains(svg, fmt.Sprintf(`height="%d"`, height)) {
+		t.Error("Expected width=\"100%\" without a fixed height")
+	}
+}
+
+func TestGenerateYearlyHeatmapSVG_MinNonZeroLevel(t *testing.T) {
+	// すべて1のデータは通常は最も薄い緑（レベル1）になる
+	var data []Data
+	for day := 1; day <= 10; day++ {
+		data = append(data, Data{Date: time.Date(2025, 1, day, 0, 0, 0, 0, time.UTC), Value: 1})
+	}
+	opts := &Options{
+		CellSize:    12,
+		CellPadding: 2,
+		FontSize:    10,
+		FontFamily:  "sans-serif",
+		Colors:      DefaultColors,
+		From:        time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:          time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+	}
+
+	svg := GenerateYearlyHeatmapSVG(data, opts)
+	if count := strings.Count(svg, `fill="`+DefaultColors[1]+`"`); count != len(data) {
+		t.Fatalf("Expected %d cells at level 1 by default, got %d", len(data), count)
+	}
+
+	opts.MinNonZeroLevel = 2
+	svg = GenerateYearlyHeatmapSVG(data, opts)
+	if count := strings.Count(svg, `fill="`+DefaultColors[2]+`"`); count != len(data) {
+		t.Errorf("Expected %d cells at the minimum level 2, got %d", len(data), count)
+	}
+	if strings.Contains(svg, `fill="`+DefaultColors[1]+`"`) {
+		t.Error("Expected no non-zero cells below the minimum level")
+	}
+	// 0値のセルはレベル0のまま
+	if !strings.Contains(svg, `fill="`+DefaultColors[0]+`" data-date="2025-01-11"`) {
+		t.Error("Expected zero cells to stay at level 0")
+	}
+}
+
+func TestGenerateYearlyHeatmapSVG_MonthGap(t *testing.T) {
+	opts := &Options{
+		CellSize:    12,
+		CellPadding: 2,
+		FontSize:    10,
+		FontFamily:  "sans-serif",
+		Colors:      DefaultColors,
+		From:        time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:          time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC),
+	}
+
+	labelX := func(svg, month string) int {
+		t.Helper()
+		end := strings.Index(svg, `class="label">`+month+`<`)
+		if end < 0 {
+			t.Fatalf("Month label %s not found", month)
+		}
+		start := strings.LastIndex(svg[:end], `<text x="`)
+		var x int
+		if _, err := fmt.Sscanf(svg[start:end], `<text x="%d"`, &x); err != nil {
+			t.Fatalf("Failed to parse x of %s: %v", month, err)
+		}
+		return x
+	}
+	svgWidth := func(svg string) int {
+		t.Helper()
+		var width int
+		if _, err := fmt.Sscanf(svg, `<svg width="%d"`, &width); err != nil {
+			t.Fatalf("Failed to parse width: %v", err)
+		}
+		return width
+	}
+
+	base := GenerateYearlyHeatmapSVG(nil, opts)
+	opts.MonthGap = 6
+	gapped := GenerateYearlyHeatmapSVG(nil, opts)
+
+	// 2024-12-29から始まる最初の列は12月の続きなので、Jan/Feb/Marの3列の前に隙間が入る
+	if got, want := svgWidth(gapped), svgWidth(base)+3*6; got != want {
+		t.Errorf("Expected width %d, got %d", want, got)
+	}
+	for i, month := range []string{"Jan", "Feb", "Mar"} {
+		if got, want := labelX(gapped, month), labelX(base, month)+(i+1)*6; got != want {
+			t.Errorf("Expected %s label at x=%d, got %d", month, want, got)
+		}
+	}
+	// 月の最初の列のセルもラベルと同じ位置に移動する（2025-01-05は1月の最初の列）
+	if want := fmt.Sprintf(`<rect x="%d" y="%d" width="12" height="12" fill="%s" data-date="2025-01-05"`,
+		labelX(gapped, "Jan"), 2+10+4, DefaultColors[0]); !strings.Contains(gapped, want) {
+		t.Errorf("Expected first cell of January at the label position: %s", want)
+	}
+}
+
+func TestGenerateYearlyHeatmapSVG_ShowValues(t *testing.T) {
+	data := []Data{
+		{Date: time.Date(2025, 5, 20, 0, 0, 0, 0, time.UTC), Value: 7},
+		{Date: time.Date(2025, 5, 21, 0, 0, 0, 0, time.UTC), Value: 1},
+	}
+	opts := &Options{
+		CellSize:    20,
+		CellPadding: 2,
+		FontSize:    10,
+		Colors:      DefaultColors,
+		From:        time.Date(2025, 5, 19, 0, 0, 0, 0, time.UTC),
+		To:          time.Date(2025, 5, 25, 0, 0, 0, 0, time.UTC),
+		ShowValues:  true,
+	}
+
+	svg := GenerateYearlyHeatmapSVG(data, opts)
+
+	// the highest value gets the darkest color, so its text is white
+	if !strings.Contains(svg, `fill="#fff" pointer-events="none">7</text>`) {
+		t.Errorf("Expected value text for the non-zero cell, got:\n%s", svg)
+	}
+	if !strings.Contains(svg, `pointer-events="none">1</text>`) {
+		t.Error("Expected value text for the low-value cell")
+	}
+	if count := strings.Count(svg, `pointer-events="none">`); count != 2 {
+		t.Errorf("Expected value text only for the 2 non-zero cells, got %d", count)
+	}
+
+	// values do not fit in small cells
+	opts.CellSize = 12
+	svg = GenerateYearlyHeatmapSVG(data, opts)
+	if strings.Contains(svg, `pointer-events="none">`) {
+		t.Error("Expected no value text when cells are too small")
+	}
+}
+
+func TestGenerateYearlyHeatmapSVG_FormatNumbers(t *testing.T) {
+	data := []Data{
+		{Date: time.Date(2025, 5, 20, 0, 0, 0, 0, time.UTC), Value: 12345},
+		{Date: time.Date(2025, 5, 21, 0, 0, 0, 0, time.UTC), Value: 1234.5},
+	}
+	opts := &Options{
+		CellSize:    20,
+		CellPadding: 2,
+		FontSize:    10,
+		Colors:      DefaultColors,
+		From:        time.Date(2025, 5, 19, 0, 0, 0, 0, time.UTC),
+		To:          time.Date(2025, 5, 25, 0, 0, 0, 0, time.UTC),
+		ShowValues:  true,
+	}
+
+	// no separators by default
+	svg := GenerateYearlyHeatmapSVG(data, opts)
+	if !strings.Contains(svg, "<title>2025年05月20日: 12345</title>") {
+		t.Errorf("Expected raw value in tooltip by default, got:\n%s", svg)
+	}
+
+	opts.FormatNumbers = true
+	opts.Locale = "en-US"
+	svg = GenerateYearlyHeatmapSVG(data, opts)
+	for _, want := range []string{
+		"<title>2025年05月20日: 12,345</title>",
+		`pointer-events="none">12,345</text>`,
+		"<title>2025年05月21日: 1,234.5</title>",
+		`data-value="12345"`, // machine-readable attributes stay unformatted
+	} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("Expected SVG to contain %q", want)
+		}
+	}
+
+	opts.Locale = "de"
+	svg = GenerateYearlyHeatmapSVG(data, opts)
+	if !strings.Contains(svg, "<title>2025年05月21日: 1.234,5</title>") {
+		t.Error("Expected German separators in tooltip")
+	}
+}
+
+func TestGenerateYearlyHeatmapSVG_MaxWidth(t *testing.T) {
+	opts := &Options{
+		CellSize:    12,
+		CellPadding: 2,
+		FontSize:    10,
+		Colors:      DefaultColors,
+		From:        time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:          time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC),
+	}
+
+	// two years are 1472px wide at the default cell size
+	svg := GenerateYearlyHeatmapSVG(nil, opts)
+	if !strings.Contains(svg, `<svg width="1472"`) {
+		t.Fatalf("Unexpected unconstrained size: %s", svg[:strings.Index(svg, "\n")])
+	}
+
+	opts.MaxWidth = 800
+	svg = GenerateYearlyHeatmapSVG(nil, opts)
+
+	var width, height int
+	if _, err := fmt.Sscanf(svg, `<svg width="%d" height="%d"`, &width, &height); err != nil {
+		t.Fatalf("Failed to parse SVG size: %v", err)
+	}
+	if width > 800 {
+		t.Errorf("Expected width <= 800, got %d", width)
+	}
+	if width < 700 {
+		t.Errorf("Expected cells to shrink only as much as needed, got width %d", width)
+	}
+	// 縮小後のセルが描画されていること
+	if !strings.Contains(svg, `<rect x="1" y="`) || strings.Contains(svg, `width="12" height="12"`) {
+		t.Error("Expected shrunk cells")
+	}
+	// 呼び出し元のOptionsは変更されない
+	if opts.CellSize != 12 || opts.CellPadding != 2 {
+		t.Errorf("Expected options to be unchanged, got CellSize=%d CellPadding=%d", opts.CellSize, opts.CellPadding)
+	}
+}
+
+func TestGenerateYearlyHeatmapSVG_WeekendTint(t *testing.T) {
+	opts := &Options{
+		CellSize:    12,
+		CellPadding: 2,
+		FontSize:    10,
+		Colors:      DefaultColors,
+		From:        time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC),  // Monday
+		To:          time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC), // Sunday
+	}
+
+	// 未設定時は従来どおりの出力
+	plain := GenerateYearlyHeatmapSVG(nil, opts)
+	if strings.Contains(plain, "data-weekend") {
+		t.Error("Expected no weekend marker when WeekendTint is unset")
+	}
+
+	opts.WeekendTint = "#0000ff"
+	svg := GenerateYearlyHeatmapSVG(nil, opts)
+
+	// 2025-06-01(日)〜06-15(日)のうち土日は5日
+	if count := strings.Count(svg, `data-weekend="true"`); count != 5 {
+		t.Errorf("Expected 5 weekend cells, got %d", count)
+	}
+	for _, date := range []string{"2025-06-01", "2025-06-07", "2025-06-08", "2025-06-14", "2025-06-15"} {
+		if !strings.Contains(svg, `data-date="`+date+`" data-value="0" data-weekend="true"`) {
+			t.Errorf("Expected %s to be marked as weekend", date)
+		}
+	}
+	if strings.Contains(svg, `data-date="2025-06-09" data-value="0" data-weekend`) {
+		t.Error("Expected weekdays not to be marked")
+	}
+
+	// 週末は水準の色に青を混ぜた色、平日は水準の色のまま
+	if !strings.Contains(svg, `fill="#b4b4f4" data-date="2025-06-07"`) {
+		t.Error("Expected weekend fill blended with the tint")
+	}
+	if !strings.Contains(svg, `fill="#f0f0f0" data-date="2025-06-09"`) {
+		t.Error("Expected weekday fill unchanged")
+	}
+}
+
+func TestGenerateYearlyHeatmapSVG_CellStroke(t *testing.T) {
+	opts := &Options{
+		CellSize:        12,
+		CellPadding:     2,
+		FontSize:        10,
+		Colors:          DefaultColors,
+		From:            time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC),
+		To:              time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC),
+		Now:             time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC),
+		HighlightToday:  true,
+		CellStroke:      "#ccc",
+		CellStrokeWidth: 0, // 既定は1px
+	}
+
+	svg := GenerateYearlyHeatmapSVG(nil, opts)
+
+	// 今日のセルは強調の枠線、それ以外はCellStrokeの枠線
+	cells := strings.Count(svg, "<rect ")
+	if count := strings.Count(svg, ` stroke="#ccc" stroke-width="1"`); count != cells-1 {
+		t.Errorf("Expected %d cells with a border, got %d", cells-1, count)
+	}
+	if !strings.Contains(svg, `data-date="2025-06-10" data-value="0" stroke="`+todayStrokeColor+`"`) {
+		t.Error("Expected today's outline to take precedence over the border")
+	}
+}
+
+func TestGenerateYearlyHeatmapSVG_ClampToNow(t *testing.T) {
+	opts := &Options{
+		CellSize:    12,
+		CellPadding: 2,
+		FontSize:    10,
+		Colors:      DefaultColors,
+		From:        time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:          time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC),
+		Now:         time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC),
+	}
+
+	// 既定ではToまで描画する
+	if svg := GenerateYearlyHeatmapSVG(nil, opts); !strings.Contains(svg, `data-date="2025-12-31"`) {
+		t.Error("Expected cells up to To without ClampToNow")
+	}
+
+	// 描画された最後のセルの日付
+	lastDate := func(svg string) string {
+		last := ""
+		for _, match := range regexp.MustCompile(`data-date="([0-9-]+)"`).FindAllStringSubmatch(svg, -1) {
+			last = max(last, match[1])
+		}
+		return last
+	}
+
+	// ClampToNowでは今日のセルで終わる
+	opts.ClampToNow = true
+	if last := lastDate(GenerateYearlyHeatmapSVG(nil, opts)); last != "2025-03-10" {
+		t.Errorf("Expected the last cell to be today, got %s", last)
+	}
+	if cells := YearlyMatrix(nil, opts); len(cells.Rows[0]) != 11 {
+		t.Errorf("Expected the matrix to end at today's week (11 columns), got %d", len(cells.Rows[0]))
+	}
+
+	// Nowが期間より前の場合はFromのセルで終わる
+	opts.Now = time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)
+	if last := lastDate(GenerateYearlyHeatmapSVG(nil, opts)); last != "2025-01-01" {
+		t.Errorf("Expected the last cell to be From, got %s", last)
+	}
+}
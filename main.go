@@ -2,20 +2,35 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/stsysd/sougen/api"
 	"github.com/stsysd/sougen/config"
 	"github.com/stsysd/sougen/db"
+	"github.com/stsysd/sougen/notifier"
 	"github.com/stsysd/sougen/store"
 )
 
+// shutdownTimeout はシャットダウン時に処理中のリクエストと通知の完了を待つ時間です。
+const shutdownTimeout = 10 * time.Second
+
 func main() {
 	// 設定の読み込み
 	cfg := config.NewConfig()
 
 	// SQLiteストアの初期化（マイグレーション関数を渡す）
-	sqliteStore, err := store.NewSQLiteStore(cfg.DataDir, db.Migrate)
+	sqliteStore, err := store.NewSQLiteStore(cfg.DataDir, db.Migrate, store.SQLiteOptions{
+		Synchronous: cfg.SQLiteSynchronous,
+
+		CaseInsensitiveProjectNames: cfg.CaseInsensitiveProjectNames,
+	})
 	if err != nil {
 		log.Fatalf("Failed to initialize SQLite store: %v", err)
 	}
@@ -24,6 +39,43 @@ func main() {
 	// サーバーインスタンスの作成
 	server := api.NewServer(sqliteStore, cfg)
 
+	// Webhook通知の設定
+	var webhook *notifier.Notifier
+	if cfg.WebhookURL != "" {
+		webhook = notifier.New(cfg.WebhookURL, notifier.Options{
+			BatchSize:     cfg.WebhookBatchSize,
+			BatchInterval: cfg.WebhookBatchInterval,
+		})
+		server.SetNotifier(webhook)
+	}
+
 	// サーバーの起動
-	log.Fatal(server.Run(":" + cfg.Port))
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.Run(":" + cfg.Port)
+	}()
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	case <-ctx.Done():
+		log.Printf("Shutting down server")
+	}
+
+	// シャットダウン（処理中のリクエストを待ってから未送信の通知をフラッシュ）
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Error shutting down server: %v", err)
+	}
+	if webhook != nil {
+		if err := webhook.Close(shutdownCtx); err != nil {
+			log.Printf("Error flushing webhook notifications: %v", err)
+		}
+	}
 }
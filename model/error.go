@@ -7,6 +7,21 @@ import "errors"
 var (
 	ErrRecordNotFound  = errors.New("record not found")
 	ErrProjectNotFound = errors.New("project not found")
+
+	ErrTagAliasNotFound = errors.New("tag alias not found")
+)
+
+// センチネルエラー - 一意制約に違反する場合
+var (
+	ErrProjectNameTaken = errors.New("project name already exists")
+	ErrProjectIDTaken   = errors.New("project id already exists")
+
+	ErrExternalIDTaken = errors.New("external id already exists")
+)
+
+// センチネルエラー - 件数の上限に達した場合
+var (
+	ErrProjectLimitReached = errors.New("project limit reached")
 )
 
 // ValidationError はバリデーションエラーを表す型
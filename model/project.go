@@ -2,7 +2,12 @@
 package model
 
 import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
+	"unicode"
 )
 
 // Project はプロジェクトエンティティを表すモデルです。
@@ -12,6 +17,68 @@ type Project struct {
 	Description string    `json:"description"` // プロジェクトの説明
 	CreatedAt   time.Time `json:"created_at"`  // 作成日時
 	UpdatedAt   time.Time `json:"updated_at"`  // 更新日時
+
+	TrackDefaultValue *int      `json:"track_default_value"` // trackで作成するレコードの既定値（nilの場合は1）
+	Color             *string   `json:"color"`               // UIでの表示色（#RGBまたは#RRGGBB、nilの場合は未設定）
+	ValueType         ValueType `json:"value_type"`          // レコード値の型（"int"または"float"）
+	RetentionDays     *int      `json:"retention_days"`      // レコードの保持日数（超過したレコードは自動削除、nilの場合は無期限）
+	MinValue          *float64  `json:"min_value"`           // レコード値の下限（nilの場合は制限なし）
+	MaxValue          *float64  `json:"max_value"`           // レコード値の上限（nilの場合は制限なし）
+	Timezone          *string   `json:"timezone"`            // グラフの日付の区切りに使うIANAタイムゾーン名（nilの場合はサーバーのタイムゾーン）
+
+	RecordCount *int `json:"record_count,omitempty"` // レコード数（records順の一覧取得時のみ設定）
+}
+
+// colorPattern はプロジェクトの表示色として許可する16進カラーコードの形式です。
+var colorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// IsHexColor は文字列が16進カラーコード（#RGBまたは#RRGGBB）かどうかを判定します。
+func IsHexColor(s string) bool {
+	return colorPattern.MatchString(s)
+}
+
+// TrackValue はアクセスカウンターで作成するレコードの値を返します。
+func (p *Project) TrackValue() int {
+	if p.TrackDefaultValue == nil {
+		return 1
+	}
+	return *p.TrackDefaultValue
+}
+
+// RecordValueType はレコード値の型を返します（未設定の場合は整数）。
+func (p *Project) RecordValueType() ValueType {
+	if p.ValueType == "" {
+		return ValueTypeInt
+	}
+	return p.ValueType
+}
+
+// Location はグラフの日付の区切りに使うタイムゾーンを返します（未設定の場合はサーバーのタイムゾーン）。
+func (p *Project) Location() *time.Location {
+	if p.Timezone == nil {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(*p.Timezone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// CheckValue はレコード値がプロジェクトの下限・上限の範囲内かを検証します。
+func (p *Project) CheckValue(value float64) error {
+	if p.MinValue != nil && value < *p.MinValue {
+		return NewValidationError(fmt.Sprintf("value must be at least %s (min_value of the project)", formatBound(*p.MinValue)))
+	}
+	if p.MaxValue != nil && value > *p.MaxValue {
+		return NewValidationError(fmt.Sprintf("value must be at most %s (max_value of the project)", formatBound(*p.MaxValue)))
+	}
+	return nil
+}
+
+// formatBound は下限・上限を末尾の0なしで文字列にします。
+func formatBound(bound float64) string {
+	return strconv.FormatFloat(bound, 'f', -1, 64)
 }
 
 // NewProject は新しいProjectインスタンスを作成します。
@@ -24,6 +91,7 @@ func NewProject(name, description string) (*Project, error) {
 		Description: description,
 		CreatedAt:   now,
 		UpdatedAt:   now,
+		ValueType:   ValueTypeInt,
 	}
 	if err := p.Validate(); err != nil {
 		return nil, err
@@ -31,6 +99,23 @@ func NewProject(name, description string) (*Project, error) {
 	return p, nil
 }
 
+// NormalizeProjectName はプロジェクト名をURLで扱いやすい形に正規化します（SOUGEN_NORMALIZE_PROJECT_NAMES）。
+// 前後の空白を除き、連続する空白（タブや改行を含む）を1つの空白にまとめます。
+// 名前によるURLのパスを壊す"/"や制御文字を含む名前はバリデーションエラーとします。
+func NormalizeProjectName(name string) (string, error) {
+	normalized := strings.Join(strings.Fields(name), " ")
+	if normalized == "" {
+		return "", NewValidationError("name is required")
+	}
+	if strings.Contains(normalized, "/") {
+		return "", NewValidationError("name must not contain '/'")
+	}
+	if strings.IndexFunc(normalized, unicode.IsControl) >= 0 {
+		return "", NewValidationError("name must not contain control characters")
+	}
+	return normalized, nil
+}
+
 // LoadProject は既存のProjectインスタンスを作成します。
 func LoadProject(id HexID, name, description string, createdAt, updatedAt time.Time) (*Project, error) {
 	p := &Project{
@@ -39,6 +124,7 @@ func LoadProject(id HexID, name, description string, createdAt, updatedAt time.T
 		Description: description,
 		CreatedAt:   createdAt,
 		UpdatedAt:   updatedAt,
+		ValueType:   ValueTypeInt,
 	}
 	if err := p.Validate(); err != nil {
 		return nil, err
@@ -57,5 +143,25 @@ func (p *Project) Validate() error {
 	if p.UpdatedAt.IsZero() {
 		return NewValidationError("updated_at is required")
 	}
+	if p.TrackDefaultValue != nil && *p.TrackDefaultValue < 1 {
+		return NewValidationError("track_default_value must be a positive integer greater than 0")
+	}
+	if p.Color != nil && !IsHexColor(*p.Color) {
+		return NewValidationError("color must be a hex color (#RGB or #RRGGBB)")
+	}
+	if p.ValueType != "" && p.ValueType != ValueTypeInt && p.ValueType != ValueTypeFloat {
+		return NewValidationError("value_type must be 'int' or 'float'")
+	}
+	if p.RetentionDays != nil && *p.RetentionDays < 1 {
+		return NewValidationError("retention_days must be a positive integer greater than 0")
+	}
+	if p.MinValue != nil && p.MaxValue != nil && *p.MinValue > *p.MaxValue {
+		return NewValidationError("min_value must not be greater than max_value")
+	}
+	if p.Timezone != nil {
+		if _, err := time.LoadLocation(*p.Timezone); err != nil || *p.Timezone == "" {
+			return NewValidationError("timezone must be an IANA time zone name (e.g. Asia/Tokyo)")
+		}
+	}
 	return nil
 }
@@ -53,6 +53,36 @@ func TestNewProjectEmptyName(t *testing.T) {
 	}
 }
 
+// TestNormalizeProjectName tests project name normalization
+func TestNormalizeProjectName(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+		wantErr  bool
+	}{
+		{"work", "work", false},
+		{"  side   project ", "side project", false},
+		{"tab\tand\nnewline", "tab and newline", false},
+		{"Work Log", "Work Log", false},
+		{"a/b", "", true},
+		{"/", "", true},
+		{"bell\x07", "", true},
+		{"   ", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeProjectName(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NormalizeProjectName(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("NormalizeProjectName(%q) = %q, want %q", tt.name, got, tt.expected)
+			}
+		})
+	}
+}
+
 // TestLoadProject tests the LoadProject constructor
 func TestLoadProject(t *testing.T) {
 	id := NewHexID(123)
@@ -184,6 +214,91 @@ func TestProjectValidate(t *testing.T) {
 			expectError: true,
 			description: "UpdatedAtがゼロ値の場合はエラーになること",
 		},
+		{
+			name: "Valid timezone",
+			project: &Project{
+				ID:        NewHexID(1),
+				Name:      "project",
+				CreatedAt: testTime(),
+				UpdatedAt: testTime(),
+				Timezone:  ptr("Asia/Tokyo"),
+			},
+			expectError: false,
+			description: "IANAタイムゾーン名は検証をパスすること",
+		},
+		{
+			name: "Invalid timezone",
+			project: &Project{
+				ID:        NewHexID(1),
+				Name:      "project",
+				CreatedAt: testTime(),
+				UpdatedAt: testTime(),
+				Timezone:  ptr("Mars/Olympus"),
+			},
+			expectError: true,
+			description: "未知のタイムゾーン名はエラーになること",
+		},
+		{
+			name: "Valid color",
+			project: &Project{
+				ID:        NewHexID(1),
+				Name:      "project",
+				CreatedAt: testTime(),
+				UpdatedAt: testTime(),
+				Color:     ptr("#1A2b3c"),
+			},
+			expectError: false,
+			description: "16進カラーコードは検証をパスすること",
+		},
+		{
+			name: "Valid short color",
+			project: &Project{
+				ID:        NewHexID(1),
+				Name:      "project",
+				CreatedAt: testTime(),
+				UpdatedAt: testTime(),
+				Color:     ptr("#fa0"),
+			},
+			expectError: false,
+			description: "3桁の16進カラーコードは検証をパスすること",
+		},
+		{
+			name: "Invalid color",
+			project: &Project{
+				ID:        NewHexID(1),
+				Name:      "project",
+				CreatedAt: testTime(),
+				UpdatedAt: testTime(),
+				Color:     ptr("red"),
+			},
+			expectError: true,
+			description: "16進カラーコード以外の場合はエラーになること",
+		},
+		{
+			name: "Zero retention days",
+			project: &Project{
+				ID:            NewHexID(1),
+				Name:          "project",
+				CreatedAt:     testTime(),
+				UpdatedAt:     testTime(),
+				RetentionDays: ptr(0),
+			},
+			expectError: true,
+			description: "保持日数が0以下の場合はエラーになること",
+		},
+		{
+			name: "Min value greater than max value",
+			project: &Project{
+				ID:        NewHexID(1),
+				Name:      "project",
+				CreatedAt: testTime(),
+				UpdatedAt: testTime(),
+				MinValue:  ptr(10.0),
+				MaxValue:  ptr(5.0),
+			},
+			expectError: true,
+			description: "下限が上限より大きい場合はエラーになること",
+		},
 	}
 
 	for _, tt := range tests {
@@ -201,3 +316,7 @@ func TestProjectValidate(t *testing.T) {
 		})
 	}
 }
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -2,6 +2,7 @@
 package model
 
 import (
+	"math"
 	"strings"
 	"time"
 )
@@ -13,8 +14,22 @@ type Record struct {
 	Value     int       `json:"value"`      // 記録値
 	Timestamp time.Time `json:"timestamp"`  // アクティビティの日時
 	Tags      []string  `json:"tags"`       // タグ一覧
+	Source    string    `json:"source"`     // レコードの作成元（"api", "track"など）
+	CreatedAt time.Time `json:"created_at"` // レコードが記録された日時（保存時に設定）
+
+	ValueFloat *float64   `json:"value_float,omitempty"` // 小数の記録値（value_typeがfloatのプロジェクトのみ、nilの場合はValueを使用）
+	DeletedAt  *time.Time `json:"deleted_at,omitempty"`  // 論理削除された日時（nilの場合は削除されていない）
+	ExternalID *string    `json:"external_id,omitempty"` // インポート元の外部システムでのID（プロジェクト内で一意、nilの場合はなし）
 }
 
+// レコードの作成元
+const (
+	// RecordSourceAPI はREST API経由で作成されたレコードを表します。
+	RecordSourceAPI = "api"
+	// RecordSourceTrack はグラフのアクセスカウンター機能で作成されたレコードを表します。
+	RecordSourceTrack = "track"
+)
+
 // NewRecord はRecordの新しいインスタンスを作成します。
 // IDはデータベース側で自動生成されるため、ゼロ値（無効な状態）を設定します。
 func NewRecord(timestamp time.Time, projectID HexID, value int, tags []string) (*Record, error) {
@@ -27,6 +42,7 @@ func NewRecord(timestamp time.Time, projectID HexID, value int, tags []string) (
 		Value:     value,
 		Timestamp: timestamp,
 		Tags:      tags,
+		Source:    RecordSourceAPI,
 	}
 	if err := rec.Validate(); err != nil {
 		return nil, err
@@ -50,6 +66,7 @@ func LoadRecord(id HexID, timestamp time.Time, projectID HexID, value int, tags
 		Value:     value,
 		Timestamp: timestamp,
 		Tags:      tags,
+		Source:    RecordSourceAPI,
 	}
 	err := rec.Validate()
 	if err != nil {
@@ -58,6 +75,38 @@ func LoadRecord(id HexID, timestamp time.Time, projectID HexID, value int, tags
 	return rec, nil
 }
 
+// Amount は集計に用いるレコード値を返します。
+// 小数の記録値が設定されている場合はそちらを優先します。
+func (r *Record) Amount() float64 {
+	if r.ValueFloat != nil {
+		return *r.ValueFloat
+	}
+	return float64(r.Value)
+}
+
+// SetValueFloat は小数の記録値を設定します。
+// 整数を前提とする集計（プロジェクトサマリーなど）のため、Valueには四捨五入した値（最小1）を設定します。
+func (r *Record) SetValueFloat(value float64) {
+	r.ValueFloat = &value
+	r.Value = max(1, int(math.Round(value)))
+}
+
+// HasSameTags はレコードのタグがtagsと同じ集合か（順序と重複を無視）を判定します。
+func (r *Record) HasSameTags(tags []string) bool {
+	set := make(map[string]bool, len(r.Tags))
+	for _, tag := range r.Tags {
+		set[tag] = true
+	}
+	others := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		if !set[tag] {
+			return false
+		}
+		others[tag] = true
+	}
+	return len(set) == len(others)
+}
+
 // Validate はレコードのデータバリデーションを行います。
 func (r *Record) Validate() error {
 	// 日時の検証
@@ -70,16 +119,29 @@ func (r *Record) Validate() error {
 		return NewValidationError("project_id is required")
 	}
 
+	// 小数の記録値の検証
+	if r.ValueFloat != nil && (math.IsNaN(*r.ValueFloat) || math.IsInf(*r.ValueFloat, 0) || *r.ValueFloat <= 0) {
+		return NewValidationError("value_float must be a positive number")
+	}
+
 	// タグの検証
 	for _, tag := range r.Tags {
-		if tag == "" {
-			return NewValidationError("tag cannot be empty")
-		}
-		// スペースは区切り文字として使用するため禁止
-		if strings.Contains(tag, " ") {
-			return NewValidationError("tag cannot contain spaces")
+		if err := ValidateTag(tag); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
+
+// ValidateTag はタグの形式を検証します。
+func ValidateTag(tag string) error {
+	if tag == "" {
+		return NewValidationError("tag cannot be empty")
+	}
+	// スペースは区切り文字として使用するため禁止
+	if strings.Contains(tag, " ") {
+		return NewValidationError("tag cannot contain spaces")
+	}
+	return nil
+}
@@ -99,6 +99,36 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestHasSameTags(t *testing.T) {
+	record := &Record{Tags: []string{"work", "go"}}
+
+	tests := []struct {
+		name string
+		tags []string
+		want bool
+	}{
+		{"same order", []string{"work", "go"}, true},
+		{"different order", []string{"go", "work"}, true},
+		{"duplicates", []string{"go", "work", "go"}, true},
+		{"subset", []string{"work"}, false},
+		{"superset", []string{"work", "go", "rust"}, false},
+		{"empty", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := record.HasSameTags(tt.tags); got != tt.want {
+				t.Errorf("HasSameTags(%v) = %v, want %v", tt.tags, got, tt.want)
+			}
+		})
+	}
+
+	// タグなし同士は同じ集合
+	if !(&Record{}).HasSameTags(nil) {
+		t.Error("Expected records without tags to have the same tags")
+	}
+}
+
 func TestNewDateRange(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -0,0 +1,62 @@
+package model
+
+// TagAlias はプロジェクト内でタグの別名を正規のタグに置き換える対応です。
+type TagAlias struct {
+	Alias string `json:"alias"` // 別名（書き込み時に正規のタグに置き換える）
+	Tag   string `json:"tag"`   // 正規のタグ
+}
+
+// NewTagAlias は検証済みのTagAliasを生成します。
+func NewTagAlias(alias, tag string) (*TagAlias, error) {
+	a := &TagAlias{Alias: alias, Tag: tag}
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Validate はタグの別名を検証します。
+func (a *TagAlias) Validate() error {
+	if err := ValidateTag(a.Alias); err != nil {
+		return NewValidationError("alias: " + err.Error())
+	}
+	if err := ValidateTag(a.Tag); err != nil {
+		return err
+	}
+	if a.Alias == a.Tag {
+		return NewValidationError("alias must differ from tag")
+	}
+	return nil
+}
+
+// TagAliases は別名から正規のタグへの対応表です。
+type TagAliases map[string]string
+
+// NewTagAliases はタグの別名の一覧から対応表を生成します。
+func NewTagAliases(aliases []*TagAlias) TagAliases {
+	m := make(TagAliases, len(aliases))
+	for _, a := range aliases {
+		m[a.Alias] = a.Tag
+	}
+	return m
+}
+
+// Canonicalize は別名を正規のタグに置き換えたタグを返します。
+// 重複したタグ（置き換えによって重複したものを含む）は最初の位置のみ残します。
+func (m TagAliases) Canonicalize(tags []string) []string {
+	if len(tags) == 0 {
+		return tags
+	}
+	result := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		if canonical, ok := m[tag]; ok {
+			tag = canonical
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			result = append(result, tag)
+		}
+	}
+	return result
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNewTagAlias(t *testing.T) {
+	tests := []struct {
+		name    string
+		alias   string
+		tag     string
+		wantErr bool
+	}{
+		{"valid alias", "js", "javascript", false},
+		{"empty alias", "", "javascript", true},
+		{"empty tag", "js", "", true},
+		{"alias with spaces", "java script", "javascript", true},
+		{"alias equal to tag", "javascript", "javascript", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewTagAlias(tt.alias, tt.tag)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewTagAlias(%q, %q) error = %v, wantErr %v", tt.alias, tt.tag, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTagAliasesCanonicalize(t *testing.T) {
+	aliases := NewTagAliases([]*TagAlias{
+		{Alias: "js", Tag: "javascript"},
+		{Alias: "ts", Tag: "typescript"},
+	})
+
+	tests := []struct {
+		name string
+		tags []string
+		want []string
+	}{
+		{"replaces aliases", []string{"js", "web"}, []string{"javascript", "web"}},
+		{"removes duplicates after replacement", []string{"js", "javascript", "ts"}, []string{"javascript", "typescript"}},
+		{"keeps canonical tags", []string{"web", "javascript"}, []string{"web", "javascript"}},
+		{"empty", []string{}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := aliases.Canonicalize(tt.tags); !slices.Equal(got, tt.want) {
+				t.Errorf("Canonicalize(%v) = %v, want %v", tt.tags, got, tt.want)
+			}
+		})
+	}
+
+	// 別名がない場合はそのまま返す
+	var empty TagAliases
+	if got := empty.Canonicalize([]string{"js"}); !slices.Equal(got, []string{"js"}) {
+		t.Errorf("Expected tags unchanged without aliases, got %v", got)
+	}
+
+	// 別名がなくても重複したタグは除く
+	if got := empty.Canonicalize([]string{"js", "web", "js"}); !slices.Equal(got, []string{"js", "web"}) {
+		t.Errorf("Expected duplicate tags removed without aliases, got %v", got)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"math"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -169,6 +171,14 @@ func (d *DateRange) To() time.Time {
 	return d.to
 }
 
+// In returns a date range covering the same calendar days in loc.
+func (d *DateRange) In(loc *time.Location) *DateRange {
+	return &DateRange{
+		from: time.Date(d.from.Year(), d.from.Month(), d.from.Day(), 0, 0, 0, 0, loc),
+		to:   normalizeToEndOfDay(time.Date(d.to.Year(), d.to.Month(), d.to.Day(), 0, 0, 0, 0, loc)),
+	}
+}
+
 // getDefaultDateRange calculates the default date range for the latest week + 52 weeks.
 func getDefaultDateRange() (time.Time, time.Time) {
 	now := time.Now()
@@ -223,10 +233,10 @@ func NewTags(tagsStr string) *Tags {
 	for i, tag := range tags {
 		tags[i] = strings.TrimSpace(tag)
 	}
-	// Remove empty tags
+	// Remove empty and duplicate tags, keeping the first occurrence
 	var filteredTags []string
 	for _, tag := range tags {
-		if tag != "" {
+		if tag != "" && !slices.Contains(filteredTags, tag) {
 			filteredTags = append(filteredTags, tag)
 		}
 	}
@@ -244,6 +254,234 @@ func (t *Tags) IsEmpty() bool {
 	return len(t.values) == 0
 }
 
+// Weekdays represents a set of weekdays value object.
+// An empty set means no weekday filtering.
+type Weekdays struct {
+	days map[time.Weekday]bool
+}
+
+// weekdayNames maps accepted weekday names to time.Weekday.
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// NewWeekdays creates a new weekdays value object from a comma-separated list.
+// Each entry is either a short English name (sun, mon, ...) or a number (0=Sunday .. 6=Saturday).
+func NewWeekdays(weekdaysStr string) (*Weekdays, error) {
+	days := make(map[time.Weekday]bool)
+	for _, entry := range strings.Split(weekdaysStr, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if day, ok := weekdayNames[entry]; ok {
+			days[day] = true
+			continue
+		}
+		n, err := strconv.Atoi(entry)
+		if err != nil || n < 0 || n > 6 {
+			return nil, fmt.Errorf("invalid weekdays parameter: %q (use sun..sat or 0..6)", entry)
+		}
+		days[time.Weekday(n)] = true
+	}
+	return &Weekdays{days: days}, nil
+}
+
+// Contains checks if the given weekday is included.
+// An empty set contains every weekday.
+func (w *Weekdays) Contains(day time.Weekday) bool {
+	return w.IsEmpty() || w.days[day]
+}
+
+// IsEmpty checks if no weekday is specified.
+func (w *Weekdays) IsEmpty() bool {
+	return len(w.days) == 0
+}
+
+// String returns the weekdays as sorted numbers (e.g. "0,6").
+func (w *Weekdays) String() string {
+	var days []string
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if w.days[d] {
+			days = append(days, strconv.Itoa(int(d)))
+		}
+	}
+	return strings.Join(days, ",")
+}
+
+// NewTimezone loads a time zone from an IANA name (e.g. "Asia/Tokyo").
+// An empty string means the server's local time zone.
+func NewTimezone(tzStr string) (*time.Location, error) {
+	if tzStr == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(tzStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tz parameter: %q (use an IANA time zone name)", tzStr)
+	}
+	return loc, nil
+}
+
+// Aggregation represents how record values in the same bucket are combined.
+type Aggregation string
+
+const (
+	AggregationSum   Aggregation = "sum"   // Sum of values (default)
+	AggregationMax   Aggregation = "max"   // Largest value
+	AggregationCount Aggregation = "count" // Number of records
+	AggregationAvg   Aggregation = "avg"   // Average value (rounded to the nearest integer for int projects)
+)
+
+// NewAggregation creates a new aggregation from a string.
+// An empty string means sum.
+func NewAggregation(aggStr string) (Aggregation, error) {
+	switch agg := Aggregation(aggStr); agg {
+	case "":
+		return AggregationSum, nil
+	case AggregationSum, AggregationMax, AggregationCount, AggregationAvg:
+		return agg, nil
+	default:
+		return "", fmt.Errorf("invalid agg parameter: %q (use sum, max, count or avg)", aggStr)
+	}
+}
+
+// ValueType represents whether record values of a project are integers or floats.
+type ValueType string
+
+const (
+	ValueTypeInt   ValueType = "int"   // Integer values (default)
+	ValueTypeFloat ValueType = "float" // Fractional values (e.g. 5.3 km)
+)
+
+// NewValueType creates a new value type from a string.
+// An empty string means int.
+func NewValueType(valueTypeStr string) (ValueType, error) {
+	switch valueType := ValueType(valueTypeStr); valueType {
+	case "":
+		return ValueTypeInt, nil
+	case ValueTypeInt, ValueTypeFloat:
+		return valueType, nil
+	default:
+		return "", fmt.Errorf("invalid value_type: %q (use int or float)", valueTypeStr)
+	}
+}
+
+// RecordOrder represents the chronological order of a record listing.
+type RecordOrder string
+
+const (
+	RecordOrderDesc RecordOrder = "desc" // newest first (default)
+	RecordOrderAsc  RecordOrder = "asc"  // oldest first
+)
+
+// NewRecordOrder creates a new record order from a string.
+// An empty string means desc.
+func NewRecordOrder(orderStr string) (RecordOrder, error) {
+	switch order := RecordOrder(orderStr); order {
+	case "":
+		return RecordOrderDesc, nil
+	case RecordOrderDesc, RecordOrderAsc:
+		return order, nil
+	default:
+		return "", fmt.Errorf("invalid order parameter: %q (use asc or desc)", orderStr)
+	}
+}
+
+// RecordTimeField represents the record column that a listing filters and orders by.
+type RecordTimeField string
+
+const (
+	RecordTimeFieldTimestamp RecordTimeField = "timestamp"  // when the activity happened (default)
+	RecordTimeFieldCreatedAt RecordTimeField = "created_at" // when the record was logged
+)
+
+// NewRecordTimeField creates a new record time field from a string.
+// An empty string means timestamp.
+func NewRecordTimeField(fieldStr string) (RecordTimeField, error) {
+	switch field := RecordTimeField(fieldStr); field {
+	case "":
+		return RecordTimeFieldTimestamp, nil
+	case RecordTimeFieldTimestamp, RecordTimeFieldCreatedAt:
+		return field, nil
+	default:
+		return "", fmt.Errorf("invalid time_field parameter: %q (use timestamp or created_at)", fieldStr)
+	}
+}
+
+// ProjectSort represents the order of the project list.
+type ProjectSort string
+
+const (
+	ProjectSortUpdated ProjectSort = "updated" // updated_at descending (default)
+	ProjectSortName    ProjectSort = "name"    // name ascending
+	ProjectSortCreated ProjectSort = "created" // created_at descending
+	ProjectSortRecords ProjectSort = "records" // number of records descending
+)
+
+// NewProjectSort creates a new project sort from a string.
+// An empty string means updated.
+func NewProjectSort(sortStr string) (ProjectSort, error) {
+	switch sort := ProjectSort(sortStr); sort {
+	case "":
+		return ProjectSortUpdated, nil
+	case ProjectSortUpdated, ProjectSortName, ProjectSortCreated, ProjectSortRecords:
+		return sort, nil
+	default:
+		return "", fmt.Errorf("invalid sort parameter: %q (use updated, name, created or records)", sortStr)
+	}
+}
+
+// Aggregator accumulates record values of a single bucket.
+type Aggregator struct {
+	agg       Aggregation
+	valueType ValueType
+	sum       float64
+	max       float64
+	count     int
+}
+
+// NewAggregator creates a new aggregator for the given aggregation.
+// For integer projects the average is rounded to the nearest integer.
+func NewAggregator(agg Aggregation, valueType ValueType) *Aggregator {
+	return &Aggregator{agg: agg, valueType: valueType}
+}
+
+// Add adds a record value to the bucket.
+func (a *Aggregator) Add(value float64) {
+	if a.count == 0 || value > a.max {
+		a.max = value
+	}
+	a.sum += value
+	a.count++
+}
+
+// Result returns the aggregated value of the bucket.
+func (a *Aggregator) Result() float64 {
+	switch a.agg {
+	case AggregationMax:
+		return a.max
+	case AggregationCount:
+		return float64(a.count)
+	case AggregationAvg:
+		if a.count == 0 {
+			return 0
+		}
+		avg := a.sum / float64(a.count)
+		if a.valueType != ValueTypeFloat {
+			avg = math.Round(avg)
+		}
+		return avg
+	default:
+		return a.sum
+	}
+}
+
 // Timestamp represents a timestamp value object.
 type Timestamp struct {
 	value time.Time
@@ -264,6 +502,19 @@ func NewTimestamp(timestampStr string) (*Timestamp, error) {
 	return &Timestamp{value: timestamp}, nil
 }
 
+// unixMillisThreshold is the smallest absolute epoch value treated as milliseconds.
+// 1e12 seconds is far beyond year 30000, while 1e12 milliseconds is September 2001.
+const unixMillisThreshold = 1_000_000_000_000
+
+// NewTimestampFromUnix creates a new timestamp value object from a Unix epoch value in UTC.
+// Values of 1e12 or more (in absolute value) are treated as milliseconds, smaller values as seconds.
+func NewTimestampFromUnix(epoch int64) *Timestamp {
+	if epoch >= unixMillisThreshold || epoch <= -unixMillisThreshold {
+		return &Timestamp{value: time.UnixMilli(epoch).UTC()}
+	}
+	return &Timestamp{value: time.Unix(epoch, 0).UTC()}
+}
+
 // Time returns the time value.
 func (t *Timestamp) Time() time.Time {
 	return t.value
@@ -295,28 +546,41 @@ func (v *Value) Int() int {
 
 // RecordFilterParams represents filter parameters for record queries.
 type RecordFilterParams struct {
-	ProjectID HexID    `json:"project_id"`     // Project ID for filtering
-	From      string   `json:"from"`           // Start date for filtering (RFC3339)
-	To        string   `json:"to"`             // End date for filtering (RFC3339)
-	Tags      []string `json:"tags,omitempty"` // Tags for filtering
+	ProjectID HexID    `json:"project_id"`       // Project ID for filtering
+	From      string   `json:"from"`             // Start date for filtering (RFC3339)
+	To        string   `json:"to"`               // End date for filtering (RFC3339)
+	Tags      []string `json:"tags,omitempty"`   // Tags for filtering
+	Source    string   `json:"source,omitempty"` // Record source for filtering
+
+	TimeField RecordTimeField `json:"time_field,omitempty"` // Column that From/To and the order apply to (empty means timestamp)
 }
 
 // RecordCursor represents a keyset cursor for record pagination.
 // It embeds RecordFilterParams to guarantee all filter parameters are included.
 type RecordCursor struct {
 	RecordFilterParams        // Embedded filter parameters
-	Timestamp          string `json:"timestamp"` // RFC3339 formatted timestamp of the last record
+	Timestamp          string `json:"timestamp"` // RFC3339 formatted time of the last record in TimeField
 	ID                 HexID  `json:"id"`        // ID of the last record
 }
 
 // ProjectCursor represents a keyset cursor for project pagination.
+// Only the key of the sort order it was issued for is set besides Name.
 type ProjectCursor struct {
-	UpdatedAt string `json:"updated_at"` // RFC3339 formatted updated_at of the last project
-	Name      string `json:"name"`       // Name of the last project
+	Sort        ProjectSort `json:"sort,omitempty"`         // Sort order of the list (empty means updated)
+	UpdatedAt   string      `json:"updated_at,omitempty"`   // RFC3339 formatted updated_at of the last project
+	CreatedAt   string      `json:"created_at,omitempty"`   // RFC3339 formatted created_at of the last project
+	RecordCount *int        `json:"record_count,omitempty"` // Number of records of the last project
+	Name        string      `json:"name"`                   // Name of the last project
+}
+
+// TagCursor represents a keyset cursor for tag pagination.
+type TagCursor struct {
+	Tag string `json:"tag"` // Name of the last tag
 }
 
 // EncodeRecordCursor encodes a record cursor to a Base64 string.
-func EncodeRecordCursor(timestamp time.Time, id HexID, projectID HexID, from, to time.Time, tags []string) string {
+// timestamp is the time of the last record in timeField.
+func EncodeRecordCursor(timestamp time.Time, id HexID, projectID HexID, from, to time.Time, tags []string, source string, timeField RecordTimeField) string {
 	// Convert zero-value times to empty strings
 	fromStr := ""
 	if !from.IsZero() {
@@ -333,6 +597,9 @@ func EncodeRecordCursor(timestamp time.Time, id HexID, projectID HexID, from, to
 			From:      fromStr,
 			To:        toStr,
 			Tags:      tags,
+			Source:    source,
+
+			TimeField: timeField,
 		},
 		Timestamp: timestamp.Format(time.RFC3339),
 		ID:        id,
@@ -370,6 +637,22 @@ func EncodeProjectCursor(updatedAt time.Time, name string) string {
 	return base64.URLEncoding.EncodeToString(jsonData)
 }
 
+// EncodeSortedProjectCursor encodes a cursor pointing at the project for the given sort order.
+// For ProjectSortRecords, project.RecordCount must be set.
+func EncodeSortedProjectCursor(sort ProjectSort, project *Project) string {
+	cursor := ProjectCursor{Sort: sort, Name: project.Name}
+	switch sort {
+	case ProjectSortUpdated:
+		cursor.UpdatedAt = project.UpdatedAt.Format(time.RFC3339)
+	case ProjectSortCreated:
+		cursor.CreatedAt = project.CreatedAt.Format(time.RFC3339)
+	case ProjectSortRecords:
+		cursor.RecordCount = project.RecordCount
+	}
+	jsonData, _ := json.Marshal(cursor)
+	return base64.URLEncoding.EncodeToString(jsonData)
+}
+
 // DecodeProjectCursor decodes a Base64 encoded project cursor string.
 func DecodeProjectCursor(encoded string) (*ProjectCursor, error) {
 	if encoded == "" {
@@ -389,15 +672,58 @@ func DecodeProjectCursor(encoded string) (*ProjectCursor, error) {
 	return &cursor, nil
 }
 
+// EncodeTagCursor encodes a tag cursor to a Base64 string.
+func EncodeTagCursor(tag string) string {
+	jsonData, _ := json.Marshal(TagCursor{Tag: tag})
+	return base64.URLEncoding.EncodeToString(jsonData)
+}
+
+// DecodeTagCursor decodes a Base64 encoded tag cursor string.
+func DecodeTagCursor(encoded string) (*TagCursor, error) {
+	if encoded == "" {
+		return nil, nil
+	}
+
+	decoded, err := base64.URLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: failed to decode base64: %w", err)
+	}
+
+	var cursor TagCursor
+	if err := json.Unmarshal(decoded, &cursor); err != nil {
+		return nil, fmt.Errorf("invalid cursor: failed to unmarshal json: %w", err)
+	}
+
+	return &cursor, nil
+}
+
 // Pagination represents cursor-based pagination parameters for records and projects.
 type Pagination struct {
 	limit  int
 	cursor *string // Cursor for pagination (nil means start from the beginning)
 }
 
+// DefaultMaxLimit is the default upper bound for the limit parameter.
+const DefaultMaxLimit = 1000
+
 // NewPagination creates a new cursor-based pagination value object.
+// The limit is clamped to DefaultMaxLimit.
 func NewPagination(limitStr, cursorStr string) (*Pagination, error) {
-	limit := 100 // Default value
+	return NewPaginationWithMax(limitStr, cursorStr, DefaultMaxLimit)
+}
+
+// NewPaginationWithMax creates a new cursor-based pagination value object
+// whose limit is clamped to the given per-call maximum.
+func NewPaginationWithMax(limitStr, cursorStr string, max int) (*Pagination, error) {
+	return NewPaginationWithBounds(limitStr, cursorStr, 1, max)
+}
+
+// NewPaginationWithBounds creates a new cursor-based pagination value object
+// whose limit is clamped to the given maximum. Limits below minLimit (e.g. a
+// server-wide minimum against limit=1 round-trips) are silently raised to it;
+// the maximum still takes precedence.
+func NewPaginationWithBounds(limitStr, cursorStr string, minLimit, maxLimit int) (*Pagination, error) {
+	limit := min(100, maxLimit) // Default value
 
 	// Process limit parameter
 	if limitStr != "" {
@@ -408,11 +734,9 @@ func NewPagination(limitStr, cursorStr string) (*Pagination, error) {
 		if parsedLimit <= 0 {
 			return nil, fmt.Errorf("limit must be greater than 0")
 		}
-		if parsedLimit > 1000 { // Set upper limit
-			parsedLimit = 1000
-		}
 		limit = parsedLimit
 	}
+	limit = min(max(limit, minLimit), maxLimit) // Raise to the minimum, then set upper limit
 
 	// Process cursor parameter
 	var cursor *string
@@ -2,6 +2,7 @@ package model
 
 import (
 	"encoding/base64"
+	"slices"
 	"testing"
 	"time"
 )
@@ -128,6 +129,87 @@ func TestNewPagination(t *testing.T) {
 	}
 }
 
+// TestNewPaginationWithMax tests the NewPaginationWithMax function
+func TestNewPaginationWithMax(t *testing.T) {
+	tests := []struct {
+		name          string
+		limitStr      string
+		max           int
+		expectedLimit int
+		expectError   bool
+	}{
+		{"Default limit below max", "", 5000, 100, false},
+		{"Default limit clamped to small max", "", 50, 50, false},
+		{"Limit within max", "3000", 5000, 3000, false},
+		{"Limit clamped to max", "9000", 5000, 5000, false},
+		{"Limit clamped to small max", "20", 10, 10, false},
+		{"Invalid limit", "abc", 5000, 0, true},
+		{"Zero limit", "0", 5000, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pagination, err := NewPaginationWithMax(tt.limitStr, "", tt.max)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error for limit %q", tt.limitStr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if pagination.Limit() != tt.expectedLimit {
+				t.Errorf("Expected limit %d, got %d", tt.expectedLimit, pagination.Limit())
+			}
+		})
+	}
+
+	// NewPaginationはDefaultMaxLimitで制限される
+	pagination, err := NewPagination("5000", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if pagination.Limit() != DefaultMaxLimit {
+		t.Errorf("Expected NewPagination to clamp to %d, got %d", DefaultMaxLimit, pagination.Limit())
+	}
+}
+
+// TestNewPaginationWithBounds tests the NewPaginationWithBounds function
+func TestNewPaginationWithBounds(t *testing.T) {
+	tests := []struct {
+		name          string
+		limitStr      string
+		minLimit      int
+		max           int
+		expectedLimit int
+	}{
+		{"Limit raised to min", "1", 10, 1000, 10},
+		{"Limit above min", "20", 10, 1000, 20},
+		{"Default limit above min", "", 10, 1000, 100},
+		{"Default limit raised to min", "", 200, 1000, 200},
+		{"Max takes precedence over min", "1", 10, 5, 5},
+		{"Min of 1 keeps small limits", "1", 1, 1000, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pagination, err := NewPaginationWithBounds(tt.limitStr, "", tt.minLimit, tt.max)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if pagination.Limit() != tt.expectedLimit {
+				t.Errorf("Expected limit %d, got %d", tt.expectedLimit, pagination.Limit())
+			}
+		})
+	}
+
+	// 最小値があっても不正なlimitはエラー
+	if _, err := NewPaginationWithBounds("0", "", 10, 1000); err == nil {
+		t.Error("Expected error for zero limit")
+	}
+}
+
 // TestNewPaginationWithValues tests the NewPaginationWithValues function
 func TestNewPaginationWithValues(t *testing.T) {
 	cursor := "test-cursor"
@@ -229,3 +311,198 @@ func TestDecodeInvalidProjectCursor(t *testing.T) {
 		})
 	}
 }
+
+// TestNewWeekdays tests the NewWeekdays function
+func TestNewWeekdays(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    []time.Weekday
+		expectError bool
+	}{
+		{"Empty", "", nil, false},
+		{"Names", "sat,sun", []time.Weekday{time.Saturday, time.Sunday}, false},
+		{"Numbers", "0,6", []time.Weekday{time.Sunday, time.Saturday}, false},
+		{"Mixed case and spaces", " Mon , 3 ", []time.Weekday{time.Monday, time.Wednesday}, false},
+		{"Out of range", "7", nil, true},
+		{"Unknown name", "funday", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			weekdays, err := NewWeekdays(tt.input)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error for %q", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(tt.expected) == 0 {
+				if !weekdays.IsEmpty() {
+					t.Errorf("Expected empty weekdays")
+				}
+				for d := time.Sunday; d <= time.Saturday; d++ {
+					if !weekdays.Contains(d) {
+						t.Errorf("Empty weekdays should contain %v", d)
+					}
+				}
+				return
+			}
+
+			included := make(map[time.Weekday]bool)
+			for _, d := range tt.expected {
+				included[d] = true
+			}
+			for d := time.Sunday; d <= time.Saturday; d++ {
+				if weekdays.Contains(d) != included[d] {
+					t.Errorf("Contains(%v) = %v, expected %v", d, weekdays.Contains(d), included[d])
+				}
+			}
+		})
+	}
+}
+
+func TestAggregator(t *testing.T) {
+	tests := []struct {
+		input       string
+		expected    float64
+		expectError bool
+	}{
+		{"", 10, false},
+		{"sum", 10, false},
+		{"max", 5, false},
+		{"count", 3, false},
+		{"avg", 3, false}, // 10/3 = 3.33...
+		{"median", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			agg, err := NewAggregation(tt.input)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error for %q", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			aggregator := NewAggregator(agg, ValueTypeInt)
+			for _, v := range []float64{2, 5, 3} {
+				aggregator.Add(v)
+			}
+			if got := aggregator.Result(); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestAggregatorFloat(t *testing.T) {
+	tests := []struct {
+		agg      Aggregation
+		expected float64
+	}{
+		{AggregationSum, 8.75},
+		{AggregationMax, 5.25},
+		{AggregationCount, 3},
+		{AggregationAvg, 8.75 / 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.agg), func(t *testing.T) {
+			aggregator := NewAggregator(tt.agg, ValueTypeFloat)
+			for _, v := range []float64{1.5, 5.25, 2} {
+				aggregator.Add(v)
+			}
+			if got := aggregator.Result(); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNewValueType(t *testing.T) {
+	tests := []struct {
+		input       string
+		expected    ValueType
+		expectError bool
+	}{
+		{"", ValueTypeInt, false},
+		{"int", ValueTypeInt, false},
+		{"float", ValueTypeFloat, false},
+		{"decimal", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := NewValueType(tt.input)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error for %q", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNewTags(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"", nil},
+		{"work", []string{"work"}},
+		{" work , urgent ", []string{"work", "urgent"}},
+		{"work,,urgent,", []string{"work", "urgent"}},
+		{"work,work", []string{"work"}},
+		{"urgent,work,urgent", []string{"urgent", "work"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := NewTags(tt.input).Values(); !slices.Equal(got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNewTimestampFromUnix(t *testing.T) {
+	tests := []struct {
+		name     string
+		epoch    int64
+		expected time.Time
+	}{
+		{"Seconds", 1735689600, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"Milliseconds", 1735689600123, time.Date(2025, 1, 1, 0, 0, 0, 123_000_000, time.UTC)},
+		{"Zero", 0, time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"Largest seconds", 999_999_999_999, time.Unix(999_999_999_999, 0).UTC()},
+		{"Smallest milliseconds", 1_000_000_000_000, time.Date(2001, 9, 9, 1, 46, 40, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewTimestampFromUnix(tt.epoch).Time()
+			if !got.Equal(tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+			if got.Location() != time.UTC {
+				t.Errorf("Expected UTC, got %v", got.Location())
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+// Package notifier はレコード作成時のWebhook通知を提供します。
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/stsysd/sougen/model"
+)
+
+// DefaultBatchInterval はバッチサイズのみ指定された場合のフラッシュ間隔です。
+const DefaultBatchInterval = 5 * time.Second
+
+// queueSize は送信待ちのレコードを保持する上限です。超えた分は破棄します。
+const queueSize = 256
+
+// Options はNotifierの動作を設定します。
+type Options struct {
+	// BatchSize はまとめて送信するレコード数の上限です。1以下の場合はレコードごとに送信します。
+	BatchSize int
+	// BatchInterval はバッファをフラッシュする間隔です。0の場合はDefaultBatchIntervalを使用します。
+	BatchInterval time.Duration
+	// Client は送信に使用するHTTPクライアントです。nilの場合はタイムアウト付きのクライアントを使用します。
+	Client *http.Client
+}
+
+// Notifier は作成されたレコードをWebhookにPOSTします。
+// バッチモードではレコードを一定数または一定時間バッファし、配列としてまとめて送信します。
+type Notifier struct {
+	url           string
+	client        *http.Client
+	batchSize     int
+	batchInterval time.Duration
+
+	records chan *model.Record
+	done    chan struct{}
+	mu      sync.Mutex
+	closed  bool
+}
+
+// New は指定URLに送信するNotifierを作成し、送信用のゴルーチンを起動します。
+func New(url string, opts Options) *Notifier {
+	client := opts.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	interval := opts.BatchInterval
+	if interval <= 0 {
+		interval = DefaultBatchInterval
+	}
+
+	n := &Notifier{
+		url:           url,
+		client:        client,
+		batchSize:     opts.BatchSize,
+		batchInterval: interval,
+		records:       make(chan *model.Record, queueSize),
+		done:          make(chan struct{}),
+	}
+	go n.run()
+	return n
+}
+
+// Notify はレコードを送信キューに追加します。
+// Webhookの応答が遅くキューが一杯の場合は、リクエストを待たせないようレコードを破棄します。
+// Close後に呼び出した場合は何もしません。
+func (n *Notifier) Notify(record *model.Record) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.closed {
+		log.Printf("Webhook notifier is closed, dropping record %s", record.ID)
+		return
+	}
+	select {
+	case n.records <- record:
+	default:
+		log.Printf("Webhook queue is full, dropping record %s", record.ID)
+	}
+}
+
+// Close は新規の受け付けを停止し、バッファに残ったレコードをフラッシュします。
+// Notifyはブロックしないため受け付けの停止はすぐに終わり、
+// ctxがキャンセルされた場合はフラッシュの完了を待たずに戻ります。
+func (n *Notifier) Close(ctx context.Context) error {
+	n.mu.Lock()
+	if !n.closed {
+		n.closed = true
+		close(n.records)
+	}
+	n.mu.Unlock()
+
+	select {
+	case <-n.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// batching はバッチモードが有効かどうかを返します。
+func (n *Notifier) batching() bool {
+	return n.batchSize > 1
+}
+
+// run はキューからレコードを受け取り、送信します。
+func (n *Notifier) run() {
+	defer close(n.done)
+
+	if !n.batching() {
+		for record := range n.records {
+			n.post(record)
+		}
+		return
+	}
+
+	ticker := time.NewTicker(n.batchInterval)
+	defer ticker.Stop()
+
+	var buf []*model.Record
+	flush := func() {
+		if len(buf) == 0 {
+			return
+		}
+		n.post(buf)
+		buf = nil
+	}
+
+	for {
+		select {
+		case record, ok := <-n.records:
+			if !ok {
+				// シャットダウン時は残りをすべて送信
+				flush()
+				return
+			}
+			buf = append(buf, record)
+			if len(buf) >= n.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// post はペイロードをJSONとしてWebhookに送信します。
+// 送信の失敗はログに記録し、リトライは行いません。
+func (n *Notifier) post(payload any) {
+	if err := n.send(payload); err != nil {
+		log.Printf("Error sending webhook: %v", err)
+	}
+}
+
+// send はペイロードをJSONとしてWebhookにPOSTします。
+func (n *Notifier) send(payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	resp, err := n.client.Post(n.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stsysd/sougen/model"
+)
+
+// fakeSink はWebhookリクエストを記録するテスト用のHTTPサーバーです。
+type fakeSink struct {
+	*httptest.Server
+	mu     sync.Mutex
+	bodies [][]byte
+}
+
+func newFakeSink(t *testing.T) *fakeSink {
+	t.Helper()
+	sink := &fakeSink{}
+	sink.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		sink.mu.Lock()
+		sink.bodies = append(sink.bodies, body)
+		sink.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(sink.Close)
+	return sink
+}
+
+func (s *fakeSink) requests() [][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([][]byte(nil), s.bodies...)
+}
+
+func newTestRecord(t *testing.T, value int) *model.Record {
+	t.Helper()
+	record, err := model.NewRecord(time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC), model.NewHexID(1), value, nil)
+	if err != nil {
+		t.Fatalf("Failed to create record: %v", err)
+	}
+	return record
+}
+
+func TestNotifierSendsEachRecord(t *testing.T) {
+	sink := newFakeSink(t)
+	n := New(sink.URL, Options{})
+
+	n.Notify(newTestRecord(t, 1))
+	n.Notify(newTestRecord(t, 2))
+	if err := n.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	requests := sink.requests()
+	if len(requests) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(requests))
+	}
+	var record model.Record
+	if err := json.Unmarshal(requests[0], &record); err != nil {
+		t.Fatalf("Expected a single record object: %v", err)
+	}
+	if record.Value != 1 {
+		t.Errorf("Expected value 1, got %d", record.Value)
+	}
+}
+
+func TestNotifierBatchesBySize(t *testing.T) {
+	sink := newFakeSink(t)
+	n := New(sink.URL, Options{BatchSize: 2, BatchInterval: time.Hour})
+
+	for i := 1; i <= 5; i++ {
+		n.Notify(newTestRecord(t, i))
+	}
+	if err := n.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	// 2件 + 2件 + シャットダウン時の1件
+	requests := sink.requests()
+	if len(requests) != 3 {
+		t.Fatalf("Expected 3 requests, got %d", len(requests))
+	}
+	expectedSizes := []int{2, 2, 1}
+	for i, body := range requests {
+		var records []model.Record
+		if err := json.Unmarshal(body, &records); err != nil {
+			t.Fatalf("Expected an array of records: %v", err)
+		}
+		if len(records) != expectedSizes[i] {
+			t.Errorf("Request %d: expected %d records, got %d", i, expectedSizes[i], len(records))
+		}
+	}
+}
+
+func TestNotifierFlushesOnShutdown(t *testing.T) {
+	sink := newFakeSink(t)
+	// バッチサイズにも間隔にも達しない設定
+	n := New(sink.URL, Options{BatchSize: 100, BatchInterval: time.Hour})
+
+	n.Notify(newTestRecord(t, 1))
+	n.Notify(newTestRecord(t, 2))
+	n.Notify(newTestRecord(t, 3))
+
+	if len(sink.requests()) != 0 {
+		t.Fatalf("Expected records to be buffered before shutdown")
+	}
+
+	if err := n.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	requests := sink.requests()
+	if len(requests) != 1 {
+		t.Fatalf("Expected 1 request on shutdown, got %d", len(requests))
+	}
+	var records []model.Record
+	if err := json.Unmarshal(requests[0], &records); err != nil {
+		t.Fatalf("Expected an array of records: %v", err)
+	}
+	if len(records) != 3 {
+		t.Errorf("Expected 3 flushed records, got %d", len(records))
+	}
+
+	// Close後の通知は破棄される
+	n.Notify(newTestRecord(t, 4))
+	if len(sink.requests()) != 1 {
+		t.Errorf("Expected no requests after Close")
+	}
+}
+
+func TestNotifierFlushesOnInterval(t *testing.T) {
+	sink := newFakeSink(t)
+	n := New(sink.URL, Options{BatchSize: 100, BatchInterval: 20 * time.Millisecond})
+	defer n.Close(context.Background())
+
+	n.Notify(newTestRecord(t, 1))
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(sink.requests()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected buffered record to be flushed by interval")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestNotifierDoesNotBlockOnStalledSink(t *testing.T) {
+	// 応答を返さないWebhook
+	release := make(chan struct{})
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(sink.Close)
+	t.Cleanup(func() { close(release) })
+	n := New(sink.URL, Options{})
+
+	// キューの上限を超えて通知しても、Notifyはブロックしない
+	notified := make(chan struct{})
+	go func() {
+		defer close(notified)
+		for i := 1; i <= queueSize*2; i++ {
+			n.Notify(newTestRecord(t, i))
+		}
+	}()
+	select {
+	case <-notified:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Notify blocked on a stalled sink")
+	}
+
+	// Closeはctxの期限で戻る
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := n.Close(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected Close to return at the deadline, took %v", elapsed)
+	}
+}
@@ -25,7 +25,7 @@ func TestRouter(t *testing.T) {
 	cfg := config.NewConfig()
 
 	// SQLiteストアの初期化（マイグレーション関数を渡す）
-	sqliteStore, err := store.NewSQLiteStore(cfg.DataDir, db.Migrate)
+	sqliteStore, err := store.NewSQLiteStore(cfg.DataDir, db.Migrate, store.SQLiteOptions{Synchronous: cfg.SQLiteSynchronous})
 	if err != nil {
 		t.Fatalf("Failed to initialize SQLite store: %v", err)
 	}
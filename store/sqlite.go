@@ -4,23 +4,36 @@ package store
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"iter"
+	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/mattn/go-sqlite3"
 	"github.com/stsysd/sougen/db/sqlc"
 	"github.com/stsysd/sougen/model"
 )
 
 // ListProjectsParams はプロジェクト一覧取得のパラメータです。
 type ListProjectsParams struct {
-	Pagination      *model.Pagination
-	CursorUpdatedAt *time.Time // Cursor position: updated_at (nil if no cursor)
-	CursorName      *string    // Cursor position: name (nil if no cursor)
+	Pagination        *model.Pagination
+	Sort              model.ProjectSort // 並び順（空の場合はupdated）
+	CursorUpdatedAt   *time.Time        // Cursor position: updated_at (nil if no cursor, updated sort)
+	CursorCreatedAt   *time.Time        // Cursor position: created_at (nil if no cursor, created sort)
+	CursorRecordCount *int              // Cursor position: record count (nil if no cursor, records sort)
+	CursorName        *string           // Cursor position: name (nil if no cursor)
+}
+
+// GetProjectTagsParams はタグ一覧取得のパラメータです。
+type GetProjectTagsParams struct {
+	ProjectID  model.HexID
+	Pagination *model.Pagination // nilの場合はすべてのタグを取得
+	CursorTag  *string           // Cursor position: tag name (nil if no cursor)
 }
 
 // ListRecordsParams はレコード一覧取得のパラメータです。
@@ -30,8 +43,13 @@ type ListRecordsParams struct {
 	To              time.Time
 	Pagination      *model.Pagination
 	Tags            []string
-	CursorTimestamp *time.Time   // Cursor position: timestamp (nil if no cursor)
+	Source          string       // 作成元でフィルタ（空の場合はすべて）
+	ExternalID      string       // 外部IDでフィルタ（空の場合はすべて）
+	CursorTimestamp *time.Time   // Cursor position: time in TimeField (nil if no cursor)
 	CursorID        *model.HexID // Cursor position: ID (nil if no cursor)
+
+	Order     model.RecordOrder     // 時系列の順序（空の場合は新しい順）
+	TimeField model.RecordTimeField // From/Toと順序を適用する日時（空の場合はtimestamp）
 }
 
 // ListAllRecordsParams は全レコード取得のパラメータです（ページネーションなし）。
@@ -40,40 +58,206 @@ type ListAllRecordsParams struct {
 	From      time.Time
 	To        time.Time
 	Tags      []string
+	Source    string // 作成元でフィルタ（空の場合はすべて）
+
+	Order model.RecordOrder // 時系列の順序（空の場合は新しい順）
+}
+
+// DayRange は期間指定のFrom/Toを丸一日単位の境界に揃えます。
+// Fromはその日の00:00:00、Toはその日の23:59:59.999999999（いずれもFrom/Toのタイムゾーン）で、
+// 期間は両端を含みます。ストアの実装はすべてこの境界でレコードを絞り込みます。
+func DayRange(from, to time.Time) (time.Time, time.Time) {
+	fromDate := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	toDate := time.Date(to.Year(), to.Month(), to.Day(), 23, 59, 59, 999999999, to.Location())
+	return fromDate, toDate
+}
+
+// GetDailyTotalsParams は日別集計のパラメータです。
+type GetDailyTotalsParams struct {
+	ProjectID model.HexID
+	From      time.Time
+	To        time.Time
+	Tags      []string
+
+	Aggregation model.Aggregation // 集計方法（空の場合は合計）
+	ValueType   model.ValueType   // プロジェクトのレコード値の型（空の場合は整数）
+	Location    *time.Location    // 日付の区切りに使うタイムゾーン（nilの場合はローカルタイム）
+}
+
+// DailyTotal は1日分の集計結果です。
+type DailyTotal struct {
+	Date  time.Time // その日の00:00:00（集計したタイムゾーン）
+	Value float64   // その日のレコード値の集計値（既定は合計、floatのプロジェクトでは小数を含む）
+}
+
+// RetentionPolicy はプロジェクトのレコード保持期間です。
+type RetentionPolicy struct {
+	ProjectID     model.HexID
+	RetentionDays int // この日数より古いレコードを削除する
+}
+
+// GetTagBreakdownParams はタグ別集計のパラメータです。
+type GetTagBreakdownParams struct {
+	ProjectID model.HexID
+	From      time.Time
+	To        time.Time
+}
+
+// TagTotal は1タグ分の集計結果です。
+type TagTotal struct {
+	Tag         string  `json:"tag"`
+	TotalValue  float64 `json:"total_value"` // floatのプロジェクトでは小数を含む
+	RecordCount int     `json:"record_count"`
+}
+
+// GetValueHistogramParams はレコード値の分布の集計パラメータです。
+type GetValueHistogramParams struct {
+	ProjectID model.HexID
+	From      time.Time
+	To        time.Time
+	Tags      []string // 指定したすべてのタグを持つレコードのみ集計する
+}
+
+// ValueCount はレコード値ごとのレコード数です。
+type ValueCount struct {
+	Value       float64 `json:"value"` // floatのプロジェクトでは小数を含む
+	RecordCount int     `json:"record_count"`
+}
+
+// AddTagParams は条件に一致するレコードへのタグの一括追加のパラメータです。
+type AddTagParams struct {
+	ProjectID model.HexID
+	Tag       string // 追加するタグ
+	From      time.Time
+	To        time.Time
+	Tags      []string // 指定したすべてのタグを持つレコードのみ対象にする
+}
+
+// DeleteProjectResult はプロジェクト削除の結果です。
+type DeleteProjectResult struct {
+	Existed        bool `json:"existed"`         // プロジェクトが存在したか
+	RecordsDeleted int  `json:"records_deleted"` // 削除されたレコード数（論理削除済みのレコードを含む）
+}
+
+// ProjectSummary はプロジェクトのレコード集計です。
+type ProjectSummary struct {
+	RecordCount   int        `json:"record_count"`
+	TotalValue    int        `json:"total_value"`
+	FirstRecordAt *time.Time `json:"first_record_at"` // レコードがない場合はnil
+	LastRecordAt  *time.Time `json:"last_record_at"`  // レコードがない場合はnil
 }
 
 // Store はレコードとプロジェクトの永続化を行うインターフェースです。
 type Store interface {
 	// Record operations
 	// CreateRecord は新しいレコードを作成します。
+	// 同じ外部IDのレコードがプロジェクトにある場合は作成せずにmodel.ErrExternalIDTakenを返します。
 	CreateRecord(ctx context.Context, record *model.Record) error
-	// GetRecord は指定されたIDのレコードを取得します。
+	// GetRecordByExternalID は指定されたプロジェクトで外部IDを持つレコードを、論理削除されたものも含めて取得します。
+	GetRecordByExternalID(ctx context.Context, projectID model.HexID, externalID string) (*model.Record, error)
+	// GetRecord は指定されたIDのレコードを取得します。論理削除されたレコードは含みません。
 	GetRecord(ctx context.Context, id model.HexID) (*model.Record, error)
+	// GetRecordIncludingDeleted は論理削除されたレコードも含めて指定されたIDのレコードを取得します。
+	GetRecordIncludingDeleted(ctx context.Context, id model.HexID) (*model.Record, error)
+	// RecordExists は指定されたIDのレコードが存在するかを返します。
+	RecordExists(ctx context.Context, id model.HexID) (bool, error)
 	// UpdateRecord は指定されたIDのレコードを更新します。
 	UpdateRecord(ctx context.Context, record *model.Record) error
-	// DeleteRecord は指定されたIDのレコードを削除します。
+	// IncrementRecordValue は指定されたIDのレコードの値にdeltaをアトミックに加算し、加算後の値を返します。
+	// 加算後の値が1未満、またはprojectの下限・上限（nilの場合は制限なし）を外れる場合はバリデーションエラーになります。
+	IncrementRecordValue(ctx context.Context, id model.HexID, delta int, project *model.Project) (int, error)
+	// UpdateRecordWithIncrement はレコードの日時・プロジェクト・タグを更新し、値は保存されている値にdeltaをアトミックに加算します。
+	// 値の検証はIncrementRecordValueと同じで、record.Valueは加算後の値に更新されます。
+	UpdateRecordWithIncrement(ctx context.Context, record *model.Record, delta int, project *model.Project) error
+	// UpsertDailyRecord はrecordと同じプロジェクト・作成元・日（locのタイムゾーン）のレコードがあればその値にrecord.Valueを加算し、
+	// なければrecordを作成します。同時に呼び出されても1日に1件のレコードになります。
+	// recordのIDと値は保存後のレコードのものに更新され、新しく作成した場合はtrueを返します。
+	UpsertDailyRecord(ctx context.Context, record *model.Record, loc *time.Location) (bool, error)
+	// CreateRecordUniquePerDay はrecordと同じプロジェクト・タグの集合・日（locのタイムゾーン）のレコードがなければrecordを作成し、
+	// あれば作成せずにそのレコードを返します。新しく作成した場合はtrueを返します。
+	CreateRecordUniquePerDay(ctx context.Context, record *model.Record, loc *time.Location) (*model.Record, bool, error)
+	// DeleteRecord は指定されたIDのレコードを論理削除します。削除済みのレコードはmodel.ErrRecordNotFoundになります。
 	DeleteRecord(ctx context.Context, id model.HexID) error
+	// RestoreRecord は論理削除されたレコードを復元します。
+	RestoreRecord(ctx context.Context, id model.HexID) error
+	// PurgeRecord は論理削除されたものを含め、指定されたIDのレコードを完全に削除します。
+	PurgeRecord(ctx context.Context, id model.HexID) error
 	// DeleteRecordsUntil は指定日時より前のレコードを削除します。
 	DeleteRecordsUntil(ctx context.Context, projectID model.HexID, until time.Time) (int, error)
 	// ListRecords は指定されたパラメータに基づいてレコードを取得します。
 	ListRecords(ctx context.Context, params *ListRecordsParams) ([]*model.Record, error)
+	// ListRecordsForDay はdayのタイムゾーンでの暦日のレコードを、tagsのすべてを持つものに絞り込んで古い順にすべて返します。
+	ListRecordsForDay(ctx context.Context, projectID model.HexID, day time.Time, tags []string) ([]*model.Record, error)
+	// ListRecentRecords はすべてのプロジェクトのレコードを新しい順（日時の降順）に最大limit件返します。
+	ListRecentRecords(ctx context.Context, limit int) ([]*model.Record, error)
 	// ListAllRecords は指定されたパラメータに基づいて全てのレコードをイテレータで返します（ページネーションなし）。
 	// イテレータはレコードとエラーのペアを返します。エラーが発生した場合、エラーが返され処理が終了します。
 	ListAllRecords(ctx context.Context, params *ListAllRecordsParams) iter.Seq2[*model.Record, error]
+	// GetDailyTotals は指定されたパラメータに基づいてレコード値を日別に集計します。
+	// 結果は日付の昇順で、レコードが存在する日のみを含みます。
+	GetDailyTotals(ctx context.Context, params *GetDailyTotalsParams) ([]*DailyTotal, error)
 
 	// Project operations
 	// CreateProject は新しいプロジェクトを作成します。
+	// project.IDが有効な場合はそのIDで作成し、既に使われている場合はErrProjectIDTakenを返します。
 	CreateProject(ctx context.Context, project *model.Project) error
+	// CreateProjectWithLimit はプロジェクトの総数がmaxProjectsを超えない場合のみ新しいプロジェクトを作成します。
+	// 超える場合はmodel.ErrProjectLimitReachedを返します。maxProjectsが0以下の場合は上限を設けません。
+	CreateProjectWithLimit(ctx context.Context, project *model.Project, maxProjects int) error
 	// GetProject は指定されたIDのプロジェクトを取得します。
 	GetProject(ctx context.Context, id model.HexID) (*model.Project, error)
 	// UpdateProject は指定されたプロジェクトを更新します。
 	UpdateProject(ctx context.Context, project *model.Project) error
+	// CloneProject はprojectを新しいプロジェクトとして作成し、withRecordsがtrueの場合はsourceIDのプロジェクトのレコードも複製します。
+	// 複製元が存在しない場合はmodel.ErrProjectNotFound、名前が重複する場合はmodel.ErrProjectNameTakenを返します。
+	// プロジェクトの総数がmaxProjects（0以下の場合は上限なし）を超える場合はmodel.ErrProjectLimitReachedを返します。
+	CloneProject(ctx context.Context, sourceID model.HexID, project *model.Project, withRecords bool, maxProjects int) (int, error)
 	// DeleteProject は指定されたプロジェクトIDのすべてのレコードとプロジェクトを削除します。
-	DeleteProject(ctx context.Context, projectID model.HexID) error
+	// プロジェクトが存在しない場合もエラーにせず、Existedがfalseの結果を返します。
+	DeleteProject(ctx context.Context, projectID model.HexID) (*DeleteProjectResult, error)
+	// CountProjects はプロジェクトの総数を返します。
+	CountProjects(ctx context.Context) (int, error)
+	// ListRetentionPolicies は保持日数が設定されたプロジェクトの一覧を返します。
+	ListRetentionPolicies(ctx context.Context) ([]*RetentionPolicy, error)
 	// ListProjects は指定されたパラメータに基づいてプロジェクトを取得します。
 	ListProjects(ctx context.Context, params *ListProjectsParams) ([]*model.Project, error)
-	// GetProjectTags は指定されたプロジェクトIDのタグ一覧を取得します。
-	GetProjectTags(ctx context.Context, projectID model.HexID) ([]string, error)
+	// GetProjectTags は指定されたパラメータに基づいてプロジェクトのタグ一覧を名前順に取得します。
+	// プロジェクトが存在しない場合はmodel.ErrProjectNotFoundを返します。
+	GetProjectTags(ctx context.Context, params *GetProjectTagsParams) ([]string, error)
+	// ListTagAliases は指定されたプロジェクトのタグの別名を別名の順に取得します。
+	ListTagAliases(ctx context.Context, projectID model.HexID) ([]*model.TagAlias, error)
+	// SetTagAlias はタグの別名を作成し、既に存在する場合は正規のタグを置き換えます。
+	// 以後に書き込まれるタグと検索に使うタグは別名から正規のタグに置き換えられます。
+	SetTagAlias(ctx context.Context, projectID model.HexID, alias *model.TagAlias) error
+	// DeleteTagAlias はタグの別名を削除します。存在しない場合はmodel.ErrTagAliasNotFoundを返します。
+	DeleteTagAlias(ctx context.Context, projectID model.HexID, alias string) error
+	// AddTag は条件に一致するレコードにタグを追加し、追加したレコード数を返します。
+	// 既にタグを持つレコードは変更しません。
+	AddTag(ctx context.Context, params *AddTagParams) (int, error)
+	// CopyDayRecords はfromDateの日のレコードを同じ時刻・値・タグのままtoDateの日に複製し、作成したレコード数を返します。
+	// 同じ日を指定した場合はバリデーションエラー、プロジェクトが存在しない場合はmodel.ErrProjectNotFoundを返します。
+	CopyDayRecords(ctx context.Context, projectID model.HexID, fromDate, toDate time.Time) (int, error)
+	// GetProjectSummary は指定されたプロジェクトIDのレコード集計を取得します。
+	GetProjectSummary(ctx context.Context, projectID model.HexID) (*ProjectSummary, error)
+	// GetProjectLastModified はプロジェクトの設定、レコード・タグ・タグの別名のいずれかが最後に変更された日時を返します。
+	// レコードの削除や過去の日時での作成でも進むため、グラフのLast-Modifiedに使用します。
+	GetProjectLastModified(ctx context.Context, projectID model.HexID) (time.Time, error)
+	// GetTagBreakdown は指定期間のレコード値と件数をタグごとに集計し、合計値の降順で返します。
+	// 複数のタグを持つレコードはそれぞれのタグに計上されます。
+	GetTagBreakdown(ctx context.Context, params *GetTagBreakdownParams) ([]*TagTotal, error)
+
+	// GetValueHistogram は指定期間のレコード数をレコード値ごとに集計し、値の昇順で返します。
+	GetValueHistogram(ctx context.Context, params *GetValueHistogramParams) ([]*ValueCount, error)
+
+	// Maintenance operations
+	// PruneOrphanTags は対応するレコードが存在しないタグ行を削除し、削除した件数を返します。
+	PruneOrphanTags(ctx context.Context) (int, error)
+	// Vacuum はデータベースファイルを最適化し、削除によって生じた未使用領域を解放します。
+	// 対応しないストアでは何もしません。
+	Vacuum(ctx context.Context) error
+
+	// SchemaVersion は適用済みのスキーママイグレーションのバージョンを返します。
+	SchemaVersion(ctx context.Context) (int64, error)
 
 	// Close はストアの接続を閉じます。
 	Close() error
@@ -83,14 +267,140 @@ type Store interface {
 type SQLiteStore struct {
 	conn    *sql.DB
 	queries *sqlc.Queries
+
+	// writer は検索から書き込みまでを直列化するトランザクション用の接続です。
+	// トランザクションをBEGIN IMMEDIATEで開始するため、書き込みロックを先に取得し、
+	// 別のプロセスを含む同時の呼び出しはロックの解放を待ってから検索します。
+	writer *sql.DB
+
+	caseInsensitiveProjectNames bool
+}
+
+// isUniqueConstraintError はエラーがSQLiteのUNIQUE制約違反かどうかを判定します。
+func isUniqueConstraintError(err error) bool {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique
+	}
+	return false
+}
+
+// isPrimaryKeyConstraintError はエラーがSQLiteのPRIMARY KEY制約違反かどうかを判定します。
+func isPrimaryKeyConstraintError(err error) bool {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey
+	}
+	return false
+}
+
+// isForeignKeyConstraintError はエラーがSQLiteのFOREIGN KEY制約違反かどうかを判定します。
+func isForeignKeyConstraintError(err error) bool {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey
+	}
+	return false
+}
+
+// toNullInt64 は省略可能な整数値をNULL許容の列の値に変換します。
+func toNullInt64(v *int) sql.NullInt64 {
+	if v == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: int64(*v), Valid: true}
+}
+
+// formatTime は日時をデータベースに保存・比較する文字列（UTCのRFC3339形式）に変換します。
+// 日時の列は文字列として範囲の比較や並べ替えを行うため、オフセットを揃えてUTCで保存します。
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// toNullString は省略可能な文字列をNULL許容の列の値に変換します。
+func toNullString(v *string) sql.NullString {
+	if v == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *v, Valid: true}
+}
+
+// fromNullString はNULL許容の列の値を省略可能な文字列に変換します。
+func fromNullString(v sql.NullString) *string {
+	if !v.Valid {
+		return nil
+	}
+	return &v.String
+}
+
+// toNullFloat64 は省略可能な小数値をNULL許容の列の値に変換します。
+func toNullFloat64(v *float64) sql.NullFloat64 {
+	if v == nil {
+		return sql.NullFloat64{}
+	}
+	return sql.NullFloat64{Float64: *v, Valid: true}
+}
+
+// fromNullFloat64 はNULL許容の列の値を省略可能な小数値に変換します。
+func fromNullFloat64(v sql.NullFloat64) *float64 {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Float64
+}
+
+// fromNullInt64 はNULL許容の列の値を省略可能な整数値に変換します。
+func fromNullInt64(v sql.NullInt64) *int {
+	if !v.Valid {
+		return nil
+	}
+	n := int(v.Int64)
+	return &n
 }
 
 // MigrationFunc はデータベースマイグレーションを実行する関数の型です。
 type MigrationFunc func(*sql.DB) error
 
+// SQLiteOptions はSQLiteの接続ごとに設定するPRAGMAの指定です。
+type SQLiteOptions struct {
+	// PRAGMA synchronousの値（OFF、NORMAL、FULL、EXTRA。空の場合はNORMAL）
+	Synchronous string
+
+	// プロジェクト名の重複を大文字・小文字（ASCII）を区別せずに判定するか
+	// 名前は入力どおりに保存し、"Work"がある場合の"work"の作成・変更はmodel.ErrProjectNameTakenとします。
+	CaseInsensitiveProjectNames bool
+}
+
+// dsn はdbPathに接続するためのDSNを返します。
+// database/sqlはコネクションプールから新しい接続を作るため、PRAGMAはDSNで指定して
+// すべての接続で外部キー制約と同期モードが有効になるようにします。
+func (o SQLiteOptions) dsn(dbPath string) (string, error) {
+	synchronous := strings.ToUpper(o.Synchronous)
+	switch synchronous {
+	case "":
+		synchronous = "NORMAL"
+	case "OFF", "NORMAL", "FULL", "EXTRA":
+	default:
+		return "", fmt.Errorf("invalid synchronous mode: %s", o.Synchronous)
+	}
+	params := url.Values{}
+	params.Set("_foreign_keys", "on")
+	params.Set("_synchronous", synchronous)
+	return dbPath + "?" + params.Encode(), nil
+}
+
+// writerDSN はdsnに加えてトランザクションをBEGIN IMMEDIATEで開始するDSNを返します。
+func (o SQLiteOptions) writerDSN(dbPath string) (string, error) {
+	dsn, err := o.dsn(dbPath)
+	if err != nil {
+		return "", err
+	}
+	return dsn + "&_txlock=immediate", nil
+}
+
 // NewSQLiteStore は新しいSQLiteStoreを作成します。
 // migrationFunc が nil でない場合、データベース接続後にマイグレーションを実行します。
-func NewSQLiteStore(dataDir string, migrationFunc MigrationFunc) (*SQLiteStore, error) {
+func NewSQLiteStore(dataDir string, migrationFunc MigrationFunc, opts SQLiteOptions) (*SQLiteStore, error) {
 	// データディレクトリの作成（存在しない場合）
 	if err := os.MkdirAll(dataDir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create data directory: %w", err)
@@ -98,17 +408,19 @@ func NewSQLiteStore(dataDir string, migrationFunc MigrationFunc) (*SQLiteStore,
 
 	// SQLiteデータベースファイルのパス
 	dbPath := filepath.Join(dataDir, "sougen.db")
+	dsn, err := opts.dsn(dbPath)
+	if err != nil {
+		return nil, err
+	}
 
-	// SQLiteデータベースへの接続
-	conn, err := sql.Open("sqlite3", dbPath)
+	// SQLiteデータベースへの接続（外部キー制約と同期モードは接続ごとにDSNで設定）
+	conn, err := sql.Open("sqlite3", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to SQLite database: %w", err)
 	}
-
-	// 外部キー制約を有効化
-	if _, err := conn.Exec(`PRAGMA foreign_keys = ON`); err != nil {
+	if err := conn.Ping(); err != nil {
 		conn.Close()
-		return nil, fmt.Errorf("failed to enable foreign keys: %w", err)
+		return nil, fmt.Errorf("failed to connect to SQLite database: %w", err)
 	}
 
 	// マイグレーションの実行（指定されている場合）
@@ -119,32 +431,83 @@ func NewSQLiteStore(dataDir string, migrationFunc MigrationFunc) (*SQLiteStore,
 		}
 	}
 
+	// 検索から書き込みまでを直列化するトランザクション用の接続
+	writerDSN, err := opts.writerDSN(dbPath)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	writer, err := sql.Open("sqlite3", writerDSN)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to connect to SQLite database: %w", err)
+	}
+
 	return &SQLiteStore{
 		conn:    conn,
 		queries: sqlc.New(conn),
+		writer:  writer,
+
+		caseInsensitiveProjectNames: opts.CaseInsensitiveProjectNames,
 	}, nil
 }
 
 // CreateRecord は新しいレコードをデータベースに保存します。
+// 同じ外部IDのレコードがプロジェクトにある場合は作成せずにmodel.ErrExternalIDTakenを返します。
 func (s *SQLiteStore) CreateRecord(ctx context.Context, record *model.Record) error {
 	// バリデーション
 	if err := record.Validate(); err != nil {
 		return err
 	}
 
+	// 作成元が未設定の場合はAPIとして扱う
+	if record.Source == "" {
+		record.Source = model.RecordSourceAPI
+	}
+
+	return createRecord(ctx, s.queries, record)
+}
+
+// createRecord は指定されたクエリ（トランザクション内の場合を含む）でレコードとタグを保存し、採番されたIDを設定します。
+// 同じ外部IDのレコードがプロジェクトにある場合は何も挿入せずにmodel.ErrExternalIDTakenを返します。
+func createRecord(ctx context.Context, queries *sqlc.Queries, record *model.Record) error {
+	// タグの別名を正規のタグに置き換える
+	if err := canonicalizeRecordTags(ctx, queries, record); err != nil {
+		return err
+	}
+
+	// 記録された日時は保存時に設定する（保存時刻と同じ秒精度）
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = time.Now().Truncate(time.Second)
+	}
+
 	// 日時をRFC3339形式に統一して保存
-	formattedTime := record.Timestamp.Format(time.RFC3339)
+	formattedTime := formatTime(record.Timestamp)
 
 	// sqlcで生成されたクエリを使用（IDは自動生成）
-	ret, err := s.queries.CreateRecord(ctx, sqlc.CreateRecordParams{
+	ret, err := queries.CreateRecord(ctx, sqlc.CreateRecordParams{
 		ProjectID: record.ProjectID.ToInt64(),
 		Value:     int64(record.Value),
 		Timestamp: formattedTime,
+		Source:    record.Source,
+		CreatedAt: formatTime(record.CreatedAt),
+
+		ValueFloat: toNullFloat64(record.ValueFloat),
+		ExternalID: toNullString(record.ExternalID),
 	})
 	if err != nil {
 		return err
 	}
 
+	// 同じ外部IDのレコードがプロジェクトにあれば挿入されない
+	affected, err := ret.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if affected == 0 {
+		return model.ErrExternalIDTaken
+	}
+
 	id, err := ret.LastInsertId()
 	if err != nil {
 		return fmt.Errorf("failed to get last insert ID: %w", err)
@@ -153,7 +516,7 @@ func (s *SQLiteStore) CreateRecord(ctx context.Context, record *model.Record) er
 
 	// タグを個別に挿入
 	for i, tag := range record.Tags {
-		err = s.queries.CreateRecordTag(ctx, sqlc.CreateRecordTagParams{
+		err = queries.CreateRecordTag(ctx, sqlc.CreateRecordTagParams{
 			RecordID:   id,
 			Tag:        tag,
 			OrderIndex: int64(i),
@@ -166,6 +529,44 @@ func (s *SQLiteStore) CreateRecord(ctx context.Context, record *model.Record) er
 	return nil
 }
 
+// tagAliases は指定されたプロジェクトのタグの別名の対応表を取得します。
+func tagAliases(ctx context.Context, queries *sqlc.Queries, projectID model.HexID) (model.TagAliases, error) {
+	rows, err := queries.ListTagAliases(ctx, projectID.ToInt64())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tag aliases: %w", err)
+	}
+	aliases := make(model.TagAliases, len(rows))
+	for _, row := range rows {
+		aliases[row.Alias] = row.Tag
+	}
+	return aliases, nil
+}
+
+// canonicalizeRecordTags はレコードのタグのうち別名を正規のタグに置き換えます。
+func canonicalizeRecordTags(ctx context.Context, queries *sqlc.Queries, record *model.Record) error {
+	if len(record.Tags) == 0 {
+		return nil
+	}
+	aliases, err := tagAliases(ctx, queries, record.ProjectID)
+	if err != nil {
+		return err
+	}
+	record.Tags = aliases.Canonicalize(record.Tags)
+	return nil
+}
+
+// canonicalFilterTags は検索に使うタグのうち別名を正規のタグに置き換えます。
+func (s *SQLiteStore) canonicalFilterTags(ctx context.Context, projectID model.HexID, tags []string) ([]string, error) {
+	if len(tags) == 0 {
+		return tags, nil
+	}
+	aliases, err := tagAliases(ctx, s.queries, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return aliases.Canonicalize(tags), nil
+}
+
 // UpdateRecord は指定されたIDのレコードを更新します。
 func (s *SQLiteStore) UpdateRecord(ctx context.Context, record *model.Record) error {
 	// バリデーション
@@ -187,7 +588,7 @@ func (s *SQLiteStore) UpdateRecord(ctx context.Context, record *model.Record) er
 	}()
 
 	// 日時をRFC3339形式に統一して更新
-	formattedTime := record.Timestamp.Format(time.RFC3339)
+	formattedTime := formatTime(record.Timestamp)
 
 	// sqlcで生成されたクエリを使用（トランザクション内で）
 	queriesWithTx := s.queries.WithTx(tx)
@@ -198,6 +599,8 @@ func (s *SQLiteStore) UpdateRecord(ctx context.Context, record *model.Record) er
 		Value:     int64(record.Value),
 		Timestamp: formattedTime,
 		ID:        record.ID.ToInt64(),
+
+		ValueFloat: toNullFloat64(record.ValueFloat),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to update record: %w", err)
@@ -214,15 +617,91 @@ func (s *SQLiteStore) UpdateRecord(ctx context.Context, record *model.Record) er
 		return model.ErrRecordNotFound
 	}
 
-	// 既存のタグを削除
-	err = queriesWithTx.DeleteRecordTags(ctx, record.ID.ToInt64())
+	// タグを置き換える
+	if err := replaceRecordTags(ctx, queriesWithTx, record); err != nil {
+		return err
+	}
+
+	// トランザクションのコミット
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	tx = nil // コミットが成功したのでnilにして遅延関数でのロールバックを防ぐ
+
+	return nil
+}
+
+// UpdateRecordWithIncrement はUpdateRecordと同様にレコードの日時・プロジェクト・タグを更新し、
+// 値は保存されている値にdeltaを加算します（record.Valueは使用しません）。
+// 加算と値の検証（1以上、projectの下限・上限の範囲内）は1つのUPDATE文で行うため、同時の加算は失われません。
+// 検証に失敗した場合は何も更新せずにバリデーションエラーを返し、成功した場合はrecord.Valueを加算後の値に更新します。
+func (s *SQLiteStore) UpdateRecordWithIncrement(ctx context.Context, record *model.Record, delta int, project *model.Project) error {
+	// バリデーション
+	if err := record.Validate(); err != nil {
+		return err
+	}
+
+	// トランザクションの開始
+	tx, err := s.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	// トランザクションをロールバックするための遅延関数
+	defer func() {
+		if tx != nil {
+			tx.Rollback() // 成功した場合は既にnilになっているためエラーは無視
+		}
+	}()
+
+	queriesWithTx := s.queries.WithTx(tx)
+
+	minValue, maxValue := valueBounds(project)
+	value, err := queriesWithTx.UpdateRecordWithIncrement(ctx, sqlc.UpdateRecordWithIncrementParams{
+		ProjectID: record.ProjectID.ToInt64(),
+		Timestamp: formatTime(record.Timestamp),
+		Delta:     int64(delta),
+		ID:        record.ID.ToInt64(),
+		MinValue:  minValue,
+		MaxValue:  maxValue,
+	})
+	if err == sql.ErrNoRows {
+		return incrementError(ctx, queriesWithTx, record.ID, delta, project)
+	}
 	if err != nil {
+		return fmt.Errorf("failed to update record: %w", err)
+	}
+	record.Value = int(value)
+
+	// タグを置き換える
+	if err := replaceRecordTags(ctx, queriesWithTx, record); err != nil {
+		return err
+	}
+
+	// トランザクションのコミット
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	tx = nil
+
+	return nil
+}
+
+// replaceRecordTags はレコードのタグを別名を正規のタグに置き換えた上でrecord.Tagsに置き換えます。
+func replaceRecordTags(ctx context.Context, queries *sqlc.Queries, record *model.Record) error {
+	// タグの別名を正規のタグに置き換える
+	if err := canonicalizeRecordTags(ctx, queries, record); err != nil {
+		return err
+	}
+
+	// 既存のタグを削除
+	if err := queries.DeleteRecordTags(ctx, record.ID.ToInt64()); err != nil {
 		return fmt.Errorf("failed to delete existing tags: %w", err)
 	}
 
 	// 新しいタグを個別に挿入
 	for i, tag := range record.Tags {
-		err = queriesWithTx.CreateRecordTag(ctx, sqlc.CreateRecordTagParams{
+		err := queries.CreateRecordTag(ctx, sqlc.CreateRecordTagParams{
 			RecordID:   record.ID.ToInt64(),
 			Tag:        tag,
 			OrderIndex: int64(i),
@@ -231,145 +710,613 @@ func (s *SQLiteStore) UpdateRecord(ctx context.Context, record *model.Record) er
 			return fmt.Errorf("failed to create tag %s: %w", tag, err)
 		}
 	}
+	return nil
+}
 
-	// トランザクションのコミット
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+// valueBounds はprojectの値の下限・上限をクエリのパラメータに変換します（projectがnilの場合は制限なし）。
+func valueBounds(project *model.Project) (any, any) {
+	if project == nil {
+		return nil, nil
 	}
-	tx = nil // コミットが成功したのでnilにして遅延関数でのロールバックを防ぐ
-
-	return nil
+	return toNullFloat64(project.MinValue), toNullFloat64(project.MaxValue)
 }
 
-// GetRecord は指定されたIDのレコードを取得します。
-func (s *SQLiteStore) GetRecord(ctx context.Context, id model.HexID) (*model.Record, error) {
-	// sqlcで生成されたクエリを使用
-	dbRecord, err := s.queries.GetRecord(ctx, id.ToInt64())
-	if err == sql.ErrNoRows {
-		return nil, model.ErrRecordNotFound
+// incrementError は値の加算が行われなかった理由をエラーにします。
+// レコードがない（論理削除を含む）場合はmodel.ErrRecordNotFound、それ以外は加算後の値のバリデーションエラーです。
+func incrementError(ctx context.Context, queries *sqlc.Queries, id model.HexID, delta int, project *model.Project) error {
+	row, err := queries.GetRecord(ctx, id.ToInt64())
+	if err == sql.ErrNoRows || err == nil && row.DeletedAt.Valid {
+		return model.ErrRecordNotFound
 	}
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("failed to get record: %w", err)
 	}
-
-	// 文字列から時間に変換
-	timestamp, err := time.Parse(time.RFC3339, dbRecord.Timestamp)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse record date: %w", err)
+	value := int(row.Value) + delta
+	if value >= 1 && project != nil {
+		if err := project.CheckValue(float64(value)); err != nil {
+			return err
+		}
 	}
+	return model.NewValidationError("value must be a positive integer greater than 0")
+}
 
-	// タグを取得
-	tags, err := s.queries.GetRecordTags(ctx, dbRecord.ID)
+// RecordExists は指定されたIDのレコードが存在するかを返します。
+func (s *SQLiteStore) RecordExists(ctx context.Context, id model.HexID) (bool, error) {
+	// sqlcで生成されたクエリを使用
+	exists, err := s.queries.RecordExists(ctx, id.ToInt64())
 	if err != nil {
-		return nil, fmt.Errorf("failed to get record tags: %w", err)
+		return false, fmt.Errorf("failed to check record existence: %w", err)
 	}
 
-	// レコードの作成
-	return model.LoadRecord(model.NewHexID(dbRecord.ID), timestamp, model.NewHexID(dbRecord.ProjectID), int(dbRecord.Value), tags)
+	return exists == 1, nil
 }
 
-// ListRecords は指定されたプロジェクトの、指定した期間内のレコードを取得します。
-func (s *SQLiteStore) ListRecords(ctx context.Context, params *ListRecordsParams) ([]*model.Record, error) {
-	// 日付の範囲を丸一日に設定（秒以下の精度を取り除く）
-	fromDate := time.Date(params.From.Year(), params.From.Month(), params.From.Day(), 0, 0, 0, 0, params.From.Location())
-	fromStr := fromDate.Format(time.RFC3339)
+// IncrementRecordValue は指定されたIDのレコードの値にdeltaをアトミックに加算し、加算後の値を返します。
+// 加算後の値が1未満、またはprojectの下限・上限（nilの場合は制限なし）を外れる場合は更新せずにバリデーションエラーを返します。
+func (s *SQLiteStore) IncrementRecordValue(ctx context.Context, id model.HexID, delta int, project *model.Project) (int, error) {
+	// sqlcで生成されたクエリを使用（値の検証も同じUPDATE文で行う）
+	minValue, maxValue := valueBounds(project)
+	value, err := s.queries.IncrementRecordValue(ctx, sqlc.IncrementRecordValueParams{
+		Delta:    int64(delta),
+		ID:       id.ToInt64(),
+		MinValue: minValue,
+		MaxValue: maxValue,
+	})
+	if err == sql.ErrNoRows {
+		// 更新されなかった理由を判別
+		return 0, incrementError(ctx, s.queries, id, delta, project)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to increment record value: %w", err)
+	}
 
-	toDate := time.Date(params.To.Year(), params.To.Month(), params.To.Day(), 23, 59, 59, 999999999, params.To.Location())
-	toStr := toDate.Format(time.RFC3339)
+	return int(value), nil
+}
 
-	limit := int64(params.Pagination.Limit())
+// UpsertDailyRecord はrecordと同じプロジェクト・作成元・日（locのタイムゾーン）のレコードがあればその値にrecord.Valueを加算し、
+// なければrecordを作成します。新しく作成した場合はtrueを返します。
+// SQLiteの遅延トランザクションでは同時に検索した双方が作成してしまうため、
+// 検索から書き込みまでをBEGIN IMMEDIATEのトランザクション内で行い、別のプロセスからの呼び出しとも直列化します。
+func (s *SQLiteStore) UpsertDailyRecord(ctx context.Context, record *model.Record, loc *time.Location) (bool, error) {
+	// バリデーション
+	if err := record.Validate(); err != nil {
+		return false, err
+	}
 
-	// カーソルベースのページネーションパラメータ
-	var cursorID int64
-	var cursorTimestamp string
-	var cursorColumn any
-	if params.CursorTimestamp != nil && params.CursorID != nil {
-		// カーソルが指定されている場合、パラメータから直接取得
-		cursorID = params.CursorID.ToInt64()
-		cursorTimestamp = params.CursorTimestamp.Format(time.RFC3339)
-		cursorColumn = 1 // 非NULL値を設定してSQLの "? IS NULL" をFALSEにする
-	} else {
-		// カーソルが指定されていない場合は NULL
-		cursorColumn = nil
-		cursorTimestamp = ""
-		cursorID = 0
+	// 作成元が未設定の場合はAPIとして扱う
+	if record.Source == "" {
+		record.Source = model.RecordSourceAPI
 	}
 
-	var records []*model.Record
+	// トランザクションの開始（書き込みロックを先に取得する）
+	tx, err := s.writer.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
 
-	if len(params.Tags) == 0 {
-		// タグフィルタなし
-		dbRecords, err := s.queries.ListRecords(ctx, sqlc.ListRecordsParams{
-			Timestamp:   fromStr,
-			Timestamp_2: toStr,
-			ProjectID:   params.ProjectID.ToInt64(),
-			Column4:     cursorColumn,
-			Timestamp_3: cursorTimestamp,
-			Timestamp_4: cursorTimestamp,
-			ID:          cursorID,
-			Limit:       limit,
-		})
-		if err != nil {
-			return nil, err
+	// トランザクションをロールバックするための遅延関数
+	defer func() {
+		if tx != nil {
+			tx.Rollback() // 成功した場合は既にnilになっているためエラーは無視
 		}
+	}()
 
-		for _, dbRecord := range dbRecords {
-			timestamp, err := time.Parse(time.RFC3339, dbRecord.Timestamp)
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse record date: %w", err)
+	queriesWithTx := s.queries.WithTx(tx)
+
+	// グラフと同じ日付境界（locのタイムゾーン）で同じ日のレコードを検索
+	local := record.Timestamp.In(loc)
+	dayStart := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	dayEnd := dayStart.AddDate(0, 0, 1).Add(-time.Second)
+	id, err := queriesWithTx.FindDailyRecord(ctx, sqlc.FindDailyRecordParams{
+		DayStart:  formatTime(dayStart),
+		DayEnd:    formatTime(dayEnd),
+		ProjectID: record.ProjectID.ToInt64(),
+		Source:    record.Source,
+	})
+
+	created := false
+	switch {
+	case err == sql.ErrNoRows:
+		if err := createRecord(ctx, queriesWithTx, record); err != nil {
+			return false, err
+		}
+		created = true
+	case err != nil:
+		return false, fmt.Errorf("failed to find daily record: %w", err)
+	default:
+		value, err := queriesWithTx.IncrementRecordValue(ctx, sqlc.IncrementRecordValueParams{
+			Delta: int64(record.Value),
+			ID:    id,
+		})
+		if err != nil {
+			return false, fmt.Errorf("failed to increment record value: %w", err)
+		}
+		record.ID = model.NewHexID(id)
+		record.Value = int(value)
+	}
+
+	// トランザクションのコミット
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	tx = nil
+
+	return created, nil
+}
+
+// CreateRecordUniquePerDay はrecordと同じプロジェクト・タグの集合・日（locのタイムゾーン）のレコードがなければrecordを作成し、
+// あれば作成せずにそのレコードを返します。新しく作成した場合はtrueを返します。
+// UpsertDailyRecordと同様に、検索から書き込みまでをBEGIN IMMEDIATEのトランザクション内で行います。
+func (s *SQLiteStore) CreateRecordUniquePerDay(ctx context.Context, record *model.Record, loc *time.Location) (*model.Record, bool, error) {
+	// バリデーション
+	if err := record.Validate(); err != nil {
+		return nil, false, err
+	}
+
+	// 作成元が未設定の場合はAPIとして扱う
+	if record.Source == "" {
+		record.Source = model.RecordSourceAPI
+	}
+
+	// トランザクションの開始（書き込みロックを先に取得する）
+	tx, err := s.writer.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	// トランザクションをロールバックするための遅延関数
+	defer func() {
+		if tx != nil {
+			tx.Rollback() // 成功した場合は既にnilになっているためエラーは無視
+		}
+	}()
+
+	queriesWithTx := s.queries.WithTx(tx)
+
+	// 保存されているタグと比較できるよう、別名を正規のタグに置き換えておく
+	if err := canonicalizeRecordTags(ctx, queriesWithTx, record); err != nil {
+		return nil, false, err
+	}
+
+	// 同じ日のレコードからタグの集合が同じものを探す
+	local := record.Timestamp.In(loc)
+	dayStart := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	dayEnd := dayStart.AddDate(0, 0, 1).Add(-time.Second)
+	rows, err := queriesWithTx.ListRecordTimestamps(ctx, sqlc.ListRecordTimestampsParams{
+		Timestamp:   formatTime(dayStart),
+		Timestamp_2: formatTime(dayEnd),
+		ProjectID:   record.ProjectID.ToInt64(),
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to list records of the day: %w", err)
+	}
+	for _, row := range rows {
+		tags, err := queriesWithTx.GetRecordTags(ctx, row.ID)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get record tags: %w", err)
+		}
+		if record.HasSameTags(tags) {
+			// 既存のレコードはトランザクションを終えてから取得する
+			tx.Rollback()
+			tx = nil
+			existing, err := s.GetRecord(ctx, model.NewHexID(row.ID))
+			if err != nil {
+				return nil, false, err
+			}
+			return existing, false, nil
+		}
+	}
+
+	if err := createRecord(ctx, queriesWithTx, record); err != nil {
+		return nil, false, err
+	}
+
+	// トランザクションのコミット
+	if err := tx.Commit(); err != nil {
+		return nil, false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	tx = nil
+
+	return record, true, nil
+}
+
+// GetRecordByExternalID は指定されたプロジェクトで外部IDを持つレコードを、論理削除されたものも含めて取得します。
+// 論理削除されたレコードも外部IDを保持するため、再インポートで作成されなかったレコードの確認に使えます。
+func (s *SQLiteStore) GetRecordByExternalID(ctx context.Context, projectID model.HexID, externalID string) (*model.Record, error) {
+	id, err := s.queries.GetRecordIDByExternalID(ctx, sqlc.GetRecordIDByExternalIDParams{
+		ProjectID:  projectID.ToInt64(),
+		ExternalID: sql.NullString{String: externalID, Valid: true},
+	})
+	if err == sql.ErrNoRows {
+		return nil, model.ErrRecordNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.getRecord(ctx, model.NewHexID(id), true)
+}
+
+// GetRecord は指定されたIDのレコードを取得します。論理削除されたレコードは見つからないものとして扱います。
+func (s *SQLiteStore) GetRecord(ctx context.Context, id model.HexID) (*model.Record, error) {
+	return s.getRecord(ctx, id, false)
+}
+
+// GetRecordIncludingDeleted は論理削除されたレコードも含めて指定されたIDのレコードを取得します。
+func (s *SQLiteStore) GetRecordIncludingDeleted(ctx context.Context, id model.HexID) (*model.Record, error) {
+	return s.getRecord(ctx, id, true)
+}
+
+// getRecord は指定されたIDのレコードを取得します。includeDeletedがfalseの場合は論理削除されたレコードを除外します。
+func (s *SQLiteStore) getRecord(ctx context.Context, id model.HexID, includeDeleted bool) (*model.Record, error) {
+	// sqlcで生成されたクエリを使用
+	dbRecord, err := s.queries.GetRecord(ctx, id.ToInt64())
+	if err == sql.ErrNoRows {
+		return nil, model.ErrRecordNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if dbRecord.DeletedAt.Valid && !includeDeleted {
+		return nil, model.ErrRecordNotFound
+	}
+
+	// 文字列から時間に変換
+	timestamp, err := time.Parse(time.RFC3339, dbRecord.Timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse record date: %w", err)
+	}
+
+	// タグを取得
+	tags, err := s.queries.GetRecordTags(ctx, dbRecord.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get record tags: %w", err)
+	}
+
+	// レコードの作成
+	record, err := model.LoadRecord(model.NewHexID(dbRecord.ID), timestamp, model.NewHexID(dbRecord.ProjectID), int(dbRecord.Value), tags)
+	if err != nil {
+		return nil, err
+	}
+	record.Source = dbRecord.Source
+	record.ValueFloat = fromNullFloat64(dbRecord.ValueFloat)
+	record.ExternalID = fromNullString(dbRecord.ExternalID)
+	createdAt, err := time.Parse(time.RFC3339, dbRecord.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse record created_at: %w", err)
+	}
+	record.CreatedAt = createdAt
+	if dbRecord.DeletedAt.Valid {
+		deletedAt, err := time.Parse(time.RFC3339, dbRecord.DeletedAt.String)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse record deleted_at: %w", err)
+		}
+		record.DeletedAt = &deletedAt
+	}
+	return record, nil
+}
+
+// ListRecords は指定されたプロジェクトの、指定した期間内のレコードを取得します。
+func (s *SQLiteStore) ListRecords(ctx context.Context, params *ListRecordsParams) ([]*model.Record, error) {
+	// 日付の範囲を丸一日に設定（両端を含む）
+	// 保存時刻は秒精度のため、Toの秒未満を切り捨てた23:59:59との比較でもその日の最後のレコードを含む
+	fromDate, toDate := DayRange(params.From, params.To)
+	fromStr := formatTime(fromDate)
+	toStr := formatTime(toDate)
+
+	// 別名のタグは正規のタグで検索する
+	tags, err := s.canonicalFilterTags(ctx, params.ProjectID, params.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := int64(params.Pagination.Limit())
+
+	// カーソルベースのページネーションパラメータ
+	var cursorID int64
+	var cursorTimestamp string
+	var cursorColumn any
+	if params.CursorTimestamp != nil && params.CursorID != nil {
+		// カーソルが指定されている場合、パラメータから直接取得
+		cursorID = params.CursorID.ToInt64()
+		cursorTimestamp = formatTime(*params.CursorTimestamp)
+		cursorColumn = 1 // 非NULL値を設定してSQLの "? IS NULL" をFALSEにする
+	} else {
+		// カーソルが指定されていない場合は NULL
+		cursorColumn = nil
+		cursorTimestamp = ""
+		cursorID = 0
+	}
+
+	// 期間と順序をtimestamp（アクティビティの日時）とcreated_at（記録された日時）のどちらに適用するか
+	byCreatedAt := params.TimeField == model.RecordTimeFieldCreatedAt
+	asc := params.Order == model.RecordOrderAsc
+
+	var records []*model.Record
+
+	if len(tags) == 0 {
+		// タグフィルタなし
+		// 各クエリは同じ列構成のため、行の型を変換して共通の処理で読み込む
+		var dbRecords []sqlc.ListRecordsRow
+		if byCreatedAt {
+			queryParams := sqlc.ListRecordsByCreatedAtParams{
+				CreatedAt:   fromStr,
+				CreatedAt_2: toStr,
+				ProjectID:   params.ProjectID.ToInt64(),
+				Column4:     params.Source,
+				Source:      params.Source,
+				Column6:     params.ExternalID,
+				ExternalID:  sql.NullString{String: params.ExternalID, Valid: true},
+				Column8:     cursorColumn,
+				CreatedAt_3: cursorTimestamp,
+				CreatedAt_4: cursorTimestamp,
+				ID:          cursorID,
+				Limit:       limit,
+			}
+			if asc {
+				rows, err := s.queries.ListRecordsByCreatedAtAsc(ctx, sqlc.ListRecordsByCreatedAtAscParams(queryParams))
+				if err != nil {
+					return nil, err
+				}
+				for _, row := range rows {
+					dbRecords = append(dbRecords, sqlc.ListRecordsRow(row))
+				}
+			} else {
+				rows, err := s.queries.ListRecordsByCreatedAt(ctx, queryParams)
+				if err != nil {
+					return nil, err
+				}
+				for _, row := range rows {
+					dbRecords = append(dbRecords, sqlc.ListRecordsRow(row))
+				}
+			}
+		} else {
+			queryParams := sqlc.ListRecordsParams{
+				Timestamp:   fromStr,
+				Timestamp_2: toStr,
+				ProjectID:   params.ProjectID.ToInt64(),
+				Column4:     params.Source,
+				Source:      params.Source,
+				Column6:     params.ExternalID,
+				ExternalID:  sql.NullString{String: params.ExternalID, Valid: true},
+				Column8:     cursorColumn,
+				Timestamp_3: cursorTimestamp,
+				Timestamp_4: cursorTimestamp,
+				ID:          cursorID,
+				Limit:       limit,
 			}
+			if asc {
+				rows, err := s.queries.ListRecordsAsc(ctx, sqlc.ListRecordsAscParams(queryParams))
+				if err != nil {
+					return nil, err
+				}
+				for _, row := range rows {
+					dbRecords = append(dbRecords, sqlc.ListRecordsRow(row))
+				}
+			} else {
+				var err error
+				dbRecords, err = s.queries.ListRecords(ctx, queryParams)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
 
+		for _, dbRecord := range dbRecords {
 			var tags []string
 			if tagsStr, ok := dbRecord.Tags.(string); ok && tagsStr != "" {
 				tags = strings.Split(tagsStr, " ")
 			}
 
-			record, err := model.LoadRecord(model.NewHexID(dbRecord.ID), timestamp, model.NewHexID(dbRecord.ProjectID), int(dbRecord.Value), tags)
+			record, err := loadListedRecord(dbRecord.ID, dbRecord.ProjectID, dbRecord.Value, dbRecord.Timestamp, dbRecord.CreatedAt, tags)
 			if err != nil {
 				return nil, err
 			}
+			record.Source = dbRecord.Source
+			record.ValueFloat = fromNullFloat64(dbRecord.ValueFloat)
+			record.ExternalID = fromNullString(dbRecord.ExternalID)
 			records = append(records, record)
 		}
 	} else {
 		// タグフィルタあり
-		dbRecords, err := s.queries.ListRecordsWithTags(ctx, sqlc.ListRecordsWithTagsParams{
-			Timestamp:   fromStr,
-			Timestamp_2: toStr,
-			ProjectID:   params.ProjectID.ToInt64(),
-			Tags:        params.Tags,
-			Column5:     cursorColumn,
-			Timestamp_3: cursorTimestamp,
-			Timestamp_4: cursorTimestamp,
-			ID:          cursorID,
-			Column9:     int64(len(params.Tags)),
-			Limit:       limit,
-		})
-		if err != nil {
-			return nil, err
+		var dbRecords []sqlc.ListRecordsWithTagsRow
+		if byCreatedAt {
+			queryParams := sqlc.ListRecordsWithTagsByCreatedAtParams{
+				CreatedAt:   fromStr,
+				CreatedAt_2: toStr,
+				ProjectID:   params.ProjectID.ToInt64(),
+				Tags:        tags,
+				Column5:     params.Source,
+				Source:      params.Source,
+				Column7:     params.ExternalID,
+				ExternalID:  sql.NullString{String: params.ExternalID, Valid: true},
+				Column9:     cursorColumn,
+				CreatedAt_3: cursorTimestamp,
+				CreatedAt_4: cursorTimestamp,
+				ID:          cursorID,
+				Column13:    int64(len(tags)),
+				Limit:       limit,
+			}
+			if asc {
+				rows, err := s.queries.ListRecordsWithTagsByCreatedAtAsc(ctx, sqlc.ListRecordsWithTagsByCreatedAtAscParams(queryParams))
+				if err != nil {
+					return nil, err
+				}
+				for _, row := range rows {
+					dbRecords = append(dbRecords, sqlc.ListRecordsWithTagsRow(row))
+				}
+			} else {
+				rows, err := s.queries.ListRecordsWithTagsByCreatedAt(ctx, queryParams)
+				if err != nil {
+					return nil, err
+				}
+				for _, row := range rows {
+					dbRecords = append(dbRecords, sqlc.ListRecordsWithTagsRow(row))
+				}
+			}
+		} else {
+			queryParams := sqlc.ListRecordsWithTagsParams{
+				Timestamp:   fromStr,
+				Timestamp_2: toStr,
+				ProjectID:   params.ProjectID.ToInt64(),
+				Tags:        tags,
+				Column5:     params.Source,
+				Source:      params.Source,
+				Column7:     params.ExternalID,
+				ExternalID:  sql.NullString{String: params.ExternalID, Valid: true},
+				Column9:     cursorColumn,
+				Timestamp_3: cursorTimestamp,
+				Timestamp_4: cursorTimestamp,
+				ID:          cursorID,
+				Column13:    int64(len(tags)),
+				Limit:       limit,
+			}
+			if asc {
+				rows, err := s.queries.ListRecordsWithTagsAsc(ctx, sqlc.ListRecordsWithTagsAscParams(queryParams))
+				if err != nil {
+					return nil, err
+				}
+				for _, row := range rows {
+					dbRecords = append(dbRecords, sqlc.ListRecordsWithTagsRow(row))
+				}
+			} else {
+				var err error
+				dbRecords, err = s.queries.ListRecordsWithTags(ctx, queryParams)
+				if err != nil {
+					return nil, err
+				}
+			}
 		}
 
 		for _, dbRecord := range dbRecords {
-			timestamp, err := time.Parse(time.RFC3339, dbRecord.Timestamp)
+			record, err := loadListedRecord(dbRecord.ID, dbRecord.ProjectID, dbRecord.Value, dbRecord.Timestamp, dbRecord.CreatedAt, splitTags(dbRecord.AllTags))
 			if err != nil {
-				return nil, fmt.Errorf("failed to parse record date: %w", err)
+				return nil, err
 			}
+			record.Source = dbRecord.Source
+			record.ValueFloat = fromNullFloat64(dbRecord.ValueFloat)
+			record.ExternalID = fromNullString(dbRecord.ExternalID)
+			records = append(records, record)
+		}
+	}
 
-			var recordTags []string
-			if tagsStr, ok := dbRecord.AllTags.(string); ok && tagsStr != "" {
-				recordTags = strings.Split(tagsStr, " ")
-			}
+	return records, nil
+}
+
+// ListRecordsForDay はdayのタイムゾーンでの暦日（00:00:00以上、翌日の00:00:00未満）のレコードを、
+// tagsのすべてを持つものに絞り込んで古い順にすべて返します（ページネーションなし）。
+// ListRecordsと異なりカーソルや作成元の条件を持たず、プロジェクトIDと日時のインデックスで範囲を検索します。
+func (s *SQLiteStore) ListRecordsForDay(ctx context.Context, projectID model.HexID, day time.Time, tags []string) ([]*model.Record, error) {
+	dayStart := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	startStr := formatTime(dayStart)
+	endStr := formatTime(dayStart.AddDate(0, 0, 1))
 
-			record, err := model.LoadRecord(model.NewHexID(dbRecord.ID), timestamp, model.NewHexID(dbRecord.ProjectID), int(dbRecord.Value), recordTags)
+	// 別名のタグは正規のタグで検索する
+	tags, err := s.canonicalFilterTags(ctx, projectID, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	// 両方のクエリは同じ列構成のため、共通の処理で行を読み込む
+	var records []*model.Record
+	if len(tags) == 0 {
+		rows, err := s.queries.ListRecordsForDay(ctx, sqlc.ListRecordsForDayParams{
+			ProjectID:   projectID.ToInt64(),
+			Timestamp:   startStr,
+			Timestamp_2: endStr,
+		})
+		if err != nil {
+			return nil, err
+		}
+		records = make([]*model.Record, 0, len(rows))
+		for _, row := range rows {
+			record, err := loadRecordRow(row.ID, row.ProjectID, row.Value, row.Timestamp, row.Source, row.ValueFloat, row.CreatedAt, row.ExternalID, row.Tags)
+			if err != nil {
+				return nil, err
+			}
+			records = append(records, record)
+		}
+	} else {
+		rows, err := s.queries.ListRecordsForDayWithTags(ctx, sqlc.ListRecordsForDayWithTagsParams{
+			ProjectID:   projectID.ToInt64(),
+			Timestamp:   startStr,
+			Timestamp_2: endStr,
+			Tags:        tags,
+			Column5:     int64(len(tags)),
+		})
+		if err != nil {
+			return nil, err
+		}
+		records = make([]*model.Record, 0, len(rows))
+		for _, row := range rows {
+			record, err := loadRecordRow(row.ID, row.ProjectID, row.Value, row.Timestamp, row.Source, row.ValueFloat, row.CreatedAt, row.ExternalID, row.Tags)
 			if err != nil {
 				return nil, err
 			}
 			records = append(records, record)
 		}
 	}
+	return records, nil
+}
+
+// ListRecentRecords はすべてのプロジェクトのレコードを新しい順（日時の降順、同じ日時はIDの降順）に最大limit件返します。
+// ホームのダッシュボードなどで使う横断的な最近のアクティビティで、日時のインデックスで検索します。
+func (s *SQLiteStore) ListRecentRecords(ctx context.Context, limit int) ([]*model.Record, error) {
+	rows, err := s.queries.ListRecentRecords(ctx, int64(limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recent records: %w", err)
+	}
 
+	records := make([]*model.Record, 0, len(rows))
+	for _, row := range rows {
+		record, err := loadRecordRow(row.ID, row.ProjectID, row.Value, row.Timestamp, row.Source, row.ValueFloat, row.CreatedAt, row.ExternalID, row.Tags)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
 	return records, nil
 }
 
+// loadRecordRow はレコードのすべての列とGROUP_CONCATしたタグを返すクエリ
+// （ListRecordsForDay・ListRecordsForDayWithTags・ListRecentRecords）の行からレコードを作成します。
+func loadRecordRow(id, projectID, value int64, timestampStr, source string, valueFloat sql.NullFloat64, createdAtStr string, externalID sql.NullString, tags any) (*model.Record, error) {
+	record, err := loadListedRecord(id, projectID, value, timestampStr, createdAtStr, splitTags(tags))
+	if err != nil {
+		return nil, err
+	}
+	record.Source = source
+	record.ValueFloat = fromNullFloat64(valueFloat)
+	record.ExternalID = fromNullString(externalID)
+	return record, nil
+}
+
+// splitTags はGROUP_CONCATで空白区切りにしたタグの列の値をタグの配列に変換します。
+func splitTags(v any) []string {
+	if tagsStr, ok := v.(string); ok && tagsStr != "" {
+		return strings.Split(tagsStr, " ")
+	}
+	return nil
+}
+
+// loadListedRecord は一覧のクエリの行からレコードを作成します。
+func loadListedRecord(id, projectID, value int64, timestampStr, createdAtStr string, tags []string) (*model.Record, error) {
+	timestamp, err := time.Parse(time.RFC3339, timestampStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse record date: %w", err)
+	}
+	createdAt, err := time.Parse(time.RFC3339, createdAtStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse record created_at: %w", err)
+	}
+
+	record, err := model.LoadRecord(model.NewHexID(id), timestamp, model.NewHexID(projectID), int(value), tags)
+	if err != nil {
+		return nil, err
+	}
+	record.CreatedAt = createdAt
+	return record, nil
+}
+
 // ListAllRecords は指定されたパラメータに基づいて全てのレコードをイテレータで返します。
 // ページネーションを使用して段階的にレコードを取得し、メモリ効率的に処理します。
 func (s *SQLiteStore) ListAllRecords(ctx context.Context, params *ListAllRecordsParams) iter.Seq2[*model.Record, error] {
@@ -387,8 +1334,11 @@ func (s *SQLiteStore) ListAllRecords(ctx context.Context, params *ListAllRecords
 				To:              params.To,
 				Pagination:      pagination,
 				Tags:            params.Tags,
+				Source:          params.Source,
 				CursorTimestamp: cursorTimestamp,
 				CursorID:        cursorID,
+
+				Order: params.Order,
 			}
 
 			records, err := s.ListRecords(ctx, listParams)
@@ -398,60 +1348,840 @@ func (s *SQLiteStore) ListAllRecords(ctx context.Context, params *ListAllRecords
 				return
 			}
 
-			// 各レコードをyield
-			for _, record := range records {
-				if !yield(record, nil) {
-					// yieldがfalseを返したら早期終了
-					return
-				}
-			}
+			// 各レコードをyield
+			for _, record := range records {
+				if !yield(record, nil) {
+					// yieldがfalseを返したら早期終了
+					return
+				}
+			}
+
+			// 取得したレコード数がページサイズより少ない場合、これ以上レコードがない
+			if len(records) < pageSize {
+				break
+			}
+
+			// 次のページのためのカーソルを設定（新しいkeyset pagination形式）
+			// 昇順・降順どちらのクエリも最後のレコードより後ろの行を返すため、カーソルの設定は共通
+			lastRecord := records[len(records)-1]
+			cursorTimestamp = &lastRecord.Timestamp
+			cursorID = &lastRecord.ID
+		}
+	}
+}
+
+// GetDailyTotals は指定されたパラメータに基づいてレコード値を日別に集計します。
+func (s *SQLiteStore) GetDailyTotals(ctx context.Context, params *GetDailyTotalsParams) ([]*DailyTotal, error) {
+	return AggregateDailyTotals(s.ListAllRecords(ctx, &ListAllRecordsParams{
+		ProjectID: params.ProjectID,
+		From:      params.From,
+		To:        params.To,
+		Tags:      params.Tags,
+	}), params.Location, params.Aggregation, params.ValueType)
+}
+
+// AggregateDailyTotals はレコードのイテレータをlocのタイムゾーン（nilの場合はローカルタイム）の日付単位で集計します。
+// グラフ描画と同じ日付境界（プロジェクトのタイムゾーン）を用いるため、SQLではなくGo側で集計します。
+func AggregateDailyTotals(records iter.Seq2[*model.Record, error], loc *time.Location, agg model.Aggregation, valueType model.ValueType) ([]*DailyTotal, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	return AggregateRecordsBy(records, func(t time.Time) time.Time {
+		localTime := t.In(loc)
+		return time.Date(localTime.Year(), localTime.Month(), localTime.Day(), 0, 0, 0, 0, loc)
+	}, agg, valueType)
+}
+
+// AggregateRecordsBy はレコードのイテレータをbucketが返す時刻ごとに集計します。
+// 結果のDateにはバケットの時刻が入り、時刻順に並びます。
+// 小数の記録値を持つレコードはその値で集計します。
+func AggregateRecordsBy(records iter.Seq2[*model.Record, error], bucket func(time.Time) time.Time, agg model.Aggregation, valueType model.ValueType) ([]*DailyTotal, error) {
+	aggregators := make(map[time.Time]*model.Aggregator)
+	for record, err := range records {
+		if err != nil {
+			return nil, err
+		}
+		key := bucket(record.Timestamp)
+		aggregator, ok := aggregators[key]
+		if !ok {
+			aggregator = model.NewAggregator(agg, valueType)
+			aggregators[key] = aggregator
+		}
+		aggregator.Add(record.Amount())
+	}
+
+	result := make([]*DailyTotal, 0, len(aggregators))
+	for date, aggregator := range aggregators {
+		result = append(result, &DailyTotal{Date: date, Value: aggregator.Result()})
+	}
+	slices.SortFunc(result, func(a, b *DailyTotal) int {
+		return a.Date.Compare(b.Date)
+	})
+	return result, nil
+}
+
+// Close はデータベース接続を閉じます。
+func (s *SQLiteStore) Close() error {
+	return errors.Join(s.writer.Close(), s.conn.Close())
+}
+
+// DeleteRecord は指定されたIDのレコードを削除します。
+func (s *SQLiteStore) DeleteRecord(ctx context.Context, id model.HexID) error {
+	// sqlcで生成されたクエリを使用（deleted_atを設定する論理削除）
+	result, err := s.queries.DeleteRecord(ctx, sqlc.DeleteRecordParams{
+		DeletedAt: sql.NullString{String: formatTime(time.Now()), Valid: true},
+		ID:        id.ToInt64(),
+	})
+	if err != nil {
+		return err
+	}
+
+	// 削除された行数を確認
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	// レコードが見つからない場合
+	if rowsAffected == 0 {
+		return model.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// RestoreRecord は論理削除されたレコードを復元します。削除されていないレコードはそのままです。
+func (s *SQLiteStore) RestoreRecord(ctx context.Context, id model.HexID) error {
+	// sqlcで生成されたクエリを使用
+	result, err := s.queries.RestoreRecord(ctx, id.ToInt64())
+	if err != nil {
+		return err
+	}
+
+	// 更新された行数を確認
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	// レコードが見つからない場合
+	if rowsAffected == 0 {
+		return model.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// PurgeRecord は論理削除されたものを含め、指定されたIDのレコードを完全に削除します。
+func (s *SQLiteStore) PurgeRecord(ctx context.Context, id model.HexID) error {
+	// sqlcで生成されたクエリを使用
+	result, err := s.queries.PurgeRecord(ctx, id.ToInt64())
+	if err != nil {
+		return err
+	}
+
+	// 削除された行数を確認
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	// レコードが見つからない場合
+	if rowsAffected == 0 {
+		return model.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// DeleteProject は指定されたプロジェクトを削除します。
+func (s *SQLiteStore) DeleteProject(ctx context.Context, projectID model.HexID) (*DeleteProjectResult, error) {
+	// トランザクションの開始
+	tx, err := s.conn.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	// トランザクションをロールバックするための遅延関数
+	defer func() {
+		if tx != nil {
+			tx.Rollback() // 成功した場合は既にnilになっているためエラーは無視
+		}
+	}()
+
+	// sqlcで生成されたクエリを使用（トランザクション内で）
+	queriesWithTx := s.queries.WithTx(tx)
+
+	// カスケードで削除されるレコード数を削除前に数える
+	recordCount, err := queriesWithTx.CountProjectRecords(ctx, projectID.ToInt64())
+	if err != nil {
+		return nil, fmt.Errorf("failed to count project records: %w", err)
+	}
+
+	// プロジェクトを削除（ON DELETE CASCADEにより関連レコードも自動削除される）
+	result, err := queriesWithTx.DeleteProject(ctx, projectID.ToInt64())
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete project entity: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	// トランザクションのコミット
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	tx = nil // コミットが成功したのでnilにして遅延関数でのロールバックを防ぐ
+
+	return &DeleteProjectResult{
+		Existed:        rowsAffected > 0,
+		RecordsDeleted: int(recordCount),
+	}, nil
+}
+
+// DeleteRecordsUntil は指定日時より前のレコードを削除します。
+func (s *SQLiteStore) DeleteRecordsUntil(ctx context.Context, projectID model.HexID, until time.Time) (int, error) {
+	// トランザクションの開始
+	tx, err := s.conn.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	// トランザクションをロールバックするための遅延関数
+	defer func() {
+		if tx != nil {
+			tx.Rollback() // 成功した場合は既にnilになっているためエラーは無視
+		}
+	}()
+
+	// 日時を文字列に変換
+	untilStr := formatTime(until)
+
+	// sqlcで生成されたクエリを使用（トランザクション内で）
+	queriesWithTx := s.queries.WithTx(tx)
+	var result sql.Result
+	if !projectID.IsValid() {
+		// 特定のプロジェクト指定がない場合は全プロジェクトから削除
+		result, err = queriesWithTx.DeleteRecordsUntil(ctx, untilStr)
+	} else {
+		// 特定プロジェクトのレコードを削除
+		result, err = queriesWithTx.DeleteRecordsUntilByProject(ctx, sqlc.DeleteRecordsUntilByProjectParams{
+			ProjectID: projectID.ToInt64(),
+			Timestamp: untilStr,
+		})
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete records until specified date: %w", err)
+	}
+
+	// 削除された行数を取得
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	// トランザクションのコミット
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	tx = nil // コミットが成功したのでnilにして遅延関数でのロールバックを防ぐ
+
+	return int(rowsAffected), nil
+}
+
+// CreateProject は新しいプロジェクトをデータベースに保存します。
+func (s *SQLiteStore) CreateProject(ctx context.Context, project *model.Project) error {
+	return s.CreateProjectWithLimit(ctx, project, 0)
+}
+
+// CreateProjectWithLimit はプロジェクトの総数がmaxProjectsを超えない場合のみ新しいプロジェクトを保存します。
+func (s *SQLiteStore) CreateProjectWithLimit(ctx context.Context, project *model.Project, maxProjects int) error {
+	// バリデーション
+	if err := project.Validate(); err != nil {
+		return err
+	}
+
+	if !s.caseInsensitiveProjectNames && maxProjects <= 0 {
+		return createProject(ctx, s.queries, project)
+	}
+
+	// 名前や件数の確認が必要な場合は、確認から作成までをBEGIN IMMEDIATEのトランザクション内で行う
+	tx, err := s.writer.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	// トランザクションをロールバックするための遅延関数
+	defer func() {
+		if tx != nil {
+			tx.Rollback() // 成功した場合は既にnilになっているためエラーは無視
+		}
+	}()
+
+	queriesWithTx := s.queries.WithTx(tx)
+	if s.caseInsensitiveProjectNames {
+		if err := s.checkProjectName(ctx, queriesWithTx, project); err != nil {
+			return err
+		}
+	}
+	if err := createProject(ctx, queriesWithTx, project); err != nil {
+		return err
+	}
+	if err := checkProjectLimit(ctx, queriesWithTx, maxProjects); err != nil {
+		return err
+	}
+
+	// トランザクションのコミット
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	tx = nil // コミットが成功したのでnilにして遅延関数でのロールバックを防ぐ
+
+	return nil
+}
+
+// checkProjectName は大文字・小文字だけが異なる名前（同じ名前を含む）の別のプロジェクトがあれば
+// model.ErrProjectNameTakenを返します。CaseInsensitiveProjectNamesが有効な場合に、作成・変更と同じBEGIN IMMEDIATEのトランザクション内で呼び出します。
+func (s *SQLiteStore) checkProjectName(ctx context.Context, queries *sqlc.Queries, project *model.Project) error {
+	taken, err := queries.ProjectNameTakenIgnoringCase(ctx, sqlc.ProjectNameTakenIgnoringCaseParams{
+		Name: project.Name,
+		ID:   project.ID.ToInt64(), // 未採番（作成時）の場合はどのプロジェクトとも一致しない
+	})
+	if err != nil {
+		return fmt.Errorf("failed to check project name: %w", err)
+	}
+	if taken != 0 {
+		return model.ErrProjectNameTaken
+	}
+	return nil
+}
+
+// checkProjectLimit は作成後のプロジェクトの総数がmaxProjectsを超えていればmodel.ErrProjectLimitReachedを返します（0以下の場合は上限なし）。
+// 作成と同じBEGIN IMMEDIATEのトランザクション内で呼び出し、エラーの場合は作成をロールバックします。
+// 名前やIDの重複を上限より先に報告するため、作成の後に確認します。
+func checkProjectLimit(ctx context.Context, queries *sqlc.Queries, maxProjects int) error {
+	if maxProjects <= 0 {
+		return nil
+	}
+	count, err := queries.CountProjects(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to count projects: %w", err)
+	}
+	if int(count) > maxProjects {
+		return model.ErrProjectLimitReached
+	}
+	return nil
+}
+
+// createProject は指定されたクエリ（トランザクション内の場合を含む）でプロジェクトを保存し、採番されたIDを設定します。
+func createProject(ctx context.Context, queries *sqlc.Queries, project *model.Project) error {
+	// 日時をRFC3339形式に統一して保存
+	createdAtStr := formatTime(project.CreatedAt)
+	updatedAtStr := formatTime(project.UpdatedAt)
+
+	// IDが指定されている場合は採番せずにそのIDで保存
+	if project.ID.IsValid() {
+		_, err := queries.CreateProjectWithID(ctx, sqlc.CreateProjectWithIDParams{
+			ID:          project.ID.ToInt64(),
+			Name:        project.Name,
+			Description: project.Description,
+			CreatedAt:   createdAtStr,
+			UpdatedAt:   updatedAtStr,
+
+			TrackDefaultValue: toNullInt64(project.TrackDefaultValue),
+			Color:             toNullString(project.Color),
+			ValueType:         string(project.RecordValueType()),
+			RetentionDays:     toNullInt64(project.RetentionDays),
+			MinValue:          toNullFloat64(project.MinValue),
+			MaxValue:          toNullFloat64(project.MaxValue),
+			Timezone:          toNullString(project.Timezone),
+		})
+		if err != nil {
+			if isPrimaryKeyConstraintError(err) {
+				return model.ErrProjectIDTaken
+			}
+			if isUniqueConstraintError(err) {
+				return model.ErrProjectNameTaken
+			}
+			return fmt.Errorf("failed to create project: %w", err)
+		}
+		return nil
+	}
+
+	// sqlcで生成されたクエリを使用
+	ret, err := queries.CreateProject(ctx, sqlc.CreateProjectParams{
+		Name:        project.Name,
+		Description: project.Description,
+		CreatedAt:   createdAtStr,
+		UpdatedAt:   updatedAtStr,
+
+		TrackDefaultValue: toNullInt64(project.TrackDefaultValue),
+		Color:             toNullString(project.Color),
+		ValueType:         string(project.RecordValueType()),
+		RetentionDays:     toNullInt64(project.RetentionDays),
+		MinValue:          toNullFloat64(project.MinValue),
+		MaxValue:          toNullFloat64(project.MaxValue),
+		Timezone:          toNullString(project.Timezone),
+	})
+	if err != nil {
+		if isUniqueConstraintError(err) {
+			return model.ErrProjectNameTaken
+		}
+		return fmt.Errorf("failed to create project: %w", err)
+	}
+	id, err := ret.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	project.ID = model.NewHexID(id)
+	return nil
+}
+
+// CloneProject はprojectを新しいプロジェクトとして保存し、withRecordsがtrueの場合は
+// sourceIDのプロジェクトのレコードとタグを同じトランザクション内で複製します。
+// 複製したレコードの件数を返します。
+func (s *SQLiteStore) CloneProject(ctx context.Context, sourceID model.HexID, project *model.Project, withRecords bool, maxProjects int) (int, error) {
+	// バリデーション
+	if err := project.Validate(); err != nil {
+		return 0, err
+	}
+
+	// トランザクションの開始（名前・件数の確認から作成までを直列化するため、書き込みロックを先に取得する）
+	tx, err := s.writer.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	// トランザクションをロールバックするための遅延関数
+	defer func() {
+		if tx != nil {
+			tx.Rollback() // 成功した場合は既にnilになっているためエラーは無視
+		}
+	}()
+
+	// sqlcで生成されたクエリを使用（トランザクション内で）
+	queriesWithTx := s.queries.WithTx(tx)
+
+	exists, err := queriesWithTx.ProjectExists(ctx, sourceID.ToInt64())
+	if err != nil {
+		return 0, fmt.Errorf("failed to check project existence: %w", err)
+	}
+	if exists == 0 {
+		return 0, model.ErrProjectNotFound
+	}
+
+	if s.caseInsensitiveProjectNames {
+		if err := s.checkProjectName(ctx, queriesWithTx, project); err != nil {
+			return 0, err
+		}
+	}
+	if err := createProject(ctx, queriesWithTx, project); err != nil {
+		return 0, err
+	}
+	if err := checkProjectLimit(ctx, queriesWithTx, maxProjects); err != nil {
+		return 0, err
+	}
+
+	copied := 0
+	if withRecords {
+		recordIDs, err := queriesWithTx.ListProjectRecordIDs(ctx, sourceID.ToInt64())
+		if err != nil {
+			return 0, fmt.Errorf("failed to list records: %w", err)
+		}
+		for _, recordID := range recordIDs {
+			ret, err := queriesWithTx.CopyRecord(ctx, sqlc.CopyRecordParams{
+				ProjectID: project.ID.ToInt64(),
+				SourceID:  recordID,
+			})
+			if err != nil {
+				return 0, fmt.Errorf("failed to copy record: %w", err)
+			}
+			newID, err := ret.LastInsertId()
+			if err != nil {
+				return 0, fmt.Errorf("failed to get last insert ID: %w", err)
+			}
+			err = queriesWithTx.CopyRecordTags(ctx, sqlc.CopyRecordTagsParams{
+				RecordID:       newID,
+				SourceRecordID: recordID,
+			})
+			if err != nil {
+				return 0, fmt.Errorf("failed to copy tags: %w", err)
+			}
+			copied++
+		}
+	}
+
+	// トランザクションのコミット
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	tx = nil // コミットが成功したのでnilにして遅延関数でのロールバックを防ぐ
+
+	return copied, nil
+}
+
+// GetProject は指定されたIDのプロジェクトを取得します。
+func (s *SQLiteStore) GetProject(ctx context.Context, id model.HexID) (*model.Project, error) {
+	// sqlcで生成されたクエリを使用
+	dbProject, err := s.queries.GetProject(ctx, id.ToInt64())
+	if err == sql.ErrNoRows {
+		return nil, model.ErrProjectNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+	return toModelProject(dbProject)
+}
+
+// toModelProject はデータベースのプロジェクトをmodel.Projectに変換します。
+func toModelProject(dbProject sqlc.Project) (*model.Project, error) {
+	// 文字列から時間に変換
+	createdAt, err := time.Parse(time.RFC3339, dbProject.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+
+	updatedAt, err := time.Parse(time.RFC3339, dbProject.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse updated_at: %w", err)
+	}
+
+	// プロジェクトの作成
+	project, err := model.LoadProject(model.NewHexID(dbProject.ID), dbProject.Name, dbProject.Description, createdAt, updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	project.TrackDefaultValue = fromNullInt64(dbProject.TrackDefaultValue)
+	project.Color = fromNullString(dbProject.Color)
+	project.ValueType = model.ValueType(dbProject.ValueType)
+	project.RetentionDays = fromNullInt64(dbProject.RetentionDays)
+	project.MinValue = fromNullFloat64(dbProject.MinValue)
+	project.MaxValue = fromNullFloat64(dbProject.MaxValue)
+	project.Timezone = fromNullString(dbProject.Timezone)
+	return project, nil
+}
+
+// UpdateProject は指定されたプロジェクトを更新します。
+func (s *SQLiteStore) UpdateProject(ctx context.Context, project *model.Project) error {
+	// バリデーション
+	if err := project.Validate(); err != nil {
+		return err
+	}
+
+	if !s.caseInsensitiveProjectNames {
+		return updateProject(ctx, s.queries, project)
+	}
+
+	// 大文字・小文字を区別しない場合は、確認から更新までをBEGIN IMMEDIATEのトランザクション内で行う
+	tx, err := s.writer.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	// トランザクションをロールバックするための遅延関数
+	defer func() {
+		if tx != nil {
+			tx.Rollback() // 成功した場合は既にnilになっているためエラーは無視
+		}
+	}()
+
+	queriesWithTx := s.queries.WithTx(tx)
+	if err := s.checkProjectName(ctx, queriesWithTx, project); err != nil {
+		return err
+	}
+	if err := updateProject(ctx, queriesWithTx, project); err != nil {
+		return err
+	}
+
+	// トランザクションのコミット
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	tx = nil // コミットが成功したのでnilにして遅延関数でのロールバックを防ぐ
+
+	return nil
+}
+
+// updateProject は指定されたクエリ（トランザクション内の場合を含む）でプロジェクトを更新します。
+func updateProject(ctx context.Context, queries *sqlc.Queries, project *model.Project) error {
+	// 日時をRFC3339形式に統一して保存
+	updatedAtStr := formatTime(project.UpdatedAt)
+
+	// sqlcで生成されたクエリを使用
+	result, err := queries.UpdateProject(ctx, sqlc.UpdateProjectParams{
+		Name:        project.Name,
+		Description: project.Description,
+		UpdatedAt:   updatedAtStr,
+		ID:          project.ID.ToInt64(),
+
+		TrackDefaultValue: toNullInt64(project.TrackDefaultValue),
+		Color:             toNullString(project.Color),
+		ValueType:         string(project.RecordValueType()),
+		RetentionDays:     toNullInt64(project.RetentionDays),
+		MinValue:          toNullFloat64(project.MinValue),
+		MaxValue:          toNullFloat64(project.MaxValue),
+		Timezone:          toNullString(project.Timezone),
+	})
+	if err != nil {
+		if isUniqueConstraintError(err) {
+			return model.ErrProjectNameTaken
+		}
+		return fmt.Errorf("failed to update project: %w", err)
+	}
+
+	// 更新された行数を確認
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	// プロジェクトが見つからない場合
+	if rowsAffected == 0 {
+		return model.ErrProjectNotFound
+	}
+
+	return nil
+}
+
+// CountProjects はプロジェクトの総数を返します。
+func (s *SQLiteStore) CountProjects(ctx context.Context) (int, error) {
+	count, err := s.queries.CountProjects(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count projects: %w", err)
+	}
+	return int(count), nil
+}
+
+// ListRetentionPolicies は保持日数が設定されたプロジェクトの一覧をID順に返します。
+func (s *SQLiteStore) ListRetentionPolicies(ctx context.Context) ([]*RetentionPolicy, error) {
+	rows, err := s.queries.ListProjectRetentions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list retention policies: %w", err)
+	}
+	policies := make([]*RetentionPolicy, 0, len(rows))
+	for _, row := range rows {
+		policies = append(policies, &RetentionPolicy{
+			ProjectID:     model.NewHexID(row.ID),
+			RetentionDays: int(row.RetentionDays.Int64),
+		})
+	}
+	return policies, nil
+}
+
+// ListProjects はすべてのプロジェクトをparams.Sortの順に取得します。
+func (s *SQLiteStore) ListProjects(ctx context.Context, params *ListProjectsParams) ([]*model.Project, error) {
+	limit := int64(params.Pagination.Limit())
+
+	// カーソルの名前（カーソルが指定されていない場合は使用されない）
+	var cursorName string
+	if params.CursorName != nil {
+		cursorName = *params.CursorName
+	}
+
+	var dbProjects []sqlc.Project
+	var recordCounts []int
+	var err error
+	switch params.Sort {
+	case model.ProjectSortName:
+		var cursor any // NULLの場合はSQLの "? IS NULL" がTRUEになり先頭から取得
+		if params.CursorName != nil {
+			cursor = cursorName
+		}
+		dbProjects, err = s.queries.ListProjectsByName(ctx, sqlc.ListProjectsByNameParams{
+			CursorName: cursor,
+			Limit:      limit,
+		})
+	case model.ProjectSortCreated:
+		var cursor any
+		if params.CursorCreatedAt != nil && params.CursorName != nil {
+			cursor = formatTime(*params.CursorCreatedAt)
+		}
+		dbProjects, err = s.queries.ListProjectsByCreatedAt(ctx, sqlc.ListProjectsByCreatedAtParams{
+			CursorCreatedAt: cursor,
+			CursorName:      cursorName,
+			Limit:           limit,
+		})
+	case model.ProjectSortRecords:
+		var cursor any
+		if params.CursorRecordCount != nil && params.CursorName != nil {
+			cursor = *params.CursorRecordCount
+		}
+		var rows []sqlc.ListProjectsByRecordCountRow
+		rows, err = s.queries.ListProjectsByRecordCount(ctx, sqlc.ListProjectsByRecordCountParams{
+			CursorRecordCount: cursor,
+			CursorName:        cursorName,
+			Limit:             limit,
+		})
+		for _, row := range rows {
+			dbProjects = append(dbProjects, sqlc.Project{
+				ID:                row.ID,
+				Name:              row.Name,
+				Description:       row.Description,
+				CreatedAt:         row.CreatedAt,
+				UpdatedAt:         row.UpdatedAt,
+				TrackDefaultValue: row.TrackDefaultValue,
+				Color:             row.Color,
+				ValueType:         row.ValueType,
+				RetentionDays:     row.RetentionDays,
+				MinValue:          row.MinValue,
+				MaxValue:          row.MaxValue,
+				Timezone:          row.Timezone,
+			})
+			recordCounts = append(recordCounts, int(row.RecordCount))
+		}
+	default:
+		// カーソルベースのページネーションパラメータ
+		var cursorUpdatedAt string
+		var cursorColumn any
+		if params.CursorUpdatedAt != nil && params.CursorName != nil {
+			cursorUpdatedAt = formatTime(*params.CursorUpdatedAt)
+			cursorColumn = 1 // 非NULL値を設定してSQLの "? IS NULL" をFALSEにする
+		}
+		dbProjects, err = s.queries.ListProjects(ctx, sqlc.ListProjectsParams{
+			Column1:     cursorColumn,
+			UpdatedAt:   cursorUpdatedAt,
+			UpdatedAt_2: cursorUpdatedAt,
+			Name:        cursorName,
+			Limit:       limit,
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+
+	// 結果の変換
+	var projects []*model.Project
+	for i, dbProject := range dbProjects {
+		project, err := toModelProject(dbProject)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load project: %w", err)
+		}
+		if recordCounts != nil {
+			project.RecordCount = &recordCounts[i]
+		}
+		projects = append(projects, project)
+	}
+
+	return projects, nil
+}
+
+// GetProjectTags は指定されたパラメータに基づいてプロジェクトのタグ一覧を名前順に取得します。
+// プロジェクトが存在しない場合はmodel.ErrProjectNotFoundを返します。
+func (s *SQLiteStore) GetProjectTags(ctx context.Context, params *GetProjectTagsParams) ([]string, error) {
+	// ページネーションが指定されていない場合は全件（SQLiteのLIMIT -1は無制限）
+	limit := int64(-1)
+	if params.Pagination != nil {
+		limit = int64(params.Pagination.Limit())
+	}
 
-			// 取得したレコード数がページサイズより少ない場合、これ以上レコードがない
-			if len(records) < pageSize {
-				break
-			}
+	// カーソルベースのページネーションパラメータ
+	var cursorColumn any
+	var cursorTag string
+	if params.CursorTag != nil {
+		cursorColumn = 1 // 非NULL値を設定してSQLの "? IS NULL" をFALSEにする
+		cursorTag = *params.CursorTag
+	}
 
-			// 次のページのためのカーソルを設定（新しいkeyset pagination形式）
-			lastRecord := records[len(records)-1]
-			cursorTimestamp = &lastRecord.Timestamp
-			cursorID = &lastRecord.ID
+	// sqlcで生成されたクエリを使用
+	tags, err := s.queries.GetProjectTags(ctx, sqlc.GetProjectTagsParams{
+		ProjectID: params.ProjectID.ToInt64(),
+		Column2:   cursorColumn,
+		Tag:       cursorTag,
+		Limit:     limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project tags: %w", err)
+	}
+
+	// タグがない場合のみ、プロジェクトが存在しないのか区別する
+	if len(tags) == 0 {
+		exists, err := s.queries.ProjectExists(ctx, params.ProjectID.ToInt64())
+		if err != nil {
+			return nil, fmt.Errorf("failed to check project existence: %w", err)
+		}
+		if exists != 1 {
+			return nil, model.ErrProjectNotFound
 		}
 	}
-}
 
-// Close はデータベース接続を閉じます。
-func (s *SQLiteStore) Close() error {
-	return s.conn.Close()
+	return tags, nil
 }
 
-// DeleteRecord は指定されたIDのレコードを削除します。
-func (s *SQLiteStore) DeleteRecord(ctx context.Context, id model.HexID) error {
-	// sqlcで生成されたクエリを使用
-	result, err := s.queries.DeleteRecord(ctx, id.ToInt64())
+// ListTagAliases は指定されたプロジェクトのタグの別名を取得します。
+func (s *SQLiteStore) ListTagAliases(ctx context.Context, projectID model.HexID) ([]*model.TagAlias, error) {
+	rows, err := s.queries.ListTagAliases(ctx, projectID.ToInt64())
 	if err != nil {
+		return nil, fmt.Errorf("failed to list tag aliases: %w", err)
+	}
+
+	aliases := make([]*model.TagAlias, 0, len(rows))
+	for _, row := range rows {
+		aliases = append(aliases, &model.TagAlias{Alias: row.Alias, Tag: row.Tag})
+	}
+	return aliases, nil
+}
+
+// SetTagAlias はタグの別名を作成または更新します。
+func (s *SQLiteStore) SetTagAlias(ctx context.Context, projectID model.HexID, alias *model.TagAlias) error {
+	// バリデーション
+	if err := alias.Validate(); err != nil {
 		return err
 	}
 
-	// 削除された行数を確認
-	rowsAffected, err := result.RowsAffected()
+	err := s.queries.UpsertTagAlias(ctx, sqlc.UpsertTagAliasParams{
+		ProjectID: projectID.ToInt64(),
+		Alias:     alias.Alias,
+		Tag:       alias.Tag,
+	})
 	if err != nil {
-		return err
+		// プロジェクトが存在しない場合は外部キー制約違反になる
+		if isForeignKeyConstraintError(err) {
+			return model.ErrProjectNotFound
+		}
+		return fmt.Errorf("failed to set tag alias: %w", err)
+	}
+	return nil
+}
+
+// DeleteTagAlias はタグの別名を削除します。
+func (s *SQLiteStore) DeleteTagAlias(ctx context.Context, projectID model.HexID, alias string) error {
+	result, err := s.queries.DeleteTagAlias(ctx, sqlc.DeleteTagAliasParams{
+		ProjectID: projectID.ToInt64(),
+		Alias:     alias,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete tag alias: %w", err)
 	}
 
-	// レコードが見つからない場合
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
 	if rowsAffected == 0 {
-		return model.ErrRecordNotFound
+		return model.ErrTagAliasNotFound
 	}
-
 	return nil
 }
 
-// DeleteProject は指定されたプロジェクトを削除します。
-func (s *SQLiteStore) DeleteProject(ctx context.Context, projectID model.HexID) error {
+// AddTag は条件に一致するレコードにタグを追加します。
+func (s *SQLiteStore) AddTag(ctx context.Context, params *AddTagParams) (int, error) {
+	// バリデーション
+	if err := model.ValidateTag(params.Tag); err != nil {
+		return 0, err
+	}
+
+	// 日付の範囲を1日単位に拡張（ListRecordsと同じ境界）
+	fromDate, toDate := DayRange(params.From, params.To)
+	fromStr := formatTime(fromDate)
+	toStr := formatTime(toDate)
+
 	// トランザクションの開始
-	tx, err := s.conn.Begin()
+	tx, err := s.conn.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 
 	// トランザクションをロールバックするための遅延関数
@@ -461,28 +2191,69 @@ func (s *SQLiteStore) DeleteProject(ctx context.Context, projectID model.HexID)
 		}
 	}()
 
-	// sqlcで生成されたクエリを使用（トランザクション内で）
 	queriesWithTx := s.queries.WithTx(tx)
 
-	// プロジェクトを削除（ON DELETE CASCADEにより関連レコードも自動削除される）
-	err = queriesWithTx.DeleteProject(ctx, projectID.ToInt64())
+	// 追加するタグと検索に使うタグの別名を正規のタグに置き換える
+	aliases, err := tagAliases(ctx, queriesWithTx, params.ProjectID)
+	if err != nil {
+		return 0, err
+	}
+	tag := aliases.Canonicalize([]string{params.Tag})[0]
+	filterTags := aliases.Canonicalize(params.Tags)
+
+	var result sql.Result
+	if len(filterTags) == 0 {
+		result, err = queriesWithTx.AddTagToRecords(ctx, sqlc.AddTagToRecordsParams{
+			Tag:           tag,
+			FromTimestamp: fromStr,
+			ToTimestamp:   toStr,
+			ProjectID:     params.ProjectID.ToInt64(),
+		})
+	} else {
+		// タグフィルタあり（指定したすべてのタグを持つレコードのみ）
+		result, err = queriesWithTx.AddTagToRecordsWithTags(ctx, sqlc.AddTagToRecordsWithTagsParams{
+			Tag:         tag,
+			Timestamp:   fromStr,
+			Timestamp_2: toStr,
+			ProjectID:   params.ProjectID.ToInt64(),
+			FilterTags:  filterTags,
+			Column6:     int64(len(filterTags)),
+		})
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to add tag: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("failed to delete project entity: %w", err)
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
 	}
 
 	// トランザクションのコミット
 	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 	tx = nil // コミットが成功したのでnilにして遅延関数でのロールバックを防ぐ
 
-	return nil
+	return int(rowsAffected), nil
 }
 
-// DeleteRecordsUntil は指定日時より前のレコードを削除します。
-func (s *SQLiteStore) DeleteRecordsUntil(ctx context.Context, projectID model.HexID, until time.Time) (int, error) {
+// CopyDayRecords はfromDateの日のレコードをtoDateの日に複製します。
+// 日付はfromDate/toDateのタイムゾーンでの暦日として扱い、各レコードの時刻はそのまま日数だけずらします。
+func (s *SQLiteStore) CopyDayRecords(ctx context.Context, projectID model.HexID, fromDate, toDate time.Time) (int, error) {
+	// 暦日の差（夏時間の影響を受けないようUTCの日付で計算）
+	fromDay := time.Date(fromDate.Year(), fromDate.Month(), fromDate.Day(), 0, 0, 0, 0, time.UTC)
+	toDay := time.Date(toDate.Year(), toDate.Month(), toDate.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(toDay.Sub(fromDay).Hours() / 24)
+	if days == 0 {
+		return 0, model.NewValidationError("to_date must be a different day from from_date")
+	}
+
+	// 複製元の日の範囲（ListRecordsと同じ境界）
+	dayStart, dayEnd := DayRange(fromDate, fromDate)
+
 	// トランザクションの開始
-	tx, err := s.conn.Begin()
+	tx, err := s.conn.BeginTx(ctx, nil)
 	if err != nil {
 		return 0, fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -494,31 +2265,51 @@ func (s *SQLiteStore) DeleteRecordsUntil(ctx context.Context, projectID model.He
 		}
 	}()
 
-	// 日時を文字列に変換
-	untilStr := until.Format(time.RFC3339)
-
-	// sqlcで生成されたクエリを使用（トランザクション内で）
 	queriesWithTx := s.queries.WithTx(tx)
-	var result sql.Result
-	if !projectID.IsValid() {
-		// 特定のプロジェクト指定がない場合は全プロジェクトから削除
-		result, err = queriesWithTx.DeleteRecordsUntil(ctx, untilStr)
-	} else {
-		// 特定プロジェクトのレコードを削除
-		result, err = queriesWithTx.DeleteRecordsUntilByProject(ctx, sqlc.DeleteRecordsUntilByProjectParams{
-			ProjectID: projectID.ToInt64(),
-			Timestamp: untilStr,
-		})
-	}
 
+	exists, err := queriesWithTx.ProjectExists(ctx, projectID.ToInt64())
 	if err != nil {
-		return 0, fmt.Errorf("failed to delete records until specified date: %w", err)
+		return 0, fmt.Errorf("failed to check project existence: %w", err)
+	}
+	if exists == 0 {
+		return 0, model.ErrProjectNotFound
 	}
 
-	// 削除された行数を取得
-	rowsAffected, err := result.RowsAffected()
+	rows, err := queriesWithTx.ListRecordTimestamps(ctx, sqlc.ListRecordTimestampsParams{
+		Timestamp:   formatTime(dayStart),
+		Timestamp_2: formatTime(dayEnd),
+		ProjectID:   projectID.ToInt64(),
+	})
 	if err != nil {
-		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+		return 0, fmt.Errorf("failed to list records: %w", err)
+	}
+
+	// コピーしたレコードは今記録されたものとして扱う
+	now := formatTime(time.Now())
+	for _, row := range rows {
+		timestamp, err := time.Parse(time.RFC3339, row.Timestamp)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse record date: %w", err)
+		}
+		ret, err := queriesWithTx.CopyRecordToTimestamp(ctx, sqlc.CopyRecordToTimestampParams{
+			Timestamp: formatTime(timestamp.AddDate(0, 0, days)),
+			CreatedAt: now,
+			SourceID:  row.ID,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to copy record: %w", err)
+		}
+		newID, err := ret.LastInsertId()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+		err = queriesWithTx.CopyRecordTags(ctx, sqlc.CopyRecordTagsParams{
+			RecordID:       newID,
+			SourceRecordID: row.ID,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to copy tags: %w", err)
+		}
 	}
 
 	// トランザクションのコミット
@@ -527,164 +2318,192 @@ func (s *SQLiteStore) DeleteRecordsUntil(ctx context.Context, projectID model.He
 	}
 	tx = nil // コミットが成功したのでnilにして遅延関数でのロールバックを防ぐ
 
-	return int(rowsAffected), nil
+	return len(rows), nil
 }
 
-// CreateProject は新しいプロジェクトをデータベースに保存します。
-func (s *SQLiteStore) CreateProject(ctx context.Context, project *model.Project) error {
-	// バリデーション
-	if err := project.Validate(); err != nil {
-		return err
-	}
-
-	// 日時をRFC3339形式に統一して保存
-	createdAtStr := project.CreatedAt.Format(time.RFC3339)
-	updatedAtStr := project.UpdatedAt.Format(time.RFC3339)
+// GetTagBreakdown は指定期間のレコード値と件数をタグごとに集計します。
+func (s *SQLiteStore) GetTagBreakdown(ctx context.Context, params *GetTagBreakdownParams) ([]*TagTotal, error) {
+	// 日付の範囲を1日単位に拡張（ListRecordsと同じ境界）
+	fromDate, toDate := DayRange(params.From, params.To)
 
 	// sqlcで生成されたクエリを使用
-	ret, err := s.queries.CreateProject(ctx, sqlc.CreateProjectParams{
-		Name:        project.Name,
-		Description: project.Description,
-		CreatedAt:   createdAtStr,
-		UpdatedAt:   updatedAtStr,
+	rows, err := s.queries.GetTagBreakdown(ctx, sqlc.GetTagBreakdownParams{
+		Timestamp:   formatTime(fromDate),
+		Timestamp_2: formatTime(toDate),
+		ProjectID:   params.ProjectID.ToInt64(),
 	})
 	if err != nil {
-		return fmt.Errorf("failed to create project: %w", err)
+		return nil, fmt.Errorf("failed to get tag breakdown: %w", err)
 	}
-	id, err := ret.LastInsertId()
+
+	result := make([]*TagTotal, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, &TagTotal{
+			Tag:         row.Tag,
+			TotalValue:  row.TotalValue,
+			RecordCount: int(row.RecordCount),
+		})
+	}
+	return result, nil
+}
+
+// GetValueHistogram は指定期間のレコード数をレコード値ごとに集計します。
+func (s *SQLiteStore) GetValueHistogram(ctx context.Context, params *GetValueHistogramParams) ([]*ValueCount, error) {
+	// 日付の範囲を1日単位に拡張（ListRecordsと同じ境界）
+	fromDate, toDate := DayRange(params.From, params.To)
+	fromStr := formatTime(fromDate)
+	toStr := formatTime(toDate)
+
+	// 別名のタグは正規のタグで検索する
+	tags, err := s.canonicalFilterTags(ctx, params.ProjectID, params.Tags)
 	if err != nil {
-		return fmt.Errorf("failed to get last insert ID: %w", err)
+		return nil, err
 	}
 
-	project.ID = model.NewHexID(id)
-	return nil
+	result := []*ValueCount{}
+	if len(tags) == 0 {
+		rows, err := s.queries.GetValueHistogram(ctx, sqlc.GetValueHistogramParams{
+			Timestamp:   fromStr,
+			Timestamp_2: toStr,
+			ProjectID:   params.ProjectID.ToInt64(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get value histogram: %w", err)
+		}
+		for _, row := range rows {
+			result = append(result, &ValueCount{Value: row.Value, RecordCount: int(row.RecordCount)})
+		}
+	} else {
+		// タグフィルタあり（指定したすべてのタグを持つレコードのみ）
+		rows, err := s.queries.GetValueHistogramWithTags(ctx, sqlc.GetValueHistogramWithTagsParams{
+			Timestamp:   fromStr,
+			Timestamp_2: toStr,
+			ProjectID:   params.ProjectID.ToInt64(),
+			Tags:        tags,
+			Column5:     int64(len(tags)),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get value histogram: %w", err)
+		}
+		for _, row := range rows {
+			result = append(result, &ValueCount{Value: row.Value, RecordCount: int(row.RecordCount)})
+		}
+	}
+	return result, nil
 }
 
-// GetProject は指定されたIDのプロジェクトを取得します。
-func (s *SQLiteStore) GetProject(ctx context.Context, id model.HexID) (*model.Project, error) {
+// GetProjectSummary は指定されたプロジェクトIDのレコード集計を取得します。
+func (s *SQLiteStore) GetProjectSummary(ctx context.Context, projectID model.HexID) (*ProjectSummary, error) {
 	// sqlcで生成されたクエリを使用
-	dbProject, err := s.queries.GetProject(ctx, id.ToInt64())
-	if err == sql.ErrNoRows {
-		return nil, model.ErrProjectNotFound
-	}
+	row, err := s.queries.GetProjectSummary(ctx, projectID.ToInt64())
 	if err != nil {
-		return nil, fmt.Errorf("failed to get project: %w", err)
+		return nil, fmt.Errorf("failed to get project summary: %w", err)
 	}
 
-	// 文字列から時間に変換
-	createdAt, err := time.Parse(time.RFC3339, dbProject.CreatedAt)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse created_at: %w", err)
+	summary := &ProjectSummary{
+		RecordCount: int(row.RecordCount),
+		TotalValue:  int(row.TotalValue),
 	}
 
-	updatedAt, err := time.Parse(time.RFC3339, dbProject.UpdatedAt)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse updated_at: %w", err)
+	// レコードがない場合は空文字列になる
+	if row.FirstRecordAt != "" {
+		firstRecordAt, err := time.Parse(time.RFC3339, row.FirstRecordAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse first_record_at: %w", err)
+		}
+		summary.FirstRecordAt = &firstRecordAt
+	}
+	if row.LastRecordAt != "" {
+		lastRecordAt, err := time.Parse(time.RFC3339, row.LastRecordAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse last_record_at: %w", err)
+		}
+		summary.LastRecordAt = &lastRecordAt
 	}
 
-	// プロジェクトの作成
-	return model.LoadProject(model.NewHexID(dbProject.ID), dbProject.Name, dbProject.Description, createdAt, updatedAt)
+	return summary, nil
 }
 
-// UpdateProject は指定されたプロジェクトを更新します。
-func (s *SQLiteStore) UpdateProject(ctx context.Context, project *model.Project) error {
-	// バリデーション
-	if err := project.Validate(); err != nil {
-		return err
+// GetProjectLastModified はプロジェクトの設定、レコード・タグ・タグの別名のいずれかが最後に変更された日時を返します。
+// レコード・タグ・タグの別名の変更日時はトリガーでprojects.changed_atに記録されます。
+func (s *SQLiteStore) GetProjectLastModified(ctx context.Context, projectID model.HexID) (time.Time, error) {
+	// sqlcで生成されたクエリを使用
+	lastModifiedStr, err := s.queries.GetProjectLastModified(ctx, projectID.ToInt64())
+	if err == sql.ErrNoRows {
+		return time.Time{}, model.ErrProjectNotFound
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get project last modified: %w", err)
 	}
 
-	// 日時をRFC3339形式に統一して保存
-	updatedAtStr := project.UpdatedAt.Format(time.RFC3339)
+	lastModified, err := time.Parse(time.RFC3339, lastModifiedStr)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse project last modified: %w", err)
+	}
+	return lastModified, nil
+}
 
+// PruneOrphanTags は対応するレコードが存在しないタグ行を削除し、削除した件数を返します。
+func (s *SQLiteStore) PruneOrphanTags(ctx context.Context) (int, error) {
 	// sqlcで生成されたクエリを使用
-	result, err := s.queries.UpdateProject(ctx, sqlc.UpdateProjectParams{
-		Name:        project.Name,
-		Description: project.Description,
-		UpdatedAt:   updatedAtStr,
-		ID:          project.ID.ToInt64(),
-	})
+	result, err := s.queries.DeleteOrphanTags(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to update project: %w", err)
+		return 0, fmt.Errorf("failed to prune orphan tags: %w", err)
 	}
 
-	// 更新された行数を確認
+	// 削除された行数を取得
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
 	}
 
-	// プロジェクトが見つからない場合
-	if rowsAffected == 0 {
-		return model.ErrProjectNotFound
-	}
+	return int(rowsAffected), nil
+}
 
+// Vacuum はVACUUMで未使用領域を解放し、ANALYZEで統計情報を更新します。
+func (s *SQLiteStore) Vacuum(ctx context.Context) error {
+	if _, err := s.conn.ExecContext(ctx, `VACUUM`); err != nil {
+		return fmt.Errorf("failed to vacuum database: %w", err)
+	}
+	if _, err := s.conn.ExecContext(ctx, `ANALYZE`); err != nil {
+		return fmt.Errorf("failed to analyze database: %w", err)
+	}
 	return nil
 }
 
-// ListProjects はすべてのプロジェクトを取得します。
-func (s *SQLiteStore) ListProjects(ctx context.Context, params *ListProjectsParams) ([]*model.Project, error) {
-	limit := int64(params.Pagination.Limit())
-
-	// カーソルベースのページネーションパラメータ
-	var cursorName string
-	var cursorUpdatedAt string
-	var cursorColumn any
-	if params.CursorUpdatedAt != nil && params.CursorName != nil {
-		// カーソルが指定されている場合、パラメータから直接取得
-		cursorName = *params.CursorName
-		cursorUpdatedAt = params.CursorUpdatedAt.Format(time.RFC3339)
-		cursorColumn = 1 // 非NULL値を設定してSQLの "? IS NULL" をFALSEにする
-	} else {
-		// カーソルが指定されていない場合は NULL
-		cursorColumn = nil
-		cursorUpdatedAt = ""
-		cursorName = ""
+// DatabaseSize はデータベースファイルのサイズ（バイト）を返します。
+func (s *SQLiteStore) DatabaseSize(ctx context.Context) (int64, error) {
+	var pageCount, pageSize int64
+	if err := s.conn.QueryRowContext(ctx, `PRAGMA page_count`).Scan(&pageCount); err != nil {
+		return 0, fmt.Errorf("failed to get page count: %w", err)
 	}
+	if err := s.conn.QueryRowContext(ctx, `PRAGMA page_size`).Scan(&pageSize); err != nil {
+		return 0, fmt.Errorf("failed to get page size: %w", err)
+	}
+	return pageCount * pageSize, nil
+}
 
-	// sqlcで生成されたクエリを使用
-	dbProjects, err := s.queries.ListProjects(ctx, sqlc.ListProjectsParams{
-		Column1:     cursorColumn,
-		UpdatedAt:   cursorUpdatedAt,
-		UpdatedAt_2: cursorUpdatedAt,
-		Name:        cursorName,
-		Limit:       limit,
-	})
+// SchemaVersion はgooseのバージョンテーブルから適用済みの最新マイグレーションバージョンを返します。
+// バージョンテーブルが存在しない場合（gooseを使わずに初期化された場合）は0を返します。
+func (s *SQLiteStore) SchemaVersion(ctx context.Context) (int64, error) {
+	var tableCount int
+	err := s.conn.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'goose_db_version'`,
+	).Scan(&tableCount)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list projects: %w", err)
+		return 0, fmt.Errorf("failed to check goose version table: %w", err)
 	}
-
-	// 結果の変換
-	var projects []*model.Project
-	for _, dbProject := range dbProjects {
-		// 文字列から時間に変換
-		createdAt, err := time.Parse(time.RFC3339, dbProject.CreatedAt)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse created_at: %w", err)
-		}
-
-		updatedAt, err := time.Parse(time.RFC3339, dbProject.UpdatedAt)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse updated_at: %w", err)
-		}
-
-		// プロジェクトの作成
-		project, err := model.LoadProject(model.NewHexID(dbProject.ID), dbProject.Name, dbProject.Description, createdAt, updatedAt)
-		if err != nil {
-			return nil, fmt.Errorf("failed to load project: %w", err)
-		}
-		projects = append(projects, project)
+	if tableCount == 0 {
+		return 0, nil
 	}
 
-	return projects, nil
-}
-
-// GetProjectTags は指定されたプロジェクトIDのタグ一覧を取得します。
-func (s *SQLiteStore) GetProjectTags(ctx context.Context, projectID model.HexID) ([]string, error) {
-	// sqlcで生成されたクエリを使用
-	tags, err := s.queries.GetProjectTags(ctx, projectID.ToInt64())
+	var version sql.NullInt64
+	err = s.conn.QueryRowContext(ctx,
+		`SELECT MAX(version_id) FROM goose_db_version WHERE is_applied = 1`,
+	).Scan(&version)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get project tags: %w", err)
+		return 0, fmt.Errorf("failed to get schema version: %w", err)
 	}
 
-	return tags, nil
+	return version.Int64, nil
 }
@@ -4,67 +4,20 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"maps"
 	"os"
 	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/stsysd/sougen/db"
 	"github.com/stsysd/sougen/model"
 )
 
-// testMigration はテスト用のシンプルなマイグレーション関数です。
-func testMigration(conn *sql.DB) error {
-	// 外部キー制約を有効化
-	_, err := conn.Exec(`PRAGMA foreign_keys = ON;`)
-	if err != nil {
-		return err
-	}
-
-	// テーブルの作成
-	_, err = conn.Exec(`
-		-- Projects table
-		CREATE TABLE IF NOT EXISTS projects (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			name TEXT NOT NULL UNIQUE,
-			description TEXT NOT NULL DEFAULT '',
-			created_at TEXT NOT NULL,
-			updated_at TEXT NOT NULL
-		);
-
-		-- Records table
-		CREATE TABLE IF NOT EXISTS records (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			project_id INTEGER NOT NULL,
-			value INTEGER NOT NULL,
-			timestamp TEXT NOT NULL,
-			FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
-		);
-
-		-- Tags table
-		CREATE TABLE IF NOT EXISTS tags (
-			record_id INTEGER NOT NULL,
-			tag TEXT NOT NULL,
-			order_index INTEGER NOT NULL DEFAULT 0,
-			PRIMARY KEY (record_id, tag),
-			FOREIGN KEY (record_id) REFERENCES records(id) ON DELETE CASCADE
-		);
-
-		-- Indexes
-		CREATE INDEX IF NOT EXISTS idx_records_project_id_timestamp
-		ON records(project_id, timestamp);
-
-		CREATE INDEX IF NOT EXISTS idx_tags_record_id ON tags(record_id);
-		CREATE INDEX IF NOT EXISTS idx_tags_tag ON tags(tag);
-		CREATE INDEX IF NOT EXISTS idx_projects_updated_at ON projects(updated_at);
-		CREATE INDEX IF NOT EXISTS idx_projects_name ON projects(name);
-
-		-- Unique constraint for tag order within same record
-		CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_record_order ON tags(record_id, order_index);
-	`)
-	return err
-}
-
 func setupTestStore(t *testing.T) (*SQLiteStore, func()) {
 	// テスト用の一時ディレクトリを作成
 	tempDir, err := os.MkdirTemp("", "sougen-test")
@@ -73,7 +26,7 @@ func setupTestStore(t *testing.T) (*SQLiteStore, func()) {
 	}
 
 	// テスト用のSQLiteストアを初期化
-	store, err := NewSQLiteStore(tempDir, testMigration)
+	store, err := NewSQLiteStore(tempDir, db.Migrate, SQLiteOptions{})
 	if err != nil {
 		os.RemoveAll(tempDir)
 		t.Fatalf("Failed to create test store: %v", err)
@@ -227,6 +180,127 @@ func TestDeleteRecord(t *testing.T) {
 	}
 }
 
+// TestSoftDeleteAndRestoreRecord は論理削除したレコードが一覧や集計から除外され、復元できることをテストします。
+func TestSoftDeleteAndRestoreRecord(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	project, _ := model.NewProject("soft-delete", "")
+	if err := store.CreateProject(ctx, project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	timestamp := time.Date(2025, 5, 21, 14, 30, 0, 0, time.Local)
+	record, _ := model.NewRecord(timestamp, project.ID, 3, []string{"trash"})
+	if err := store.CreateRecord(ctx, record); err != nil {
+		t.Fatalf("Failed to create record: %v", err)
+	}
+
+	countRecords := func() int {
+		records, err := store.ListRecords(ctx, &ListRecordsParams{
+			ProjectID:  project.ID,
+			From:       timestamp.AddDate(0, 0, -1),
+			To:         timestamp.AddDate(0, 0, 1),
+			Pagination: model.NewPaginationWithValues(100, nil),
+		})
+		if err != nil {
+			t.Fatalf("Failed to list records: %v", err)
+		}
+		return len(records)
+	}
+
+	if err := store.DeleteRecord(ctx, record.ID); err != nil {
+		t.Fatalf("Failed to delete record: %v", err)
+	}
+
+	// 論理削除したレコードは通常の取得・一覧・集計から除外される
+	if _, err := store.GetRecord(ctx, record.ID); !errors.Is(err, model.ErrRecordNotFound) {
+		t.Errorf("Expected ErrRecordNotFound, got %v", err)
+	}
+	if exists, _ := store.RecordExists(ctx, record.ID); exists {
+		t.Error("Expected soft-deleted record not to exist")
+	}
+	if n := countRecords(); n != 0 {
+		t.Errorf("Expected no listed records, got %d", n)
+	}
+	if summary, _ := store.GetProjectSummary(ctx, project.ID); summary.RecordCount != 0 {
+		t.Errorf("Expected soft-deleted record to be excluded from the summary, got %d", summary.RecordCount)
+	}
+	if tags, err := store.GetProjectTags(ctx, &GetProjectTagsParams{ProjectID: project.ID}); err != nil || len(tags) != 0 {
+		t.Errorf("Expected no tags, got %v (%v)", tags, err)
+	}
+	if _, err := store.IncrementRecordValue(ctx, record.ID, 1, nil); !errors.Is(err, model.ErrRecordNotFound) {
+		t.Errorf("Expected ErrRecordNotFound when incrementing, got %v", err)
+	}
+	if err := store.DeleteRecord(ctx, record.ID); !errors.Is(err, model.ErrRecordNotFound) {
+		t.Errorf("Expected ErrRecordNotFound when deleting twice, got %v", err)
+	}
+
+	// 削除済みを含めた取得
+	deleted, err := store.GetRecordIncludingDeleted(ctx, record.ID)
+	if err != nil {
+		t.Fatalf("Failed to get soft-deleted record: %v", err)
+	}
+	if deleted.DeletedAt == nil || deleted.Value != 3 || !slices.Equal(deleted.Tags, []string{"trash"}) {
+		t.Errorf("Unexpected soft-deleted record: %+v", deleted)
+	}
+
+	// 復元
+	if err := store.RestoreRecord(ctx, record.ID); err != nil {
+		t.Fatalf("Failed to restore record: %v", err)
+	}
+	restored, err := store.GetRecord(ctx, record.ID)
+	if err != nil {
+		t.Fatalf("Failed to get restored record: %v", err)
+	}
+	if restored.DeletedAt != nil || !slices.Equal(restored.Tags, []string{"trash"}) {
+		t.Errorf("Unexpected restored record: %+v", restored)
+	}
+	if n := countRecords(); n != 1 {
+		t.Errorf("Expected 1 listed record after restore, got %d", n)
+	}
+	if err := store.RestoreRecord(ctx, model.NewHexID(99999)); !errors.Is(err, model.ErrRecordNotFound) {
+		t.Errorf("Expected ErrRecordNotFound when restoring a missing record, got %v", err)
+	}
+}
+
+// TestPurgeRecord は論理削除済みのレコードを含めて完全に削除できることをテストします。
+func TestPurgeRecord(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	project, _ := model.NewProject("purge", "")
+	if err := store.CreateProject(ctx, project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	active, _ := model.NewRecord(time.Now(), project.ID, 1, nil)
+	trashed, _ := model.NewRecord(time.Now(), project.ID, 2, []string{"old"})
+	for _, record := range []*model.Record{active, trashed} {
+		if err := store.CreateRecord(ctx, record); err != nil {
+			t.Fatalf("Failed to create record: %v", err)
+		}
+	}
+	if err := store.DeleteRecord(ctx, trashed.ID); err != nil {
+		t.Fatalf("Failed to delete record: %v", err)
+	}
+
+	for _, record := range []*model.Record{active, trashed} {
+		if err := store.PurgeRecord(ctx, record.ID); err != nil {
+			t.Fatalf("Failed to purge record: %v", err)
+		}
+		if _, err := store.GetRecordIncludingDeleted(ctx, record.ID); !errors.Is(err, model.ErrRecordNotFound) {
+			t.Errorf("Expected purged record to be gone, got %v", err)
+		}
+		if err := store.RestoreRecord(ctx, record.ID); !errors.Is(err, model.ErrRecordNotFound) {
+			t.Errorf("Expected purged record not to be restorable, got %v", err)
+		}
+	}
+	if err := store.PurgeRecord(ctx, active.ID); !errors.Is(err, model.ErrRecordNotFound) {
+		t.Errorf("Expected ErrRecordNotFound when purging twice, got %v", err)
+	}
+}
+
 func TestListRecords(t *testing.T) {
 	store, cleanup := setupTestStore(t)
 	defer cleanup()
@@ -352,12 +426,12 @@ func TestListRecords(t *testing.T) {
 					t.Errorf("Expected project ID %s, got %s", readingProject.ID, r.ProjectID)
 				}
 
-				// 取得したレコードの日付を年月日のみで比較
-				rYear, rMonth, rDay := r.Timestamp.Date()
+				// 取得したレコードの日付を年月日のみで比較（日時はUTCで保存されるため、期間のタイムゾーンで比較）
+				rYear, rMonth, rDay := r.Timestamp.In(tc.from.Location()).Date()
 				fromYear, fromMonth, fromDay := tc.from.Date()
 				toYear, toMonth, toDay := tc.to.Date()
 
-				rDate := time.Date(rYear, rMonth, rDay, 0, 0, 0, 0, r.Timestamp.Location())
+				rDate := time.Date(rYear, rMonth, rDay, 0, 0, 0, 0, tc.from.Location())
 				fromDate := time.Date(fromYear, fromMonth, fromDay, 0, 0, 0, 0, tc.from.Location())
 				toDate := time.Date(toYear, toMonth, toDay, 0, 0, 0, 0, tc.to.Location())
 
@@ -433,7 +507,7 @@ func TestDeleteProject(t *testing.T) {
 	}
 
 	// プロジェクト1を削除
-	err = store.DeleteProject(context.Background(), project1.ID)
+	_, err = store.DeleteProject(context.Background(), project1.ID)
 	if err != nil {
 		t.Fatalf("Failed to delete project: %v", err)
 	}
@@ -481,10 +555,22 @@ func TestDeleteProject(t *testing.T) {
 	}
 
 	// 存在しないプロジェクトを削除してもエラーにならないことを確認
-	err = store.DeleteProject(context.Background(), model.NewHexID(99999))
+	result, err := store.DeleteProject(context.Background(), model.NewHexID(99999))
 	if err != nil {
 		t.Errorf("Expected no error when deleting non-existent project, got: %v", err)
 	}
+	if result.Existed || result.RecordsDeleted != 0 {
+		t.Errorf("Expected nothing deleted for non-existent project, got %+v", result)
+	}
+
+	// 削除結果にはカスケードで削除されたレコード数が含まれる
+	result, err = store.DeleteProject(context.Background(), project2.ID)
+	if err != nil {
+		t.Fatalf("Failed to delete project2: %v", err)
+	}
+	if !result.Existed || result.RecordsDeleted != 2 {
+		t.Errorf("Expected project2 to exist with 2 records deleted, got %+v", result)
+	}
 }
 
 // TestListRecordsWithTags はタグフィルタでのレコード取得のテスト
@@ -561,6 +647,18 @@ func TestListRecordsWithTags(t *testing.T) {
 			expectedCount: 1,
 			expectedIDs:   []model.HexID{record3.ID},
 		},
+		{
+			name:          "Filter by duplicated tag",
+			tags:          []string{"work", "work"},
+			expectedCount: 2,
+			expectedIDs:   []model.HexID{record1.ID, record3.ID},
+		},
+		{
+			name:          "Filter by multiple tags with a duplicate (AND - work and urgent)",
+			tags:          []string{"work", "urgent", "work"},
+			expectedCount: 1,
+			expectedIDs:   []model.HexID{record1.ID},
+		},
 		{
 			name:          "Filter by non-existent tag",
 			tags:          []string{"nonexistent"},
@@ -735,6 +833,226 @@ func TestListRecordsDateRange(t *testing.T) {
 	}
 }
 
+func TestListRecordsForDay(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	project, _ := model.NewProject("day", "")
+	if err := store.CreateProject(ctx, project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	other, _ := model.NewProject("other", "")
+	if err := store.CreateProject(ctx, other); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	at := func(d, hour, minute int) time.Time { return time.Date(2025, 6, d, hour, minute, 0, 0, time.UTC) }
+	create := func(projectID model.HexID, timestamp time.Time, tags []string) *model.Record {
+		t.Helper()
+		record, _ := model.NewRecord(timestamp, projectID, 1, tags)
+		if err := store.CreateRecord(ctx, record); err != nil {
+			t.Fatalf("Failed to create record: %v", err)
+		}
+		return record
+	}
+	create(project.ID, at(1, 23, 59), []string{"work"}) // 前日
+	create(project.ID, at(2, 18, 0), []string{"work", "urgent"})
+	create(project.ID, at(2, 0, 0), []string{"home"})
+	create(project.ID, at(2, 9, 30), []string{"work"})
+	create(project.ID, at(3, 0, 0), []string{"work"}) // 翌日の00:00:00は含まない
+	create(other.ID, at(2, 12, 0), []string{"work"})
+	deleted := create(project.ID, at(2, 12, 0), []string{"work"})
+	if err := store.DeleteRecord(ctx, deleted.ID); err != nil {
+		t.Fatalf("Failed to delete record: %v", err)
+	}
+
+	ids := func(records []*model.Record) []model.HexID {
+		result := make([]model.HexID, len(records))
+		for i, record := range records {
+			result[i] = record.ID
+		}
+		return result
+	}
+
+	for _, tags := range [][]string{nil, {"work"}, {"work", "urgent"}, {"none"}} {
+		t.Run(fmt.Sprintf("tags=%v", tags), func(t *testing.T) {
+			forDay, err := store.ListRecordsForDay(ctx, project.ID, at(2, 15, 0), tags)
+			if err != nil {
+				t.Fatalf("Failed to list records for day: %v", err)
+			}
+
+			// 一般的な一覧（新しい順）を古い順にしたものと一致する
+			general, err := store.ListRecords(ctx, &ListRecordsParams{
+				ProjectID:  project.ID,
+				From:       at(2, 0, 0),
+				To:         at(2, 0, 0),
+				Tags:       tags,
+				Pagination: model.NewPaginationWithValues(100, nil),
+			})
+			if err != nil {
+				t.Fatalf("Failed to list records: %v", err)
+			}
+			slices.Reverse(general)
+
+			if !slices.EqualFunc(ids(forDay), ids(general), model.HexID.Equals) {
+				t.Errorf("Expected %v, got %v", ids(general), ids(forDay))
+			}
+			for i, record := range forDay {
+				if !slices.Equal(record.Tags, general[i].Tags) || !record.Timestamp.Equal(general[i].Timestamp) {
+					t.Errorf("Expected record %+v, got %+v", general[i], record)
+				}
+			}
+		})
+	}
+
+	// 古い順で、その日の範囲のみ
+	records, err := store.ListRecordsForDay(ctx, project.ID, at(2, 0, 0), nil)
+	if err != nil {
+		t.Fatalf("Failed to list records for day: %v", err)
+	}
+	var timestamps []time.Time
+	for _, record := range records {
+		timestamps = append(timestamps, record.Timestamp.UTC())
+	}
+	if expected := []time.Time{at(2, 0, 0), at(2, 9, 30), at(2, 18, 0)}; !slices.EqualFunc(timestamps, expected, time.Time.Equal) {
+		t.Errorf("Expected timestamps %v, got %v", expected, timestamps)
+	}
+}
+
+func TestListRecentRecords(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	work, _ := model.NewProject("work", "")
+	home, _ := model.NewProject("home", "")
+	for _, project := range []*model.Project{work, home} {
+		if err := store.CreateProject(ctx, project); err != nil {
+			t.Fatalf("Failed to create project: %v", err)
+		}
+	}
+
+	// 2つのプロジェクトのレコードを異なるオフセットの日時で交互に作成
+	// （文字列として並べるとオフセットの大きい日時が新しく見えるため、UTCで保存されていることも確認する）
+	baseTime := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	zones := []*time.Location{time.FixedZone("JST", 9*60*60), time.FixedZone("EDT", -4*60*60)}
+	var created []*model.Record
+	for i, projectID := range []model.HexID{work.ID, home.ID, work.ID, home.ID, work.ID} {
+		timestamp := baseTime.Add(time.Duration(i) * time.Hour).In(zones[i%2])
+		record, _ := model.NewRecord(timestamp, projectID, i+1, []string{fmt.Sprintf("tag%d", i)})
+		if err := store.CreateRecord(ctx, record); err != nil {
+			t.Fatalf("Failed to create record: %v", err)
+		}
+		created = append(created, record)
+	}
+	// 論理削除されたレコードは含まない
+	if err := store.DeleteRecord(ctx, created[4].ID); err != nil {
+		t.Fatalf("Failed to delete record: %v", err)
+	}
+
+	records, err := store.ListRecentRecords(ctx, 3)
+	if err != nil {
+		t.Fatalf("Failed to list recent records: %v", err)
+	}
+	expected := []*model.Record{created[3], created[2], created[1]}
+	if len(records) != len(expected) {
+		t.Fatalf("Expected %d records, got %d", len(expected), len(records))
+	}
+	for i, e := range expected {
+		got := records[i]
+		if !got.ID.Equals(e.ID) || !got.ProjectID.Equals(e.ProjectID) || !got.Timestamp.Equal(e.Timestamp) || !slices.Equal(got.Tags, e.Tags) {
+			t.Errorf("Expected record %d to be %+v, got %+v", i, e, got)
+		}
+	}
+
+	// limitが件数より大きい場合はすべて返す
+	records, err = store.ListRecentRecords(ctx, 100)
+	if err != nil {
+		t.Fatalf("Failed to list recent records: %v", err)
+	}
+	if len(records) != 4 {
+		t.Errorf("Expected 4 records, got %d", len(records))
+	}
+}
+
+func TestListRecordsByCreatedAt(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	project, _ := model.NewProject("backdated", "")
+	if err := store.CreateProject(ctx, project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	// backdatedは6/1のアクティビティを6/5に記録したもの
+	day := func(d, hour int) time.Time { return time.Date(2025, 6, d, hour, 0, 0, 0, time.UTC) }
+	backdated, _ := model.NewRecord(day(1, 10), project.ID, 1, []string{"work"})
+	backdated.CreatedAt = day(5, 9)
+	older, _ := model.NewRecord(day(3, 10), project.ID, 1, []string{"work"})
+	older.CreatedAt = day(3, 10)
+	newer, _ := model.NewRecord(day(4, 10), project.ID, 1, []string{"work"})
+	newer.CreatedAt = day(4, 10)
+	for _, record := range []*model.Record{backdated, older, newer} {
+		if err := store.CreateRecord(ctx, record); err != nil {
+			t.Fatalf("Failed to create record: %v", err)
+		}
+	}
+
+	list := func(params ListRecordsParams) []model.HexID {
+		t.Helper()
+		params.ProjectID = project.ID
+		if params.From.IsZero() {
+			params.From, params.To = day(1, 0), day(5, 0)
+		}
+		if params.Pagination == nil {
+			params.Pagination = model.NewPaginationWithValues(100, nil)
+		}
+		records, err := store.ListRecords(ctx, &params)
+		if err != nil {
+			t.Fatalf("Failed to list records: %v", err)
+		}
+		ids := make([]model.HexID, len(records))
+		for i, record := range records {
+			ids[i] = record.ID
+		}
+		return ids
+	}
+
+	tests := []struct {
+		name   string
+		params ListRecordsParams
+		want   []model.HexID
+	}{
+		{"timestamp", ListRecordsParams{}, []model.HexID{newer.ID, older.ID, backdated.ID}},
+		{"created_at", ListRecordsParams{TimeField: model.RecordTimeFieldCreatedAt}, []model.HexID{backdated.ID, newer.ID, older.ID}},
+		{"created_at asc", ListRecordsParams{TimeField: model.RecordTimeFieldCreatedAt, Order: model.RecordOrderAsc}, []model.HexID{older.ID, newer.ID, backdated.ID}},
+		{"created_at with tags", ListRecordsParams{TimeField: model.RecordTimeFieldCreatedAt, Tags: []string{"work"}}, []model.HexID{backdated.ID, newer.ID, older.ID}},
+		// 期間もtime_fieldの日時に適用される
+		{"timestamp on 6/5", ListRecordsParams{From: day(5, 0), To: day(5, 0)}, []model.HexID{}},
+		{"created_at on 6/5", ListRecordsParams{From: day(5, 0), To: day(5, 0), TimeField: model.RecordTimeFieldCreatedAt}, []model.HexID{backdated.ID}},
+		// カーソルの位置もcreated_at
+		{"created_at after cursor", ListRecordsParams{TimeField: model.RecordTimeFieldCreatedAt, CursorTimestamp: &backdated.CreatedAt, CursorID: &backdated.ID}, []model.HexID{newer.ID, older.ID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := list(tt.params); !slices.Equal(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	// 取得したレコードには記録された日時が設定される
+	record, err := store.GetRecord(ctx, backdated.ID)
+	if err != nil {
+		t.Fatalf("Failed to get record: %v", err)
+	}
+	if !record.CreatedAt.Equal(day(5, 9)) {
+		t.Errorf("Expected created_at %v, got %v", day(5, 9), record.CreatedAt)
+	}
+}
+
 // TestCreateProject はプロジェクト作成機能をテストします。
 func TestCreateProject(t *testing.T) {
 	store, cleanup := setupTestStore(t)
@@ -817,103 +1135,633 @@ func TestCreateDuplicateProject(t *testing.T) {
 	if err == nil {
 		t.Error("Expected error when creating duplicate project, got nil")
 	}
+	if !errors.Is(err, model.ErrProjectNameTaken) {
+		t.Errorf("Expected ErrProjectNameTaken, got %v", err)
+	}
 }
 
-// TestUpdateProject はプロジェクト更新機能をテストします。
-func TestUpdateProject(t *testing.T) {
+// TestCloneProject はプロジェクトの複製（レコードとタグを含む）をテストします。
+func TestCloneProject(t *testing.T) {
 	store, cleanup := setupTestStore(t)
 	defer cleanup()
+	ctx := context.Background()
 
-	// プロジェクトを作成
-	project, err := model.NewProject("update-test", "Original description")
-	if err != nil {
-		t.Fatalf("Failed to create project model: %v", err)
-	}
-	err = store.CreateProject(context.Background(), project)
-	if err != nil {
+	source, _ := model.NewProject("clone-source", "Source project")
+	if err := store.CreateProject(ctx, source); err != nil {
 		t.Fatalf("Failed to create project: %v", err)
 	}
+	timestamp := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	for i, tags := range [][]string{{"b", "a"}, nil} {
+		record, _ := model.NewRecord(timestamp.AddDate(0, 0, i), source.ID, i+2, tags)
+		if err := store.CreateRecord(ctx, record); err != nil {
+			t.Fatalf("Failed to create record: %v", err)
+		}
+	}
 
-	// 説明を更新
-	originalUpdatedAt := project.UpdatedAt
-
-	// 秒単位で時間差を確保してより明確な時間差を作る
-	time.Sleep(1 * time.Second)
-	project.Description = "Updated description"
-	project.UpdatedAt = time.Now()
+	listRecords := func(projectID model.HexID) []*model.Record {
+		var records []*model.Record
+		for record, err := range store.ListAllRecords(ctx, &ListAllRecordsParams{
+			ProjectID: projectID,
+			From:      timestamp.AddDate(0, 0, -1),
+			To:        timestamp.AddDate(0, 0, 7),
+		}) {
+			if err != nil {
+				t.Fatalf("Failed to list records: %v", err)
+			}
+			records = append(records, record)
+		}
+		return records
+	}
 
-	// 更新を保存
-	err = store.UpdateProject(context.Background(), project)
+	// メタデータのみ
+	metadataOnly, _ := model.NewProject("clone-metadata", source.Description)
+	copied, err := store.CloneProject(ctx, source.ID, metadataOnly, false, 0)
 	if err != nil {
-		t.Fatalf("Failed to update project: %v", err)
+		t.Fatalf("Failed to clone project: %v", err)
+	}
+	if copied != 0 || len(listRecords(metadataOnly.ID)) != 0 {
+		t.Errorf("Expected no records to be copied, got %d", copied)
 	}
 
-	// 更新されたプロジェクトを再取得
-	updatedProject, err := store.GetProject(context.Background(), project.ID)
+	// レコードも複製
+	withRecords, _ := model.NewProject("clone-records", source.Description)
+	copied, err = store.CloneProject(ctx, source.ID, withRecords, true, 0)
 	if err != nil {
-		t.Fatalf("Failed to get updated project: %v", err)
+		t.Fatalf("Failed to clone project: %v", err)
+	}
+	if copied != 2 {
+		t.Errorf("Expected 2 copied records, got %d", copied)
+	}
+	original := listRecords(source.ID)
+	cloned := listRecords(withRecords.ID)
+	if len(cloned) != len(original) {
+		t.Fatalf("Expected %d cloned records, got %d", len(original), len(cloned))
+	}
+	for i := range cloned {
+		if cloned[i].ID == original[i].ID || cloned[i].ProjectID != withRecords.ID {
+			t.Errorf("Expected a new record in the cloned project, got %+v", cloned[i])
+		}
+		if cloned[i].Value != original[i].Value || !cloned[i].Timestamp.Equal(original[i].Timestamp) {
+			t.Errorf("Expected record %+v to match %+v", cloned[i], original[i])
+		}
+		if !slices.Equal(cloned[i].Tags, original[i].Tags) {
+			t.Errorf("Expected tags %v (in order), got %v", original[i].Tags, cloned[i].Tags)
+		}
 	}
 
-	// 更新内容を確認
-	if updatedProject.Description != "Updated description" {
-		t.Errorf("Expected description 'Updated description', got %s", updatedProject.Description)
+	// 名前が重複する場合は何も作成しない
+	duplicate, _ := model.NewProject("clone-source", "")
+	if _, err := store.CloneProject(ctx, source.ID, duplicate, true, 0); !errors.Is(err, model.ErrProjectNameTaken) {
+		t.Errorf("Expected ErrProjectNameTaken, got %v", err)
 	}
 
-	// 時間比較を秒単位で行う
-	if !updatedProject.UpdatedAt.Truncate(time.Second).After(originalUpdatedAt.Truncate(time.Second)) {
-		t.Errorf("UpdatedAt should be after original time. Original: %v, Updated: %v", originalUpdatedAt, updatedProject.UpdatedAt)
+	// 複製元が存在しない場合
+	missing, _ := model.NewProject("clone-missing", "")
+	if _, err := store.CloneProject(ctx, model.NewHexID(999), missing, true, 0); !errors.Is(err, model.ErrProjectNotFound) {
+		t.Errorf("Expected ErrProjectNotFound, got %v", err)
+	}
+	projects, err := store.ListProjects(ctx, &ListProjectsParams{Pagination: model.NewPaginationWithValues(100, nil)})
+	if err != nil {
+		t.Fatalf("Failed to list projects: %v", err)
+	}
+	if len(projects) != 3 {
+		t.Errorf("Expected 3 projects after failed clones, got %d", len(projects))
 	}
 }
 
-// TestUpdateNonExistentProject は存在しないプロジェクトの更新をテストします。
-func TestUpdateNonExistentProject(t *testing.T) {
+// TestUpdateProjectToDuplicateName は既存の名前への変更が型付きエラーになることをテストします。
+func TestUpdateProjectToDuplicateName(t *testing.T) {
 	store, cleanup := setupTestStore(t)
 	defer cleanup()
 
-	// 存在しないプロジェクトを更新
-	project, err := model.NewProject("non-existent", "Description")
-	if err != nil {
-		t.Fatalf("Failed to create project model: %v", err)
+	project1, _ := model.NewProject("first", "")
+	if err := store.CreateProject(context.Background(), project1); err != nil {
+		t.Fatalf("Failed to create first project: %v", err)
+	}
+	project2, _ := model.NewProject("second", "")
+	if err := store.CreateProject(context.Background(), project2); err != nil {
+		t.Fatalf("Failed to create second project: %v", err)
 	}
 
-	err = store.UpdateProject(context.Background(), project)
-	if err == nil {
-		t.Error("Expected error when updating non-existent project, got nil")
+	project2.Name = "first"
+	err := store.UpdateProject(context.Background(), project2)
+	if !errors.Is(err, model.ErrProjectNameTaken) {
+		t.Errorf("Expected ErrProjectNameTaken, got %v", err)
 	}
 }
 
-// TestDeleteProjectEntity はプロジェクトエンティティ削除機能をテストします。
-func TestDeleteProjectEntity(t *testing.T) {
+// TestCaseInsensitiveProjectNames は大文字・小文字を区別しない名前の重複判定をテストします。
+func TestCaseInsensitiveProjectNames(t *testing.T) {
+	ctx := context.Background()
+
+	// 既定では大文字・小文字が異なれば別の名前
 	store, cleanup := setupTestStore(t)
 	defer cleanup()
+	for _, name := range []string{"Work", "work"} {
+		project, _ := model.NewProject(name, "")
+		if err := store.CreateProject(ctx, project); err != nil {
+			t.Fatalf("Expected %q to be created by default, got %v", name, err)
+		}
+	}
 
-	// プロジェクトを作成
-	project, err := model.NewProject("delete-test", "Test project")
+	store, err := NewSQLiteStore(t.TempDir(), db.Migrate, SQLiteOptions{CaseInsensitiveProjectNames: true})
 	if err != nil {
-		t.Fatalf("Failed to create project model: %v", err)
+		t.Fatalf("Failed to create store: %v", err)
 	}
-	err = store.CreateProject(context.Background(), project)
-	if err != nil {
+	defer store.Close()
+
+	work, _ := model.NewProject("Work", "")
+	if err := store.CreateProject(ctx, work); err != nil {
 		t.Fatalf("Failed to create project: %v", err)
 	}
 
-	// プロジェクトエンティティを削除
-	err = store.DeleteProject(context.Background(), project.ID)
-	if err != nil {
-		t.Fatalf("Failed to delete project entity: %v", err)
+	// 作成・複製は大文字・小文字だけが異なる名前を拒否する
+	duplicate, _ := model.NewProject("work", "")
+	if err := store.CreateProject(ctx, duplicate); !errors.Is(err, model.ErrProjectNameTaken) {
+		t.Errorf("Expected ErrProjectNameTaken on create, got %v", err)
+	}
+	clone, _ := model.NewProject("WORK", "")
+	if _, err := store.CloneProject(ctx, work.ID, clone, false, 0); !errors.Is(err, model.ErrProjectNameTaken) {
+		t.Errorf("Expected ErrProjectNameTaken on clone, got %v", err)
 	}
 
-	// プロジェクトが削除されたことを確認
-	_, err = store.GetProject(context.Background(), project.ID)
-	if err == nil {
-		t.Error("Expected error when getting deleted project, got nil")
+	// 別のプロジェクトの変更も拒否する
+	home, _ := model.NewProject("home", "")
+	if err := store.CreateProject(ctx, home); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	home.Name = "wORK"
+	if err := store.UpdateProject(ctx, home); !errors.Is(err, model.ErrProjectNameTaken) {
+		t.Errorf("Expected ErrProjectNameTaken on update, got %v", err)
 	}
-}
 
-// TestListProjects はプロジェクト一覧取得機能をテストします。
-func TestListProjects(t *testing.T) {
-	store, cleanup := setupTestStore(t)
-	defer cleanup()
+	// 自分自身の大文字・小文字の変更は許可し、名前は入力どおりに保存する
+	work.Name = "WORK"
+	if err := store.UpdateProject(ctx, work); err != nil {
+		t.Fatalf("Expected renaming the same project to succeed, got %v", err)
+	}
+	got, err := store.GetProject(ctx, work.ID)
+	if err != nil {
+		t.Fatalf("Failed to get project: %v", err)
+	}
+	if got.Name != "WORK" {
+		t.Errorf("Expected the name to be stored as given, got %q", got.Name)
+	}
+}
+
+// TestCaseInsensitiveProjectNamesConcurrentStores は同じデータベースを開いた別々のストア（別のプロセスに相当）から
+// 大文字・小文字だけが異なる名前で同時に作成しても、1件だけが作成されることをテストします。
+func TestCaseInsensitiveProjectNamesConcurrentStores(t *testing.T) {
+	dataDir := t.TempDir()
+	names := []string{"Work", "work", "WORK", "wOrK"}
+	stores := make([]*SQLiteStore, len(names))
+	for i := range stores {
+		store, err := NewSQLiteStore(dataDir, db.Migrate, SQLiteOptions{CaseInsensitiveProjectNames: true})
+		if err != nil {
+			t.Fatalf("Failed to create store: %v", err)
+		}
+		defer store.Close()
+		stores[i] = store
+	}
+
+	var wg sync.WaitGroup
+	var createdCount atomic.Int32
+	errs := make(chan error, len(names))
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			project, _ := model.NewProject(name, "")
+			err := stores[i].CreateProject(context.Background(), project)
+			switch {
+			case err == nil:
+				createdCount.Add(1)
+			case !errors.Is(err, model.ErrProjectNameTaken):
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	if got := createdCount.Load(); got != 1 {
+		t.Errorf("Expected exactly 1 project to be created, got %d", got)
+	}
+}
+
+func TestCreateProjectWithLimit(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	for i := range 2 {
+		project, _ := model.NewProject(fmt.Sprintf("project-%d", i), "")
+		if err := store.CreateProjectWithLimit(ctx, project, 2); err != nil {
+			t.Fatalf("Failed to create project: %v", err)
+		}
+	}
+
+	over, _ := model.NewProject("project-2", "")
+	if err := store.CreateProjectWithLimit(ctx, over, 2); !errors.Is(err, model.ErrProjectLimitReached) {
+		t.Errorf("Expected ErrProjectLimitReached, got %v", err)
+	}
+	// 名前の重複は上限より先に報告する
+	duplicate, _ := model.NewProject("project-0", "")
+	if err := store.CreateProjectWithLimit(ctx, duplicate, 2); !errors.Is(err, model.ErrProjectNameTaken) {
+		t.Errorf("Expected ErrProjectNameTaken, got %v", err)
+	}
+	clone, _ := model.NewProject("clone", "")
+	if _, err := store.CloneProject(ctx, model.NewHexID(1), clone, false, 2); !errors.Is(err, model.ErrProjectLimitReached) {
+		t.Errorf("Expected ErrProjectLimitReached for clone, got %v", err)
+	}
+	if count, _ := store.CountProjects(ctx); count != 2 {
+		t.Errorf("Expected 2 projects, got %d", count)
+	}
+
+	// 0は上限なし
+	unlimited, _ := model.NewProject("unlimited", "")
+	if err := store.CreateProjectWithLimit(ctx, unlimited, 0); err != nil {
+		t.Errorf("Expected no limit for 0, got %v", err)
+	}
+}
+
+func TestCreateProjectWithLimitConcurrentStores(t *testing.T) {
+	dataDir := t.TempDir()
+	const maxProjects = 3
+	stores := make([]*SQLiteStore, 8)
+	for i := range stores {
+		store, err := NewSQLiteStore(dataDir, db.Migrate, SQLiteOptions{})
+		if err != nil {
+			t.Fatalf("Failed to create store: %v", err)
+		}
+		defer store.Close()
+		stores[i] = store
+	}
+
+	var wg sync.WaitGroup
+	var createdCount atomic.Int32
+	errs := make(chan error, len(stores))
+	for i := range stores {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			project, _ := model.NewProject(fmt.Sprintf("project-%d", i), "")
+			err := stores[i].CreateProjectWithLimit(context.Background(), project, maxProjects)
+			switch {
+			case err == nil:
+				createdCount.Add(1)
+			case !errors.Is(err, model.ErrProjectLimitReached):
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	if got := createdCount.Load(); got != maxProjects {
+		t.Errorf("Expected exactly %d projects to be created, got %d", maxProjects, got)
+	}
+	if count, _ := stores[0].CountProjects(context.Background()); count != maxProjects {
+		t.Errorf("Expected %d projects, got %d", maxProjects, count)
+	}
+}
+
+// TestUpdateProject はプロジェクト更新機能をテストします。
+func TestUpdateProject(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	// プロジェクトを作成
+	project, err := model.NewProject("update-test", "Original description")
+	if err != nil {
+		t.Fatalf("Failed to create project model: %v", err)
+	}
+	err = store.CreateProject(context.Background(), project)
+	if err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	// 説明を更新
+	originalUpdatedAt := project.UpdatedAt
+
+	// 秒単位で時間差を確保してより明確な時間差を作る
+	time.Sleep(1 * time.Second)
+	project.Description = "Updated description"
+	project.UpdatedAt = time.Now()
+
+	// 更新を保存
+	err = store.UpdateProject(context.Background(), project)
+	if err != nil {
+		t.Fatalf("Failed to update project: %v", err)
+	}
+
+	// 更新されたプロジェクトを再取得
+	updatedProject, err := store.GetProject(context.Background(), project.ID)
+	if err != nil {
+		t.Fatalf("Failed to get updated project: %v", err)
+	}
+
+	// 更新内容を確認
+	if updatedProject.Description != "Updated description" {
+		t.Errorf("Expected description 'Updated description', got %s", updatedProject.Description)
+	}
+
+	// 時間比較を秒単位で行う
+	if !updatedProject.UpdatedAt.Truncate(time.Second).After(originalUpdatedAt.Truncate(time.Second)) {
+		t.Errorf("UpdatedAt should be after original time. Original: %v, Updated: %v", originalUpdatedAt, updatedProject.UpdatedAt)
+	}
+}
+
+// TestUpdateNonExistentProject は存在しないプロジェクトの更新をテストします。
+func TestUpdateNonExistentProject(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	// 存在しないプロジェクトを更新
+	project, err := model.NewProject("non-existent", "Description")
+	if err != nil {
+		t.Fatalf("Failed to create project model: %v", err)
+	}
+
+	err = store.UpdateProject(context.Background(), project)
+	if err == nil {
+		t.Error("Expected error when updating non-existent project, got nil")
+	}
+}
+
+// TestDeleteProjectEntity はプロジェクトエンティティ削除機能をテストします。
+func TestDeleteProjectEntity(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	// プロジェクトを作成
+	project, err := model.NewProject("delete-test", "Test project")
+	if err != nil {
+		t.Fatalf("Failed to create project model: %v", err)
+	}
+	err = store.CreateProject(context.Background(), project)
+	if err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	// プロジェクトエンティティを削除
+	_, err = store.DeleteProject(context.Background(), project.ID)
+	if err != nil {
+		t.Fatalf("Failed to delete project entity: %v", err)
+	}
+
+	// プロジェクトが削除されたことを確認
+	_, err = store.GetProject(context.Background(), project.ID)
+	if err == nil {
+		t.Error("Expected error when getting deleted project, got nil")
+	}
+}
+
+// TestCountProjects はプロジェクト数の取得をテストします。
+func TestCountProjects(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	for i := range 3 {
+		count, err := store.CountProjects(context.Background())
+		if err != nil {
+			t.Fatalf("Failed to count projects: %v", err)
+		}
+		if count != i {
+			t.Errorf("Expected %d projects, got %d", i, count)
+		}
+		project, _ := model.NewProject(fmt.Sprintf("project-%d", i), "")
+		if err := store.CreateProject(context.Background(), project); err != nil {
+			t.Fatalf("Failed to create project: %v", err)
+		}
+	}
+}
+
+// TestCreateProjectWithID は指定したIDでのプロジェクト作成をテストします。
+func TestCreateProjectWithID(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	project, _ := model.NewProject("beacon", "")
+	project.ID = model.NewHexID(0x2a)
+	if err := store.CreateProject(context.Background(), project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	got, err := store.GetProject(context.Background(), model.NewHexID(0x2a))
+	if err != nil {
+		t.Fatalf("Failed to get project: %v", err)
+	}
+	if got.Name != "beacon" {
+		t.Errorf("Expected name %q, got %q", "beacon", got.Name)
+	}
+
+	// 同じIDは作成できない
+	duplicate, _ := model.NewProject("other", "")
+	duplicate.ID = model.NewHexID(0x2a)
+	if err := store.CreateProject(context.Background(), duplicate); !errors.Is(err, model.ErrProjectIDTaken) {
+		t.Errorf("Expected ErrProjectIDTaken, got %v", err)
+	}
+
+	// 以降の採番は指定したIDの後に続く
+	next, _ := model.NewProject("next", "")
+	if err := store.CreateProject(context.Background(), next); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	if next.ID.ToInt64() <= 0x2a {
+		t.Errorf("Expected auto-assigned ID after 0x2a, got %s", next.ID)
+	}
+}
+
+// TestUpsertDailyRecordConcurrentStores は同じデータベースを開いた別々のストア（別のプロセスに相当）から
+// 同時にupsertしても1日1件のレコードに加算されることをテストします。
+func TestUpsertDailyRecordConcurrentStores(t *testing.T) {
+	dataDir := t.TempDir()
+	stores := make([]*SQLiteStore, 4)
+	for i := range stores {
+		store, err := NewSQLiteStore(dataDir, db.Migrate, SQLiteOptions{})
+		if err != nil {
+			t.Fatalf("Failed to create store: %v", err)
+		}
+		defer store.Close()
+		stores[i] = store
+	}
+
+	project, _ := model.NewProject("beacon", "")
+	if err := stores[0].CreateProject(context.Background(), project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	const n = 20
+	day := time.Date(2025, 6, 2, 12, 0, 0, 0, time.Local)
+	var wg sync.WaitGroup
+	var createdCount atomic.Int32
+	errs := make(chan error, n)
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			record, _ := model.NewRecord(day.Add(time.Duration(i)*time.Minute), project.ID, 1, nil)
+			record.Source = model.RecordSourceTrack
+			created, err := stores[i%len(stores)].UpsertDailyRecord(context.Background(), record, time.Local)
+			if err != nil {
+				errs <- err
+				return
+			}
+			if created {
+				createdCount.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("Failed to upsert record: %v", err)
+	}
+
+	if got := createdCount.Load(); got != 1 {
+		t.Errorf("Expected exactly 1 upsert to create the record, got %d", got)
+	}
+	records, err := stores[0].ListRecords(context.Background(), &ListRecordsParams{
+		ProjectID:  project.ID,
+		From:       day,
+		To:         day,
+		Pagination: model.NewPaginationWithValues(100, nil),
+	})
+	if err != nil {
+		t.Fatalf("Failed to list records: %v", err)
+	}
+	if len(records) != 1 || records[0].Value != n {
+		t.Errorf("Expected 1 record with daily total %d, got %+v", n, records)
+	}
+}
+
+// TestUpsertDailyRecordConcurrent は同時のupsertでも1日1件のレコードに加算されることをテストします。
+func TestUpsertDailyRecordConcurrent(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	project, _ := model.NewProject("beacon", "")
+	if err := store.CreateProject(context.Background(), project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	const n = 20
+	day := time.Date(2025, 6, 2, 12, 0, 0, 0, time.Local)
+	var wg sync.WaitGroup
+	var createdCount atomic.Int32
+	errs := make(chan error, n)
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			record, _ := model.NewRecord(day.Add(time.Duration(i)*time.Minute), project.ID, 1, nil)
+			record.Source = model.RecordSourceTrack
+			created, err := store.UpsertDailyRecord(context.Background(), record, time.Local)
+			if err != nil {
+				errs <- err
+				return
+			}
+			if created {
+				createdCount.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("Failed to upsert record: %v", err)
+	}
+
+	if got := createdCount.Load(); got != 1 {
+		t.Errorf("Expected exactly 1 upsert to create the record, got %d", got)
+	}
+	records, err := store.ListRecords(context.Background(), &ListRecordsParams{
+		ProjectID:  project.ID,
+		From:       day,
+		To:         day,
+		Pagination: model.NewPaginationWithValues(100, nil),
+	})
+	if err != nil {
+		t.Fatalf("Failed to list records: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 record for the day, got %d", len(records))
+	}
+	if records[0].Value != n {
+		t.Errorf("Expected daily total %d, got %d", n, records[0].Value)
+	}
+
+	// 別の日や別の作成元は別のレコード
+	nextDay, _ := model.NewRecord(day.AddDate(0, 0, 1), project.ID, 1, nil)
+	nextDay.Source = model.RecordSourceTrack
+	if created, err := store.UpsertDailyRecord(context.Background(), nextDay, time.Local); err != nil || !created {
+		t.Errorf("Expected a new record for the next day, got created=%v err=%v", created, err)
+	}
+	otherSource, _ := model.NewRecord(day, project.ID, 1, nil)
+	if created, err := store.UpsertDailyRecord(context.Background(), otherSource, time.Local); err != nil || !created {
+		t.Errorf("Expected a new record for another source, got created=%v err=%v", created, err)
+	}
+}
+
+func TestCreateRecordUniquePerDay(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	project, _ := model.NewProject("habit", "")
+	if err := store.CreateProject(ctx, project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	day := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	first, _ := model.NewRecord(day, project.ID, 1, []string{"run", "morning"})
+	saved, created, err := store.CreateRecordUniquePerDay(ctx, first, time.UTC)
+	if err != nil || !created {
+		t.Fatalf("Expected the first record to be created, got created=%v err=%v", created, err)
+	}
+
+	// 同じ日・同じタグの集合（順序違い）は作成せず既存のレコードを返す
+	again, _ := model.NewRecord(day.Add(10*time.Hour), project.ID, 5, []string{"morning", "run"})
+	existing, created, err := store.CreateRecordUniquePerDay(ctx, again, time.UTC)
+	if err != nil || created {
+		t.Fatalf("Expected the existing record, got created=%v err=%v", created, err)
+	}
+	if existing.ID != saved.ID || existing.Value != 1 {
+		t.Errorf("Expected existing record %s with value 1, got %s with value %d", saved.ID, existing.ID, existing.Value)
+	}
+
+	// タグの集合が違う場合や別の日は新しく作成する
+	otherTags, _ := model.NewRecord(day, project.ID, 1, []string{"run"})
+	if _, created, err := store.CreateRecordUniquePerDay(ctx, otherTags, time.UTC); err != nil || !created {
+		t.Errorf("Expected a new record for other tags, got created=%v err=%v", created, err)
+	}
+	nextDay, _ := model.NewRecord(day.AddDate(0, 0, 1), project.ID, 1, []string{"run", "morning"})
+	if _, created, err := store.CreateRecordUniquePerDay(ctx, nextDay, time.UTC); err != nil || !created {
+		t.Errorf("Expected a new record for the next day, got created=%v err=%v", created, err)
+	}
+
+	// 日の区切りはlocのタイムゾーン（UTCの6/2 20:00は東京では6/3）
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	late, _ := model.NewRecord(time.Date(2025, 6, 2, 20, 0, 0, 0, time.UTC), project.ID, 1, []string{"run", "morning"})
+	existing, created, err = store.CreateRecordUniquePerDay(ctx, late, tokyo)
+	if err != nil || created {
+		t.Fatalf("Expected the next day's record in Asia/Tokyo, got created=%v err=%v", created, err)
+	}
+	if existing.ID != nextDay.ID {
+		t.Errorf("Expected record %s, got %s", nextDay.ID, existing.ID)
+	}
+}
+
+// TestListProjects はプロジェクト一覧取得機能をテストします。
+func TestListProjects(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
 
 	// 複数のプロジェクトを作成
 	projects := []struct {
@@ -1054,7 +1902,7 @@ func TestProjectDeletionWithOrphanedRecords(t *testing.T) {
 	}
 
 	// プロジェクトエンティティを削除
-	err = store.DeleteProject(context.Background(), project.ID)
+	_, err = store.DeleteProject(context.Background(), project.ID)
 	if err != nil {
 		t.Fatalf("Failed to delete project entity: %v", err)
 	}
@@ -1084,43 +1932,229 @@ func TestProjectDeletionWithOrphanedRecords(t *testing.T) {
 	}
 }
 
-// TestUpdateRecordWithInvalidProject は存在しないプロジェクトへのレコード更新をテストします。
-func TestUpdateRecordWithInvalidProject(t *testing.T) {
+// TestRecordExists はレコードの存在確認をテストします。
+func TestRecordExists(t *testing.T) {
 	store, cleanup := setupTestStore(t)
 	defer cleanup()
 
-	// 2つのプロジェクトを作成
-	project1, err := model.NewProject("project1", "Project 1")
+	project, err := model.NewProject("exists-project", "")
 	if err != nil {
-		t.Fatalf("Failed to create project1 model: %v", err)
+		t.Fatalf("Failed to create project model: %v", err)
 	}
-	err = store.CreateProject(context.Background(), project1)
-	if err != nil {
-		t.Fatalf("Failed to create project1: %v", err)
+	if err := store.CreateProject(context.Background(), project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	record, _ := model.NewRecord(time.Date(2025, 5, 21, 10, 0, 0, 0, time.UTC), project.ID, 1, nil)
+	if err := store.CreateRecord(context.Background(), record); err != nil {
+		t.Fatalf("Failed to create record: %v", err)
 	}
 
-	project2, err := model.NewProject("project2", "Project 2")
+	exists, err := store.RecordExists(context.Background(), record.ID)
 	if err != nil {
-		t.Fatalf("Failed to create project2 model: %v", err)
+		t.Fatalf("Failed to check record existence: %v", err)
 	}
-	err = store.CreateProject(context.Background(), project2)
-	if err != nil {
-		t.Fatalf("Failed to create project2: %v", err)
+	if !exists {
+		t.Errorf("Expected record to exist")
 	}
 
-	// project1にレコードを作成
-	timestamp := time.Date(2025, 5, 21, 14, 30, 0, 0, time.Local)
-	record, err := model.NewRecord(timestamp, project1.ID, 1, []string{"test"})
+	exists, err = store.RecordExists(context.Background(), model.NewHexID(99999))
 	if err != nil {
-		t.Fatalf("Failed to create record model: %v", err)
+		t.Fatalf("Failed to check record existence: %v", err)
 	}
-	err = store.CreateRecord(context.Background(), record)
-	if err != nil {
-		t.Fatalf("Failed to create record: %v", err)
+	if exists {
+		t.Errorf("Expected record not to exist")
 	}
+}
 
-	// project2を削除
-	err = store.DeleteProject(context.Background(), project2.ID)
+// TestIncrementRecordValue はレコード値のアトミックな加算をテストします。
+func TestIncrementRecordValue(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	project, err := model.NewProject("increment-project", "")
+	if err != nil {
+		t.Fatalf("Failed to create project model: %v", err)
+	}
+	if err := store.CreateProject(context.Background(), project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	record, _ := model.NewRecord(time.Date(2025, 5, 21, 10, 0, 0, 0, time.UTC), project.ID, 2, []string{"counter"})
+	if err := store.CreateRecord(context.Background(), record); err != nil {
+		t.Fatalf("Failed to create record: %v", err)
+	}
+
+	value, err := store.IncrementRecordValue(context.Background(), record.ID, 3, nil)
+	if err != nil {
+		t.Fatalf("Failed to increment record value: %v", err)
+	}
+	if value != 5 {
+		t.Errorf("Expected value 5, got %d", value)
+	}
+	stored, err := store.GetRecord(context.Background(), record.ID)
+	if err != nil {
+		t.Fatalf("Failed to get record: %v", err)
+	}
+	if stored.Value != 5 {
+		t.Errorf("Expected stored value 5, got %d", stored.Value)
+	}
+
+	// 1未満になる加算はバリデーションエラー
+	_, err = store.IncrementRecordValue(context.Background(), record.ID, -5, nil)
+	var validationErr *model.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Errorf("Expected validation error, got %v", err)
+	}
+
+	// 存在しないレコード
+	_, err = store.IncrementRecordValue(context.Background(), model.NewHexID(99999), 1, nil)
+	if !errors.Is(err, model.ErrRecordNotFound) {
+		t.Errorf("Expected ErrRecordNotFound, got %v", err)
+	}
+}
+
+// TestIncrementRecordValueWithBounds はプロジェクトの下限・上限が加算と同じUPDATE文で検証されることをテストします。
+func TestIncrementRecordValueWithBounds(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	project, err := model.NewProject("bounded-increment-project", "")
+	if err != nil {
+		t.Fatalf("Failed to create project model: %v", err)
+	}
+	minValue, maxValue := 2.0, 5.0
+	project.MinValue = &minValue
+	project.MaxValue = &maxValue
+	if err := store.CreateProject(context.Background(), project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	record, _ := model.NewRecord(time.Date(2025, 5, 21, 10, 0, 0, 0, time.UTC), project.ID, 3, []string{"counter"})
+	if err := store.CreateRecord(context.Background(), record); err != nil {
+		t.Fatalf("Failed to create record: %v", err)
+	}
+
+	// 範囲内の加算
+	value, err := store.IncrementRecordValue(context.Background(), record.ID, 2, project)
+	if err != nil {
+		t.Fatalf("Failed to increment record value: %v", err)
+	}
+	if value != 5 {
+		t.Errorf("Expected value 5, got %d", value)
+	}
+
+	// 上限・下限を外れる加算はバリデーションエラーで、値は変わらない
+	for _, delta := range []int{1, -4} {
+		_, err := store.IncrementRecordValue(context.Background(), record.ID, delta, project)
+		var validationErr *model.ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Errorf("Expected validation error for delta %d, got %v", delta, err)
+		}
+	}
+	stored, err := store.GetRecord(context.Background(), record.ID)
+	if err != nil {
+		t.Fatalf("Failed to get record: %v", err)
+	}
+	if stored.Value != 5 {
+		t.Errorf("Expected stored value 5, got %d", stored.Value)
+	}
+}
+
+// TestUpdateRecordWithIncrement は他の項目の更新と値の加算が1つの書き込みで行われることをテストします。
+func TestUpdateRecordWithIncrement(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	project, err := model.NewProject("update-increment-project", "")
+	if err != nil {
+		t.Fatalf("Failed to create project model: %v", err)
+	}
+	maxValue := 10.0
+	project.MaxValue = &maxValue
+	if err := store.CreateProject(context.Background(), project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	record, _ := model.NewRecord(time.Date(2025, 5, 21, 10, 0, 0, 0, time.UTC), project.ID, 2, []string{"counter"})
+	if err := store.CreateRecord(context.Background(), record); err != nil {
+		t.Fatalf("Failed to create record: %v", err)
+	}
+
+	// 読み込んだ後に別の加算が行われても、その加算は失われない
+	updated, err := store.GetRecord(context.Background(), record.ID)
+	if err != nil {
+		t.Fatalf("Failed to get record: %v", err)
+	}
+	if _, err := store.IncrementRecordValue(context.Background(), record.ID, 3, project); err != nil {
+		t.Fatalf("Failed to increment record value: %v", err)
+	}
+	updated.Timestamp = time.Date(2025, 5, 22, 10, 0, 0, 0, time.UTC)
+	updated.Tags = []string{"moved"}
+	if err := store.UpdateRecordWithIncrement(context.Background(), updated, 4, project); err != nil {
+		t.Fatalf("Failed to update record with increment: %v", err)
+	}
+	if updated.Value != 9 {
+		t.Errorf("Expected value 9, got %d", updated.Value)
+	}
+	stored, err := store.GetRecord(context.Background(), record.ID)
+	if err != nil {
+		t.Fatalf("Failed to get record: %v", err)
+	}
+	if stored.Value != 9 || !stored.Timestamp.Equal(updated.Timestamp) || len(stored.Tags) != 1 || stored.Tags[0] != "moved" {
+		t.Errorf("Unexpected stored record: value=%d timestamp=%v tags=%v", stored.Value, stored.Timestamp, stored.Tags)
+	}
+
+	// 上限を超える場合は他の項目も更新されない
+	updated.Timestamp = time.Date(2025, 5, 23, 10, 0, 0, 0, time.UTC)
+	updated.Tags = []string{"overflow"}
+	err = store.UpdateRecordWithIncrement(context.Background(), updated, 2, project)
+	var validationErr *model.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Errorf("Expected validation error, got %v", err)
+	}
+	stored, err = store.GetRecord(context.Background(), record.ID)
+	if err != nil {
+		t.Fatalf("Failed to get record: %v", err)
+	}
+	if stored.Value != 9 || stored.Tags[0] != "moved" {
+		t.Errorf("Expected record to be unchanged, got value=%d tags=%v", stored.Value, stored.Tags)
+	}
+}
+
+// TestUpdateRecordWithInvalidProject は存在しないプロジェクトへのレコード更新をテストします。
+func TestUpdateRecordWithInvalidProject(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	// 2つのプロジェクトを作成
+	project1, err := model.NewProject("project1", "Project 1")
+	if err != nil {
+		t.Fatalf("Failed to create project1 model: %v", err)
+	}
+	err = store.CreateProject(context.Background(), project1)
+	if err != nil {
+		t.Fatalf("Failed to create project1: %v", err)
+	}
+
+	project2, err := model.NewProject("project2", "Project 2")
+	if err != nil {
+		t.Fatalf("Failed to create project2 model: %v", err)
+	}
+	err = store.CreateProject(context.Background(), project2)
+	if err != nil {
+		t.Fatalf("Failed to create project2: %v", err)
+	}
+
+	// project1にレコードを作成
+	timestamp := time.Date(2025, 5, 21, 14, 30, 0, 0, time.Local)
+	record, err := model.NewRecord(timestamp, project1.ID, 1, []string{"test"})
+	if err != nil {
+		t.Fatalf("Failed to create record model: %v", err)
+	}
+	err = store.CreateRecord(context.Background(), record)
+	if err != nil {
+		t.Fatalf("Failed to create record: %v", err)
+	}
+
+	// project2を削除
+	_, err = store.DeleteProject(context.Background(), project2.ID)
 	if err != nil {
 		t.Fatalf("Failed to delete project2: %v", err)
 	}
@@ -1168,7 +2202,7 @@ func TestGetProjectTags(t *testing.T) {
 	}
 
 	// プロジェクトのタグ一覧を取得
-	tags, err := store.GetProjectTags(context.Background(), project.ID)
+	tags, err := store.GetProjectTags(context.Background(), &GetProjectTagsParams{ProjectID: project.ID})
 	if err != nil {
 		t.Fatalf("Failed to get project tags: %v", err)
 	}
@@ -1194,12 +2228,9 @@ func TestGetProjectTagsNonExistentProject(t *testing.T) {
 	defer cleanup()
 
 	// 存在しないプロジェクトのタグを取得
-	tags, err := store.GetProjectTags(context.Background(), model.NewHexID(99999))
-	if err != nil {
-		t.Errorf("Expected no error when getting tags for non-existent project, got: %v", err)
-	}
-	if len(tags) != 0 {
-		t.Errorf("Expected 0 tags for non-existent project, got %d", len(tags))
+	_, err := store.GetProjectTags(context.Background(), &GetProjectTagsParams{ProjectID: model.NewHexID(99999)})
+	if !errors.Is(err, model.ErrProjectNotFound) {
+		t.Errorf("Expected ErrProjectNotFound for non-existent project, got: %v", err)
 	}
 }
 
@@ -1227,7 +2258,7 @@ func TestGetProjectTagsEmptyProject(t *testing.T) {
 	}
 
 	// プロジェクトのタグ一覧を取得（空配列が返されるはず）
-	tags, err := store.GetProjectTags(context.Background(), project.ID)
+	tags, err := store.GetProjectTags(context.Background(), &GetProjectTagsParams{ProjectID: project.ID})
 	if err != nil {
 		t.Fatalf("Failed to get project tags: %v", err)
 	}
@@ -1267,7 +2298,7 @@ func TestGetProjectTagsWithMultipleRecords(t *testing.T) {
 	}
 
 	// プロジェクトのタグ一覧を取得
-	tags, err := store.GetProjectTags(context.Background(), project.ID)
+	tags, err := store.GetProjectTags(context.Background(), &GetProjectTagsParams{ProjectID: project.ID})
 	if err != nil {
 		t.Fatalf("Failed to get project tags: %v", err)
 	}
@@ -1296,6 +2327,79 @@ func TestGetProjectTagsWithMultipleRecords(t *testing.T) {
 	}
 }
 
+// TestGetProjectTagsPagination は多数のタグをカーソルでページングできることをテストします。
+func TestGetProjectTagsPagination(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	project, err := model.NewProject("many-tags", "")
+	if err != nil {
+		t.Fatalf("Failed to create project model: %v", err)
+	}
+	if err := store.CreateProject(context.Background(), project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	// 1レコードあたり5タグ、計50タグ（一部重複させる）
+	baseTime := time.Date(2025, 5, 21, 10, 0, 0, 0, time.UTC)
+	var expected []string
+	for i := 0; i < 10; i++ {
+		var tags []string
+		for j := 0; j < 5; j++ {
+			tag := fmt.Sprintf("tag-%02d", i*5+j)
+			tags = append(tags, tag)
+			expected = append(expected, tag)
+		}
+		if i > 0 {
+			tags = append(tags, "tag-00")
+		}
+		record, _ := model.NewRecord(baseTime.Add(time.Duration(i)*time.Hour), project.ID, 1, tags)
+		if err := store.CreateRecord(context.Background(), record); err != nil {
+			t.Fatalf("Failed to create record: %v", err)
+		}
+	}
+
+	// ページネーションなしでは全件が名前順
+	all, err := store.GetProjectTags(context.Background(), &GetProjectTagsParams{ProjectID: project.ID})
+	if err != nil {
+		t.Fatalf("Failed to get project tags: %v", err)
+	}
+	if !slices.Equal(all, expected) {
+		t.Fatalf("Expected all tags in order, got %v", all)
+	}
+
+	// 7件ずつページング
+	var paged []string
+	var cursor *string
+	pages := 0
+	for {
+		tags, err := store.GetProjectTags(context.Background(), &GetProjectTagsParams{
+			ProjectID:  project.ID,
+			Pagination: model.NewPaginationWithValues(7, nil),
+			CursorTag:  cursor,
+		})
+		if err != nil {
+			t.Fatalf("Failed to get project tags page: %v", err)
+		}
+		if len(tags) == 0 {
+			break
+		}
+		if len(tags) > 7 {
+			t.Fatalf("Expected at most 7 tags per page, got %d", len(tags))
+		}
+		paged = append(paged, tags...)
+		cursor = &tags[len(tags)-1]
+		pages++
+	}
+
+	if pages != 8 {
+		t.Errorf("Expected 8 pages, got %d", pages)
+	}
+	if !slices.Equal(paged, expected) {
+		t.Errorf("Paged tags do not match: %v", paged)
+	}
+}
+
 // TestListRecordsWithCursorPagination tests cursor-based pagination for records
 func TestListRecordsWithCursorPagination(t *testing.T) {
 	store, cleanup := setupTestStore(t)
@@ -1521,7 +2625,7 @@ func TestListProjectsWithCursorPagination(t *testing.T) {
 			expectedIndex := i + 3
 			if !projects[i].ID.Equals(allProjects[expectedIndex].ID) {
 				t.Errorf("Project at index %d on second page has incorrect ID. Expected %s (from allProjects[%d]), got %s",
-					i, allProjects[expectedIndex].ID, expectedIndex, projects[i])
+					i, allProjects[expectedIndex].ID, expectedIndex, projects[i].ID)
 			}
 		}
 
@@ -1532,6 +2636,111 @@ func TestListProjectsWithCursorPagination(t *testing.T) {
 	})
 }
 
+// TestListProjectsSortByName は名前の昇順でページをまたいで取得できることをテストします。
+func TestListProjectsSortByName(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	// 名前順とは異なる順序で作成
+	for _, name := range []string{"delta", "alpha", "echo", "charlie", "bravo"} {
+		project, err := model.NewProject(name, "")
+		if err != nil {
+			t.Fatalf("Failed to create project model: %v", err)
+		}
+		if err := store.CreateProject(context.Background(), project); err != nil {
+			t.Fatalf("Failed to create project: %v", err)
+		}
+	}
+
+	// limit=2で最後のページまで取得
+	var names []string
+	var cursorName *string
+	for page := 0; ; page++ {
+		if page > 5 {
+			t.Fatal("Pagination did not terminate")
+		}
+		projects, err := store.ListProjects(context.Background(), &ListProjectsParams{
+			Pagination: model.NewPaginationWithValues(2, nil),
+			Sort:       model.ProjectSortName,
+			CursorName: cursorName,
+		})
+		if err != nil {
+			t.Fatalf("Failed to list projects: %v", err)
+		}
+		for _, p := range projects {
+			names = append(names, p.Name)
+		}
+		if len(projects) < 2 {
+			break
+		}
+		cursorName = &projects[len(projects)-1].Name
+	}
+
+	expected := []string{"alpha", "bravo", "charlie", "delta", "echo"}
+	if !slices.Equal(names, expected) {
+		t.Errorf("Expected %v, got %v", expected, names)
+	}
+}
+
+// TestListProjectsSortByRecordCount はレコード数の降順（同数は名前順）で取得できることをテストします。
+func TestListProjectsSortByRecordCount(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	// プロジェクトごとのレコード数
+	counts := map[string]int{"alpha": 1, "bravo": 3, "charlie": 0, "delta": 1}
+	for _, name := range []string{"alpha", "bravo", "charlie", "delta"} {
+		project, err := model.NewProject(name, "")
+		if err != nil {
+			t.Fatalf("Failed to create project model: %v", err)
+		}
+		if err := store.CreateProject(context.Background(), project); err != nil {
+			t.Fatalf("Failed to create project: %v", err)
+		}
+		for i := range counts[name] {
+			record, err := model.NewRecord(time.Date(2025, 1, 1+i, 0, 0, 0, 0, time.UTC), project.ID, 1, nil)
+			if err != nil {
+				t.Fatalf("Failed to create record: %v", err)
+			}
+			if err := store.CreateRecord(context.Background(), record); err != nil {
+				t.Fatalf("Failed to store record: %v", err)
+			}
+		}
+	}
+
+	// 1ページ目
+	first, err := store.ListProjects(context.Background(), &ListProjectsParams{
+		Pagination: model.NewPaginationWithValues(2, nil),
+		Sort:       model.ProjectSortRecords,
+	})
+	if err != nil {
+		t.Fatalf("Failed to list projects: %v", err)
+	}
+	if len(first) != 2 || first[0].Name != "bravo" || first[1].Name != "alpha" {
+		t.Fatalf("Unexpected first page: %v", first)
+	}
+	if first[0].RecordCount == nil || *first[0].RecordCount != 3 {
+		t.Errorf("Expected record count 3 for bravo, got %v", first[0].RecordCount)
+	}
+
+	// 2ページ目（同数のdeltaはalphaの後に続く）
+	second, err := store.ListProjects(context.Background(), &ListProjectsParams{
+		Pagination:        model.NewPaginationWithValues(2, nil),
+		Sort:              model.ProjectSortRecords,
+		CursorRecordCount: first[1].RecordCount,
+		CursorName:        &first[1].Name,
+	})
+	if err != nil {
+		t.Fatalf("Failed to list projects with cursor: %v", err)
+	}
+	if len(second) != 2 || second[0].Name != "delta" || second[1].Name != "charlie" {
+		t.Fatalf("Unexpected second page: %v", second)
+	}
+	if second[1].RecordCount == nil || *second[1].RecordCount != 0 {
+		t.Errorf("Expected record count 0 for charlie, got %v", second[1].RecordCount)
+	}
+}
+
 // TestListAllRecordsWithPagination tests that ListAllRecords correctly handles
 // pagination across multiple pages using the new cursor format
 func TestListAllRecordsWithPagination(t *testing.T) {
@@ -1608,6 +2817,112 @@ func TestListAllRecordsWithPagination(t *testing.T) {
 	t.Logf("Successfully retrieved %d unique records across multiple pages", len(retrievedRecords))
 }
 
+// TestListAllRecordsAscending tests that ListAllRecords iterates oldest first
+// across multiple pages when Order is asc
+func TestListAllRecordsRangeBoundaries(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	project, _ := model.NewProject("boundaries", "")
+	if err := store.CreateProject(ctx, project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	// 期間の両端ちょうどのレコードは含み、その直前・直後のレコードは含まない
+	timestamps := map[time.Time]bool{
+		time.Date(2025, 5, 31, 23, 59, 59, 0, time.UTC): false,
+		time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC):     true,
+		time.Date(2025, 6, 30, 23, 59, 59, 0, time.UTC): true,
+		time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC):     false,
+	}
+	expected := make(map[time.Time]bool)
+	for timestamp, inRange := range timestamps {
+		record, _ := model.NewRecord(timestamp, project.ID, 1, []string{"edge"})
+		if err := store.CreateRecord(ctx, record); err != nil {
+			t.Fatalf("Failed to create record: %v", err)
+		}
+		if inRange {
+			expected[timestamp] = true
+		}
+	}
+
+	// From/Toは時刻を含んでいても丸一日に揃えられる
+	for _, tags := range [][]string{nil, {"edge"}} {
+		params := &ListAllRecordsParams{
+			ProjectID: project.ID,
+			From:      time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+			To:        time.Date(2025, 6, 30, 8, 0, 0, 0, time.UTC),
+			Tags:      tags,
+		}
+		got := make(map[time.Time]bool)
+		for record, err := range store.ListAllRecords(ctx, params) {
+			if err != nil {
+				t.Fatalf("Failed to list records: %v", err)
+			}
+			got[record.Timestamp.UTC()] = true
+		}
+		if !maps.Equal(got, expected) {
+			t.Errorf("tags=%v: expected records at %v, got %v", tags, expected, got)
+		}
+	}
+}
+
+func TestListAllRecordsAscending(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	project, err := model.NewProject("ascending-project", "")
+	if err != nil {
+		t.Fatalf("Failed to create project model: %v", err)
+	}
+	if err := store.CreateProject(context.Background(), project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	// pageSize=1000を超える2500件を作成（タグ付きのクエリも確認するため全件にタグを付ける）
+	baseTime := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	expectedCount := 2500
+	for i := 0; i < expectedCount; i++ {
+		record, err := model.NewRecord(baseTime.Add(time.Duration(i)*time.Minute), project.ID, 1, []string{"export"})
+		if err != nil {
+			t.Fatalf("Failed to create record: %v", err)
+		}
+		if err := store.CreateRecord(context.Background(), record); err != nil {
+			t.Fatalf("Failed to store record: %v", err)
+		}
+	}
+
+	for _, tags := range [][]string{nil, {"export"}} {
+		params := &ListAllRecordsParams{
+			ProjectID: project.ID,
+			From:      baseTime.AddDate(0, 0, -1),
+			To:        baseTime.AddDate(0, 0, 2),
+			Tags:      tags,
+			Order:     model.RecordOrderAsc,
+		}
+
+		var previous time.Time
+		count := 0
+		for record, err := range store.ListAllRecords(context.Background(), params) {
+			if err != nil {
+				t.Fatalf("Error during iteration: %v", err)
+			}
+			if count > 0 && !record.Timestamp.After(previous) {
+				t.Fatalf("Expected strictly increasing timestamps (tags=%v), got %s after %s", tags, record.Timestamp, previous)
+			}
+			previous = record.Timestamp
+			count++
+			if count > expectedCount {
+				t.Fatalf("Retrieved too many records (tags=%v). Possible infinite loop due to broken cursor!", tags)
+			}
+		}
+		if count != expectedCount {
+			t.Errorf("Expected %d records (tags=%v), got %d", expectedCount, tags, count)
+		}
+	}
+}
+
 // TestRecordTagsOrder tests that tags maintain their insertion order
 func TestRecordTagsOrder(t *testing.T) {
 	store, cleanup := setupTestStore(t)
@@ -1724,3 +3039,1446 @@ func TestRecordTagsOrder(t *testing.T) {
 		}
 	}
 }
+
+func TestGetDailyTotals(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	project, err := model.NewProject("daily-project", "")
+	if err != nil {
+		t.Fatalf("Failed to create project model: %v", err)
+	}
+	if err := store.CreateProject(context.Background(), project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	// 同じ日に複数レコード、別の日に1レコード
+	entries := []struct {
+		timestamp time.Time
+		value     int
+		tags      []string
+	}{
+		{time.Date(2025, 6, 1, 9, 0, 0, 0, time.Local), 2, []string{"work"}},
+		{time.Date(2025, 6, 1, 21, 0, 0, 0, time.Local), 3, nil},
+		{time.Date(2025, 6, 3, 12, 0, 0, 0, time.Local), 4, []string{"work"}},
+	}
+	for _, e := range entries {
+		record, err := model.NewRecord(e.timestamp, project.ID, e.value, e.tags)
+		if err != nil {
+			t.Fatalf("Failed to create record: %v", err)
+		}
+		if err := store.CreateRecord(context.Background(), record); err != nil {
+			t.Fatalf("Failed to store record: %v", err)
+		}
+	}
+
+	params := &GetDailyTotalsParams{
+		ProjectID: project.ID,
+		From:      time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local),
+		To:        time.Date(2025, 6, 30, 23, 59, 59, 0, time.Local),
+	}
+	totals, err := store.GetDailyTotals(context.Background(), params)
+	if err != nil {
+		t.Fatalf("Failed to get daily totals: %v", err)
+	}
+
+	if len(totals) != 2 {
+		t.Fatalf("Expected 2 daily totals, got %d", len(totals))
+	}
+	if !totals[0].Date.Equal(time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local)) || totals[0].Value != 5 {
+		t.Errorf("Unexpected first total: %v = %v", totals[0].Date, totals[0].Value)
+	}
+	if !totals[1].Date.Equal(time.Date(2025, 6, 3, 0, 0, 0, 0, time.Local)) || totals[1].Value != 4 {
+		t.Errorf("Unexpected second total: %v = %v", totals[1].Date, totals[1].Value)
+	}
+
+	// タグフィルタ付き
+	params.Tags = []string{"work"}
+	totals, err = store.GetDailyTotals(context.Background(), params)
+	if err != nil {
+		t.Fatalf("Failed to get daily totals with tags: %v", err)
+	}
+	if len(totals) != 2 || totals[0].Value != 2 || totals[1].Value != 4 {
+		t.Errorf("Unexpected totals with tag filter: %+v", totals)
+	}
+}
+
+func TestGetDailyTotalsAggregation(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	project, err := model.NewProject("agg-project", "")
+	if err != nil {
+		t.Fatalf("Failed to create project model: %v", err)
+	}
+	if err := store.CreateProject(context.Background(), project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	// 同じ日に3レコード
+	for i, value := range []int{2, 7, 4} {
+		record, err := model.NewRecord(time.Date(2025, 6, 1, 9+i, 0, 0, 0, time.Local), project.ID, value, nil)
+		if err != nil {
+			t.Fatalf("Failed to create record: %v", err)
+		}
+		if err := store.CreateRecord(context.Background(), record); err != nil {
+			t.Fatalf("Failed to store record: %v", err)
+		}
+	}
+
+	tests := []struct {
+		aggregation model.Aggregation
+		expected    float64
+	}{
+		{"", 13},
+		{model.AggregationSum, 13},
+		{model.AggregationMax, 7},
+		{model.AggregationCount, 3},
+		{model.AggregationAvg, 4},
+	}
+	for _, tt := range tests {
+		totals, err := store.GetDailyTotals(context.Background(), &GetDailyTotalsParams{
+			ProjectID:   project.ID,
+			From:        time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local),
+			To:          time.Date(2025, 6, 30, 23, 59, 59, 0, time.Local),
+			Aggregation: tt.aggregation,
+		})
+		if err != nil {
+			t.Fatalf("Failed to get daily totals (%q): %v", tt.aggregation, err)
+		}
+		if len(totals) != 1 || totals[0].Value != tt.expected {
+			t.Errorf("agg=%q: expected a single total of %v, got %+v", tt.aggregation, tt.expected, totals)
+		}
+	}
+}
+
+func TestGetTagBreakdown(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	project, err := model.NewProject("breakdown-project", "")
+	if err != nil {
+		t.Fatalf("Failed to create project model: %v", err)
+	}
+	if err := store.CreateProject(context.Background(), project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	// 2つのタグを持つレコードは両方のタグに計上される
+	entries := []struct {
+		timestamp time.Time
+		value     int
+		tags      []string
+	}{
+		{time.Date(2025, 6, 1, 9, 0, 0, 0, time.Local), 2, []string{"work", "meeting"}},
+		{time.Date(2025, 6, 2, 9, 0, 0, 0, time.Local), 5, []string{"work"}},
+		{time.Date(2025, 6, 3, 9, 0, 0, 0, time.Local), 4, []string{"study"}},
+		{time.Date(2025, 6, 3, 21, 0, 0, 0, time.Local), 7, nil},
+		{time.Date(2025, 7, 1, 9, 0, 0, 0, time.Local), 100, []string{"meeting"}}, // 期間外
+	}
+	for _, e := range entries {
+		record, err := model.NewRecord(e.timestamp, project.ID, e.value, e.tags)
+		if err != nil {
+			t.Fatalf("Failed to create record model: %v", err)
+		}
+		if err := store.CreateRecord(context.Background(), record); err != nil {
+			t.Fatalf("Failed to create record: %v", err)
+		}
+	}
+
+	breakdown, err := store.GetTagBreakdown(context.Background(), &GetTagBreakdownParams{
+		ProjectID: project.ID,
+		From:      time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local),
+		To:        time.Date(2025, 6, 30, 0, 0, 0, 0, time.Local),
+	})
+	if err != nil {
+		t.Fatalf("Failed to get tag breakdown: %v", err)
+	}
+
+	expected := []TagTotal{
+		{Tag: "work", TotalValue: 7, RecordCount: 2},
+		{Tag: "study", TotalValue: 4, RecordCount: 1},
+		{Tag: "meeting", TotalValue: 2, RecordCount: 1},
+	}
+	if len(breakdown) != len(expected) {
+		t.Fatalf("Expected %d tags, got %d", len(expected), len(breakdown))
+	}
+	for i, e := range expected {
+		if *breakdown[i] != e {
+			t.Errorf("Index %d: expected %+v, got %+v", i, e, *breakdown[i])
+		}
+	}
+}
+
+func TestGetValueHistogram(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	project, err := model.NewProject("histogram-project", "")
+	if err != nil {
+		t.Fatalf("Failed to create project model: %v", err)
+	}
+	if err := store.CreateProject(ctx, project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	// 値1が3件、値2が2件、値5が1件（期間外・削除済みのレコードは除く）
+	entries := []struct {
+		timestamp time.Time
+		value     int
+		tags      []string
+	}{
+		{time.Date(2025, 6, 1, 9, 0, 0, 0, time.Local), 1, []string{"work"}},
+		{time.Date(2025, 6, 1, 18, 0, 0, 0, time.Local), 1, nil},
+		{time.Date(2025, 6, 2, 9, 0, 0, 0, time.Local), 1, []string{"work", "meeting"}},
+		{time.Date(2025, 6, 3, 9, 0, 0, 0, time.Local), 2, []string{"work"}},
+		{time.Date(2025, 6, 30, 23, 0, 0, 0, time.Local), 2, []string{"study"}},
+		{time.Date(2025, 6, 4, 9, 0, 0, 0, time.Local), 5, []string{"work", "meeting"}},
+		{time.Date(2025, 7, 1, 9, 0, 0, 0, time.Local), 1, []string{"work"}}, // 期間外
+	}
+	for _, e := range entries {
+		record, err := model.NewRecord(e.timestamp, project.ID, e.value, e.tags)
+		if err != nil {
+			t.Fatalf("Failed to create record model: %v", err)
+		}
+		if err := store.CreateRecord(ctx, record); err != nil {
+			t.Fatalf("Failed to create record: %v", err)
+		}
+	}
+	deleted, _ := model.NewRecord(time.Date(2025, 6, 5, 9, 0, 0, 0, time.Local), project.ID, 9, nil)
+	if err := store.CreateRecord(ctx, deleted); err != nil {
+		t.Fatalf("Failed to create record: %v", err)
+	}
+	if err := store.DeleteRecord(ctx, deleted.ID); err != nil {
+		t.Fatalf("Failed to delete record: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		tags     []string
+		expected []ValueCount
+	}{
+		{"all records", nil, []ValueCount{{Value: 1, RecordCount: 3}, {Value: 2, RecordCount: 2}, {Value: 5, RecordCount: 1}}},
+		{"single tag", []string{"work"}, []ValueCount{{Value: 1, RecordCount: 2}, {Value: 2, RecordCount: 1}, {Value: 5, RecordCount: 1}}},
+		{"all of multiple tags", []string{"work", "meeting"}, []ValueCount{{Value: 1, RecordCount: 1}, {Value: 5, RecordCount: 1}}},
+		{"no match", []string{"unknown"}, []ValueCount{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			histogram, err := store.GetValueHistogram(ctx, &GetValueHistogramParams{
+				ProjectID: project.ID,
+				From:      time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local),
+				To:        time.Date(2025, 6, 30, 0, 0, 0, 0, time.Local),
+				Tags:      tt.tags,
+			})
+			if err != nil {
+				t.Fatalf("Failed to get value histogram: %v", err)
+			}
+			if len(histogram) != len(tt.expected) {
+				t.Fatalf("Expected %d buckets, got %d", len(tt.expected), len(histogram))
+			}
+			for i, e := range tt.expected {
+				if *histogram[i] != e {
+					t.Errorf("Index %d: expected %+v, got %+v", i, e, *histogram[i])
+				}
+			}
+		})
+	}
+}
+
+func TestProjectTrackDefaultValue(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	project, err := model.NewProject("weighted-project", "")
+	if err != nil {
+		t.Fatalf("Failed to create project model: %v", err)
+	}
+	if err := store.CreateProject(context.Background(), project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	// 未設定の場合はnil
+	got, err := store.GetProject(context.Background(), project.ID)
+	if err != nil {
+		t.Fatalf("Failed to get project: %v", err)
+	}
+	if got.TrackDefaultValue != nil {
+		t.Errorf("Expected nil track_default_value, got %d", *got.TrackDefaultValue)
+	}
+
+	// 更新で設定した値が保存される
+	value := 3
+	got.TrackDefaultValue = &value
+	if err := store.UpdateProject(context.Background(), got); err != nil {
+		t.Fatalf("Failed to update project: %v", err)
+	}
+	got, err = store.GetProject(context.Background(), project.ID)
+	if err != nil {
+		t.Fatalf("Failed to get project: %v", err)
+	}
+	if got.TrackDefaultValue == nil || *got.TrackDefaultValue != 3 {
+		t.Errorf("Expected track_default_value 3, got %v", got.TrackDefaultValue)
+	}
+
+	// 一覧でも取得できる
+	projects, err := store.ListProjects(context.Background(), &ListProjectsParams{
+		Pagination: model.NewPaginationWithValues(10, nil),
+	})
+	if err != nil {
+		t.Fatalf("Failed to list projects: %v", err)
+	}
+	if len(projects) != 1 || projects[0].TrackValue() != 3 {
+		t.Errorf("Expected listed project to have track value 3")
+	}
+}
+
+func TestProjectColor(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	project, err := model.NewProject("colored-project", "")
+	if err != nil {
+		t.Fatalf("Failed to create project model: %v", err)
+	}
+	color := "#ff8800"
+	project.Color = &color
+	if err := store.CreateProject(context.Background(), project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	got, err := store.GetProject(context.Background(), project.ID)
+	if err != nil {
+		t.Fatalf("Failed to get project: %v", err)
+	}
+	if got.Color == nil || *got.Color != color {
+		t.Errorf("Expected color %s, got %v", color, got.Color)
+	}
+
+	// 解除
+	got.Color = nil
+	if err := store.UpdateProject(context.Background(), got); err != nil {
+		t.Fatalf("Failed to update project: %v", err)
+	}
+	projects, err := store.ListProjects(context.Background(), &ListProjectsParams{
+		Pagination: model.NewPaginationWithValues(10, nil),
+	})
+	if err != nil {
+		t.Fatalf("Failed to list projects: %v", err)
+	}
+	if len(projects) != 1 || projects[0].Color != nil {
+		t.Errorf("Expected listed project without color")
+	}
+
+	// 不正な色は保存できない
+	invalid := "orange"
+	got.Color = &invalid
+	if err := store.UpdateProject(context.Background(), got); err == nil {
+		t.Errorf("Expected validation error for invalid color")
+	}
+}
+
+func TestProjectRetentionDays(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	retentionDays := 90
+	expiring, _ := model.NewProject("expiring", "")
+	expiring.RetentionDays = &retentionDays
+	if err := store.CreateProject(ctx, expiring); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	forever, _ := model.NewProject("forever", "")
+	if err := store.CreateProject(ctx, forever); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	got, err := store.GetProject(ctx, expiring.ID)
+	if err != nil {
+		t.Fatalf("Failed to get project: %v", err)
+	}
+	if got.RetentionDays == nil || *got.RetentionDays != retentionDays {
+		t.Errorf("Expected retention days %d, got %v", retentionDays, got.RetentionDays)
+	}
+
+	// 保持日数が設定されたプロジェクトのみ返す
+	policies, err := store.ListRetentionPolicies(ctx)
+	if err != nil {
+		t.Fatalf("Failed to list retention policies: %v", err)
+	}
+	if len(policies) != 1 || !policies[0].ProjectID.Equals(expiring.ID) || policies[0].RetentionDays != retentionDays {
+		t.Errorf("Unexpected retention policies: %+v", policies)
+	}
+
+	// 解除
+	got.RetentionDays = nil
+	if err := store.UpdateProject(ctx, got); err != nil {
+		t.Fatalf("Failed to update project: %v", err)
+	}
+	if policies, _ := store.ListRetentionPolicies(ctx); len(policies) != 0 {
+		t.Errorf("Expected no retention policies, got %+v", policies)
+	}
+}
+
+func TestProjectValueBounds(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	minValue, maxValue := 0.0, 100.0
+	project, _ := model.NewProject("bounded", "")
+	project.MinValue = &minValue
+	project.MaxValue = &maxValue
+	if err := store.CreateProject(ctx, project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	got, err := store.GetProject(ctx, project.ID)
+	if err != nil {
+		t.Fatalf("Failed to get project: %v", err)
+	}
+	if got.MinValue == nil || *got.MinValue != minValue || got.MaxValue == nil || *got.MaxValue != maxValue {
+		t.Errorf("Expected bounds [%v, %v], got [%v, %v]", minValue, maxValue, got.MinValue, got.MaxValue)
+	}
+
+	// 解除
+	got.MinValue = nil
+	got.MaxValue = nil
+	if err := store.UpdateProject(ctx, got); err != nil {
+		t.Fatalf("Failed to update project: %v", err)
+	}
+	got, _ = store.GetProject(ctx, project.ID)
+	if got.MinValue != nil || got.MaxValue != nil {
+		t.Errorf("Expected no bounds, got [%v, %v]", got.MinValue, got.MaxValue)
+	}
+}
+
+func TestProjectTimezone(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	timezone := "Asia/Tokyo"
+	project, _ := model.NewProject("tokyo", "")
+	project.Timezone = &timezone
+	if err := store.CreateProject(ctx, project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	got, err := store.GetProject(ctx, project.ID)
+	if err != nil {
+		t.Fatalf("Failed to get project: %v", err)
+	}
+	if got.Timezone == nil || *got.Timezone != timezone {
+		t.Errorf("Expected timezone %s, got %v", timezone, got.Timezone)
+	}
+
+	// 解除
+	got.Timezone = nil
+	if err := store.UpdateProject(ctx, got); err != nil {
+		t.Fatalf("Failed to update project: %v", err)
+	}
+	got, _ = store.GetProject(ctx, project.ID)
+	if got.Timezone != nil {
+		t.Errorf("Expected no timezone, got %v", *got.Timezone)
+	}
+}
+
+// TestProjectTimezoneMixedOffsets はプロジェクトのタイムゾーンと異なるオフセットの日時のレコードが、
+// プロジェクトのタイムゾーンの暦日で検索・集約されることをテストします。
+func TestProjectTimezoneMixedOffsets(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatalf("Failed to load location: %v", err)
+	}
+	project, _ := model.NewProject("tokyo", "")
+	if err := store.CreateProject(ctx, project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	// 2026-10-16 05:00/06:00 JST（UTCでは前日）と、同じ日の夜（+05:00）
+	at5 := time.Date(2026, 10, 15, 20, 0, 0, 0, time.UTC)
+	at6 := time.Date(2026, 10, 15, 21, 0, 0, 0, time.UTC)
+	evening := time.Date(2026, 10, 16, 18, 0, 0, 0, time.FixedZone("", 5*60*60))
+
+	// upsertは同じ日のレコードに加算する
+	for i, timestamp := range []time.Time{at5, at6} {
+		record, _ := model.NewRecord(timestamp, project.ID, 1, nil)
+		record.Source = model.RecordSourceTrack
+		created, err := store.UpsertDailyRecord(ctx, record, tokyo)
+		if err != nil {
+			t.Fatalf("Failed to upsert record: %v", err)
+		}
+		if created != (i == 0) {
+			t.Errorf("Expected created=%v for upsert %d, got %v", i == 0, i, created)
+		}
+	}
+
+	// 1日1件の作成は同じ日の既存のレコードを返す
+	for i, timestamp := range []time.Time{at6, evening} {
+		record, _ := model.NewRecord(timestamp, project.ID, 1, []string{"done"})
+		_, created, err := store.CreateRecordUniquePerDay(ctx, record, tokyo)
+		if err != nil {
+			t.Fatalf("Failed to create record: %v", err)
+		}
+		if created != (i == 0) {
+			t.Errorf("Expected created=%v for create %d, got %v", i == 0, i, created)
+		}
+	}
+
+	// 日ごとの一覧はプロジェクトのタイムゾーンの暦日の2件を古い順に返す
+	records, err := store.ListRecordsForDay(ctx, project.ID, time.Date(2026, 10, 16, 0, 0, 0, 0, tokyo), nil)
+	if err != nil {
+		t.Fatalf("Failed to list records for day: %v", err)
+	}
+	if len(records) != 2 || !records[0].Timestamp.Equal(at5) || !records[1].Timestamp.Equal(at6) {
+		t.Errorf("Expected the records at %v and %v, got %+v", at5, at6, records)
+	}
+
+	// 一覧の並び順はオフセットではなく日時の順
+	evening2, _ := model.NewRecord(evening, project.ID, 1, nil)
+	if err := store.CreateRecord(ctx, evening2); err != nil {
+		t.Fatalf("Failed to create record: %v", err)
+	}
+	listed, err := store.ListRecords(ctx, &ListRecordsParams{
+		ProjectID:  project.ID,
+		From:       time.Date(2026, 10, 16, 0, 0, 0, 0, tokyo),
+		To:         time.Date(2026, 10, 16, 0, 0, 0, 0, tokyo),
+		Pagination: model.NewPaginationWithValues(100, nil),
+	})
+	if err != nil {
+		t.Fatalf("Failed to list records: %v", err)
+	}
+	if len(listed) != 3 || !listed[0].Timestamp.Equal(evening) || !listed[2].Timestamp.Equal(at5) {
+		t.Errorf("Expected 3 records newest first, got %+v", listed)
+	}
+}
+
+func TestFloatValues(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	project, err := model.NewProject("distance", "")
+	if err != nil {
+		t.Fatalf("Failed to create project model: %v", err)
+	}
+	project.ValueType = model.ValueTypeFloat
+	if err := store.CreateProject(context.Background(), project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	got, err := store.GetProject(context.Background(), project.ID)
+	if err != nil {
+		t.Fatalf("Failed to get project: %v", err)
+	}
+	if got.ValueType != model.ValueTypeFloat {
+		t.Errorf("Expected value_type float, got %q", got.ValueType)
+	}
+
+	// 同じ日に2レコード、別の日に1レコード
+	var recordIDs []model.HexID
+	for _, tt := range []struct {
+		day   int
+		value float64
+	}{{1, 1.5}, {1, 2.25}, {2, 5.3}} {
+		record, err := model.NewRecord(time.Date(2025, 6, tt.day, 9, 0, 0, 0, time.Local), project.ID, 1, nil)
+		if err != nil {
+			t.Fatalf("Failed to create record: %v", err)
+		}
+		record.SetValueFloat(tt.value)
+		if err := store.CreateRecord(context.Background(), record); err != nil {
+			t.Fatalf("Failed to store record: %v", err)
+		}
+		recordIDs = append(recordIDs, record.ID)
+	}
+
+	record, err := store.GetRecord(context.Background(), recordIDs[2])
+	if err != nil {
+		t.Fatalf("Failed to get record: %v", err)
+	}
+	if record.ValueFloat == nil || *record.ValueFloat != 5.3 || record.Value != 5 {
+		t.Errorf("Expected value_float 5.3 with value 5, got %v / %d", record.ValueFloat, record.Value)
+	}
+
+	totals, err := store.GetDailyTotals(context.Background(), &GetDailyTotalsParams{
+		ProjectID: project.ID,
+		From:      time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local),
+		To:        time.Date(2025, 6, 30, 23, 59, 59, 0, time.Local),
+		ValueType: model.ValueTypeFloat,
+	})
+	if err != nil {
+		t.Fatalf("Failed to get daily totals: %v", err)
+	}
+	if len(totals) != 2 || totals[0].Value != 3.75 || totals[1].Value != 5.3 {
+		t.Errorf("Expected fractional totals [3.75 5.3], got %+v", totals)
+	}
+
+	// 平均は丸めずに返す
+	totals, err = store.GetDailyTotals(context.Background(), &GetDailyTotalsParams{
+		ProjectID:   project.ID,
+		From:        time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local),
+		To:          time.Date(2025, 6, 1, 23, 59, 59, 0, time.Local),
+		Aggregation: model.AggregationAvg,
+		ValueType:   model.ValueTypeFloat,
+	})
+	if err != nil {
+		t.Fatalf("Failed to get daily totals: %v", err)
+	}
+	if len(totals) != 1 || totals[0].Value != 1.875 {
+		t.Errorf("Expected average 1.875, got %+v", totals)
+	}
+}
+
+func TestGetProjectSummary(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	project, err := model.NewProject("summary-project", "")
+	if err != nil {
+		t.Fatalf("Failed to create project model: %v", err)
+	}
+	if err := store.CreateProject(context.Background(), project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	// レコードがない場合
+	summary, err := store.GetProjectSummary(context.Background(), project.ID)
+	if err != nil {
+		t.Fatalf("Failed to get project summary: %v", err)
+	}
+	if summary.RecordCount != 0 || summary.TotalValue != 0 || summary.FirstRecordAt != nil || summary.LastRecordAt != nil {
+		t.Errorf("Expected empty summary, got %+v", summary)
+	}
+
+	first := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	last := time.Date(2025, 6, 3, 18, 30, 0, 0, time.UTC)
+	for i, ts := range []time.Time{last, first, first.Add(time.Hour)} {
+		record, err := model.NewRecord(ts, project.ID, i+1, nil)
+		if err != nil {
+			t.Fatalf("Failed to create record: %v", err)
+		}
+		if err := store.CreateRecord(context.Background(), record); err != nil {
+			t.Fatalf("Failed to store record: %v", err)
+		}
+	}
+
+	summary, err = store.GetProjectSummary(context.Background(), project.ID)
+	if err != nil {
+		t.Fatalf("Failed to get project summary: %v", err)
+	}
+	if summary.RecordCount != 3 {
+		t.Errorf("Expected record_count 3, got %d", summary.RecordCount)
+	}
+	if summary.TotalValue != 6 {
+		t.Errorf("Expected total_value 6, got %d", summary.TotalValue)
+	}
+	if summary.FirstRecordAt == nil || !summary.FirstRecordAt.Equal(first) {
+		t.Errorf("Expected first_record_at %v, got %v", first, summary.FirstRecordAt)
+	}
+	if summary.LastRecordAt == nil || !summary.LastRecordAt.Equal(last) {
+		t.Errorf("Expected last_record_at %v, got %v", last, summary.LastRecordAt)
+	}
+}
+
+func TestGetProjectLastModified(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	project, err := model.NewProject("last-modified-project", "")
+	if err != nil {
+		t.Fatalf("Failed to create project model: %v", err)
+	}
+	if err := store.CreateProject(ctx, project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	if _, err := store.GetProjectLastModified(ctx, model.NewHexID(99999)); !errors.Is(err, model.ErrProjectNotFound) {
+		t.Errorf("Expected ErrProjectNotFound for a missing project, got %v", err)
+	}
+
+	records := make([]*model.Record, 3)
+	for i := range records {
+		records[i], _ = model.NewRecord(time.Date(2025, 6, 1+i, 9, 0, 0, 0, time.UTC), project.ID, 1, []string{"work"})
+		if err := store.CreateRecord(ctx, records[i]); err != nil {
+			t.Fatalf("Failed to create record: %v", err)
+		}
+	}
+
+	// 変更日時は秒単位のため、過去にずらしてから各変更で進むことを確認する
+	backdated := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		change func() error
+	}{
+		{"back-dated insert", func() error {
+			record, _ := model.NewRecord(time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC), project.ID, 1, nil)
+			return store.CreateRecord(ctx, record)
+		}},
+		{"value edit", func() error {
+			records[0].Value = 5
+			return store.UpdateRecord(ctx, records[0])
+		}},
+		{"add tag", func() error {
+			_, err := store.AddTag(ctx, &AddTagParams{
+				ProjectID: project.ID,
+				Tag:       "extra",
+				From:      time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+				To:        time.Date(2025, 6, 3, 0, 0, 0, 0, time.UTC),
+			})
+			return err
+		}},
+		{"soft delete", func() error { return store.DeleteRecord(ctx, records[1].ID) }},
+		{"restore", func() error { return store.RestoreRecord(ctx, records[1].ID) }},
+		{"purge", func() error { return store.PurgeRecord(ctx, records[2].ID) }},
+		{"delete until", func() error {
+			_, err := store.DeleteRecordsUntil(ctx, project.ID, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+			return err
+		}},
+		{"set tag alias", func() error {
+			alias, _ := model.NewTagAlias("w", "work")
+			return store.SetTagAlias(ctx, project.ID, alias)
+		}},
+		{"delete tag alias", func() error { return store.DeleteTagAlias(ctx, project.ID, "w") }},
+		{"project update", func() error {
+			project.Description = "updated"
+			project.UpdatedAt = time.Now()
+			return store.UpdateProject(ctx, project)
+		}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := store.conn.ExecContext(ctx, "UPDATE projects SET updated_at = ?, changed_at = ? WHERE id = ?",
+				formatTime(backdated), formatTime(backdated), project.ID.ToInt64()); err != nil {
+				t.Fatalf("Failed to backdate project: %v", err)
+			}
+			lastModified, err := store.GetProjectLastModified(ctx, project.ID)
+			if err != nil {
+				t.Fatalf("Failed to get last modified: %v", err)
+			}
+			if !lastModified.Equal(backdated) {
+				t.Fatalf("Expected last modified %v before the change, got %v", backdated, lastModified)
+			}
+
+			if err := tc.change(); err != nil {
+				t.Fatalf("Failed to apply change: %v", err)
+			}
+			lastModified, err = store.GetProjectLastModified(ctx, project.ID)
+			if err != nil {
+				t.Fatalf("Failed to get last modified: %v", err)
+			}
+			if !lastModified.After(backdated) {
+				t.Errorf("Expected last modified to advance after %s, got %v", tc.name, lastModified)
+			}
+		})
+	}
+}
+
+func TestRecordSource(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	project, err := model.NewProject("source-project", "")
+	if err != nil {
+		t.Fatalf("Failed to create project model: %v", err)
+	}
+	if err := store.CreateProject(context.Background(), project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.Local)
+	sources := []string{model.RecordSourceAPI, model.RecordSourceTrack, model.RecordSourceTrack}
+	var ids []model.HexID
+	for i, source := range sources {
+		record, err := model.NewRecord(base.Add(time.Duration(i)*time.Hour), project.ID, i+1, []string{"web"})
+		if err != nil {
+			t.Fatalf("Failed to create record: %v", err)
+		}
+		record.Source = source
+		if err := store.CreateRecord(context.Background(), record); err != nil {
+			t.Fatalf("Failed to store record: %v", err)
+		}
+		ids = append(ids, record.ID)
+	}
+
+	// 取得時に作成元が復元される
+	got, err := store.GetRecord(context.Background(), ids[1])
+	if err != nil {
+		t.Fatalf("Failed to get record: %v", err)
+	}
+	if got.Source != model.RecordSourceTrack {
+		t.Errorf("Expected source %q, got %q", model.RecordSourceTrack, got.Source)
+	}
+
+	// タグフィルタの有無に関わらず作成元で絞り込める
+	for _, tags := range [][]string{nil, {"web"}} {
+		records, err := store.ListRecords(context.Background(), &ListRecordsParams{
+			ProjectID:  project.ID,
+			From:       base.AddDate(0, 0, -1),
+			To:         base.AddDate(0, 0, 1),
+			Pagination: model.NewPaginationWithValues(100, nil),
+			Tags:       tags,
+			Source:     model.RecordSourceTrack,
+		})
+		if err != nil {
+			t.Fatalf("Failed to list records: %v", err)
+		}
+		if len(records) != 2 {
+			t.Fatalf("Expected 2 track records with tags %v, got %d", tags, len(records))
+		}
+		for _, r := range records {
+			if r.Source != model.RecordSourceTrack {
+				t.Errorf("Expected only track records, got %q", r.Source)
+			}
+		}
+	}
+
+	// フィルタなしではすべて返る
+	records, err := store.ListRecords(context.Background(), &ListRecordsParams{
+		ProjectID:  project.ID,
+		From:       base.AddDate(0, 0, -1),
+		To:         base.AddDate(0, 0, 1),
+		Pagination: model.NewPaginationWithValues(100, nil),
+	})
+	if err != nil {
+		t.Fatalf("Failed to list records: %v", err)
+	}
+	if len(records) != 3 {
+		t.Errorf("Expected 3 records without source filter, got %d", len(records))
+	}
+}
+
+func TestRecordExternalID(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	project, _ := model.NewProject("import-project", "")
+	other, _ := model.NewProject("other-project", "")
+	for _, p := range []*model.Project{project, other} {
+		if err := store.CreateProject(ctx, p); err != nil {
+			t.Fatalf("Failed to create project: %v", err)
+		}
+	}
+
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	newRecord := func(projectID model.HexID, externalID string) *model.Record {
+		record, err := model.NewRecord(base, projectID, 3, []string{"imported"})
+		if err != nil {
+			t.Fatalf("Failed to create record model: %v", err)
+		}
+		if externalID != "" {
+			record.ExternalID = &externalID
+		}
+		return record
+	}
+
+	first := newRecord(project.ID, "ext-1")
+	if err := store.CreateRecord(ctx, first); err != nil {
+		t.Fatalf("Failed to create record: %v", err)
+	}
+
+	// 同じ外部IDの再インポートは作成されない
+	if err := store.CreateRecord(ctx, newRecord(project.ID, "ext-1")); !errors.Is(err, model.ErrExternalIDTaken) {
+		t.Fatalf("Expected ErrExternalIDTaken for a re-import, got %v", err)
+	}
+	listParams := &ListRecordsParams{
+		ProjectID:  project.ID,
+		From:       base.AddDate(0, 0, -1),
+		To:         base.AddDate(0, 0, 1),
+		Pagination: model.NewPaginationWithValues(100, nil),
+	}
+	records, err := store.ListRecords(ctx, listParams)
+	if err != nil {
+		t.Fatalf("Failed to list records: %v", err)
+	}
+	if len(records) != 1 || !records[0].ID.Equals(first.ID) {
+		t.Fatalf("Expected only the first import, got %+v", records)
+	}
+	if records[0].ExternalID == nil || *records[0].ExternalID != "ext-1" {
+		t.Errorf("Expected external_id ext-1 in the list, got %v", records[0].ExternalID)
+	}
+
+	// 外部IDで取得できる
+	got, err := store.GetRecordByExternalID(ctx, project.ID, "ext-1")
+	if err != nil {
+		t.Fatalf("Failed to get record by external ID: %v", err)
+	}
+	if !got.ID.Equals(first.ID) || got.ExternalID == nil || *got.ExternalID != "ext-1" {
+		t.Errorf("Expected the first import, got %+v", got)
+	}
+	if _, err := store.GetRecordByExternalID(ctx, other.ID, "ext-1"); !errors.Is(err, model.ErrRecordNotFound) {
+		t.Errorf("Expected ErrRecordNotFound in another project, got %v", err)
+	}
+
+	// 外部IDは別のプロジェクトでは重複でき、外部IDのないレコードはいくつでも作成できる
+	for _, record := range []*model.Record{newRecord(other.ID, "ext-1"), newRecord(project.ID, ""), newRecord(project.ID, "")} {
+		if err := store.CreateRecord(ctx, record); err != nil {
+			t.Fatalf("Failed to create record: %v", err)
+		}
+	}
+
+	// タグフィルタの有無に関わらず外部IDで絞り込める
+	for _, tags := range [][]string{nil, {"imported"}} {
+		listParams.Tags = tags
+		listParams.ExternalID = "ext-1"
+		records, err := store.ListRecords(ctx, listParams)
+		if err != nil {
+			t.Fatalf("Failed to list records: %v", err)
+		}
+		if len(records) != 1 || !records[0].ID.Equals(first.ID) {
+			t.Errorf("Expected only the record with external_id ext-1 with tags %v, got %+v", tags, records)
+		}
+	}
+
+	// 論理削除されたレコードも外部IDを保持するため、再インポートで復活しない
+	if err := store.DeleteRecord(ctx, first.ID); err != nil {
+		t.Fatalf("Failed to delete record: %v", err)
+	}
+	if err := store.CreateRecord(ctx, newRecord(project.ID, "ext-1")); !errors.Is(err, model.ErrExternalIDTaken) {
+		t.Errorf("Expected ErrExternalIDTaken for a deleted record, got %v", err)
+	}
+}
+
+func TestPruneOrphanTags(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	project, err := model.NewProject("prune-project", "")
+	if err != nil {
+		t.Fatalf("Failed to create project model: %v", err)
+	}
+	if err := store.CreateProject(ctx, project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	record, err := model.NewRecord(time.Now(), project.ID, 1, []string{"keep"})
+	if err != nil {
+		t.Fatalf("Failed to create record: %v", err)
+	}
+	if err := store.CreateRecord(ctx, record); err != nil {
+		t.Fatalf("Failed to store record: %v", err)
+	}
+
+	// 外部キー制約を無効にした接続で孤立したタグ行を挿入
+	conn, err := store.conn.Conn(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get connection: %v", err)
+	}
+	if _, err := conn.ExecContext(ctx, `PRAGMA foreign_keys = OFF`); err != nil {
+		t.Fatalf("Failed to disable foreign keys: %v", err)
+	}
+	if _, err := conn.ExecContext(ctx, `INSERT INTO tags (record_id, tag, order_index) VALUES (9999, 'orphan', 0), (9999, 'orphan2', 1)`); err != nil {
+		t.Fatalf("Failed to insert orphan tags: %v", err)
+	}
+	if _, err := conn.ExecContext(ctx, `PRAGMA foreign_keys = ON`); err != nil {
+		t.Fatalf("Failed to enable foreign keys: %v", err)
+	}
+	conn.Close()
+
+	count, err := store.PruneOrphanTags(ctx)
+	if err != nil {
+		t.Fatalf("Failed to prune orphan tags: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 pruned tags, got %d", count)
+	}
+
+	// 既存レコードのタグは残る
+	got, err := store.GetRecord(ctx, record.ID)
+	if err != nil {
+		t.Fatalf("Failed to get record: %v", err)
+	}
+	if len(got.Tags) != 1 || got.Tags[0] != "keep" {
+		t.Errorf("Expected record tags to be kept, got %v", got.Tags)
+	}
+
+	// 2回目は何も削除されない
+	count, err = store.PruneOrphanTags(ctx)
+	if err != nil {
+		t.Fatalf("Failed to prune orphan tags: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("Expected 0 pruned tags on second run, got %d", count)
+	}
+}
+
+func TestVacuumAfterBulkDeletion(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	project, err := model.NewProject("vacuum-project", "")
+	if err != nil {
+		t.Fatalf("Failed to create project model: %v", err)
+	}
+	if err := store.CreateProject(ctx, project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range 500 {
+		record, err := model.NewRecord(base.Add(time.Duration(i)*time.Minute), project.ID, 1, []string{"bulk", "vacuum"})
+		if err != nil {
+			t.Fatalf("Failed to create record: %v", err)
+		}
+		if err := store.CreateRecord(ctx, record); err != nil {
+			t.Fatalf("Failed to store record: %v", err)
+		}
+	}
+
+	if _, err := store.DeleteRecordsUntil(ctx, project.ID, base.AddDate(0, 0, 1)); err != nil {
+		t.Fatalf("Failed to delete records: %v", err)
+	}
+
+	before, err := store.DatabaseSize(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get database size: %v", err)
+	}
+
+	if err := store.Vacuum(ctx); err != nil {
+		t.Fatalf("Failed to vacuum: %v", err)
+	}
+
+	after, err := store.DatabaseSize(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get database size: %v", err)
+	}
+	if after > before {
+		t.Errorf("Expected database not to grow after vacuum: before=%d, after=%d", before, after)
+	}
+}
+
+func TestSchemaVersion(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "sougen-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// gooseによるマイグレーションを実行
+	migrated, err := NewSQLiteStore(tempDir, db.Migrate, SQLiteOptions{})
+	if err != nil {
+		t.Fatalf("Failed to create store with migrations: %v", err)
+	}
+	defer migrated.Close()
+
+	version, err := migrated.SchemaVersion(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to get schema version: %v", err)
+	}
+
+	// 埋め込まれたマイグレーションファイルの最新バージョンと一致すること
+	entries, err := os.ReadDir("../db/schema")
+	if err != nil {
+		t.Fatalf("Failed to read schema dir: %v", err)
+	}
+	var latest int64
+	for _, e := range entries {
+		var v int64
+		if _, err := fmt.Sscanf(e.Name(), "%d_", &v); err == nil && v > latest {
+			latest = v
+		}
+	}
+	if version != latest {
+		t.Errorf("Expected schema version %d, got %d", latest, version)
+	}
+}
+
+func TestSchemaVersionWithoutGoose(t *testing.T) {
+	// マイグレーションを実行せずに初期化
+	store, err := NewSQLiteStore(t.TempDir(), nil, SQLiteOptions{})
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	// gooseのバージョンテーブルがない場合は0
+	version, err := store.SchemaVersion(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to get schema version: %v", err)
+	}
+	if version != 0 {
+		t.Errorf("Expected schema version 0, got %d", version)
+	}
+}
+
+// TestMigrationIndexes はマイグレーション関数で作成したストアに、クエリが前提とするインデックスがあることをテストします。
+func TestMigrationIndexes(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	rows, err := store.conn.QueryContext(context.Background(),
+		`SELECT name FROM sqlite_master WHERE type = 'index' AND name LIKE 'idx_%' ORDER BY name`,
+	)
+	if err != nil {
+		t.Fatalf("Failed to list indexes: %v", err)
+	}
+	defer rows.Close()
+	var indexes []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatalf("Failed to scan index: %v", err)
+		}
+		indexes = append(indexes, name)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("Failed to list indexes: %v", err)
+	}
+
+	expected := []string{
+		"idx_projects_name",
+		"idx_projects_updated_at",
+		"idx_records_project_id_created_at",
+		"idx_records_project_id_external_id",
+		"idx_records_project_id_source",
+		"idx_records_project_id_timestamp",
+		"idx_records_timestamp",
+		"idx_tags_record_id",
+		"idx_tags_record_order",
+		"idx_tags_tag",
+	}
+	if !slices.Equal(indexes, expected) {
+		t.Errorf("Expected indexes %v, got %v", expected, indexes)
+	}
+}
+
+// TestConnectionPragmas はプールのすべての接続で外部キー制約と同期モードが有効なことをテストします。
+func TestConnectionPragmas(t *testing.T) {
+	tempDir := t.TempDir()
+	store, err := NewSQLiteStore(tempDir, db.Migrate, SQLiteOptions{Synchronous: "full"})
+	if err != nil {
+		t.Fatalf("Failed to create test store: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+
+	// 複数の接続を同時に確保して、最初の接続以外でもPRAGMAが設定されていることを確認
+	conns := make([]*sql.Conn, 0, 4)
+	defer func() {
+		for _, c := range conns {
+			c.Close()
+		}
+	}()
+	for i := range 4 {
+		c, err := store.conn.Conn(ctx)
+		if err != nil {
+			t.Fatalf("Failed to get connection %d: %v", i, err)
+		}
+		conns = append(conns, c)
+
+		var foreignKeys, synchronous int
+		if err := c.QueryRowContext(ctx, `PRAGMA foreign_keys`).Scan(&foreignKeys); err != nil {
+			t.Fatalf("Failed to read foreign_keys: %v", err)
+		}
+		if err := c.QueryRowContext(ctx, `PRAGMA synchronous`).Scan(&synchronous); err != nil {
+			t.Fatalf("Failed to read synchronous: %v", err)
+		}
+		if foreignKeys != 1 {
+			t.Errorf("Connection %d: expected foreign_keys=1, got %d", i, foreignKeys)
+		}
+		if synchronous != 2 { // FULL
+			t.Errorf("Connection %d: expected synchronous=2, got %d", i, synchronous)
+		}
+		if _, err := c.ExecContext(ctx, `INSERT INTO tags (record_id, tag, order_index) VALUES (9999, 'orphan', 0)`); err == nil {
+			t.Errorf("Connection %d: expected foreign key constraint error for an orphan tag", i)
+		}
+	}
+	for _, c := range conns {
+		c.Close()
+	}
+	conns = nil
+
+	// 操作を繰り返しても外部キー制約が維持される
+	project, err := model.NewProject("fk-project", "")
+	if err != nil {
+		t.Fatalf("Failed to create project model: %v", err)
+	}
+	if err := store.CreateProject(ctx, project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	for i := range 5 {
+		record, err := model.NewRecord(time.Date(2025, 5, 21+i, 12, 0, 0, 0, time.Local), project.ID, 1, []string{"tag"})
+		if err != nil {
+			t.Fatalf("Failed to create record model: %v", err)
+		}
+		if err := store.CreateRecord(ctx, record); err != nil {
+			t.Fatalf("Failed to create record: %v", err)
+		}
+		record.ProjectID = model.NewHexID(int64(9000 + i))
+		err = store.UpdateRecord(ctx, record)
+		if err == nil || !strings.Contains(err.Error(), "FOREIGN KEY constraint failed") {
+			t.Errorf("Operation %d: expected foreign key constraint error, got: %v", i, err)
+		}
+	}
+
+	if _, err := NewSQLiteStore(t.TempDir(), db.Migrate, SQLiteOptions{Synchronous: "sometimes"}); err == nil {
+		t.Error("Expected error for an invalid synchronous mode")
+	}
+}
+
+// TestTagAliases はタグの別名による書き込み時の正規化と検索をテストします。
+func TestTagAliases(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	project, err := model.NewProject("alias-project", "")
+	if err != nil {
+		t.Fatalf("Failed to create project model: %v", err)
+	}
+	if err := store.CreateProject(ctx, project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	alias, _ := model.NewTagAlias("js", "javascript")
+	if err := store.SetTagAlias(ctx, project.ID, alias); err != nil {
+		t.Fatalf("Failed to set tag alias: %v", err)
+	}
+	if err := store.SetTagAlias(ctx, model.NewHexID(99999), alias); !errors.Is(err, model.ErrProjectNotFound) {
+		t.Errorf("Expected ErrProjectNotFound for a missing project, got %v", err)
+	}
+
+	// 別名で書き込んだタグは正規のタグで保存される
+	record, _ := model.NewRecord(time.Date(2025, 6, 1, 9, 0, 0, 0, time.Local), project.ID, 1, []string{"js", "web"})
+	if err := store.CreateRecord(ctx, record); err != nil {
+		t.Fatalf("Failed to create record: %v", err)
+	}
+	got, err := store.GetRecord(ctx, record.ID)
+	if err != nil {
+		t.Fatalf("Failed to get record: %v", err)
+	}
+	if !slices.Equal(got.Tags, []string{"javascript", "web"}) {
+		t.Errorf("Expected canonical tags, got %v", got.Tags)
+	}
+
+	// 正規のタグと別名のどちらで検索しても見つかる
+	for _, tag := range []string{"javascript", "js"} {
+		records, err := store.ListRecords(ctx, &ListRecordsParams{
+			ProjectID:  project.ID,
+			From:       time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local),
+			To:         time.Date(2025, 6, 30, 0, 0, 0, 0, time.Local),
+			Pagination: model.NewPaginationWithValues(10, nil),
+			Tags:       []string{tag},
+		})
+		if err != nil {
+			t.Fatalf("Failed to list records: %v", err)
+		}
+		if len(records) != 1 || !records[0].ID.Equals(record.ID) {
+			t.Errorf("Expected the record when filtering by %s, got %d records", tag, len(records))
+		}
+	}
+
+	// 更新時も正規化され、置き換えで重複したタグは1つになる
+	got.Tags = []string{"js", "javascript"}
+	if err := store.UpdateRecord(ctx, got); err != nil {
+		t.Fatalf("Failed to update record: %v", err)
+	}
+	updated, _ := store.GetRecord(ctx, record.ID)
+	if !slices.Equal(updated.Tags, []string{"javascript"}) {
+		t.Errorf("Expected deduplicated canonical tags, got %v", updated.Tags)
+	}
+
+	aliases, err := store.ListTagAliases(ctx, project.ID)
+	if err != nil {
+		t.Fatalf("Failed to list tag aliases: %v", err)
+	}
+	if len(aliases) != 1 || *aliases[0] != *alias {
+		t.Errorf("Expected [%+v], got %v", *alias, aliases)
+	}
+
+	// 別名を削除すると以後は別名のまま保存される
+	if err := store.DeleteTagAlias(ctx, project.ID, "js"); err != nil {
+		t.Fatalf("Failed to delete tag alias: %v", err)
+	}
+	if err := store.DeleteTagAlias(ctx, project.ID, "js"); !errors.Is(err, model.ErrTagAliasNotFound) {
+		t.Errorf("Expected ErrTagAliasNotFound, got %v", err)
+	}
+	plain, _ := model.NewRecord(time.Date(2025, 6, 2, 9, 0, 0, 0, time.Local), project.ID, 1, []string{"js"})
+	if err := store.CreateRecord(ctx, plain); err != nil {
+		t.Fatalf("Failed to create record: %v", err)
+	}
+	if !slices.Equal(plain.Tags, []string{"js"}) {
+		t.Errorf("Expected tags unchanged after deleting the alias, got %v", plain.Tags)
+	}
+}
+
+func TestAddTag(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	project, err := model.NewProject("add-tag-project", "")
+	if err != nil {
+		t.Fatalf("Failed to create project model: %v", err)
+	}
+	if err := store.CreateProject(ctx, project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	// 6/2〜6/3のレコードが対象（6/3のレコードはすでにタグを持つ）
+	entries := []struct {
+		timestamp time.Time
+		tags      []string
+	}{
+		{time.Date(2025, 6, 1, 23, 0, 0, 0, time.Local), []string{"work"}}, // 期間外
+		{time.Date(2025, 6, 2, 0, 0, 0, 0, time.Local), []string{"work"}},
+		{time.Date(2025, 6, 2, 9, 0, 0, 0, time.Local), nil},
+		{time.Date(2025, 6, 3, 23, 0, 0, 0, time.Local), []string{"review", "work"}},
+		{time.Date(2025, 6, 4, 0, 0, 0, 0, time.Local), []string{"work"}}, // 期間外
+	}
+	records := make([]*model.Record, len(entries))
+	for i, e := range entries {
+		record, err := model.NewRecord(e.timestamp, project.ID, 1, e.tags)
+		if err != nil {
+			t.Fatalf("Failed to create record model: %v", err)
+		}
+		if err := store.CreateRecord(ctx, record); err != nil {
+			t.Fatalf("Failed to create record: %v", err)
+		}
+		records[i] = record
+	}
+
+	count, err := store.AddTag(ctx, &AddTagParams{
+		ProjectID: project.ID,
+		Tag:       "review",
+		From:      time.Date(2025, 6, 2, 0, 0, 0, 0, time.Local),
+		To:        time.Date(2025, 6, 3, 0, 0, 0, 0, time.Local),
+	})
+	if err != nil {
+		t.Fatalf("Failed to add tag: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 updated records, got %d", count)
+	}
+
+	expectedTags := [][]string{
+		{"work"},
+		{"work", "review"},
+		{"review"},
+		{"review", "work"},
+		{"work"},
+	}
+	for i, record := range records {
+		got, err := store.GetRecord(ctx, record.ID)
+		if err != nil {
+			t.Fatalf("Failed to get record: %v", err)
+		}
+		if !slices.Equal(got.Tags, expectedTags[i]) {
+			t.Errorf("Record %d: expected tags %v, got %v", i, expectedTags[i], got.Tags)
+		}
+	}
+
+	// タグフィルタあり（指定したタグを持つレコードのみ）
+	count, err = store.AddTag(ctx, &AddTagParams{
+		ProjectID: project.ID,
+		Tag:       "done",
+		From:      time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local),
+		To:        time.Date(2025, 6, 30, 0, 0, 0, 0, time.Local),
+		Tags:      []string{"work", "review"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to add tag: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 updated records with tag filter, got %d", count)
+	}
+	for i, record := range records {
+		got, err := store.GetRecord(ctx, record.ID)
+		if err != nil {
+			t.Fatalf("Failed to get record: %v", err)
+		}
+		if want := i == 1 || i == 3; slices.Contains(got.Tags, "done") != want {
+			t.Errorf("Record %d: expected done tag %v, got tags %v", i, want, got.Tags)
+		}
+	}
+
+	// 不正なタグ
+	if _, err := store.AddTag(ctx, &AddTagParams{ProjectID: project.ID, Tag: "bad tag"}); err == nil {
+		t.Error("Expected error for invalid tag, got nil")
+	}
+}
+
+func TestCopyDayRecords(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	project, err := model.NewProject("shift-project", "")
+	if err != nil {
+		t.Fatalf("Failed to create project model: %v", err)
+	}
+	if err := store.CreateProject(ctx, project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	// 6/1のレコード2件を6/2に複製する
+	for _, e := range []struct {
+		timestamp time.Time
+		value     int
+		tags      []string
+	}{
+		{time.Date(2025, 6, 1, 7, 30, 0, 0, time.Local), 3, []string{"run", "morning"}},
+		{time.Date(2025, 6, 1, 21, 0, 0, 0, time.Local), 5, nil},
+	} {
+		record, err := model.NewRecord(e.timestamp, project.ID, e.value, e.tags)
+		if err != nil {
+			t.Fatalf("Failed to create record model: %v", err)
+		}
+		if err := store.CreateRecord(ctx, record); err != nil {
+			t.Fatalf("Failed to create record: %v", err)
+		}
+	}
+
+	count, err := store.CopyDayRecords(ctx, project.ID,
+		time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local), time.Date(2025, 6, 2, 0, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatalf("Failed to copy records: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 copied records, got %d", count)
+	}
+
+	records, err := store.ListRecords(ctx, &ListRecordsParams{
+		ProjectID:  project.ID,
+		From:       time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local),
+		To:         time.Date(2025, 6, 2, 0, 0, 0, 0, time.Local),
+		Pagination: model.NewPaginationWithValues(100, nil),
+	})
+	if err != nil {
+		t.Fatalf("Failed to list records: %v", err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("Expected 4 records in total, got %d", len(records))
+	}
+	// 降順なので先頭2件が複製されたレコード
+	if !records[0].Timestamp.Equal(time.Date(2025, 6, 2, 21, 0, 0, 0, time.Local)) || records[0].Value != 5 {
+		t.Errorf("Unexpected copied record: %+v", records[0])
+	}
+	if !records[1].Timestamp.Equal(time.Date(2025, 6, 2, 7, 30, 0, 0, time.Local)) || records[1].Value != 3 ||
+		!slices.Equal(records[1].Tags, []string{"run", "morning"}) {
+		t.Errorf("Unexpected copied record: %+v", records[1])
+	}
+	if records[1].ID == records[3].ID {
+		t.Error("Expected copied records to get new IDs")
+	}
+
+	// 同じ日への複製はエラー
+	var validationErr *model.ValidationError
+	if _, err := store.CopyDayRecords(ctx, project.ID,
+		time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local), time.Date(2025, 6, 1, 12, 0, 0, 0, time.Local)); !errors.As(err, &validationErr) {
+		t.Errorf("Expected validation error for the same day, got %v", err)
+	}
+
+	// 存在しないプロジェクト
+	if _, err := store.CopyDayRecords(ctx, model.NewHexID(999),
+		time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local), time.Date(2025, 6, 2, 0, 0, 0, 0, time.Local)); !errors.Is(err, model.ErrProjectNotFound) {
+		t.Errorf("Expected ErrProjectNotFound, got %v", err)
+	}
+}